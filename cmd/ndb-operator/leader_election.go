@@ -0,0 +1,88 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package main
+
+import (
+	"context"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config"
+	"github.com/mysql/ndb-operator/pkg/metrics"
+)
+
+// leaseName is the name of the Lease used by the operator replicas to elect a leader
+const leaseName = "ndb-operator-leader-election"
+
+// getLeaderElectionIdentity returns the identity of this operator
+// replica. It is the pod name, when available, followed by an
+// UUID to keep it unique even if the pods share their hostname.
+func getLeaderElectionIdentity() string {
+	name := os.Getenv("NDB_OPERATOR_POD_NAME")
+	if name == "" {
+		var err error
+		if name, err = os.Hostname(); err != nil {
+			klog.Fatalf("Could not get the hostname : %s", err)
+		}
+	}
+	return name + "_" + string(uuid.NewUUID())
+}
+
+// runWithLeaderElection blocks until this operator replica becomes
+// the leader and then runs the given function. The operator exits
+// if the leadership is lost, so that the controllers stop and one
+// of the standby replicas takes over.
+func runWithLeaderElection(ctx context.Context, kubeClient kubernetes.Interface, run func(ctx context.Context)) {
+	identity := getLeaderElectionIdentity()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: config.LeaderElectionNamespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	klog.Infof("Attempting to acquire the leader election lease %s/%s as %q",
+		config.LeaderElectionNamespace, leaseName, identity)
+	metrics.SetIsLeader(false)
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaderElectionLeaseDuration,
+		RenewDeadline:   config.LeaderElectionRenewDeadline,
+		RetryPeriod:     config.LeaderElectionRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				klog.Infof("Acquired the leader election lease. Starting the controllers.")
+				metrics.SetIsLeader(true)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				metrics.SetIsLeader(false)
+				select {
+				case <-ctx.Done():
+					// Operator is shutting down
+					klog.Info("Released the leader election lease")
+				default:
+					klog.Fatalf("Lost the leader election lease")
+				}
+			},
+			OnNewLeader: func(currentLeader string) {
+				klog.Infof("Current leader of the NDB Operator is %q", currentLeader)
+				metrics.SetLeader(currentLeader)
+			},
+		},
+	})
+}
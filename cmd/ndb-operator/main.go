@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -19,7 +19,9 @@ import (
 	"github.com/mysql/ndb-operator/pkg/controllers"
 	clientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
 	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/healthz"
 	"github.com/mysql/ndb-operator/pkg/helpers"
+	"github.com/mysql/ndb-operator/pkg/metrics"
 	"github.com/mysql/ndb-operator/pkg/signals"
 )
 
@@ -57,6 +59,14 @@ func main() {
 		klog.Fatalf("Error getting kubeconfig: %s", err.Error())
 	}
 
+	if config.LeaderElect && config.LeaderElectionNamespace == "" {
+		// Create the leader election Lease in the namespace the operator is deployed in
+		config.LeaderElectionNamespace, err = helpers.GetCurrentNamespace()
+		if err != nil {
+			klog.Fatalf("Could not get current namespace : %s", err)
+		}
+	}
+
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		klog.Fatalf("Error building kubernetes clientset: %s", err.Error())
@@ -83,14 +93,83 @@ func main() {
 		ndbClient, time.Second*30, ndbinformers.WithNamespace(config.WatchNamespace))
 
 	controller := controllers.NewController(kubeClient, ndbClient, k8If, ndbIf)
+	backupController := controllers.NewNdbClusterBackupController(kubeClient, ndbClient, cfg, ndbIf)
+	backupScheduleController := controllers.NewNdbClusterBackupScheduleController(kubeClient, ndbClient, cfg, k8If, ndbIf)
+	mysqlUserController := controllers.NewNdbMySQLUserController(kubeClient, ndbClient, k8If, ndbIf)
+	mysqlDatabaseController := controllers.NewNdbMySQLDatabaseController(kubeClient, ndbClient, k8If, ndbIf)
+	replicationChannelController := controllers.NewNdbReplicationChannelController(kubeClient, ndbClient, k8If, ndbIf)
+	ndbClusterCollector := controllers.NewNdbClusterCollector(kubeClient, k8If, ndbIf)
 
 	// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
 	// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
 	k8If.Start(ctx.Done())
 	ndbIf.Start(ctx.Done())
 
-	if err = controller.Run(ctx, 2); err != nil {
-		klog.Fatalf("Error running controller: %s", err.Error())
+	// Serve the metrics from all the replicas, so that
+	// the leader election metrics are always available.
+	if config.MetricsBindAddress != "" {
+		go metrics.Serve(ctx, config.MetricsBindAddress)
+	}
+
+	// The replicas are ready once the informer caches have synced,
+	// and are alive as long as none of the workers are stuck.
+	if config.HealthProbeBindAddress != "" {
+		go healthz.Serve(ctx, config.HealthProbeBindAddress,
+			[]healthz.Check{
+				{Name: "workers", Check: controller.CheckWorkers},
+				{Name: "backup-workers", Check: backupController.CheckWorkers},
+				{Name: "backup-schedule-workers", Check: backupScheduleController.CheckWorkers},
+				{Name: "mysql-user-workers", Check: mysqlUserController.CheckWorkers},
+				{Name: "mysql-database-workers", Check: mysqlDatabaseController.CheckWorkers},
+				{Name: "replication-channel-workers", Check: replicationChannelController.CheckWorkers},
+			},
+			[]healthz.Check{{Name: "informers", Check: controller.CheckInformersSynced}})
+	}
+
+	// run starts all the controllers and blocks until the context is done
+	run := func(ctx context.Context) {
+		// Only the leader exports the metrics of the MySQL Clusters
+		metrics.Registry.MustRegister(ndbClusterCollector)
+
+		// Backups are taken by a separate set of workers as
+		// they have to wait for the backups to complete.
+		go func() {
+			if err := backupController.Run(ctx, 1); err != nil {
+				klog.Fatalf("Error running NdbClusterBackup controller: %s", err.Error())
+			}
+		}()
+		go func() {
+			if err := backupScheduleController.Run(ctx, 1); err != nil {
+				klog.Fatalf("Error running NdbClusterBackupSchedule controller: %s", err.Error())
+			}
+		}()
+		go func() {
+			if err := mysqlUserController.Run(ctx, 1); err != nil {
+				klog.Fatalf("Error running NdbMySQLUser controller: %s", err.Error())
+			}
+		}()
+		go func() {
+			if err := mysqlDatabaseController.Run(ctx, 1); err != nil {
+				klog.Fatalf("Error running NdbMySQLDatabase controller: %s", err.Error())
+			}
+		}()
+		go func() {
+			if err := replicationChannelController.Run(ctx, 1); err != nil {
+				klog.Fatalf("Error running NdbReplicationChannel controller: %s", err.Error())
+			}
+		}()
+
+		if err := controller.Run(ctx, 2); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}
+
+	if config.LeaderElect {
+		// Only the leader runs the controllers. The informers are
+		// started anyway to keep the caches warm for a quick takeover.
+		runWithLeaderElection(ctx, kubeClient, run)
+	} else {
+		run(ctx)
 	}
 }
 
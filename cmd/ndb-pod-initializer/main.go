@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
@@ -19,6 +20,8 @@ import (
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/mgmapi"
 	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 )
 
@@ -131,6 +134,12 @@ func waitForPodDNSUpdate(ctx context.Context, podHostname string) {
 
 // getPodMySQLClusterNodeType returns the type of the MySQL Cluster node the pod is running.
 func getPodMySQLClusterNodeType(podHostname string) constants.NdbNodeType {
+	if _, exists := os.LookupEnv("NDB_MYSQLD_START_NODE_ID"); exists {
+		// Pod runs a MySQL Server of a group declared in spec.mysqlNodeGroups.
+		// Hostname will be of form <ndbcluster name>-mysqld-<group name>-<sfset pod ordinal index>
+		return constants.NdbNodeTypeMySQLD
+	}
+
 	// Hostname will be of form <ndbcluster name>-<node-type>-<sfset pod ordinal index>
 	tokens := strings.Split(podHostname, "-")
 	return tokens[len(tokens)-2]
@@ -139,48 +148,52 @@ func getPodMySQLClusterNodeType(podHostname string) constants.NdbNodeType {
 // writeNodeIDToFile deduces the nodeId of the current MySQL Cluster
 // node, writes it to a file and then returns the nodeId.
 func writeNodeIDToFile(hostname, ndbConnectString string) (nodeId int, nodeIdPool []int, nodeType mgmapi.NodeTypeEnum) {
-	// All nodeIds are sequentially assigned based on the ordinal indices
-	// of the StatefulSet pods. So deduce the nodeId of the first MySQL
-	// Cluster node of same nodeType (i.e) the node with StatefulSet ordinal
-	// index 0, of same type. Then the nodeId of the current node will be
-	// startNodeId + pod ordinal index.
-	var startNodeIdOfSameNodeType int
-	switch getPodMySQLClusterNodeType(hostname) {
-	case constants.NdbNodeTypeMgmd:
-		// Management nodes have nodeId 1 and 2.
-		startNodeIdOfSameNodeType = 1
-		nodeType = mgmapi.NodeTypeMGM
-	case constants.NdbNodeTypeNdbmtd:
-		// Data nodes' nodeId continue sequentially
-		// after the Management nodes' nodeIds.
-		numOfManagementNode := len(strings.Split(ndbConnectString, ",")) - 1
-		startNodeIdOfSameNodeType = numOfManagementNode + 1
-		nodeType = mgmapi.NodeTypeNDB
-	case constants.NdbNodeTypeMySQLD:
-		nodeType = mgmapi.NodeTypeAPI
-	}
-
 	// Extract pod ordinal index from hostname.
 	// Hostname will be of form <ndbcluster name>-<node-type>-<sfset pod ordinal index>
 	tokens := strings.Split(hostname, "-")
 	podOrdinalIndex, _ := strconv.ParseInt(tokens[len(tokens)-1], 10, 32)
 
+	// Data nodes' nodeIds start from NDB_DATA_NODE_START_NODE_ID. If the env
+	// variable is not set, they continue sequentially after the Management
+	// nodes' nodeIds, i.e., after the number of hosts in the connectstring.
+	dataNodeStartNodeId := len(strings.Split(ndbConnectString, ","))
+	if startNodeId, exists := os.LookupEnv("NDB_DATA_NODE_START_NODE_ID"); exists {
+		dataNodeStartNodeId, _ = strconv.Atoi(startNodeId)
+	}
+
 	// Calculate nodeId
 	var nodeIdText string
-	if nodeType == mgmapi.NodeTypeAPI {
+	switch getPodMySQLClusterNodeType(hostname) {
+	case constants.NdbNodeTypeMgmd:
+		// Management nodes that fit before the data nodes have nodeIds
+		// starting from 1 and the rest are assigned nodeIds in the
+		// descending order from the highest allowed nodeId.
+		nodeId = ndbconfig.GetManagementNodeId(int(podOrdinalIndex), dataNodeStartNodeId)
+		nodeIdText = fmt.Sprintf("%d", nodeId)
+		nodeType = mgmapi.NodeTypeMGM
+	case constants.NdbNodeTypeNdbmtd:
+		// Data nodes are sequentially assigned nodeIds
+		// based on the ordinal indices of the StatefulSet pods.
+		nodeId = dataNodeStartNodeId + int(podOrdinalIndex)
+		nodeIdText = fmt.Sprintf("%d", nodeId)
+		nodeType = mgmapi.NodeTypeNDB
+	case constants.NdbNodeTypeMySQLD:
 		// For MySQL Servers, if connection pool is enabled, successive
 		// nodeIds are assigned to a single MySQL Server
 		ndbConnectionPoolSize, _ := strconv.ParseInt(os.Getenv("NDB_CONNECTION_POOL_SIZE"), 10, 32)
-		startNodeId := constants.NdbNodeTypeAPIStartNodeId + int(ndbConnectionPoolSize)*int(podOrdinalIndex)
+		// The nodeIds of a MySQL Server group start from NDB_MYSQLD_START_NODE_ID
+		startNodeId := constants.NdbNodeTypeAPIStartNodeId
+		if groupStartNodeId, exists := os.LookupEnv("NDB_MYSQLD_START_NODE_ID"); exists {
+			startNodeId, _ = strconv.Atoi(groupStartNodeId)
+		}
+		startNodeId += int(ndbConnectionPoolSize) * int(podOrdinalIndex)
 		endNodeId := startNodeId + int(ndbConnectionPoolSize)
 		for ; startNodeId < endNodeId; startNodeId++ {
 			nodeIdPool = append(nodeIdPool, startNodeId)
 			nodeIdText += fmt.Sprintf("%d,", startNodeId)
 		}
 		nodeIdText = nodeIdText[:len(nodeIdText)-1]
-	} else {
-		nodeId = startNodeIdOfSameNodeType + int(podOrdinalIndex)
-		nodeIdText = fmt.Sprintf("%d", nodeId)
+		nodeType = mgmapi.NodeTypeAPI
 	}
 
 	// Persist the nodeId into a file to be used by other scripts/commands
@@ -273,6 +286,25 @@ func waitForDataNodeFailureHandlingToComplete(nodeId int, podHostname string) (s
 	}
 }
 
+// getMgmClientTLSConfig returns the tls.Config to be used to connect to the
+// Management Servers if TLS is enabled in the MySQL Cluster, or nil otherwise.
+func getMgmClientTLSConfig() *tls.Config {
+	caDir := os.Getenv("NDB_TLS_CA_DIR")
+	if caDir == "" {
+		// TLS is not enabled
+		return nil
+	}
+
+	caCert, err := os.ReadFile(caDir + "/" + ndbtls.CACertFileName)
+	failOnError(err, "Failed to read the CA certificate : %s", err)
+	caKey, err := os.ReadFile(caDir + "/" + ndbtls.CAKeyFileName)
+	failOnError(err, "Failed to read the CA private key : %s", err)
+
+	tlsConfig, err := ndbtls.NewMgmClientTLSConfig(caCert, caKey)
+	failOnError(err, "Failed to create a client certificate : %s", err)
+	return tlsConfig
+}
+
 func main() {
 
 	ctx := context.Background()
@@ -293,7 +325,7 @@ func main() {
 	// Connect to the Management Server
 	var err error
 	var mgmClient mgmapi.MgmClient
-	mgmClient, err = mgmapi.NewMgmClient(connectstring)
+	mgmClient, err = mgmapi.NewMgmClientWithTLS(connectstring, getMgmClientTLSConfig())
 	failOnError(err, "Failed to connect to management server : %s", err)
 	defer mgmClient.Disconnect()
 
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -6,6 +6,7 @@ package config
 
 import (
 	"flag"
+	"time"
 
 	"github.com/mysql/ndb-operator/pkg/helpers"
 	klog "k8s.io/klog/v2"
@@ -19,6 +20,26 @@ var (
 	WatchNamespace string
 	// ClusterScoped if set, operator will watch the entire cluster
 	ClusterScoped bool
+
+	// LeaderElect if set, the operator replicas elect a leader
+	// and only the leader runs the controllers.
+	LeaderElect bool
+	// LeaderElectionLeaseDuration is the duration the standby
+	// replicas wait before trying to acquire an unrenewed lease.
+	LeaderElectionLeaseDuration time.Duration
+	// LeaderElectionRenewDeadline is the duration the leader
+	// retries renewing the lease before giving up the leadership.
+	LeaderElectionRenewDeadline time.Duration
+	// LeaderElectionRetryPeriod is the duration the replicas
+	// wait between the attempts to acquire or renew the lease.
+	LeaderElectionRetryPeriod time.Duration
+	// LeaderElectionNamespace is the namespace of the Lease used for leader election.
+	LeaderElectionNamespace string
+
+	// MetricsBindAddress is the address the metrics endpoint binds to
+	MetricsBindAddress string
+	// HealthProbeBindAddress is the address the health probe endpoints bind to
+	HealthProbeBindAddress string
 )
 
 func ValidateFlags() {
@@ -46,6 +67,16 @@ func ValidateFlags() {
 			klog.Fatal("For operator running in namespace-scoped mode out-of-cluster, " +
 				"'-watch-namespace' argument needs to be provided.")
 		}
+
+		// LeaderElectionNamespace is required for operator running out-of-cluster with leader election.
+		if LeaderElect && LeaderElectionNamespace == "" {
+			klog.Fatal("For operator running out-of-cluster with leader election enabled, " +
+				"'-leader-elect-lease-namespace' argument needs to be provided.")
+		}
+	}
+
+	if LeaderElect && LeaderElectionRenewDeadline >= LeaderElectionLeaseDuration {
+		klog.Fatal("'-leader-elect-renew-deadline' should be less than '-leader-elect-lease-duration'.")
 	}
 
 }
@@ -60,4 +91,24 @@ func InitFlags() {
 			"Only required if out-of-cluster.")
 	flag.BoolVar(&ClusterScoped, "cluster-scoped", true, ""+
 		"When enabled, operator looks for NdbCluster resource changes across K8s cluster.")
+	flag.BoolVar(&LeaderElect, "leader-elect", true,
+		"When enabled, the operator replicas elect a leader using a Lease and only the leader reconciles "+
+			"the resources. The other replicas stay on standby and take over when the leader fails.")
+	flag.DurationVar(&LeaderElectionLeaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration the standby replicas wait, after the last renewal of the lease by the leader, "+
+			"before trying to acquire the leadership.")
+	flag.DurationVar(&LeaderElectionRenewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration the leader retries renewing the lease before giving up the leadership. "+
+			"Should be less than the lease duration.")
+	flag.DurationVar(&LeaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration the replicas wait between the attempts to acquire or renew the lease.")
+	flag.StringVar(&LeaderElectionNamespace, "leader-elect-lease-namespace", "",
+		"The namespace in which the leader election Lease is created. "+
+			"Defaults to the namespace the operator is deployed in. Only required if out-of-cluster.")
+	flag.StringVar(&MetricsBindAddress, "metrics-bind-address", ":8080",
+		"The address the /metrics endpoint, serving the Prometheus metrics, binds to. "+
+			"Set it to an empty string to disable the endpoint.")
+	flag.StringVar(&HealthProbeBindAddress, "health-probe-bind-address", ":8081",
+		"The address the /healthz and /readyz endpoints, serving the liveness and the readiness probes, bind to. "+
+			"Set it to an empty string to disable the endpoints.")
 }
@@ -0,0 +1,150 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ndbbackup,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`,description="Name of the NdbCluster being backed up"
+// +kubebuilder:printcolumn:name="Backup Id",type=integer,JSONPath=`.status.backupId`,description="Id of the MySQL Cluster backup"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="Current phase of the backup"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbClusterBackup resource"
+
+// NdbClusterBackup is the Schema for the NdbClusterBackup CRD API.
+// It takes a native backup of the MySQL Cluster managed by an NdbCluster.
+type NdbClusterBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired backup of a MySQL NDB Cluster.
+	Spec NdbClusterBackupSpec `json:"spec"`
+	// The status of the NdbClusterBackup resource and the backup taken by it.
+	Status NdbClusterBackupStatus `json:"status,omitempty"`
+}
+
+// NdbClusterBackupSpec defines the backup to be taken
+type NdbClusterBackupSpec struct {
+	// ClusterName is the name of the NdbCluster resource, in the same
+	// namespace as the NdbClusterBackup, whose MySQL Cluster has to be
+	// backed up.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// BackupId is the id to be used for the backup. If unspecified,
+	// the next available id is picked by the MySQL Cluster data nodes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BackupId *int32 `json:"backupId,omitempty"`
+	// TimeoutSeconds is the duration in seconds the operator waits
+	// for the backup to complete before aborting it.
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// NdbClusterBackupPhase is the phase of the NdbClusterBackup
+type NdbClusterBackupPhase string
+
+const (
+	// NdbClusterBackupPhasePending is the phase of an NdbClusterBackup
+	// whose backup has not been started yet.
+	NdbClusterBackupPhasePending NdbClusterBackupPhase = "Pending"
+	// NdbClusterBackupPhaseRunning is the phase of an NdbClusterBackup
+	// whose backup has been started by the MySQL Cluster data nodes.
+	NdbClusterBackupPhaseRunning NdbClusterBackupPhase = "Running"
+	// NdbClusterBackupPhaseCompleted is the phase of an NdbClusterBackup
+	// whose backup has been successfully completed.
+	NdbClusterBackupPhaseCompleted NdbClusterBackupPhase = "Completed"
+	// NdbClusterBackupPhaseFailed is the phase of an NdbClusterBackup
+	// whose backup failed to start, was aborted or timed out.
+	NdbClusterBackupPhaseFailed NdbClusterBackupPhase = "Failed"
+)
+
+// NdbClusterBackupLocation describes where the backup files
+// written by a MySQL Cluster data node are stored.
+type NdbClusterBackupLocation struct {
+	// NodeId is the id of the data node that wrote the backup files
+	NodeId int32 `json:"nodeId"`
+	// PodName is the name of the data node pod
+	PodName string `json:"podName"`
+	// PersistentVolumeClaimName is the name of the PVC that
+	// stores the backup files. It is empty when the data node
+	// doesn't use a PVC to store its data.
+	// +optional
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+	// Path is the directory, inside the data node pod,
+	// that has the backup files of the data node.
+	Path string `json:"path"`
+}
+
+// NdbClusterBackupStatus is the status of the NdbClusterBackup resource
+type NdbClusterBackupStatus struct {
+	// Phase is the current phase of the backup
+	// +optional
+	Phase NdbClusterBackupPhase `json:"phase,omitempty"`
+	// BackupId is the id of the backup taken by the MySQL Cluster
+	// +optional
+	BackupId int32 `json:"backupId,omitempty"`
+	// StartGCP is the global checkpoint at which the backup started
+	// +optional
+	StartGCP int64 `json:"startGCP,omitempty"`
+	// StopGCP is the global checkpoint at which the backup stopped.
+	// A restored MySQL Cluster will be consistent with this checkpoint.
+	// +optional
+	StopGCP int64 `json:"stopGCP,omitempty"`
+	// NumOfRecords is the number of records in the backup
+	// +optional
+	NumOfRecords int64 `json:"numOfRecords,omitempty"`
+	// NumOfBytes is the size of the backup in bytes
+	// +optional
+	NumOfBytes int64 `json:"numOfBytes,omitempty"`
+	// StartTime is the time the backup was started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the backup completed or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Locations has the location of the backup files
+	// written by each of the MySQL Cluster data nodes.
+	// +optional
+	Locations []NdbClusterBackupLocation `json:"locations,omitempty"`
+	// Message is a human-readable message indicating
+	// details about the outcome of the backup.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbClusterBackupList contains a list of NdbClusterBackup resources
+// +kubebuilder:object:root=true
+type NdbClusterBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbClusterBackup `json:"items"`
+}
+
+// IsFinished returns true if the backup has either completed or failed
+func (ncb *NdbClusterBackup) IsFinished() bool {
+	return ncb.Status.Phase == NdbClusterBackupPhaseCompleted ||
+		ncb.Status.Phase == NdbClusterBackupPhaseFailed
+}
+
+// GetTimeoutSeconds returns the backup timeout in seconds
+func (ncb *NdbClusterBackup) GetTimeoutSeconds() int32 {
+	if ncb.Spec.TimeoutSeconds == 0 {
+		return 3600
+	}
+	return ncb.Spec.TimeoutSeconds
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ndbbackupschedule,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`,description="Name of the NdbCluster being backed up"
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="Schedule of the backups"
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,description="Whether the schedule is suspended"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=`.status.lastScheduleTime`,description="Time the last backup was scheduled"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbClusterBackupSchedule resource"
+
+// NdbClusterBackupSchedule is the Schema for the NdbClusterBackupSchedule
+// CRD API. It periodically creates NdbClusterBackup resources to back up
+// the MySQL Cluster managed by an NdbCluster and prunes the old backups
+// according to a retention policy.
+type NdbClusterBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired backup schedule and retention policy.
+	Spec NdbClusterBackupScheduleSpec `json:"spec"`
+	// The status of the NdbClusterBackupSchedule resource.
+	Status NdbClusterBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// NdbClusterBackupScheduleSpec defines the backup schedule and the retention policy
+type NdbClusterBackupScheduleSpec struct {
+	// ClusterName is the name of the NdbCluster resource, in the same
+	// namespace as the NdbClusterBackupSchedule, whose MySQL Cluster
+	// has to be backed up.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// Schedule is the schedule of the backups in Cron format,
+	// interpreted in UTC. Predefined schedules like @daily and
+	// @hourly are also accepted.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Suspend, when set to true, stops the creation of new backups.
+	// The existing backups are still pruned as per the retention policy.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// BackupTimeoutSeconds is the timeoutSeconds of
+	// the NdbClusterBackup resources created by the schedule.
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BackupTimeoutSeconds int32 `json:"backupTimeoutSeconds,omitempty"`
+	// Retention is the policy that decides which of the completed
+	// backups created by the schedule are kept. The backups that
+	// are not retained are deleted along with their backup files.
+	// +optional
+	Retention NdbClusterBackupRetentionPolicy `json:"retention,omitempty"`
+}
+
+// NdbClusterBackupRetentionPolicy defines which of the completed backups
+// are retained. A completed backup is deleted if it is not one of the
+// KeepLast most recent backups or if it is older than KeepFor. The most
+// recent completed backup is always retained. Failed backups are deleted
+// once a newer backup has completed.
+type NdbClusterBackupRetentionPolicy struct {
+	// KeepLast is the number of most recent completed backups to be retained
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast *int32 `json:"keepLast,omitempty"`
+	// KeepFor is the duration, like "168h", for which a completed backup is retained
+	// +optional
+	KeepFor *metav1.Duration `json:"keepFor,omitempty"`
+}
+
+// NdbClusterBackupScheduleStatus is the status of the NdbClusterBackupSchedule resource
+type NdbClusterBackupScheduleStatus struct {
+	// LastScheduleTime is the time the last backup was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// NextScheduleTime is the time the next backup is scheduled
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// LastBackupName is the name of the last NdbClusterBackup
+	// resource created by the schedule
+	// +optional
+	LastBackupName string `json:"lastBackupName,omitempty"`
+	// LastCompletedBackupName is the name of the most recent
+	// NdbClusterBackup resource that completed successfully
+	// +optional
+	LastCompletedBackupName string `json:"lastCompletedBackupName,omitempty"`
+	// Message is a human-readable message indicating
+	// any problem with the schedule.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbClusterBackupScheduleList contains a list of NdbClusterBackupSchedule resources
+// +kubebuilder:object:root=true
+type NdbClusterBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbClusterBackupSchedule `json:"items"`
+}
+
+// GetBackupTimeoutSeconds returns the timeout of the scheduled backups in seconds
+func (ncbs *NdbClusterBackupSchedule) GetBackupTimeoutSeconds() int32 {
+	if ncbs.Spec.BackupTimeoutSeconds == 0 {
+		return 3600
+	}
+	return ncbs.Spec.BackupTimeoutSeconds
+}
+
+// GetOwnerReferences returns a slice of OwnerReferences to be set
+// to the NdbClusterBackup resources created by the schedule
+func (ncbs *NdbClusterBackupSchedule) GetOwnerReferences() []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		*metav1.NewControllerRef(ncbs,
+			schema.GroupVersionKind{
+				Group:   SchemeGroupVersion.Group,
+				Version: SchemeGroupVersion.Version,
+				Kind:    "NdbClusterBackupSchedule",
+			})}
+}
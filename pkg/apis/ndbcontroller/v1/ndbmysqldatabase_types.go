@@ -0,0 +1,86 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ndbdb,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`,description="Name of the NdbCluster in which the database is created"
+// +kubebuilder:printcolumn:name="Database",type=string,JSONPath=`.spec.databaseName`,description="Name of the database"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbMySQLDatabase resource"
+
+// NdbMySQLDatabase is the Schema for the NdbMySQLDatabase CRD API. It
+// declares a database to be created in the MySQL Cluster managed by an
+// NdbCluster. The database is not dropped when the resource is deleted.
+type NdbMySQLDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of the database.
+	Spec NdbMySQLDatabaseSpec `json:"spec"`
+	// The status of the NdbMySQLDatabase resource.
+	Status NdbMySQLDatabaseStatus `json:"status,omitempty"`
+}
+
+// NdbMySQLDatabaseSpec defines the database to be created
+type NdbMySQLDatabaseSpec struct {
+	// ClusterName is the name of the NdbCluster resource, in the same
+	// namespace as the NdbMySQLDatabase, in which the database has to be created.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// DatabaseName is the name of the database.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	DatabaseName string `json:"databaseName"`
+	// CharacterSet is the default character set of the database.
+	// If unspecified, the default of the MySQL Server is used.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]*$`
+	// +optional
+	CharacterSet string `json:"characterSet,omitempty"`
+	// Collation is the default collation of the database.
+	// If unspecified, the default of the character set is used.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]*$`
+	// +optional
+	Collation string `json:"collation,omitempty"`
+}
+
+// NdbMySQLDatabaseStatus is the status of the NdbMySQLDatabase resource
+type NdbMySQLDatabaseStatus struct {
+	// ProcessedGeneration is the generation of the
+	// NdbMySQLDatabase spec that has been applied to the database
+	// +optional
+	ProcessedGeneration int64 `json:"processedGeneration,omitempty"`
+	// LastAppliedTime is the time the spec was last applied to the database
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// LastDriftTime is the last time the database was found to have been
+	// dropped or altered outside the operator. The database is created
+	// or altered again as per the spec when such a drift is detected.
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// Message is a human-readable message indicating
+	// any problem with creating or updating the database.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbMySQLDatabaseList contains a list of NdbMySQLDatabase resources
+// +kubebuilder:object:root=true
+type NdbMySQLDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbMySQLDatabase `json:"items"`
+}
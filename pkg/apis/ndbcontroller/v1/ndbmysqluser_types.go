@@ -0,0 +1,205 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/mysql/ndb-operator/pkg/constants"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ndbuser,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`,description="Name of the NdbCluster in which the user is created"
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.userName`,description="Name of the MySQL user"
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.spec.host`,description="Host from which the MySQL user can connect"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbMySQLUser resource"
+
+// NdbMySQLUser is the Schema for the NdbMySQLUser CRD API. It declares
+// a MySQL user account, along with its privileges and resource limits,
+// to be created in the MySQL Servers of an NdbCluster. The user is
+// dropped from the MySQL Servers when the resource is deleted.
+type NdbMySQLUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of the MySQL user.
+	Spec NdbMySQLUserSpec `json:"spec"`
+	// The status of the NdbMySQLUser resource.
+	Status NdbMySQLUserStatus `json:"status,omitempty"`
+}
+
+// NdbMySQLUserSpec defines the MySQL user account to be created
+type NdbMySQLUserSpec struct {
+	// ClusterName is the name of the NdbCluster resource, in the same
+	// namespace as the NdbMySQLUser, in which the user has to be created.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// UserName is the name of the MySQL user. The users reserved for
+	// the MySQL Server and the NDB Operator, like root, are not allowed.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=32
+	UserName string `json:"userName"`
+	// Host is the host or hosts from which the user can connect to the
+	// MySQL Servers. If unspecified, the user can connect from any host.
+	// +kubebuilder:default="%"
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	Host string `json:"host,omitempty"`
+	// PasswordSecretName is the name of the Secret, in the same namespace
+	// as the NdbMySQLUser, that holds the password of the user in its
+	// 'password' key. The password of the user is updated when the
+	// password in the Secret changes.
+	// +kubebuilder:validation:MinLength=1
+	PasswordSecretName string `json:"passwordSecretName"`
+	// Grants are the privileges to be granted to the user. Any other
+	// privilege granted to the user outside the operator is revoked.
+	// +optional
+	Grants []NdbMySQLUserGrant `json:"grants,omitempty"`
+	// ResourceLimits are the limits on the use of the MySQL Server
+	// resources by the user.
+	// +optional
+	ResourceLimits *NdbMySQLUserResourceLimits `json:"resourceLimits,omitempty"`
+	// NdbStoredUser, when set to true, grants the NDB_STORED_USER
+	// privilege to the user so that the user and its privileges are
+	// stored in the MySQL Cluster and are shared by all the MySQL
+	// Servers. Otherwise, the user is created only in the first MySQL
+	// Server of the NdbCluster.
+	// +kubebuilder:default=true
+	// +optional
+	NdbStoredUser *bool `json:"ndbStoredUser,omitempty"`
+}
+
+// NdbMySQLUserGrant defines a set of privileges granted to a MySQL user
+type NdbMySQLUserGrant struct {
+	// Privileges is the list of privileges, like SELECT and INSERT, to be granted.
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/privileges-provided.html
+	// +kubebuilder:validation:MinItems=1
+	Privileges []string `json:"privileges"`
+	// Database is the name of the database on which the
+	// privileges are granted. "*" specifies all databases.
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// Table is the name of the table, in the database, on which the
+	// privileges are granted. If unspecified, the privileges are
+	// granted on all the tables of the database.
+	// +kubebuilder:default="*"
+	// +optional
+	Table string `json:"table,omitempty"`
+	// WithGrantOption, when set to true, allows the user to
+	// grant these privileges to other users.
+	// +optional
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
+}
+
+// NdbMySQLUserResourceLimits defines the limits on the use of the MySQL
+// Server resources by a MySQL user. A value of 0 specifies no limit.
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/user-resources.html
+type NdbMySQLUserResourceLimits struct {
+	// MaxQueriesPerHour is the number of queries the user can issue in an hour
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxQueriesPerHour int32 `json:"maxQueriesPerHour,omitempty"`
+	// MaxUpdatesPerHour is the number of updates the user can issue in an hour
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUpdatesPerHour int32 `json:"maxUpdatesPerHour,omitempty"`
+	// MaxConnectionsPerHour is the number of times the user can connect in an hour
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConnectionsPerHour int32 `json:"maxConnectionsPerHour,omitempty"`
+	// MaxUserConnections is the number of simultaneous connections allowed for the user
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUserConnections int32 `json:"maxUserConnections,omitempty"`
+}
+
+// NdbMySQLUserStatus is the status of the NdbMySQLUser resource
+type NdbMySQLUserStatus struct {
+	// ProcessedGeneration is the generation of the
+	// NdbMySQLUser spec that has been applied to the user
+	// +optional
+	ProcessedGeneration int64 `json:"processedGeneration,omitempty"`
+	// PasswordSecretVersion is the resource version of the
+	// password Secret that has been applied to the user
+	// +optional
+	PasswordSecretVersion string `json:"passwordSecretVersion,omitempty"`
+	// AppliedGrants are the grants of the user, as reported by
+	// SHOW GRANTS, after the spec was last applied to the user
+	// +optional
+	AppliedGrants []string `json:"appliedGrants,omitempty"`
+	// LastAppliedTime is the time the spec was last applied to the user
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// LastDriftTime is the last time the grants of the user were found
+	// to have been modified outside the operator. The grants declared
+	// in the spec are applied again when such a drift is detected.
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+	// Message is a human-readable message indicating
+	// any problem with creating or updating the user.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbMySQLUserList contains a list of NdbMySQLUser resources
+// +kubebuilder:object:root=true
+type NdbMySQLUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbMySQLUser `json:"items"`
+}
+
+// GetHost returns the host from which the MySQL user can connect
+func (nmu *NdbMySQLUser) GetHost() string {
+	if nmu.Spec.Host == "" {
+		return "%"
+	}
+	return nmu.Spec.Host
+}
+
+// IsNdbStoredUser returns true if the user has to be
+// stored in the MySQL Cluster and shared by all MySQL Servers
+func (nmu *NdbMySQLUser) IsNdbStoredUser() bool {
+	return nmu.Spec.NdbStoredUser == nil || *nmu.Spec.NdbStoredUser
+}
+
+// HasReservedUserName returns true if the user name is one of the
+// MySQL users created by the MySQL Server or by the NDB Operator
+func (nmu *NdbMySQLUser) HasReservedUserName() bool {
+	for _, userName := range constants.ReservedMySQLUserNames {
+		// MySQL user names are case-sensitive but 'ROOT' is just as
+		// confusing as 'root', so reject the names case-insensitively.
+		if strings.EqualFold(nmu.Spec.UserName, userName) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasValidSpec validates the NdbMySQLUser spec and returns true if it is valid.
+// Otherwise, it returns false and an ErrorList with all the validation errors.
+func (nmu *NdbMySQLUser) HasValidSpec() (bool, field.ErrorList) {
+	var errList field.ErrorList
+	if nmu.HasReservedUserName() {
+		errList = append(errList, field.Invalid(field.NewPath("spec").Child("userName"),
+			nmu.Spec.UserName, "user name is reserved for the MySQL Server and the NDB Operator"))
+	}
+
+	return errList == nil, errList
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ndbrepl,categories=all
+//
+// Additional printer columns
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterName`,description="Name of the replica NdbCluster"
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.status.sourceHost`,description="Binlog MySQL Server from which the changes are replicated"
+// +kubebuilder:printcolumn:name="Healthy",type=boolean,JSONPath=`.status.healthy`,description="If the replication channel is running without errors"
+// +kubebuilder:printcolumn:name="Lag",type=integer,JSONPath=`.status.lagSeconds`,description="Seconds the replica is behind the source"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbReplicationChannel resource"
+
+// NdbReplicationChannel is the Schema for the NdbReplicationChannel CRD API.
+// It declares an asynchronous replication channel from a binlog MySQL Server
+// of a source MySQL Cluster to an NdbCluster in the same namespace. The
+// channel is run by the first binlog MySQL Server of the replica NdbCluster
+// and is started from the position, in the binary log of the source, that
+// follows the last epoch applied to the replica, as recorded in the
+// mysql.ndb_apply_status table. When the source has more than one binlog
+// MySQL Server, the channel is switched, from the same epoch, to another
+// one if the current one fails. The channel is stopped and removed from
+// the replica MySQL Server when the resource is deleted.
+type NdbReplicationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of the replication channel.
+	Spec NdbReplicationChannelSpec `json:"spec"`
+	// The status of the NdbReplicationChannel resource.
+	Status NdbReplicationChannelStatus `json:"status,omitempty"`
+}
+
+// NdbReplicationChannelSpec defines the replication channel
+type NdbReplicationChannelSpec struct {
+	// ClusterName is the name of the replica NdbCluster, in the same
+	// namespace as the NdbReplicationChannel, into which the changes are
+	// replicated. The NdbCluster is required to have spec.replication set.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+	// Source specifies the binlog MySQL Server of the source MySQL Cluster.
+	Source NdbReplicationSource `json:"source"`
+}
+
+// NdbReplicationSource specifies how the replica connects
+// to the binlog MySQL Server of the source MySQL Cluster
+type NdbReplicationSource struct {
+	// Host is the hostname or the IP address, reachable from the replica
+	// NdbCluster, of the binlog MySQL Server of the source MySQL Cluster.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// FailoverHosts are the hostnames or the IP addresses of the other binlog
+	// MySQL Servers of the source MySQL Cluster, e.g. the standby of a pair
+	// of dedicated binlog servers. When the binlog MySQL Server currently
+	// being replicated from fails, the channel is switched to the next
+	// reachable one, from the position, in its binary log, that follows
+	// the last epoch applied to the replica.
+	// +optional
+	FailoverHosts []string `json:"failoverHosts,omitempty"`
+	// Port is the port of the binlog MySQL Server.
+	// +kubebuilder:default=3306
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// UserSecretName is the name of a Secret of type kubernetes.io/basic-auth,
+	// in the same namespace as the NdbReplicationChannel, that has the
+	// password of the replication user of the source NdbCluster. This is
+	// usually a copy of the Secret specified, or generated by the operator,
+	// via the spec.replication.userSecretName of the source NdbCluster.
+	// +kubebuilder:validation:MinLength=1
+	UserSecretName string `json:"userSecretName"`
+}
+
+// NdbReplicationChannelStatus is the status of the NdbReplicationChannel resource
+type NdbReplicationChannelStatus struct {
+	// ProcessedGeneration is the generation of the NdbReplicationChannel
+	// spec that has been applied to the replica MySQL Server
+	// +optional
+	ProcessedGeneration int64 `json:"processedGeneration,omitempty"`
+	// UserSecretVersion is the resource version of the Secret
+	// whose password was used to configure the replication channel
+	// +optional
+	UserSecretVersion string `json:"userSecretVersion,omitempty"`
+	// ReplicaMySQLServer is the name of the pod of the
+	// replica MySQL Server that runs the replication channel
+	// +optional
+	ReplicaMySQLServer string `json:"replicaMySQLServer,omitempty"`
+	// SourceHost is the host of the binlog MySQL Server from which the
+	// changes are currently being replicated
+	// +optional
+	SourceHost string `json:"sourceHost,omitempty"`
+	// LastFailoverTime is the time the channel was last switched
+	// to another binlog MySQL Server of the source
+	// +optional
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+	// SourceLogFile is the binary log file of the source
+	// from which the channel was last started
+	// +optional
+	SourceLogFile string `json:"sourceLogFile,omitempty"`
+	// SourceLogPosition is the position in the SourceLogFile
+	// from which the channel was last started
+	// +optional
+	SourceLogPosition int64 `json:"sourceLogPosition,omitempty"`
+	// ReceiverState is the state of the replication receiver (I/O) thread
+	// as reported by SHOW REPLICA STATUS - Yes, No or Connecting
+	// +optional
+	ReceiverState string `json:"receiverState,omitempty"`
+	// ApplierState is the state of the replication applier (SQL)
+	// thread as reported by SHOW REPLICA STATUS - Yes or No
+	// +optional
+	ApplierState string `json:"applierState,omitempty"`
+	// Healthy is true if both the receiver and the applier
+	// threads are running without any errors
+	// +optional
+	Healthy bool `json:"healthy,omitempty"`
+	// LagSeconds is the number of seconds the replica is behind the
+	// source. It is not set when the channel is not running.
+	// +optional
+	LagSeconds *int64 `json:"lagSeconds,omitempty"`
+	// LastAppliedEpoch is the last epoch of the source
+	// MySQL Cluster that has been applied to the replica
+	// +optional
+	LastAppliedEpoch int64 `json:"lastAppliedEpoch,omitempty"`
+	// LastError is the last error reported by the receiver or the applier thread
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastCheckTime is the time the health of the channel was last checked
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Message is a human-readable message indicating
+	// any problem with setting up the replication channel.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetSourcePort returns the port of the binlog MySQL Server of the source
+func (nrc *NdbReplicationChannel) GetSourcePort() int32 {
+	if nrc.Spec.Source.Port == 0 {
+		return 3306
+	}
+	return nrc.Spec.Source.Port
+}
+
+// GetSourceHosts returns the hosts of all the binlog MySQL Servers
+// of the source, starting with the one specified in spec.source.host
+func (nrc *NdbReplicationChannel) GetSourceHosts() []string {
+	return append([]string{nrc.Spec.Source.Host}, nrc.Spec.Source.FailoverHosts...)
+}
+
+// GetSourceHost returns the host of the binlog MySQL Server of the source
+// that the channel replicates from. It is the host recorded in the status,
+// if it is still one of the source hosts, or spec.source.host otherwise.
+func (nrc *NdbReplicationChannel) GetSourceHost() string {
+	for _, host := range nrc.GetSourceHosts() {
+		if host == nrc.Status.SourceHost {
+			return host
+		}
+	}
+	return nrc.Spec.Source.Host
+}
+
+// GetSourceAddress returns the address of the given binlog MySQL Server of the source
+func (nrc *NdbReplicationChannel) GetSourceAddress(host string) string {
+	return fmt.Sprintf("%s:%d", host, nrc.GetSourcePort())
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NdbReplicationChannelList contains a list of NdbReplicationChannel resources
+// +kubebuilder:object:root=true
+type NdbReplicationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NdbReplicationChannel `json:"items"`
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -37,6 +37,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&NdbCluster{},
 		&NdbClusterList{},
+		&NdbClusterBackup{},
+		&NdbClusterBackupList{},
+		&NdbClusterBackupSchedule{},
+		&NdbClusterBackupScheduleList{},
+		&NdbMySQLUser{},
+		&NdbMySQLUserList{},
+		&NdbMySQLDatabase{},
+		&NdbMySQLDatabaseList{},
+		&NdbReplicationChannel{},
+		&NdbReplicationChannelList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
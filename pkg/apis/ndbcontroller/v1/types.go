@@ -6,12 +6,16 @@ package v1
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,6 +35,8 @@ import (
 // +kubebuilder:printcolumn:name="MySQL Servers",type=string,JSONPath=`.status.readyMySQLServers`,description="Number of ready MySQL Servers"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the NdbCluster resource"
 // +kubebuilder:printcolumn:name="Up-To-Date",type="string",JSONPath=".status.conditions[?(@.type=='UpToDate')].status",description="Indicates if the MySQL Cluster configuration is up-to-date with the spec specified in the NdbCluster resource"
+// +kubebuilder:printcolumn:name="Paused",type="string",JSONPath=".status.conditions[?(@.type=='Paused')].status",description="Indicates if the reconciliation of the NdbCluster resource has been paused"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type=='Available')].status",description="Indicates if the MySQL Cluster is available to serve queries"
 
 // NdbCluster is the Schema for the Ndb CRD API
 type NdbCluster struct {
@@ -73,6 +79,20 @@ type NdbClusterPodSpec struct {
 
 // NdbManagementNodeSpec is the specification of management node in MySQL Cluster
 type NdbManagementNodeSpec struct {
+	// NodeCount is the number of management nodes to be run in the
+	// MySQL Cluster. If unspecified, one management node is run for a
+	// redundancyLevel of 1 and two management nodes are run otherwise.
+	// The count can only be increased once the NdbCluster has been
+	// created, and the new management nodes are added to the MySQL
+	// Cluster via a rolling restart of all the MySQL Cluster nodes.
+	// Note that the MySQL Cluster will not be able to accept new
+	// connections from the MySQL Cluster nodes while the management
+	// node of a MySQL Cluster with a single management node is being
+	// restarted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4
+	// +optional
+	NodeCount int32 `json:"nodeCount,omitempty"`
 	// Config is a map of default MySQL Cluster Management node configurations.
 	//
 	// More info :
@@ -110,7 +130,10 @@ type NdbDataNodeSpec struct {
 	// The total number of data nodes in MySQL Cluster.
 	// The node count needs to be a multiple of the
 	// redundancyLevel. A maximum of 144 data nodes are
-	// allowed to run in a single MySQL Cluster.
+	// allowed to run in a single MySQL Cluster. The node count
+	// can only be increased, as NDB cannot drop nodegroups that
+	// have data in them. To reduce it, back up the MySQL Cluster
+	// and restore it into a new NdbCluster via spec.restoreFrom.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=144
 	NodeCount int32 `json:"nodeCount"`
@@ -120,6 +143,130 @@ type NdbDataNodeSpec struct {
 	// the data node pod and the container.
 	// +optional
 	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// DiskData specifies the undo log file groups and the tablespaces
+	// to be created in the MySQL Cluster for storing disk data tables.
+	// +optional
+	DiskData *NdbDiskDataSpec `json:"diskData,omitempty"`
+	// NodeGroups specifies the config and the pod placement of the data
+	// nodes of individual node groups. The data nodes are grouped into
+	// node groups of spec.redundancyLevel nodes in the order of their
+	// ordinal, i.e. the node group N has the data node pods with ordinals
+	// from N*redundancyLevel to (N+1)*redundancyLevel - 1.
+	// +optional
+	// +listType=map
+	// +listMapKey=nodeGroup
+	NodeGroups []NdbDataNodeGroupSpec `json:"nodeGroups,omitempty"`
+}
+
+// NdbDataNodeGroupSpec is the specification of the data nodes of a node group
+type NdbDataNodeGroupSpec struct {
+	// NodeGroup is the id of the node group
+	// +kubebuilder:validation:Minimum=0
+	NodeGroup int32 `json:"nodeGroup"`
+	// Config is a map of MySQL Cluster Data node configurations that
+	// will be set in the [ndbd] sections of the data nodes of this node
+	// group. They override any value set via spec.dataNode.config.
+	//
+	// More info :
+	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-params-ndbd.html
+	// +optional
+	Config map[string]*intstr.IntOrString `json:"config,omitempty"`
+	// NdbPodSpec specifies the placement of the data node pods of this
+	// node group. The NodeSelector is merged with and the Tolerations are
+	// appended to the ones specified in spec.dataNode.ndbPodSpec, and any
+	// Affinity specified here replaces the respective Affinity specified
+	// there. These are applied to the pods by the NDB Operator webhook
+	// server when the data node pods are created.
+	// +optional
+	NdbPodSpec *NdbDataNodeGroupPodSpec `json:"ndbPodSpec,omitempty"`
+}
+
+// NdbDataNodeGroupPodSpec contains the subset of the PodSpec
+// fields that can be specified for the data nodes of a node group
+type NdbDataNodeGroupPodSpec struct {
+	// NodeSelector is a selector which must be true for the pod to fit on a node.
+	// Selector which must match a node's labels for the pod to be scheduled on that node.
+	//
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#nodeselector
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// If specified, the pod's scheduling constraints
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// If specified, the pod's tolerations.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// NdbDiskDataFile is an undo log file or a data file used by disk data tables
+type NdbDiskDataFile struct {
+	// Name of the file. It should be a relative path and the file will be
+	// created in the disk data PVC, if one is specified, or else in the data
+	// node's data directory.
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_./-]+$"
+	Name string `json:"name"`
+	// InitialSize is the size of the file
+	InitialSize resource.Quantity `json:"initialSize"`
+}
+
+// NdbLogfileGroupSpec is the specification of an undo log file group
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/create-logfile-group.html
+type NdbLogfileGroupSpec struct {
+	// Name of the logfile group
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_]+$"
+	Name string `json:"name"`
+	// UndoBufferSize is the size of the buffer used for writing to the
+	// undo log files. If unspecified, the MySQL Cluster default is used.
+	// +optional
+	UndoBufferSize *resource.Quantity `json:"undoBufferSize,omitempty"`
+	// UndoFiles are the undo log files of the logfile group
+	// +kubebuilder:validation:MinItems=1
+	UndoFiles []NdbDiskDataFile `json:"undoFiles"`
+}
+
+// NdbTablespaceSpec is the specification of a tablespace
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/create-tablespace.html
+type NdbTablespaceSpec struct {
+	// Name of the tablespace
+	// +kubebuilder:validation:Pattern="^[a-zA-Z0-9_]+$"
+	Name string `json:"name"`
+	// LogfileGroup is the name of the logfile group used by the tablespace
+	LogfileGroup string `json:"logfileGroup"`
+	// ExtentSize is the size of the extents used by the data files of
+	// the tablespace. If unspecified, the MySQL Cluster default is used.
+	// +optional
+	ExtentSize *resource.Quantity `json:"extentSize,omitempty"`
+	// DataFiles are the data files of the tablespace
+	// +kubebuilder:validation:MinItems=1
+	DataFiles []NdbDiskDataFile `json:"dataFiles"`
+}
+
+// NdbDiskDataSpec specifies the disk data objects to be created in the
+// MySQL Cluster. The operator creates the declared logfile groups and
+// tablespaces and adds any new files declared to them. The existing
+// disk data objects and files cannot be modified or removed via the spec.
+//
+// More info :
+// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-disk-data-objects.html
+type NdbDiskDataSpec struct {
+	// PVCSpec is the PersistentVolumeClaimSpec of a dedicated PVC
+	// that will be created for each data node to store the disk data
+	// files. If unspecified, the disk data files are stored in the
+	// data node's data directory. This value is immutable.
+	// +optional
+	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// LogfileGroups is the list of undo log file groups.
+	// MySQL Cluster supports only one logfile group at a time.
+	// +kubebuilder:validation:MaxItems=1
+	// +optional
+	LogfileGroups []NdbLogfileGroupSpec `json:"logfileGroups,omitempty"`
+	// Tablespaces is the list of tablespaces
+	// +optional
+	Tablespaces []NdbTablespaceSpec `json:"tablespaces,omitempty"`
 }
 
 // NdbMysqldSpec is the specification of MySQL Servers to be run as an SQL Frontend
@@ -185,6 +332,104 @@ type NdbMysqldSpec struct {
 	// the mysql server pod and the container.
 	// +optional
 	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
+	// TLS enables the operator managed server certificates for the client
+	// connections to all the MySQL Servers, including the ones declared in
+	// spec.mysqlNodeGroups. The certificates are signed by a CA generated
+	// by the operator and stored in the Secret "<ndb-resource-name>-mysqld-ca".
+	// The certificates are renewed before they expire and the MySQL Servers
+	// are restarted in a rolling fashion to use them.
+	// +optional
+	TLS *NdbMysqldTLSSpec `json:"tls,omitempty"`
+	// PasswordRotation configures the rotation of the passwords of the MySQL
+	// root user, created by the operator with the host specified in rootHost,
+	// and the NDB Operator user. The password of the root user is always
+	// updated when the password in its Secret changes.
+	// +optional
+	PasswordRotation *NdbPasswordRotationSpec `json:"passwordRotation,omitempty"`
+}
+
+// NdbMysqldTLSSpec specifies the TLS configuration of the MySQL Servers
+type NdbMysqldTLSSpec struct {
+	// RequireSecureTransport, when set to true, allows the MySQL root
+	// user, created by the operator with the host specified in rootHost,
+	// and the NDB Operator user to connect to the MySQL Servers only
+	// via encrypted connections.
+	// +optional
+	RequireSecureTransport bool `json:"requireSecureTransport,omitempty"`
+}
+
+// NdbPasswordRotationSpec specifies how the passwords of the MySQL root
+// user and the NDB Operator user are rotated. A rotated user has both the
+// old and the new passwords for a while, so that the clients have time
+// to switch to the new password, after which the old password is discarded.
+type NdbPasswordRotationSpec struct {
+	// Schedule is the schedule, in Cron format interpreted in UTC, at
+	// which the operator generates new passwords for the NDB Operator
+	// user and, if the root password Secret was generated by the operator,
+	// the root user. Predefined schedules like @weekly are also accepted.
+	// If unspecified, the passwords are not rotated by the operator.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// RetainOldPasswordFor is the duration for which the old password
+	// of a rotated user remains valid along with the new password.
+	// +kubebuilder:default="1h"
+	// +optional
+	RetainOldPasswordFor *metav1.Duration `json:"retainOldPasswordFor,omitempty"`
+}
+
+// NdbMysqldGroupSpec is the specification of a named group of MySQL
+// Servers that are run in addition to the MySQL Servers specified via
+// spec.mysqlNode. Every group is run by a separate StatefulSet, named
+// "<ndb-resource-name>-mysqld-<group-name>", and is exposed via its own
+// Service of the same name.
+type NdbMysqldGroupSpec struct {
+	// Name of the MySQL Server group.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// NodeCount is the number of MySQL Servers in the group.
+	// The group can be scaled down to 0 MySQL Servers.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	NodeCount int32 `json:"nodeCount"`
+	// MaxNodeCount is the count up to which the MySQL Servers in
+	// the group would be allowed to scale up without forcing a
+	// MySQL Cluster config update. If unspecified, operator will
+	// define the MySQL Cluster config with API sections for two
+	// additional MySQL Servers.
+	// +optional
+	MaxNodeCount int32 `json:"maxNodeCount,omitempty"`
+	// ConnectionPoolSize is the number of connections a single
+	// MySQL Server of the group should use to connect to the
+	// MySQL Cluster nodes.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=63
+	// +optional
+	ConnectionPoolSize int32 `json:"connectionPoolSize,omitempty"`
+	// Configuration options to pass to the MySQL Servers of the group when they are started.
+	// +optional
+	MyCnf string `json:"myCnf,omitempty"`
+	// EnableLoadBalancer exposes the MySQL Servers of the
+	// group externally using the kubernetes cloud provider's
+	// load balancer instead of a ClusterIP type service.
+	// +kubebuilder:default=false
+	// +optional
+	EnableLoadBalancer bool `json:"enableLoadBalancer,omitempty"`
+	// NdbPodSpec contains a subset of K8s PodSpec fields which when set
+	// will be copied into to the podSpec of the group's StatefulSet.
+	// +optional
+	NdbPodSpec *NdbClusterPodSpec `json:"ndbPodSpec,omitempty"`
+	// InitScripts is a map of configMap names from the same namespace and
+	// optionally an array of keys which store the SQL scripts to be executed
+	// during the initialization of the MySQL Servers of the group.
+	// +optional
+	InitScripts map[string][]string `json:"initScripts,omitempty"`
+	// PVCSpec is the PersistentVolumeClaimSpec to be used as the
+	// VolumeClaimTemplate of the group's StatefulSet.
+	// +optional
+	PVCSpec *corev1.PersistentVolumeClaimSpec `json:"pvcSpec,omitempty"`
 }
 
 // NdbClusterSpec defines the desired state of a MySQL NDB Cluster
@@ -195,12 +440,22 @@ type NdbClusterSpec struct {
 	// Note that, setting this to 1 means that there is only a
 	// single copy of all MySQL Cluster data and failure of any
 	// Data node will cause the entire MySQL Cluster to fail.
-	// The operator also implicitly decides the number of
+	// Unless specified via spec.managementNode.nodeCount, the
+	// operator also implicitly decides the number of
 	// Management nodes to be added to the MySQL Cluster
 	// configuration based on this value. For a redundancy level
 	// of 1, one Management node will be created. For 2 or
 	// higher, two Management nodes will be created.
-	// This value is immutable.
+	// This value can only be increased once the NdbCluster has
+	// been created. The operator migrates the MySQL Cluster to
+	// the new redundancy level by taking a backup, performing an
+	// initial system restart of the data nodes with the new value
+	// and then restoring the backup. The data nodes are required
+	// to use a PVC, via spec.dataNode.pvcSpec, and to have their
+	// BackupDataDir inside it, to retain the backup across the
+	// restart. The migration can be aborted by reverting
+	// this value while the backup is being taken. The progress of
+	// the migration is reported in status.redundancyLevelMigration.
 	//
 	// More info :
 	// https://dev.mysql.com/doc/refman/8.0/en/mysql-cluster-ndbd-definition.html#ndbparam-ndbd-noofreplicas
@@ -220,6 +475,19 @@ type NdbClusterSpec struct {
 	// default add one MySQL Server to the spec.
 	// +optional
 	MysqlNode *NdbMysqldSpec `json:"mysqlNode,omitempty"`
+	// MysqlNodeGroups specifies additional named groups of MySQL Servers,
+	// each with its own my.cnf, pod spec, service and node count. The
+	// [mysqld] sections of every group are declared in the MySQL Cluster
+	// config after the ones of spec.mysqlNode, in the order of this list.
+	// Increasing the maxNodeCount or connectionPoolSize of a group, or
+	// inserting a new group before an existing one, will change the
+	// nodeIds of the groups that follow it and restart their MySQL Servers.
+	// The Root user is managed by the operator only through the MySQL
+	// Servers specified via spec.mysqlNode.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	MysqlNodeGroups []NdbMysqldGroupSpec `json:"mysqlNodeGroups,omitempty"`
 	// The number of extra API sections declared in the MySQL Cluster
 	// config, in addition to the API sections declared implicitly
 	// by the NDB Operator for the MySQL Servers.
@@ -233,6 +501,11 @@ type NdbClusterSpec struct {
 	// If a value is provided, the ndb operator will enable TDE and utilize the password
 	// stored in the Secret as the file system password for all data nodes within the
 	// MySQL Cluster. If no value is provided, TDE will not be enabled for MySQL Cluster.
+	// When the password in the Secret is changed, the data nodes are restarted, one
+	// per node group at a time, with the --initial flag to re-encrypt their file
+	// systems with the new password. The progress is tracked in status.tdePasswordRotation.
+	// Neither the password nor this field can be changed when the redundancyLevel is 1,
+	// as the data nodes cannot then be re-encrypted without losing their data.
 	// +optional
 	TDESecretName string `json:"tdeSecretName,omitempty"`
 	// The name of the MySQL Ndb Cluster image to be used.
@@ -250,6 +523,227 @@ type NdbClusterSpec struct {
 	// holds the credentials required for pulling the MySQL Cluster image.
 	// +optional
 	ImagePullSecretName string `json:"imagePullSecretName,omitempty"`
+	// RestoreFrom specifies a native backup of another MySQL Cluster to be
+	// restored into this MySQL Cluster when it is started for the first time.
+	// The MySQL Servers are started only after the restore completes.
+	// This value is immutable.
+	// +optional
+	RestoreFrom *NdbClusterRestoreSource `json:"restoreFrom,omitempty"`
+	// UpdateStrategy is the strategy used by the operator to restart the
+	// data nodes when applying a spec update. The default RollingRestart
+	// strategy restarts one data node per nodegroup at a time, keeping
+	// the MySQL Cluster available during the update. The FullRestart
+	// strategy stops all the data nodes and then starts them together
+	// via a system restart, making the MySQL Cluster unavailable until
+	// the data nodes are up again. A MySQL Cluster with redundancyLevel
+	// 1 can be updated only via the FullRestart strategy.
+	// +kubebuilder:default=RollingRestart
+	// +optional
+	UpdateStrategy NdbClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+	// TLS enables TLS for the connections between the MySQL Cluster
+	// nodes and for the connections to the Management Servers. When
+	// specified, all the MySQL Cluster nodes are required to have a
+	// certificate signed by the cluster CA and the Management and Data
+	// Nodes accept only TLS connections. This value is immutable.
+	// +optional
+	TLS *NdbClusterTLSSpec `json:"tls,omitempty"`
+	// Replication enables the NdbCluster to take part in the asynchronous
+	// replication between NdbClusters. The replication channels from
+	// other NdbClusters are declared via NdbReplicationChannel resources.
+	// +optional
+	Replication *NdbClusterReplicationSpec `json:"replication,omitempty"`
+	// Suspended, if true, gracefully shuts down the MySQL Cluster. The
+	// MySQL Servers are stopped first, then the data nodes are stopped
+	// via the Management Server and finally the Management Servers are
+	// stopped, by scaling down their StatefulSets to zero. The PVCs are
+	// retained. Setting it back to false resumes the MySQL Cluster via a
+	// system restart. No other spec change is allowed while the NdbCluster
+	// is suspended, or along with a change to this value. The backup
+	// schedules and the replication channels of the NdbCluster are not
+	// processed while it is suspended.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+}
+
+// NdbClusterReplicationSpec specifies how the MySQL Servers of an
+// NdbCluster take part in the asynchronous replication between
+// NdbClusters. Every MySQL Server is started with a unique server-id
+// and the binlog MySQL Servers are started with the binary log enabled
+// to record all the changes made to the MySQL Cluster. The binlog MySQL
+// Servers also apply the changes replicated from the other NdbClusters.
+type NdbClusterReplicationSpec struct {
+	// ServerIdOffset is added to the node id of every MySQL Server to
+	// generate its server-id. The server-ids have to be unique across
+	// all the NdbClusters taking part in the replication, and hence
+	// every NdbCluster has to use a different offset, at least 256 apart.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483392
+	ServerIdOffset int32 `json:"serverIdOffset"`
+	// BinlogMySQLServerGroup is the name of the MySQL Server group,
+	// declared in spec.mysqlNodeGroups, whose MySQL Servers write the
+	// binary log. If unspecified, the MySQL Servers declared in
+	// spec.mysqlNode are used as the binlog MySQL Servers.
+	// +optional
+	BinlogMySQLServerGroup string `json:"binlogMySQLServerGroup,omitempty"`
+	// DedicatedBinlogServers, if true, makes the MySQL Servers of the
+	// binlogMySQLServerGroup act only as binlog servers. They are excluded
+	// from the Service of the MySQL Servers declared in spec.mysqlNode,
+	// which serves the application traffic. The group has to have exactly
+	// two MySQL Servers - the replicas read the binary log of one of them
+	// and switch to the other when it fails.
+	// +optional
+	DedicatedBinlogServers bool `json:"dedicatedBinlogServers,omitempty"`
+	// UserSecretName is the name of a Secret of type kubernetes.io/basic-auth,
+	// in the same namespace as the NdbCluster, that has the password of the
+	// replication user created by the operator. The replica NdbClusters use
+	// this user to connect to the binlog MySQL Servers. If unspecified, the
+	// operator generates a random password and stores it in a Secret named
+	// "<ndbcluster-name>-replication-user-password".
+	// +optional
+	UserSecretName string `json:"userSecretName,omitempty"`
+	// ConflictRole is the role of the NdbCluster in an active-active setup
+	// that uses the NDB$EPOCH family of conflict detection functions. One
+	// of the NdbClusters has to be the PRIMARY and the other the SECONDARY.
+	// +kubebuilder:validation:Enum=PRIMARY;SECONDARY;PASS;NONE
+	// +optional
+	ConflictRole NdbConflictRole `json:"conflictRole,omitempty"`
+	// ConflictDetection specifies the conflict detection functions used by
+	// the binlog MySQL Servers when applying the changes replicated from the
+	// other NdbClusters. The operator stores them in the mysql.ndb_replication
+	// table. The functions take effect for the tables created after they are
+	// stored, or when the binlog MySQL Servers are restarted.
+	// +optional
+	ConflictDetection []NdbConflictDetectionSpec `json:"conflictDetection,omitempty"`
+}
+
+// NdbConflictRole is the role of an NdbCluster in an
+// active-active setup using the NDB$EPOCH functions
+type NdbConflictRole string
+
+const (
+	// NdbConflictRolePrimary wins all the conflicts
+	NdbConflictRolePrimary NdbConflictRole = "PRIMARY"
+	// NdbConflictRoleSecondary loses all the conflicts
+	NdbConflictRoleSecondary NdbConflictRole = "SECONDARY"
+	// NdbConflictRolePass disables the conflict detection
+	// while the role is moved to the other NdbCluster
+	NdbConflictRolePass NdbConflictRole = "PASS"
+	// NdbConflictRoleNone is the default role
+	NdbConflictRoleNone NdbConflictRole = "NONE"
+)
+
+// NdbConflictFunction is a conflict detection function of NDB replication
+// +kubebuilder:validation:Enum=NDB$EPOCH;NDB$EPOCH_TRANS;NDB$EPOCH2;NDB$EPOCH2_TRANS;NDB$MAX;NDB$MAX_DELETE_WIN;NDB$MAX_INS;NDB$MAX_DEL_WIN_INS;NDB$OLD
+type NdbConflictFunction string
+
+// Conflict detection functions supported by NDB replication
+const (
+	NdbConflictFunctionEpoch        NdbConflictFunction = "NDB$EPOCH"
+	NdbConflictFunctionEpochTrans   NdbConflictFunction = "NDB$EPOCH_TRANS"
+	NdbConflictFunctionEpoch2       NdbConflictFunction = "NDB$EPOCH2"
+	NdbConflictFunctionEpoch2Trans  NdbConflictFunction = "NDB$EPOCH2_TRANS"
+	NdbConflictFunctionMax          NdbConflictFunction = "NDB$MAX"
+	NdbConflictFunctionMaxDeleteWin NdbConflictFunction = "NDB$MAX_DELETE_WIN"
+	NdbConflictFunctionMaxIns       NdbConflictFunction = "NDB$MAX_INS"
+	NdbConflictFunctionMaxDelWinIns NdbConflictFunction = "NDB$MAX_DEL_WIN_INS"
+	NdbConflictFunctionOld          NdbConflictFunction = "NDB$OLD"
+)
+
+// IsEpochFunction returns true if the conflict function is one of the
+// NDB$EPOCH functions, which resolve the conflicts based on the conflict
+// role of the NdbClusters rather than on a column of the table.
+func (cf NdbConflictFunction) IsEpochFunction() bool {
+	return strings.HasPrefix(string(cf), string(NdbConflictFunctionEpoch))
+}
+
+// IsTransactional returns true if the conflict function rejects the
+// whole transaction, rather than only the conflicting row.
+func (cf NdbConflictFunction) IsTransactional() bool {
+	return strings.HasSuffix(string(cf), "_TRANS")
+}
+
+// NdbConflictDetectionSpec specifies the conflict detection
+// function used for a table, or a set of tables.
+type NdbConflictDetectionSpec struct {
+	// Database is the name of the database of the table. It can
+	// have the wildcard '%' to match more than one database.
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// Table is the name of the table. It can have the
+	// wildcard '%' to match more than one table.
+	// +kubebuilder:validation:MinLength=1
+	Table string `json:"table"`
+	// Function is the conflict detection function
+	Function NdbConflictFunction `json:"function"`
+	// Column is the column compared by the NDB$MAX and NDB$OLD family of
+	// functions to detect a conflict. It is required for those functions
+	// and is not allowed for the NDB$EPOCH functions.
+	// +optional
+	Column string `json:"column,omitempty"`
+	// ExceptionsTable, if true, creates the exceptions table '<table>$EX'
+	// where the conflicting rows are recorded. It can only be enabled for
+	// tables named without wildcards. The exceptions table is created once
+	// the table exists and is not dropped by the operator.
+	// +optional
+	ExceptionsTable bool `json:"exceptionsTable,omitempty"`
+}
+
+// HasWildcards returns true if the database
+// or the table name has the wildcard '%'
+func (cd *NdbConflictDetectionSpec) HasWildcards() bool {
+	return strings.Contains(cd.Database, "%") || strings.Contains(cd.Table, "%")
+}
+
+// NdbClusterTLSSpec specifies the cluster CA used to enable TLS in the
+// MySQL Cluster. Every MySQL Cluster node creates its private key and
+// a certificate signed by the cluster CA, using ndb_sign_keys, when
+// its pod starts. The NDB Operator uses a client certificate signed
+// by the same CA to connect to the Management Servers.
+type NdbClusterTLSSpec struct {
+	// CASecretName is the name of a Secret of type kubernetes.io/tls, in
+	// the same namespace as the NdbCluster, that has the certificate
+	// (tls.crt) and the private key (tls.key) of the cluster CA.
+	// If unspecified, the operator generates a new CA and stores it
+	// in a Secret named "<ndbcluster-name>-ndb-ca".
+	// +optional
+	CASecretName string `json:"caSecretName,omitempty"`
+}
+
+// NdbClusterUpdateStrategy is the strategy used to
+// restart the data nodes when applying a spec update.
+// +kubebuilder:validation:Enum=RollingRestart;FullRestart
+type NdbClusterUpdateStrategy string
+
+const (
+	// NdbClusterUpdateStrategyRollingRestart restarts
+	// one data node per nodegroup at a time.
+	NdbClusterUpdateStrategyRollingRestart NdbClusterUpdateStrategy = "RollingRestart"
+	// NdbClusterUpdateStrategyFullRestart stops all the data
+	// nodes and then starts them together via a system restart.
+	NdbClusterUpdateStrategyFullRestart NdbClusterUpdateStrategy = "FullRestart"
+)
+
+// NdbClusterRestoreSource specifies the native backup to be restored
+// into a new MySQL Cluster. The backup is restored by running ndb_restore
+// for the backup files of every data node of the backed up MySQL Cluster.
+type NdbClusterRestoreSource struct {
+	// BackupId is the id of the backup to be restored
+	// +kubebuilder:validation:Minimum=1
+	BackupId int32 `json:"backupId"`
+	// DataNodeIds are the node ids of the data nodes of the backed up
+	// MySQL Cluster. These are available in the status of the
+	// NdbClusterBackup that took the backup.
+	// +kubebuilder:validation:MinItems=1
+	DataNodeIds []int32 `json:"dataNodeIds"`
+	// PersistentVolumeClaimName is the name of the PVC, in the same
+	// namespace as the NdbCluster, that has the BACKUP-<backupId>
+	// directory with the backup files written by all the data nodes.
+	// +kubebuilder:validation:MinLength=1
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	// Path is the directory, relative to the root of the PVC,
+	// that has the BACKUP-<backupId> directory.
+	// +optional
+	Path string `json:"path,omitempty"`
 }
 
 // NdbClusterConditionType defines type for NdbCluster condition.
@@ -259,6 +753,39 @@ const (
 	// NdbClusterUpToDate specifies if the spec of the MySQL Cluster
 	// is up-to-date with the NdbCluster resource spec
 	NdbClusterUpToDate NdbClusterConditionType = "UpToDate"
+	// NdbClusterPaused specifies if the reconciliation of
+	// the NdbCluster resource has been paused by the user
+	NdbClusterPaused NdbClusterConditionType = "Paused"
+	// NdbClusterAvailable specifies if the MySQL Cluster is available,
+	// i.e. every node group has a connected data node and the MySQL
+	// Servers, if any, are ready to serve queries
+	NdbClusterAvailable NdbClusterConditionType = "Available"
+	// NdbClusterDegraded specifies if some of the Management or Data
+	// Nodes are disconnected while every node group still has a
+	// connected data node
+	NdbClusterDegraded NdbClusterConditionType = "Degraded"
+	// NdbClusterProgressing specifies if a rolling restart or an
+	// online add of data nodes is in progress in the MySQL Cluster
+	NdbClusterProgressing NdbClusterConditionType = "Progressing"
+)
+
+// PausedAnnotation is the annotation key that pauses the reconciliation
+// of the NdbCluster resource when set to "true". The operator does not
+// make any change to the MySQL Cluster while it is paused, allowing the
+// user to manage it manually, but it continues to update the status.
+// The resources that depend on the NdbCluster, like the NdbMySQLUsers
+// and the NdbClusterBackups, are not processed either while it is paused.
+const PausedAnnotation = "ndb.mysql.oracle.com/paused"
+
+const (
+	// NdbClusterPausedReasonAnnotation is the reason used when the
+	// NdbClusterPaused condition is set to True as the PausedAnnotation
+	// has been set on the NdbCluster resource.
+	NdbClusterPausedReasonAnnotation string = "PausedAnnotationSet"
+	// NdbClusterPausedReasonReconciling is the reason used when the
+	// NdbClusterPaused condition is set to False as the NdbCluster
+	// resource is being reconciled by the operator.
+	NdbClusterPausedReasonReconciling string = "Reconciling"
 )
 
 const (
@@ -278,8 +805,284 @@ const (
 	// the NdbClusterUpToDate condition is set to False when sync
 	// encounters an error.
 	NdbClusterUptoDateReasonError string = "SyncError"
+	// NdbClusterUptoDateReasonRestore is the reason used when the
+	// NdbClusterUpToDate condition is set to False when the backup
+	// specified in spec.restoreFrom is being restored into a newly
+	// started MySQL Cluster.
+	NdbClusterUptoDateReasonRestore string = "RestoringBackup"
+	// NdbClusterUptoDateReasonFullRestart is the reason used when the
+	// NdbClusterUpToDate condition is set to False when all the data
+	// nodes have been stopped to apply a spec update via the FullRestart
+	// strategy, and the MySQL Cluster is intentionally unavailable.
+	NdbClusterUptoDateReasonFullRestart string = "FullRestartInProgress"
+	// NdbClusterUptoDateReasonRedundancyLevelMigration is the reason used
+	// when the NdbClusterUpToDate condition is set to False when the MySQL
+	// Cluster is being migrated to the redundancyLevel specified in the spec.
+	NdbClusterUptoDateReasonRedundancyLevelMigration string = "RedundancyLevelMigrationInProgress"
+	// NdbClusterUptoDateReasonTDEPasswordRotation is the reason used when
+	// the NdbClusterUpToDate condition is set to False when the data node
+	// file systems are being re-encrypted with a new TDE password.
+	NdbClusterUptoDateReasonTDEPasswordRotation string = "TDEPasswordRotationInProgress"
+	// NdbClusterUptoDateReasonSuspend is the reason used when the
+	// NdbClusterUpToDate condition is set to False when the MySQL
+	// Cluster nodes are being stopped as requested by spec.suspended.
+	NdbClusterUptoDateReasonSuspend string = "SuspendInProgress"
+	// NdbClusterUptoDateReasonResume is the reason used when the
+	// NdbClusterUpToDate condition is set to False when a suspended
+	// MySQL Cluster is being started again.
+	NdbClusterUptoDateReasonResume string = "ResumeInProgress"
+	// NdbClusterUptoDateReasonPaused is the reason used when the
+	// NdbClusterUpToDate condition is set to False as the spec update
+	// cannot be applied while the reconciliation is paused.
+	NdbClusterUptoDateReasonPaused string = "ReconciliationPaused"
+)
+
+const (
+	// NdbClusterAvailableReasonAvailable is the reason used when the
+	// NdbClusterAvailable condition is set to True as every node group
+	// has a connected data node and the MySQL Servers are ready.
+	NdbClusterAvailableReasonAvailable string = "ClusterAvailable"
+	// NdbClusterAvailableReasonUnavailable is the reason used when the
+	// NdbClusterAvailable condition is set to False as a node group has
+	// no connected data node or none of the MySQL Servers are ready.
+	NdbClusterAvailableReasonUnavailable string = "ClusterUnavailable"
+	// NdbClusterAvailableReasonSuspended is the reason used when the
+	// NdbClusterAvailable condition is set to False as all the MySQL
+	// Cluster nodes have been stopped as requested by spec.suspended.
+	NdbClusterAvailableReasonSuspended string = "ClusterSuspended"
+	// NdbClusterAvailableReasonUnknown is the reason used when the
+	// NdbClusterAvailable condition is set to Unknown as the status of
+	// the MySQL Cluster could not be retrieved from the Management Server.
+	NdbClusterAvailableReasonUnknown string = "ClusterStatusUnknown"
+)
+
+const (
+	// NdbClusterDegradedReasonNodesDisconnected is the reason used when
+	// the NdbClusterDegraded condition is set to True as some of the
+	// Management or Data Nodes are disconnected.
+	NdbClusterDegradedReasonNodesDisconnected string = "NodesDisconnected"
+	// NdbClusterDegradedReasonAllNodesConnected is the reason used when
+	// the NdbClusterDegraded condition is set to False as all the
+	// Management and Data Nodes are connected.
+	NdbClusterDegradedReasonAllNodesConnected string = "AllNodesConnected"
+	// NdbClusterDegradedReasonUnavailable is the reason used when the
+	// NdbClusterDegraded condition is set to False as a node group has
+	// no connected data node and the MySQL Cluster is unavailable.
+	NdbClusterDegradedReasonUnavailable string = "ClusterUnavailable"
+	// NdbClusterDegradedReasonSuspended is the reason used when the
+	// NdbClusterDegraded condition is set to False as all the MySQL
+	// Cluster nodes have been stopped as requested by spec.suspended.
+	NdbClusterDegradedReasonSuspended string = "ClusterSuspended"
+	// NdbClusterDegradedReasonUnknown is the reason used when the
+	// NdbClusterDegraded condition is set to Unknown as the status of
+	// the MySQL Cluster could not be retrieved from the Management Server.
+	NdbClusterDegradedReasonUnknown string = "ClusterStatusUnknown"
+)
+
+const (
+	// NdbClusterProgressingReasonRollingRestart is the reason used when
+	// the NdbClusterProgressing condition is set to True as the pods of
+	// the MySQL Cluster nodes are being restarted one after another.
+	NdbClusterProgressingReasonRollingRestart string = "RollingRestartInProgress"
+	// NdbClusterProgressingReasonAddNode is the reason used when the
+	// NdbClusterProgressing condition is set to True as new data nodes
+	// are being added to the MySQL Cluster.
+	NdbClusterProgressingReasonAddNode string = "AddNodeInProgress"
+	// NdbClusterProgressingReasonFullRestart is the reason used when the
+	// NdbClusterProgressing condition is set to True as all the data
+	// nodes are being restarted via the FullRestart strategy.
+	NdbClusterProgressingReasonFullRestart string = "FullRestartInProgress"
+	// NdbClusterProgressingReasonNoRollout is the reason used when the
+	// NdbClusterProgressing condition is set to False as no rollout is
+	// in progress in the MySQL Cluster.
+	NdbClusterProgressingReasonNoRollout string = "NoRolloutInProgress"
+)
+
+// NdbClusterRestorePhase is the phase of the restore
+// of the backup specified in spec.restoreFrom
+type NdbClusterRestorePhase string
+
+const (
+	// NdbClusterRestorePhaseMetadata is the phase in which
+	// the schema of the backed up tables are restored.
+	NdbClusterRestorePhaseMetadata NdbClusterRestorePhase = "RestoringMetadata"
+	// NdbClusterRestorePhaseData is the phase in which the backup
+	// files of the data nodes are restored one after the other.
+	NdbClusterRestorePhaseData NdbClusterRestorePhase = "RestoringData"
+	// NdbClusterRestorePhaseIndexes is the phase in which the
+	// indexes disabled during the data restore are rebuilt.
+	NdbClusterRestorePhaseIndexes NdbClusterRestorePhase = "RebuildingIndexes"
+	// NdbClusterRestorePhaseCompleted is the phase
+	// once the backup has been completely restored.
+	NdbClusterRestorePhaseCompleted NdbClusterRestorePhase = "Completed"
+	// NdbClusterRestorePhaseFailed is the phase when
+	// one of the restore steps failed.
+	NdbClusterRestorePhaseFailed NdbClusterRestorePhase = "Failed"
+)
+
+// NdbClusterRestoreNodePhase is the phase of the restore of
+// the backup files written by a data node of the backed up cluster
+type NdbClusterRestoreNodePhase string
+
+const (
+	// NdbClusterRestoreNodePhasePending is the phase of a
+	// data node's backup files that are yet to be restored.
+	NdbClusterRestoreNodePhasePending NdbClusterRestoreNodePhase = "Pending"
+	// NdbClusterRestoreNodePhaseRestoring is the phase of a
+	// data node's backup files that are being restored.
+	NdbClusterRestoreNodePhaseRestoring NdbClusterRestoreNodePhase = "Restoring"
+	// NdbClusterRestoreNodePhaseRestored is the phase of a
+	// data node's backup files that have been restored.
+	NdbClusterRestoreNodePhaseRestored NdbClusterRestoreNodePhase = "Restored"
+	// NdbClusterRestoreNodePhaseFailed is the phase of a
+	// data node's backup files that failed to be restored.
+	NdbClusterRestoreNodePhaseFailed NdbClusterRestoreNodePhase = "Failed"
+)
+
+// NdbClusterRestoreNodeStatus is the restore progress of the
+// backup files written by a data node of the backed up cluster
+type NdbClusterRestoreNodeStatus struct {
+	// NodeId is the id of the data node that wrote the backup files
+	NodeId int32 `json:"nodeId"`
+	// Phase is the phase of the restore of the data node's backup files
+	Phase NdbClusterRestoreNodePhase `json:"phase"`
+}
+
+// NdbClusterRestoreStatus is the progress of the
+// restore of the backup specified in spec.restoreFrom
+type NdbClusterRestoreStatus struct {
+	// BackupId is the id of the backup being restored
+	BackupId int32 `json:"backupId"`
+	// Phase is the current phase of the restore
+	Phase NdbClusterRestorePhase `json:"phase"`
+	// Nodes has the restore progress of the backup
+	// files of each data node of the backed up cluster
+	// +optional
+	Nodes []NdbClusterRestoreNodeStatus `json:"nodes,omitempty"`
+	// Message is a human-readable message
+	// indicating details about the restore.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NdbRedundancyLevelMigrationPhase is the phase of the migration
+// of the MySQL Cluster to the redundancyLevel specified in the spec
+type NdbRedundancyLevelMigrationPhase string
+
+const (
+	// NdbRedundancyLevelMigrationPhaseBackingUp is the phase in which
+	// a backup of the MySQL Cluster is being taken. The migration can
+	// be aborted in this phase by reverting spec.redundancyLevel.
+	NdbRedundancyLevelMigrationPhaseBackingUp NdbRedundancyLevelMigrationPhase = "BackingUp"
+	// NdbRedundancyLevelMigrationPhaseInitialSystemRestart is the phase in
+	// which all the data nodes are restarted with their file systems cleared
+	// and with the new redundancy level. The MySQL Cluster data is lost at
+	// this point and the migration can no longer be aborted.
+	NdbRedundancyLevelMigrationPhaseInitialSystemRestart NdbRedundancyLevelMigrationPhase = "InitialSystemRestart"
+	// NdbRedundancyLevelMigrationPhaseRestoring is the phase in which
+	// the backup is being restored into the restarted MySQL Cluster.
+	NdbRedundancyLevelMigrationPhaseRestoring NdbRedundancyLevelMigrationPhase = "Restoring"
+	// NdbRedundancyLevelMigrationPhaseCompleted is the phase once
+	// the MySQL Cluster has been migrated to the new redundancy level.
+	NdbRedundancyLevelMigrationPhaseCompleted NdbRedundancyLevelMigrationPhase = "Completed"
+	// NdbRedundancyLevelMigrationPhaseAborted is the phase when the migration was
+	// aborted by reverting spec.redundancyLevel before the initial system restart.
+	NdbRedundancyLevelMigrationPhaseAborted NdbRedundancyLevelMigrationPhase = "Aborted"
+	// NdbRedundancyLevelMigrationPhaseFailed is the phase when
+	// either the backup or the restore of the backup failed.
+	NdbRedundancyLevelMigrationPhaseFailed NdbRedundancyLevelMigrationPhase = "Failed"
+)
+
+// NdbRedundancyLevelMigrationStatus is the progress of the migration
+// of the MySQL Cluster to the redundancyLevel specified in the spec
+type NdbRedundancyLevelMigrationStatus struct {
+	// FromRedundancyLevel is the redundancy level of the MySQL Cluster before the migration
+	FromRedundancyLevel int32 `json:"fromRedundancyLevel"`
+	// ToRedundancyLevel is the redundancy level the MySQL Cluster is being migrated to
+	ToRedundancyLevel int32 `json:"toRedundancyLevel"`
+	// Phase is the current phase of the migration
+	Phase NdbRedundancyLevelMigrationPhase `json:"phase"`
+	// BackupName is the name of the NdbClusterBackup
+	// resource that backs up the MySQL Cluster data
+	// before the initial system restart.
+	BackupName string `json:"backupName"`
+	// BackupId is the id of the backup once it is complete
+	// +optional
+	BackupId int32 `json:"backupId,omitempty"`
+	// BackupLocations has the location of the backup files
+	// written by each of the MySQL Cluster data nodes.
+	// +optional
+	BackupLocations []NdbClusterBackupLocation `json:"backupLocations,omitempty"`
+	// Restore is the progress of the restore of the backup
+	// after the initial system restart.
+	// +optional
+	Restore *NdbClusterRestoreStatus `json:"restore,omitempty"`
+	// Message is a human-readable message
+	// indicating details about the migration.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NdbTDEPasswordRotationPhase is the phase of the re-encryption
+// of the data node file systems with a new TDE password
+type NdbTDEPasswordRotationPhase string
+
+const (
+	// NdbTDEPasswordRotationPhaseInProgress is the phase in which the
+	// data nodes are being restarted, one per node group at a time, with
+	// the --initial flag to rewrite their files with the new password.
+	NdbTDEPasswordRotationPhaseInProgress NdbTDEPasswordRotationPhase = "InProgress"
+	// NdbTDEPasswordRotationPhaseCompleted is the phase once the file
+	// systems of all the data nodes have been re-encrypted.
+	NdbTDEPasswordRotationPhaseCompleted NdbTDEPasswordRotationPhase = "Completed"
 )
 
+// NdbTDEPasswordRotationStatus is the progress of the re-encryption of the
+// data node file systems after the password in the TDE Secret has changed
+type NdbTDEPasswordRotationStatus struct {
+	// SecretResourceVersion is the resource version of the TDE Secret
+	// whose password the data node file systems are re-encrypted with
+	SecretResourceVersion string `json:"secretResourceVersion"`
+	// Phase is the current phase of the rotation
+	Phase NdbTDEPasswordRotationPhase `json:"phase"`
+	// ReEncryptedNodes are the node ids of the data nodes whose
+	// file systems have been re-encrypted with the new password
+	// +optional
+	ReEncryptedNodes []int32 `json:"reEncryptedNodes,omitempty"`
+	// PendingNodes are the node ids of the data nodes whose file
+	// systems are yet to be re-encrypted with the new password
+	// +optional
+	PendingNodes []int32 `json:"pendingNodes,omitempty"`
+	// Message is a human-readable message
+	// indicating details about the rotation.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NdbConflictDetectionStatus has the conflict counters reported by
+// the binlog MySQL Server via its Ndb_conflict_* status variables. The
+// counters are reset when the binlog MySQL Server is restarted.
+type NdbConflictDetectionStatus struct {
+	// MySQLServer is the name of the binlog MySQL Server pod
+	// from which the counters were collected.
+	MySQLServer string `json:"mysqlServer"`
+	// Conflicts is the number of rows found in conflict
+	// by all the conflict detection functions.
+	Conflicts int64 `json:"conflicts"`
+	// RejectedTransactions is the number of transactions rejected by
+	// the transactional conflict detection functions.
+	RejectedTransactions int64 `json:"rejectedTransactions"`
+	// RejectedRows is the number of rows rejected as they were part of
+	// the transactions rejected by the transactional functions.
+	RejectedRows int64 `json:"rejectedRows"`
+	// DeleteDeleteConflicts is the number of delete-delete
+	// conflicts detected by the NDB$EPOCH functions.
+	DeleteDeleteConflicts int64 `json:"deleteDeleteConflicts"`
+	// LastConflictEpoch is the most recent epoch in which a conflict was detected.
+	// +optional
+	LastConflictEpoch int64 `json:"lastConflictEpoch,omitempty"`
+}
+
 // NdbClusterCondition describes the state of a MySQL Cluster installation at a certain point.
 type NdbClusterCondition struct {
 	// Type of NdbCluster condition.
@@ -314,6 +1117,42 @@ type NdbClusterStatus struct {
 	// be set to nil if a secret has been already provided to the operator via
 	// spec.mysqlNode.rootPasswordSecretName.
 	GeneratedRootPasswordSecretName string `json:"generatedRootPasswordSecretName,omitempty"`
+	// Restore is the progress of the restore of
+	// the backup specified in spec.restoreFrom.
+	// +optional
+	Restore *NdbClusterRestoreStatus `json:"restore,omitempty"`
+	// Tablespaces has the disk space usage of the tablespaces declared
+	// in spec.dataNode.diskData, collected when the disk data objects
+	// were last reconciled with the spec.
+	// +optional
+	Tablespaces []NdbTablespaceStatus `json:"tablespaces,omitempty"`
+	// RedundancyLevelMigration is the progress of the migration of
+	// the MySQL Cluster to the redundancyLevel specified in the spec.
+	// +optional
+	RedundancyLevelMigration *NdbRedundancyLevelMigrationStatus `json:"redundancyLevelMigration,omitempty"`
+	// TDEPasswordRotation is the progress of the re-encryption of the
+	// data node file systems after the password in the Secret specified
+	// in spec.tdeSecretName has changed.
+	// +optional
+	TDEPasswordRotation *NdbTDEPasswordRotationStatus `json:"tdePasswordRotation,omitempty"`
+	// ConflictDetection has the conflict counters of the binlog MySQL
+	// Server, collected when spec.replication.conflictDetection is set.
+	// +optional
+	ConflictDetection *NdbConflictDetectionStatus `json:"conflictDetection,omitempty"`
+	// Suspended is true when all the MySQL Cluster nodes
+	// have been stopped as requested by spec.suspended.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+}
+
+// NdbTablespaceStatus is the disk space usage of a tablespace
+type NdbTablespaceStatus struct {
+	// Name of the tablespace
+	Name string `json:"name"`
+	// FreeExtents is the number of free extents in all the data files of the tablespace
+	FreeExtents int64 `json:"freeExtents"`
+	// TotalExtents is the number of extents in all the data files of the tablespace
+	TotalExtents int64 `json:"totalExtents"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -360,9 +1199,14 @@ func (nc *NdbCluster) GetPodDisruptionBudgetName(resource string) string {
 	return fmt.Sprintf("%s-pdb-%s", nc.ObjectMeta.Name, resource)
 }
 
-// GetManagementNodeCount returns the number of
+// GetManagementNodeCount returns the number of management servers
+// specified in the spec or, if unspecified, the default number of
 // management servers based on the redundancy levels
 func (nc *NdbCluster) GetManagementNodeCount() int32 {
+	if nc.Spec.ManagementNode != nil && nc.Spec.ManagementNode.NodeCount != 0 {
+		return nc.Spec.ManagementNode.NodeCount
+	}
+
 	if nc.Spec.RedundancyLevel == 1 {
 		return 1
 	}
@@ -430,11 +1274,25 @@ func (nc *NdbCluster) GetOwnerReferences() []metav1.OwnerReference {
 
 // GetMySQLCnf returns any specified additional MySQL Server cnf
 func (nc *NdbCluster) GetMySQLCnf() string {
-	if nc.Spec.MysqlNode == nil || nc.Spec.MysqlNode.MyCnf == "" {
+	if nc.Spec.MysqlNode == nil {
+		return ""
+	}
+
+	return getMySQLCnfWithSectionHeader(nc.Spec.MysqlNode.MyCnf)
+}
+
+// GetMySQLCnf returns any specified additional cnf of the MySQL Server group
+func (mgs *NdbMysqldGroupSpec) GetMySQLCnf() string {
+	return getMySQLCnfWithSectionHeader(mgs.MyCnf)
+}
+
+// getMySQLCnfWithSectionHeader returns the given cnf
+// with the optional mysqld section header prepended.
+func getMySQLCnfWithSectionHeader(myCnf string) string {
+	if myCnf == "" {
 		return ""
 	}
 
-	myCnf := nc.Spec.MysqlNode.MyCnf
 	_, err := configparser.ParseString(myCnf)
 	if err != nil && strings.Contains(err.Error(), "Non-empty line without section") {
 		// section header is missing as it is optional - prepend and return
@@ -449,6 +1307,75 @@ func (nc *NdbCluster) GetWorkloadName(nodeType constants.NdbNodeType) string {
 	return nc.ObjectMeta.Name + "-" + nodeType
 }
 
+// GetMySQLServerTLSSpec returns the TLS spec of the MySQL Servers
+// or nil if the operator managed certificates are not enabled
+func (nc *NdbCluster) GetMySQLServerTLSSpec() *NdbMysqldTLSSpec {
+	if nc.Spec.MysqlNode == nil {
+		return nil
+	}
+	return nc.Spec.MysqlNode.TLS
+}
+
+// GetOldPasswordRetentionDuration returns the duration for which the
+// old password of a rotated MySQL root or NDB Operator user remains valid
+func (nc *NdbCluster) GetOldPasswordRetentionDuration() time.Duration {
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.PasswordRotation != nil &&
+		nc.Spec.MysqlNode.PasswordRotation.RetainOldPasswordFor != nil {
+		return nc.Spec.MysqlNode.PasswordRotation.RetainOldPasswordFor.Duration
+	}
+	return time.Hour
+}
+
+// GetPasswordRotationSchedule returns the schedule at which the
+// passwords of the MySQL root and NDB Operator users are rotated
+func (nc *NdbCluster) GetPasswordRotationSchedule() string {
+	if nc.Spec.MysqlNode != nil && nc.Spec.MysqlNode.PasswordRotation != nil {
+		return nc.Spec.MysqlNode.PasswordRotation.Schedule
+	}
+	return ""
+}
+
+// GetMySQLServerGroupWorkloadName returns the name of the K8s workload,
+// and its governing Service, that manages the given MySQL Server group
+func (nc *NdbCluster) GetMySQLServerGroupWorkloadName(groupName string) string {
+	return nc.GetWorkloadName(constants.NdbNodeTypeMySQLD) + "-" + groupName
+}
+
+// GetBinlogMySQLServerWorkloadName returns the name of the K8s workload
+// that manages the binlog MySQL Servers or an empty string if the
+// NdbCluster does not take part in the replication.
+func (nc *NdbCluster) GetBinlogMySQLServerWorkloadName() string {
+	replicationSpec := nc.Spec.Replication
+	if replicationSpec == nil {
+		return ""
+	}
+	if replicationSpec.BinlogMySQLServerGroup != "" {
+		return nc.GetMySQLServerGroupWorkloadName(replicationSpec.BinlogMySQLServerGroup)
+	}
+	return nc.GetWorkloadName(constants.NdbNodeTypeMySQLD)
+}
+
+// HasDedicatedBinlogServers returns true if the binlog MySQL
+// Servers of the NdbCluster do not serve the application traffic
+func (nc *NdbCluster) HasDedicatedBinlogServers() bool {
+	replicationSpec := nc.Spec.Replication
+	return replicationSpec != nil &&
+		replicationSpec.DedicatedBinlogServers && replicationSpec.BinlogMySQLServerGroup != ""
+}
+
+// IsPaused returns true if the reconciliation of the
+// NdbCluster has been paused via the PausedAnnotation
+func (nc *NdbCluster) IsPaused() bool {
+	return nc.GetAnnotations()[PausedAnnotation] == "true"
+}
+
+// HasPausedCondition returns true if the status of the NdbCluster
+// reports that its reconciliation has been paused
+func (nc *NdbCluster) HasPausedCondition() bool {
+	pausedCond := nc.getCondition(NdbClusterPaused)
+	return pausedCond != nil && pausedCond.Status == corev1.ConditionTrue
+}
+
 // getCondition returns the NdbClusterCondition of condType from NdbCluster resource
 func (nc *NdbCluster) getCondition(condType NdbClusterConditionType) *NdbClusterCondition {
 	for _, condition := range nc.Status.Conditions {
@@ -465,3 +1392,118 @@ func (nc *NdbCluster) HasSyncError() bool {
 	upToDateCond := nc.getCondition(NdbClusterUpToDate)
 	return upToDateCond != nil && upToDateCond.Reason == NdbClusterUptoDateReasonError
 }
+
+// IsRestoreInProgress returns true if the backup specified in
+// spec.restoreFrom has neither been restored nor failed yet
+func (nc *NdbCluster) IsRestoreInProgress() bool {
+	if nc.Spec.RestoreFrom == nil {
+		return false
+	}
+
+	restoreStatus := nc.Status.Restore
+	return restoreStatus == nil ||
+		(restoreStatus.Phase != NdbClusterRestorePhaseCompleted &&
+			restoreStatus.Phase != NdbClusterRestorePhaseFailed)
+}
+
+// IsRedundancyLevelMigrationInProgress returns true if the MySQL Cluster
+// is being migrated to the redundancyLevel specified in the spec.
+func (nc *NdbCluster) IsRedundancyLevelMigrationInProgress() bool {
+	migration := nc.Status.RedundancyLevelMigration
+	if migration == nil {
+		return false
+	}
+
+	switch migration.Phase {
+	case NdbRedundancyLevelMigrationPhaseBackingUp,
+		NdbRedundancyLevelMigrationPhaseInitialSystemRestart,
+		NdbRedundancyLevelMigrationPhaseRestoring:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanAbortRedundancyLevelMigration returns true if the migration to a new
+// redundancyLevel has not reached the initial system restart yet, and can
+// still be aborted by reverting spec.redundancyLevel.
+func (nc *NdbCluster) CanAbortRedundancyLevelMigration() bool {
+	migration := nc.Status.RedundancyLevelMigration
+	if migration == nil {
+		return false
+	}
+
+	// A failed migration without a restore status failed during the backup
+	return migration.Phase == NdbRedundancyLevelMigrationPhaseBackingUp ||
+		(migration.Phase == NdbRedundancyLevelMigrationPhaseFailed && migration.Restore == nil)
+}
+
+// UsesFullRestartUpdateStrategy returns true if the spec updates have
+// to be applied by restarting all the data nodes together.
+func (nc *NdbCluster) UsesFullRestartUpdateStrategy() bool {
+	return nc.Spec.UpdateStrategy == NdbClusterUpdateStrategyFullRestart
+}
+
+// GetDataNodeBackupDataDir returns the directory, inside the data node
+// pods, under which the data nodes store the files of their backups.
+func (nc *NdbCluster) GetDataNodeBackupDataDir() string {
+	// The BackupDataDir defaults to the FileSystemPath,
+	// which in turn defaults to the DataDir.
+	backupDataDir := constants.DataDir + "/data"
+	for _, configKey := range []string{"FileSystemPath", "BackupDataDir"} {
+		for key, value := range nc.Spec.DataNode.Config {
+			if strings.EqualFold(key, configKey) && value != nil {
+				backupDataDir = value.String()
+			}
+		}
+	}
+	return backupDataDir
+}
+
+// ndbFileSystemDir matches the path of a data node file system
+// directory, which is cleared when the data node starts with --initial
+var ndbFileSystemDir = regexp.MustCompile(`/ndb_\d+_fs(/|$)`)
+
+// HasBackupsRetainedAcrossInitialRestart returns true if the backup files
+// written by the data nodes are stored in the data node PVCs, outside the
+// data node file system directories that are cleared by an initial restart.
+func (nc *NdbCluster) HasBackupsRetainedAcrossInitialRestart() bool {
+	if nc.Spec.DataNode.PVCSpec == nil {
+		return false
+	}
+
+	// The data node PVC is mounted at the DataDir
+	dataDir := constants.DataDir + "/data"
+	backupDataDir := path.Clean(nc.GetDataNodeBackupDataDir())
+	return (backupDataDir == dataDir || strings.HasPrefix(backupDataDir, dataDir+"/")) &&
+		!ndbFileSystemDir.MatchString(backupDataDir)
+}
+
+// HasDiskDataPVC returns true if a dedicated PVC
+// has been specified for the disk data files
+func (nc *NdbCluster) HasDiskDataPVC() bool {
+	diskData := nc.Spec.DataNode.DiskData
+	return diskData != nil && diskData.PVCSpec != nil
+}
+
+// GetDataNodeGroupSpec returns the spec specified in
+// spec.dataNode.nodeGroups for the given node group, if any.
+func (nc *NdbCluster) GetDataNodeGroupSpec(nodeGroup int32) *NdbDataNodeGroupSpec {
+	for i := range nc.Spec.DataNode.NodeGroups {
+		if nc.Spec.DataNode.NodeGroups[i].NodeGroup == nodeGroup {
+			return &nc.Spec.DataNode.NodeGroups[i]
+		}
+	}
+	return nil
+}
+
+// GetMySQLServerGroupSpec returns the spec of the MySQL Server
+// group with the given name from spec.mysqlNodeGroups, if any.
+func (nc *NdbCluster) GetMySQLServerGroupSpec(name string) *NdbMysqldGroupSpec {
+	for i := range nc.Spec.MysqlNodeGroups {
+		if nc.Spec.MysqlNodeGroups[i].Name == name {
+			return &nc.Spec.MysqlNodeGroups[i]
+		}
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,13 +8,16 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path"
 	"reflect"
 	"strings"
 
 	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/cron"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -64,6 +67,10 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 
 	dataNodeCount := spec.DataNode.NodeCount
 	mysqlServerCount := nc.GetMySQLServerMaxNodeCount()
+	for _, mysqldGroup := range spec.MysqlNodeGroups {
+		// Every MySQL Server of a group uses connectionPoolSize [mysqld] sections
+		mysqlServerCount += mysqldGroup.MaxNodeCount * mysqldGroup.ConnectionPoolSize
+	}
 	managementNodeCount := nc.GetManagementNodeCount()
 	numOfFreeApiSlots := spec.FreeAPISlots + 1
 
@@ -85,6 +92,19 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 		errList = append(errList, field.Invalid(field.NewPath("Total Nodes"), invalidValue, msg))
 	}
 
+	// The management nodes beyond the first two are assigned the highest nodeIds,
+	// so check if they do not overlap with the nodeIds of the API nodes.
+	if managementNodeCount > 2 {
+		numOfApiNodeIds := constants.MaxNodeId - constants.NdbOperatorDedicatedAPINodeId + 1
+		if mysqlServerCount+numOfFreeApiSlots+managementNodeCount-2 > int32(numOfApiNodeIds) {
+			msg := fmt.Sprintf(
+				"Total number of MySQL Servers, free API nodes and management nodes beyond the first two "+
+					"should not exceed %d", numOfApiNodeIds)
+			errList = append(errList,
+				field.Invalid(managementNodePath.Child("nodeCount"), managementNodeCount, msg))
+		}
+	}
+
 	// check if there are any disallowed config params in dataNode's Configuration.
 	if err := validateConfigParams(nc.Spec.DataNode.Config, dataNodePath.Child("config")); err != nil {
 		errList = append(errList, err...)
@@ -121,30 +141,479 @@ func (nc *NdbCluster) HasValidSpec() (bool, field.ErrorList) {
 			errList = append(errList,
 				field.Invalid(mysqldPath.Child("maxNodeCount"), mysqldSpec.MaxNodeCount, msg))
 		}
+
+		// check if the password rotation schedule is valid
+		if schedule := nc.GetPasswordRotationSchedule(); schedule != "" {
+			if _, err := cron.Parse(schedule); err != nil {
+				errList = append(errList, field.Invalid(
+					mysqldPath.Child("passwordRotation", "schedule"), schedule, err.Error()))
+			}
+		}
 	}
 
 	// check if any passed my.cnf has proper format
-	myCnfString := nc.GetMySQLCnf()
-	if len(myCnfString) > 0 {
-		myCnf, err := configparser.ParseString(myCnfString)
-		if err != nil {
-			// error parsing the cnf
+	if err := validateMyCnf(nc.GetMySQLCnf(), mysqldPath.Child("myCnf")); err != nil {
+		errList = append(errList, err)
+	}
+
+	// check if the MySQL Server groups are specified properly
+	if len(spec.MysqlNodeGroups) != 0 {
+		errList = append(errList, validateMySQLServerGroupsSpec(nc, specPath.Child("mysqlNodeGroups"))...)
+	}
+
+	// check if the backup to be restored is specified properly
+	if spec.RestoreFrom != nil {
+		restoreFromPath := specPath.Child("restoreFrom")
+
+		// ndb_restore connects to the MySQL Cluster via a free API slot
+		if spec.FreeAPISlots < 1 {
 			errList = append(errList,
-				field.Invalid(mysqldPath.Child("myCnf"), myCnfString, err.Error()))
-		} else {
-			// accept only one mysqld section in the cnf
-			if len(myCnf) != 1 ||
-				myCnf.GetNumberOfSections("mysqld") != 1 {
-				errList = append(errList,
-					field.Invalid(mysqldPath.Child("myCnf"),
-						myCnfString, "spec.mysqlNode.myCnf can have only one mysqld section"))
+				field.Invalid(specPath.Child("freeAPISlots"), spec.FreeAPISlots,
+					"spec.freeAPISlots should be atleast 1 to restore the backup specified in spec.restoreFrom"))
+		}
+
+		// the backup path should stay within the PVC
+		if backupPath := spec.RestoreFrom.Path; backupPath != "" &&
+			(path.IsAbs(backupPath) || strings.HasPrefix(path.Clean(backupPath), "..")) {
+			errList = append(errList,
+				field.Invalid(restoreFromPath.Child("path"), backupPath,
+					"spec.restoreFrom.path should be a relative path within the PVC"))
+		}
+	}
+
+	// check if the disk data objects are specified properly
+	if spec.DataNode.DiskData != nil {
+		errList = append(errList,
+			validateDiskDataSpec(nc, spec.DataNode.DiskData, dataNodePath.Child("diskData"))...)
+	}
+
+	// check if the node group specific configs are specified properly
+	if len(spec.DataNode.NodeGroups) != 0 {
+		errList = append(errList, validateNodeGroupsSpec(nc, dataNodePath.Child("nodeGroups"))...)
+	}
+
+	// check if the binlog MySQL Server group exists
+	if spec.Replication != nil {
+		replicationPath := specPath.Child("replication")
+		if groupName := spec.Replication.BinlogMySQLServerGroup; groupName != "" &&
+			nc.GetMySQLServerGroupSpec(groupName) == nil {
+			errList = append(errList, field.Invalid(replicationPath.Child("binlogMySQLServerGroup"), groupName,
+				"spec.replication.binlogMySQLServerGroup should be the name of a group declared in spec.mysqlNodeGroups"))
+		}
+
+		// dedicated binlog servers are run as a pair by a MySQL Server group
+		if spec.Replication.DedicatedBinlogServers {
+			groupName := spec.Replication.BinlogMySQLServerGroup
+			if groupName == "" {
+				errList = append(errList, field.Required(replicationPath.Child("binlogMySQLServerGroup"),
+					"spec.replication.binlogMySQLServerGroup is required when dedicatedBinlogServers is enabled"))
+			} else if mysqldGroup := nc.GetMySQLServerGroupSpec(groupName); mysqldGroup != nil && mysqldGroup.NodeCount != 2 {
+				errList = append(errList, field.Invalid(replicationPath.Child("dedicatedBinlogServers"), true,
+					fmt.Sprintf("MySQL Server group %q should have exactly 2 MySQL Servers to be used as dedicated binlog servers",
+						groupName)))
+			}
+		}
+
+		if secretName := spec.Replication.UserSecretName; secretName != "" {
+			for _, err := range validation.IsDNS1123Subdomain(secretName) {
+				errList = append(errList, field.Invalid(replicationPath.Child("userSecretName"), secretName, err))
 			}
 		}
+
+		errList = append(errList, validateConflictDetectionSpec(spec.Replication, replicationPath)...)
+	}
+
+	// The MySQL Cluster has to be started before it can be suspended.
+	// An update to spec.suspended is validated by IsValidSpecUpdate.
+	if spec.Suspended {
+		errList = append(errList, field.Forbidden(specPath.Child("suspended"),
+			"spec.suspended can be set only after the NdbCluster has been created"))
 	}
 
 	return errList == nil, errList
 }
 
+// validateMyCnf validates the format of the given my.cnf
+func validateMyCnf(myCnfString string, myCnfPath *field.Path) *field.Error {
+	if len(myCnfString) == 0 {
+		return nil
+	}
+
+	myCnf, err := configparser.ParseString(myCnfString)
+	if err != nil {
+		// error parsing the cnf
+		return field.Invalid(myCnfPath, myCnfString, err.Error())
+	}
+
+	// accept only one mysqld section in the cnf
+	if len(myCnf) != 1 ||
+		myCnf.GetNumberOfSections("mysqld") != 1 {
+		return field.Invalid(myCnfPath,
+			myCnfString, myCnfPath.String()+" can have only one mysqld section")
+	}
+
+	return nil
+}
+
+// validateMySQLServerGroupsSpec validates the MySQL
+// Server groups specified in spec.mysqlNodeGroups
+func validateMySQLServerGroupsSpec(nc *NdbCluster, mysqldGroupsPath *field.Path) (errList field.ErrorList) {
+	groupNames := make(map[string]bool)
+	for i, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		groupPath := mysqldGroupsPath.Index(i)
+		if groupNames[mysqldGroup.Name] {
+			errList = append(errList, field.Duplicate(groupPath.Child("name"), mysqldGroup.Name))
+		}
+		groupNames[mysqldGroup.Name] = true
+
+		// check if maxNodeCount is less than nodeCount
+		if mysqldGroup.MaxNodeCount < mysqldGroup.NodeCount {
+			msg := fmt.Sprintf(
+				"%s cannot be less than %s(=%d)", groupPath.Child("maxNodeCount"),
+				groupPath.Child("nodeCount"), mysqldGroup.NodeCount)
+			errList = append(errList,
+				field.Invalid(groupPath.Child("maxNodeCount"), mysqldGroup.MaxNodeCount, msg))
+		}
+
+		if err := validateMyCnf(mysqldGroup.GetMySQLCnf(), groupPath.Child("myCnf")); err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return errList
+}
+
+// validateNodeGroupsSpec validates the node group specific
+// configs and pod specs specified in spec.dataNode.nodeGroups
+func validateNodeGroupsSpec(nc *NdbCluster, nodeGroupsPath *field.Path) (errList field.ErrorList) {
+	numOfNodeGroups := nc.Spec.DataNode.NodeCount / nc.Spec.RedundancyLevel
+	nodeGroups := make(map[int32]bool)
+	for i, ngSpec := range nc.Spec.DataNode.NodeGroups {
+		ngPath := nodeGroupsPath.Index(i)
+		if ngSpec.NodeGroup >= numOfNodeGroups {
+			errList = append(errList, field.Invalid(ngPath.Child("nodeGroup"), ngSpec.NodeGroup,
+				fmt.Sprintf("nodeGroup should be less than the number of node groups(=%d) "+
+					"in the MySQL Cluster", numOfNodeGroups)))
+		}
+
+		if nodeGroups[ngSpec.NodeGroup] {
+			errList = append(errList, field.Duplicate(ngPath.Child("nodeGroup"), ngSpec.NodeGroup))
+		}
+		nodeGroups[ngSpec.NodeGroup] = true
+
+		errList = append(errList, validateConfigParams(ngSpec.Config, ngPath.Child("config"))...)
+		for configKey := range ngSpec.Config {
+			// The operator expects the backups of all the data nodes in the same directory
+			if strings.EqualFold(configKey, "FileSystemPath") || strings.EqualFold(configKey, "BackupDataDir") {
+				errList = append(errList, field.Forbidden(ngPath.Child("config").Child(configKey),
+					fmt.Sprintf("config param %q can only be specified via spec.dataNode.config", configKey)))
+			}
+		}
+	}
+	return errList
+}
+
+// validateDiskDataFiles validates the given undo log or data files. The
+// fileNames map tracks the names of all the files validated so far.
+func validateDiskDataFiles(
+	files []NdbDiskDataFile, filesPath *field.Path, fileNames map[string]bool) (errList field.ErrorList) {
+	for i, file := range files {
+		filePath := filesPath.Index(i)
+
+		// the files should stay within the data directory or the PVC
+		if path.IsAbs(file.Name) || strings.HasPrefix(path.Clean(file.Name), "..") {
+			errList = append(errList, field.Invalid(filePath.Child("name"), file.Name,
+				"disk data file name should be a relative path within the data directory"))
+		}
+
+		// all the files are created in the same directory
+		if fileNames[path.Clean(file.Name)] {
+			errList = append(errList, field.Duplicate(filePath.Child("name"), file.Name))
+		}
+		fileNames[path.Clean(file.Name)] = true
+
+		if file.InitialSize.Sign() <= 0 {
+			errList = append(errList, field.Invalid(filePath.Child("initialSize"),
+				file.InitialSize.String(), "initialSize should be greater than 0"))
+		}
+	}
+	return errList
+}
+
+// validateDiskDataSpec validates the logfile groups and the tablespaces in the given disk data spec
+func validateDiskDataSpec(
+	nc *NdbCluster, diskData *NdbDiskDataSpec, diskDataPath *field.Path) (errList field.ErrorList) {
+
+	// The disk data objects are created via a MySQL Server
+	if (len(diskData.LogfileGroups) != 0 || len(diskData.Tablespaces) != 0) && nc.GetMySQLServerNodeCount() == 0 {
+		errList = append(errList, field.Invalid(field.NewPath("spec", "mysqlNode", "nodeCount"),
+			nc.GetMySQLServerNodeCount(),
+			"spec.mysqlNode.nodeCount should be atleast 1 to create the disk data objects declared in spec.dataNode.diskData"))
+	}
+
+	// FileSystemPathDD is set by the operator when a dedicated PVC is specified
+	if diskData.PVCSpec != nil {
+		dataNodePath := field.NewPath("spec", "dataNode")
+		validateConfig := func(config map[string]*intstr.IntOrString, configPath *field.Path) {
+			for configKey := range config {
+				if strings.EqualFold(configKey, "FileSystemPathDD") {
+					errList = append(errList, field.Forbidden(configPath.Child(configKey),
+						fmt.Sprintf("config param %q is not allowed when spec.dataNode.diskData.pvcSpec is specified", configKey)))
+				}
+			}
+		}
+
+		validateConfig(nc.Spec.DataNode.Config, dataNodePath.Child("config"))
+		for i, ngSpec := range nc.Spec.DataNode.NodeGroups {
+			validateConfig(ngSpec.Config, dataNodePath.Child("nodeGroups").Index(i).Child("config"))
+		}
+	}
+
+	fileNames := make(map[string]bool)
+	logfileGroups := make(map[string]bool)
+	for i, lfg := range diskData.LogfileGroups {
+		lfgPath := diskDataPath.Child("logfileGroups").Index(i)
+		if logfileGroups[lfg.Name] {
+			errList = append(errList, field.Duplicate(lfgPath.Child("name"), lfg.Name))
+		}
+		logfileGroups[lfg.Name] = true
+		errList = append(errList, validateDiskDataFiles(lfg.UndoFiles, lfgPath.Child("undoFiles"), fileNames)...)
+	}
+
+	tablespaces := make(map[string]bool)
+	for i, ts := range diskData.Tablespaces {
+		tsPath := diskDataPath.Child("tablespaces").Index(i)
+		if tablespaces[ts.Name] {
+			errList = append(errList, field.Duplicate(tsPath.Child("name"), ts.Name))
+		}
+		tablespaces[ts.Name] = true
+
+		if !logfileGroups[ts.LogfileGroup] {
+			errList = append(errList, field.NotFound(tsPath.Child("logfileGroup"), ts.LogfileGroup))
+		}
+		errList = append(errList, validateDiskDataFiles(ts.DataFiles, tsPath.Child("dataFiles"), fileNames)...)
+	}
+
+	return errList
+}
+
+// validateConflictDetectionSpec validates the conflict
+// detection functions declared in the replication spec
+func validateConflictDetectionSpec(
+	replication *NdbClusterReplicationSpec, replicationPath *field.Path) (errList field.ErrorList) {
+	tables := make(map[string]bool)
+	for i, cd := range replication.ConflictDetection {
+		cdPath := replicationPath.Child("conflictDetection").Index(i)
+
+		// the names are stored in mysql.ndb_replication as VARBINARY(63)
+		if len(cd.Database) > 63 {
+			errList = append(errList, field.TooLong(cdPath.Child("database"), cd.Database, 63))
+		}
+		if len(cd.Table) > 63 {
+			errList = append(errList, field.TooLong(cdPath.Child("table"), cd.Table, 63))
+		}
+
+		// only one function can be used for a table
+		tableName := cd.Database + "." + cd.Table
+		if tables[tableName] {
+			errList = append(errList, field.Duplicate(cdPath.Child("table"), tableName))
+		}
+		tables[tableName] = true
+
+		if cd.Function.IsEpochFunction() {
+			if cd.Column != "" {
+				errList = append(errList, field.Forbidden(cdPath.Child("column"),
+					fmt.Sprintf("column is not allowed for the conflict function %s", cd.Function)))
+			}
+
+			if replication.ConflictRole == "" || replication.ConflictRole == NdbConflictRoleNone {
+				errList = append(errList, field.Required(replicationPath.Child("conflictRole"),
+					fmt.Sprintf("conflictRole is required for the conflict function %s", cd.Function)))
+			}
+		} else if cd.Column == "" {
+			errList = append(errList, field.Required(cdPath.Child("column"),
+				fmt.Sprintf("column is required for the conflict function %s", cd.Function)))
+		} else if strings.ContainsAny(cd.Column, "(),` ") {
+			errList = append(errList, field.Invalid(cdPath.Child("column"), cd.Column,
+				"column should be the name of a column of the table"))
+		}
+
+		if cd.ExceptionsTable && cd.HasWildcards() {
+			errList = append(errList, field.Forbidden(cdPath.Child("exceptionsTable"),
+				"exceptionsTable is not allowed when the database or the table name has wildcards"))
+		}
+	}
+
+	return errList
+}
+
+// validateDiskDataFilesUpdate verifies that none of the
+// old disk data files have been removed or updated
+func validateDiskDataFilesUpdate(
+	oldFiles, newFiles []NdbDiskDataFile, filesPath *field.Path) (errList field.ErrorList) {
+	for _, oldFile := range oldFiles {
+		found := false
+		for _, newFile := range newFiles {
+			if oldFile.Name == newFile.Name {
+				found = true
+				if oldFile.InitialSize.Cmp(newFile.InitialSize) != 0 {
+					errList = append(errList, field.Forbidden(filesPath,
+						fmt.Sprintf("initialSize of the file %q cannot be updated", oldFile.Name)))
+				}
+				break
+			}
+		}
+		if !found {
+			errList = append(errList, field.Forbidden(filesPath,
+				fmt.Sprintf("file %q cannot be removed", oldFile.Name)))
+		}
+	}
+	return errList
+}
+
+// quantitiesEqual returns true if the given optional quantities are equal
+func quantitiesEqual(q1, q2 *resource.Quantity) bool {
+	if q1 == nil || q2 == nil {
+		return q1 == q2
+	}
+	return q1.Cmp(*q2) == 0
+}
+
+// validateDiskDataSpecUpdate verifies that the existing disk data objects
+// and their files have been neither removed nor updated. New disk data
+// objects and files can be added.
+func validateDiskDataSpecUpdate(
+	oldDiskData, newDiskData *NdbDiskDataSpec, diskDataPath *field.Path) (errList field.ErrorList) {
+	if oldDiskData == nil {
+		oldDiskData = &NdbDiskDataSpec{}
+	}
+	if newDiskData == nil {
+		newDiskData = &NdbDiskDataSpec{}
+	}
+
+	// Do not allow updating the dedicated disk data PVC
+	if !reflect.DeepEqual(oldDiskData.PVCSpec, newDiskData.PVCSpec) {
+		errList = append(errList,
+			cannotUpdateFieldError(diskDataPath.Child("pvcSpec"), newDiskData.PVCSpec))
+	}
+
+	lfgsPath := diskDataPath.Child("logfileGroups")
+	for _, oldLfg := range oldDiskData.LogfileGroups {
+		var newLfg *NdbLogfileGroupSpec
+		for i := range newDiskData.LogfileGroups {
+			if newDiskData.LogfileGroups[i].Name == oldLfg.Name {
+				newLfg = &newDiskData.LogfileGroups[i]
+				break
+			}
+		}
+
+		if newLfg == nil {
+			errList = append(errList, field.Forbidden(lfgsPath,
+				fmt.Sprintf("logfile group %q cannot be removed", oldLfg.Name)))
+			continue
+		}
+
+		if !quantitiesEqual(oldLfg.UndoBufferSize, newLfg.UndoBufferSize) {
+			errList = append(errList, field.Forbidden(lfgsPath,
+				fmt.Sprintf("undoBufferSize of the logfile group %q cannot be updated", oldLfg.Name)))
+		}
+		errList = append(errList, validateDiskDataFilesUpdate(
+			oldLfg.UndoFiles, newLfg.UndoFiles, lfgsPath.Key(oldLfg.Name).Child("undoFiles"))...)
+	}
+
+	tablespacesPath := diskDataPath.Child("tablespaces")
+	for _, oldTs := range oldDiskData.Tablespaces {
+		var newTs *NdbTablespaceSpec
+		for i := range newDiskData.Tablespaces {
+			if newDiskData.Tablespaces[i].Name == oldTs.Name {
+				newTs = &newDiskData.Tablespaces[i]
+				break
+			}
+		}
+
+		if newTs == nil {
+			errList = append(errList, field.Forbidden(tablespacesPath,
+				fmt.Sprintf("tablespace %q cannot be removed", oldTs.Name)))
+			continue
+		}
+
+		if oldTs.LogfileGroup != newTs.LogfileGroup ||
+			!quantitiesEqual(oldTs.ExtentSize, newTs.ExtentSize) {
+			errList = append(errList, field.Forbidden(tablespacesPath,
+				fmt.Sprintf("logfileGroup and extentSize of the tablespace %q cannot be updated", oldTs.Name)))
+		}
+		errList = append(errList, validateDiskDataFilesUpdate(
+			oldTs.DataFiles, newTs.DataFiles, tablespacesPath.Key(oldTs.Name).Child("dataFiles"))...)
+	}
+
+	return errList
+}
+
+// validateRedundancyLevelUpdate validates a change in spec.redundancyLevel.
+// The redundancyLevel can be increased via a migration that backs up the
+// data, clears it via an initial system restart, and restores it. It can
+// be decreased only to abort a migration that is still taking the backup.
+func validateRedundancyLevelUpdate(nc, newNc *NdbCluster, specPath *field.Path) field.ErrorList {
+	var errList field.ErrorList
+	redundancyLevelPath := specPath.Child("redundancyLevel")
+	newRedundancyLevel := newNc.Spec.RedundancyLevel
+
+	if newRedundancyLevel < nc.Spec.RedundancyLevel {
+		if !nc.CanAbortRedundancyLevelMigration() ||
+			nc.Status.RedundancyLevelMigration.FromRedundancyLevel != newRedundancyLevel {
+			errList = append(errList,
+				field.Invalid(redundancyLevelPath, newRedundancyLevel,
+					"spec.redundancyLevel can only be increased or be reverted to "+
+						"abort a migration that has not reached the initial system restart"))
+		}
+		return errList
+	}
+
+	// The backup files are stored in the data node PVCs
+	// and have to be retained across the initial restart.
+	if newNc.Spec.DataNode.PVCSpec == nil {
+		errList = append(errList,
+			field.Required(specPath.Child("dataNode", "pvcSpec"),
+				"spec.dataNode.pvcSpec is required to retain the backup "+
+					"taken while migrating to a new redundancyLevel"))
+	} else if !newNc.HasBackupsRetainedAcrossInitialRestart() {
+		errList = append(errList,
+			field.Invalid(specPath.Child("dataNode", "config"), newNc.GetDataNodeBackupDataDir(),
+				fmt.Sprintf("the data node BackupDataDir should be inside %s/data, and outside the "+
+					"data node file system directories, to retain the backup taken while "+
+					"migrating to a new redundancyLevel", constants.DataDir)))
+	}
+
+	// ndb_restore connects to the MySQL Cluster via a free API slot
+	if newNc.Spec.FreeAPISlots < 1 {
+		errList = append(errList,
+			field.Invalid(specPath.Child("freeAPISlots"), newNc.Spec.FreeAPISlots,
+				"spec.freeAPISlots should be atleast 1 to restore the backup "+
+					"taken while migrating to a new redundancyLevel"))
+	}
+
+	return errList
+}
+
+// validateSuspendedUpdate validates an update to an NdbCluster that is
+// suspended, or that is being suspended or resumed. The MySQL Cluster
+// nodes are not running when suspended, so no spec change can be applied,
+// and hence spec.suspended has to be the only field that is updated.
+func validateSuspendedUpdate(nc, newNc *NdbCluster, specPath *field.Path) field.ErrorList {
+	newSpec := newNc.Spec.DeepCopy()
+	newSpec.Suspended = nc.Spec.Suspended
+	if reflect.DeepEqual(&nc.Spec, newSpec) {
+		return nil
+	}
+
+	if nc.Spec.Suspended {
+		return field.ErrorList{field.Forbidden(specPath,
+			"spec cannot be updated while the NdbCluster is suspended, except spec.suspended to resume it")}
+	}
+	return field.ErrorList{field.Forbidden(specPath,
+		"spec.suspended has to be the only field updated when suspending the NdbCluster")}
+}
+
 func cannotUpdateFieldError(specPath *field.Path, newValue interface{}) *field.Error {
 	return field.Invalid(specPath, newValue,
 		fmt.Sprintf("%s cannot be updated once NdbCluster has been created", specPath.String()))
@@ -177,28 +646,95 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 	dataNodePath := specPath.Child("dataNode")
 	mysqldPath := specPath.Child("mysqlNode")
 
-	if nc.Spec.RedundancyLevel == 1 {
-		// MySQL Cluster replica = 1 => updating MySQL config via
-		// rolling restart is not possible. Disallow any spec update.
+	if migration := nc.Status.RedundancyLevelMigration; migration != nil &&
+		(migration.Phase == NdbRedundancyLevelMigrationPhaseInitialSystemRestart ||
+			migration.Phase == NdbRedundancyLevelMigrationPhaseRestoring) {
+		// The data nodes are being restarted with, or the backup is being
+		// restored into, the config generated from the current spec.
+		errList = append(errList,
+			field.Forbidden(specPath,
+				fmt.Sprintf("spec cannot be updated while the MySQL Cluster is "+
+					"being migrated to redundancyLevel %d", migration.ToRedundancyLevel)))
+		return false, errList
+	}
+
+	if nc.Spec.Suspended || newNc.Spec.Suspended {
+		// The NdbCluster is suspended or is being suspended
+		errList = validateSuspendedUpdate(nc, newNc, specPath)
+		return errList == nil, errList
+	}
+
+	if nc.Spec.RedundancyLevel == 1 && newNc.Spec.RedundancyLevel == 1 &&
+		!newNc.UsesFullRestartUpdateStrategy() {
+		// MySQL Cluster replica = 1 => updating MySQL config via rolling
+		// restart is not possible. Allow spec updates only via full restart.
+		// A change in redundancyLevel is applied via an initial system restart.
 		errList = append(errList,
 			field.InternalError(specPath,
-				errors.New("operator cannot handle any spec update to a MySQL Cluster whose replica is 1")))
+				errors.New("operator can apply spec updates to a MySQL Cluster whose replica is 1 "+
+					"only when spec.updateStrategy is FullRestart")))
 		return false, errList
 	}
 
-	// Do not allow decreasing Spec.DataNode.NodeCount
-	if nc.Spec.DataNode.NodeCount > newNc.Spec.DataNode.NodeCount {
+	// Do not allow decreasing Spec.DataNode.NodeCount. NDB can only drop
+	// nodegroups that have no data in them, and every running MySQL Cluster
+	// has the mysql.ndb_* system tables spread across all of its nodegroups.
+	// The count is allowed to drop only when an ongoing redundancyLevel
+	// migration is being aborted, as the new data nodes are not started
+	// until then, which is validated by validateRedundancyLevelUpdate.
+	if nc.Spec.DataNode.NodeCount > newNc.Spec.DataNode.NodeCount &&
+		nc.Spec.RedundancyLevel <= newNc.Spec.RedundancyLevel {
 		errList = append(errList,
 			field.Invalid(dataNodePath.Child("nodeCount"), newNc.Spec.DataNode.NodeCount,
-				"spec.dataNode.nodeCount cannot be reduced once MySQL Cluster has been started"))
+				"spec.dataNode.nodeCount cannot be reduced as the nodegroups of the data nodes "+
+					"being removed have data in them. Take an NdbClusterBackup and restore it, "+
+					"via spec.restoreFrom, into a new NdbCluster with fewer data nodes instead"))
+	}
+
+	// Management nodes can only be added. The count is allowed to drop
+	// only when an ongoing redundancyLevel migration is being aborted,
+	// which is validated by validateRedundancyLevelUpdate.
+	if nc.GetManagementNodeCount() > newNc.GetManagementNodeCount() &&
+		nc.Spec.RedundancyLevel <= newNc.Spec.RedundancyLevel {
+		errList = append(errList,
+			field.Invalid(managementNodePath.Child("nodeCount"), newNc.GetManagementNodeCount(),
+				fmt.Sprintf("number of management nodes cannot be reduced from %d",
+					nc.GetManagementNodeCount())))
 	}
 
-	// Do not allow updating Spec.RedundancyLevel
+	// Spec.RedundancyLevel can only be increased via a migration
 	if nc.Spec.RedundancyLevel != newNc.Spec.RedundancyLevel {
+		errList = append(errList, validateRedundancyLevelUpdate(nc, newNc, specPath)...)
+	}
+
+	// Do not allow updating Spec.RestoreFrom as the backup is restored only once
+	if !reflect.DeepEqual(nc.Spec.RestoreFrom, newNc.Spec.RestoreFrom) {
+		errList = append(errList,
+			cannotUpdateFieldError(specPath.Child("restoreFrom"), newNc.Spec.RestoreFrom))
+	}
+
+	// Do not allow enabling, disabling or changing the CA of TLS
+	// as every node has to be restarted with the new certificates.
+	if !reflect.DeepEqual(nc.Spec.TLS, newNc.Spec.TLS) {
+		errList = append(errList,
+			cannotUpdateFieldError(specPath.Child("tls"), newNc.Spec.TLS))
+	}
+
+	// The data nodes are restarted with the --initial flag to re-encrypt
+	// their file systems when TDE is enabled, disabled or uses a new Secret.
+	// With a single replica, no other data node has the data to recover it.
+	if nc.Spec.RedundancyLevel == 1 && newNc.Spec.RedundancyLevel == 1 &&
+		nc.Spec.TDESecretName != newNc.Spec.TDESecretName {
 		errList = append(errList,
-			cannotUpdateFieldError(specPath.Child("redundancyLevel"), newNc.Spec.RedundancyLevel))
+			field.Forbidden(specPath.Child("tdeSecretName"),
+				"spec.tdeSecretName cannot be updated when spec.redundancyLevel is 1 "+
+					"as re-encrypting the data nodes would wipe out their data"))
 	}
 
+	// Do not allow removing or updating the existing disk data objects
+	errList = append(errList, validateDiskDataSpecUpdate(
+		nc.Spec.DataNode.DiskData, newNc.Spec.DataNode.DiskData, dataNodePath.Child("diskData"))...)
+
 	// Do not allow updating Resource field of various ndbPodSpecs
 	if nc.Spec.ManagementNode != nil {
 		if err := validateNdbPodSpecResources(
@@ -227,6 +763,30 @@ func (nc *NdbCluster) IsValidSpecUpdate(newNc *NdbCluster) (bool, field.ErrorLis
 				"connectionPoolSize cannot be reduced once MySQL Cluster has been started"))
 	}
 
+	// Apply the same restrictions to the MySQL Server groups that continue to exist
+	for i, newMySQLdGroup := range newNc.Spec.MysqlNodeGroups {
+		mysqldGroup := nc.GetMySQLServerGroupSpec(newMySQLdGroup.Name)
+		if mysqldGroup == nil {
+			// A new group - nothing to compare
+			continue
+		}
+
+		groupPath := specPath.Child("mysqlNodeGroups").Index(i)
+		if mysqldGroup.NodeCount != 0 && newMySQLdGroup.NodeCount != 0 {
+			if err := validateNdbPodSpecResources(
+				groupPath.Child("ndbPodSpec"), mysqldGroup.NdbPodSpec, newMySQLdGroup.NdbPodSpec); err != nil {
+				errList = append(errList, err)
+			}
+		}
+
+		if mysqldGroup.ConnectionPoolSize > newMySQLdGroup.ConnectionPoolSize {
+			errList = append(errList,
+				field.Invalid(groupPath.Child("connectionPoolSize"),
+					newMySQLdGroup.ConnectionPoolSize,
+					"connectionPoolSize cannot be reduced once MySQL Cluster has been started"))
+		}
+	}
+
 	// Check if the new NdbCluster valid is spec
 	if isValid, specErrList := newNc.HasValidSpec(); !isValid {
 		errList = append(errList, specErrList...)
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -10,12 +10,14 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type validationCase struct {
 	spec       *NdbClusterSpec
 	oldSpec    *NdbClusterSpec
+	oldStatus  *NdbClusterStatus
 	shouldFail bool
 	explain    string
 }
@@ -82,6 +84,155 @@ func ndbUpdateTests(redundancy, dnc, mysqldCount,
 	}
 }
 
+func redundancyLevelUpdateTests(redundancy, oldRedundancy int32, withPVC bool,
+	migration *NdbRedundancyLevelMigrationStatus, fail bool, short string) *validationCase {
+	vc := ndbUpdateTests(redundancy, 4, 2, oldRedundancy, 4, 2, fail, short)
+	vc.spec.FreeAPISlots = 1
+	if withPVC {
+		vc.spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+		vc.oldSpec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+	}
+	if migration != nil {
+		vc.oldStatus = &NdbClusterStatus{
+			RedundancyLevelMigration: migration,
+		}
+	}
+	return vc
+}
+
+func restoreFromTests(freeAPISlots int32, restoreFrom *NdbClusterRestoreSource,
+	fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			FreeAPISlots: freeAPISlots,
+			RestoreFrom:  restoreFrom,
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func diskDataTests(oldDiskData, diskData *NdbDiskDataSpec, fail bool, short string) *validationCase {
+	vc := &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				DiskData:  diskData,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 1,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+	if oldDiskData != nil {
+		vc.oldSpec = &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+				DiskData:  oldDiskData,
+			},
+			MysqlNode: &NdbMysqldSpec{
+				NodeCount: 1,
+			},
+		}
+	}
+	return vc
+}
+
+// newTestDiskData returns a disk data spec with a logfile group
+// and a tablespace having the given number of data files
+func newTestDiskData(numOfDataFiles int, dataFileSize string) *NdbDiskDataSpec {
+	diskData := &NdbDiskDataSpec{
+		LogfileGroups: []NdbLogfileGroupSpec{
+			{
+				Name:      "lg_1",
+				UndoFiles: []NdbDiskDataFile{{Name: "undo_1.log", InitialSize: resource.MustParse("64M")}},
+			},
+		},
+		Tablespaces: []NdbTablespaceSpec{
+			{
+				Name:         "ts_1",
+				LogfileGroup: "lg_1",
+			},
+		},
+	}
+	for i := 1; i <= numOfDataFiles; i++ {
+		diskData.Tablespaces[0].DataFiles = append(diskData.Tablespaces[0].DataFiles, NdbDiskDataFile{
+			Name:        fmt.Sprintf("data_%d.dat", i),
+			InitialSize: resource.MustParse(dataFileSize),
+		})
+	}
+	return diskData
+}
+
+func nodeGroupsTests(nodeGroups []NdbDataNodeGroupSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount:  4,
+				NodeGroups: nodeGroups,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func mysqlNodeGroupsTests(oldMysqlNodeGroups, mysqlNodeGroups []NdbMysqldGroupSpec,
+	fail bool, short string) *validationCase {
+	newSpec := func(mysqlNodeGroups []NdbMysqldGroupSpec) *NdbClusterSpec {
+		return &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNodeGroups: mysqlNodeGroups,
+		}
+	}
+
+	vc := &validationCase{
+		spec:       newSpec(mysqlNodeGroups),
+		shouldFail: fail,
+		explain:    short,
+	}
+	if oldMysqlNodeGroups != nil {
+		vc.oldSpec = newSpec(oldMysqlNodeGroups)
+	}
+	return vc
+}
+
+func managementNodeCountTests(oldMgmdCount, mgmdCount int32, fail bool, short string) *validationCase {
+	newSpec := func(mgmdCount int32) *NdbClusterSpec {
+		return &NdbClusterSpec{
+			RedundancyLevel: 2,
+			ManagementNode: &NdbManagementNodeSpec{
+				NodeCount: mgmdCount,
+			},
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+		}
+	}
+
+	vc := &validationCase{
+		spec:       newSpec(mgmdCount),
+		shouldFail: fail,
+		explain:    short,
+	}
+	if oldMgmdCount != 0 {
+		vc.oldSpec = newSpec(oldMgmdCount)
+	}
+	return vc
+}
+
 func ndbUpdateNdbPodSpecTests(
 	oldNdbClusterSpec func(defaultSpec *NdbClusterSpec),
 	newNdbClusterSpec func(defaultSpec *NdbClusterSpec),
@@ -109,9 +260,68 @@ func ndbUpdateNdbPodSpecTests(
 	return vc
 }
 
+func dedicatedBinlogServersTests(
+	binlogGroup string, groupNodeCount int32, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			MysqlNodeGroups: []NdbMysqldGroupSpec{
+				{Name: "binlog", NodeCount: groupNodeCount, MaxNodeCount: 2, ConnectionPoolSize: 1},
+			},
+			Replication: &NdbClusterReplicationSpec{
+				ServerIdOffset:         1000,
+				BinlogMySQLServerGroup: binlogGroup,
+				DedicatedBinlogServers: true,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func conflictDetectionTests(conflictRole NdbConflictRole,
+	conflictDetection []NdbConflictDetectionSpec, fail bool, short string) *validationCase {
+	return &validationCase{
+		spec: &NdbClusterSpec{
+			RedundancyLevel: 2,
+			DataNode: &NdbDataNodeSpec{
+				NodeCount: 2,
+			},
+			Replication: &NdbClusterReplicationSpec{
+				ServerIdOffset:    1000,
+				ConflictRole:      conflictRole,
+				ConflictDetection: conflictDetection,
+			},
+		},
+		shouldFail: fail,
+		explain:    short,
+	}
+}
+
+func suspendedTests(oldSuspended, suspended bool,
+	oldMysqldCount, mysqldCount int32, fail bool, short string) *validationCase {
+	vc := ndbUpdateTests(2, 2, mysqldCount, 2, 2, oldMysqldCount, fail, short)
+	vc.oldSpec.Suspended = oldSuspended
+	vc.spec.Suspended = suspended
+	return vc
+}
+
+func tdeSecretNameUpdateTests(redundancy int32,
+	oldTDESecretName, tdeSecretName string, fail bool, short string) *validationCase {
+	vc := ndbUpdateTests(redundancy, 2, 2, redundancy, 2, 2, fail, short)
+	vc.spec.UpdateStrategy = NdbClusterUpdateStrategyFullRestart
+	vc.oldSpec.TDESecretName = oldTDESecretName
+	vc.spec.TDESecretName = tdeSecretName
+	return vc
+}
+
 func Test_Validation(t *testing.T) {
 
 	shouldFail := true
+	cpuList := intstr.FromString("1-2")
 	vcs := []*validationCase{
 		nodeNumberTests(0, 0, 0, shouldFail, "all zero"),
 		nodeNumberTests(0, 2, 2, shouldFail, "redundancy zero, not matching node count"),
@@ -133,8 +343,254 @@ func Test_Validation(t *testing.T) {
 		ndbUpdateTests(2, 2, 2, 1, 2, 2, shouldFail, "should not update redundancy"),
 		ndbUpdateTests(2, 4, 2, 2, 2, 2, !shouldFail, "allow increasing data node count"),
 		ndbUpdateTests(2, 4, 2, 2, 6, 2, shouldFail, "should not decrease data node count"),
+		ndbUpdateTests(2, 4, 0, 2, 6, 2, shouldFail, "should not decrease data node count without mysqlds"),
 		ndbUpdateTests(2, 2, 5, 2, 2, 2, !shouldFail, "allow increasing mysqld node count"),
 		ndbUpdateTests(1, 2, 5, 1, 2, 2, shouldFail, "update spec with replica = 1"),
+		func() *validationCase {
+			vc := ndbUpdateTests(1, 2, 5, 1, 2, 2, !shouldFail, "update spec with replica = 1 via full restart")
+			vc.spec.UpdateStrategy = NdbClusterUpdateStrategyFullRestart
+			return vc
+		}(),
+
+		redundancyLevelUpdateTests(2, 1, true, nil, !shouldFail, "allow increasing redundancy via migration"),
+		redundancyLevelUpdateTests(2, 1, false, nil, shouldFail, "redundancy migration requires data node PVCs"),
+		redundancyLevelUpdateTests(1, 2, true, nil, shouldFail, "should not decrease redundancy"),
+		func() *validationCase {
+			vc := redundancyLevelUpdateTests(2, 1, true, nil, !shouldFail, "allow backups in the data node PVC")
+			backupDataDir := intstr.FromString("/var/lib/ndb/data/backups")
+			vc.spec.DataNode.Config = map[string]*intstr.IntOrString{"BackupDataDir": &backupDataDir}
+			return vc
+		}(),
+		func() *validationCase {
+			vc := redundancyLevelUpdateTests(2, 1, true, nil, shouldFail, "migration requires backups in data node PVC")
+			backupDataDir := intstr.FromString("/tmp/backups")
+			vc.spec.DataNode.Config = map[string]*intstr.IntOrString{"BackupDataDir": &backupDataDir}
+			return vc
+		}(),
+		func() *validationCase {
+			vc := redundancyLevelUpdateTests(2, 1, true, nil, shouldFail, "backups cleared by initial restart")
+			backupDataDir := intstr.FromString("/var/lib/ndb/data/ndb_3_fs/backups")
+			vc.spec.DataNode.Config = map[string]*intstr.IntOrString{"backupdatadir": &backupDataDir}
+			return vc
+		}(),
+		redundancyLevelUpdateTests(1, 2, true, &NdbRedundancyLevelMigrationStatus{
+			FromRedundancyLevel: 1, ToRedundancyLevel: 2, Phase: NdbRedundancyLevelMigrationPhaseBackingUp},
+			!shouldFail, "allow aborting redundancy migration during backup"),
+		redundancyLevelUpdateTests(1, 2, true, &NdbRedundancyLevelMigrationStatus{
+			FromRedundancyLevel: 1, ToRedundancyLevel: 2, Phase: NdbRedundancyLevelMigrationPhaseRestoring},
+			shouldFail, "should not abort redundancy migration after initial system restart"),
+		func() *validationCase {
+			vc := redundancyLevelUpdateTests(1, 2, true, &NdbRedundancyLevelMigrationStatus{
+				FromRedundancyLevel: 1, ToRedundancyLevel: 2, Phase: NdbRedundancyLevelMigrationPhaseBackingUp},
+				!shouldFail, "allow reverting data node count when aborting redundancy migration")
+			vc.spec.DataNode.NodeCount = 2
+			return vc
+		}(),
+		func() *validationCase {
+			vc := redundancyLevelUpdateTests(2, 1, true, nil, shouldFail,
+				"should not decrease data node count while migrating redundancy")
+			vc.spec.DataNode.NodeCount = 2
+			return vc
+		}(),
+		redundancyLevelUpdateTests(2, 2, true, &NdbRedundancyLevelMigrationStatus{
+			FromRedundancyLevel: 1, ToRedundancyLevel: 2, Phase: NdbRedundancyLevelMigrationPhaseInitialSystemRestart},
+			shouldFail, "should not update spec during initial system restart"),
+
+		restoreFromTests(2, &NdbClusterRestoreSource{
+			BackupId: 1, DataNodeIds: []int32{3, 4}, PersistentVolumeClaimName: "backups", Path: "prod"},
+			!shouldFail, "valid backup to be restored"),
+		restoreFromTests(0, &NdbClusterRestoreSource{
+			BackupId: 1, DataNodeIds: []int32{3, 4}, PersistentVolumeClaimName: "backups"},
+			shouldFail, "restore requires a free API slot"),
+		restoreFromTests(2, &NdbClusterRestoreSource{
+			BackupId: 1, DataNodeIds: []int32{3, 4}, PersistentVolumeClaimName: "backups", Path: "../prod"},
+			shouldFail, "backup path outside the PVC"),
+		{
+			spec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				FreeAPISlots: 2,
+				RestoreFrom: &NdbClusterRestoreSource{
+					BackupId: 2, DataNodeIds: []int32{3, 4}, PersistentVolumeClaimName: "backups"},
+			},
+			oldSpec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				FreeAPISlots: 2,
+				RestoreFrom: &NdbClusterRestoreSource{
+					BackupId: 1, DataNodeIds: []int32{3, 4}, PersistentVolumeClaimName: "backups"},
+			},
+			shouldFail: true,
+			explain:    "updating restoreFrom is not allowed",
+		},
+		{
+			spec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				TLS: &NdbClusterTLSSpec{},
+			},
+			oldSpec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+			},
+			shouldFail: true,
+			explain:    "enabling tls is not allowed",
+		},
+
+		diskDataTests(nil, newTestDiskData(1, "128M"), !shouldFail, "valid disk data objects"),
+		diskDataTests(nil, func() *NdbDiskDataSpec {
+			diskData := newTestDiskData(1, "128M")
+			diskData.Tablespaces[0].LogfileGroup = "lg_2"
+			return diskData
+		}(), shouldFail, "tablespace uses an undeclared logfile group"),
+		diskDataTests(nil, func() *NdbDiskDataSpec {
+			diskData := newTestDiskData(1, "128M")
+			diskData.Tablespaces[0].DataFiles[0].Name = "undo_1.log"
+			return diskData
+		}(), shouldFail, "duplicate disk data file names"),
+		diskDataTests(nil, func() *NdbDiskDataSpec {
+			diskData := newTestDiskData(1, "128M")
+			diskData.Tablespaces[0].DataFiles[0].Name = "/tmp/data_1.dat"
+			return diskData
+		}(), shouldFail, "absolute disk data file path"),
+		diskDataTests(nil, newTestDiskData(1, "0"), shouldFail, "zero sized data file"),
+		func() *validationCase {
+			vc := diskDataTests(nil, newTestDiskData(1, "128M"), shouldFail, "disk data objects without MySQL Servers")
+			vc.spec.MysqlNode.NodeCount = 0
+			return vc
+		}(),
+		func() *validationCase {
+			vc := diskDataTests(newTestDiskData(1, "128M"), newTestDiskData(1, "128M"),
+				shouldFail, "should not scale down to 0 MySQL Servers with disk data objects")
+			vc.spec.MysqlNode.NodeCount = 0
+			return vc
+		}(),
+		diskDataTests(newTestDiskData(1, "128M"), newTestDiskData(2, "128M"),
+			!shouldFail, "allow adding a data file"),
+		diskDataTests(newTestDiskData(2, "128M"), newTestDiskData(1, "128M"),
+			shouldFail, "should not remove a data file"),
+		diskDataTests(newTestDiskData(1, "128M"), newTestDiskData(1, "256M"),
+			shouldFail, "should not resize a data file"),
+		diskDataTests(newTestDiskData(1, "128M"), func() *NdbDiskDataSpec {
+			diskData := newTestDiskData(1, "128M")
+			diskData.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+			return diskData
+		}(), shouldFail, "should not update the disk data PVC"),
+
+		nodeGroupsTests([]NdbDataNodeGroupSpec{{
+			NodeGroup: 1,
+			Config:    map[string]*intstr.IntOrString{"LockExecuteThreadToCPU": &cpuList},
+			NdbPodSpec: &NdbDataNodeGroupPodSpec{
+				NodeSelector: map[string]string{"disk": "ssd"},
+			},
+		}}, !shouldFail, "valid node group config"),
+		nodeGroupsTests([]NdbDataNodeGroupSpec{{NodeGroup: 2}}, shouldFail, "undefined node group"),
+		nodeGroupsTests([]NdbDataNodeGroupSpec{{NodeGroup: 0}, {NodeGroup: 0}}, shouldFail, "duplicate node group"),
+		nodeGroupsTests([]NdbDataNodeGroupSpec{{
+			NodeGroup: 0,
+			Config:    map[string]*intstr.IntOrString{"NodeGroup": &cpuList},
+		}}, shouldFail, "disallowed node group config"),
+		nodeGroupsTests([]NdbDataNodeGroupSpec{{
+			NodeGroup: 0,
+			Config:    map[string]*intstr.IntOrString{"BackupDataDir": &cpuList},
+		}}, shouldFail, "backup directory in node group config"),
+
+		managementNodeCountTests(0, 1, !shouldFail, "single management node with redundancy 2"),
+		managementNodeCountTests(0, 3, !shouldFail, "three management nodes"),
+		managementNodeCountTests(1, 3, !shouldFail, "allow adding management nodes"),
+		managementNodeCountTests(3, 2, shouldFail, "should not remove management nodes"),
+		func() *validationCase {
+			vc := managementNodeCountTests(0, 4, shouldFail, "management nodes overlap with API nodes")
+			vc.spec.FreeAPISlots = 107
+			return vc
+		}(),
+
+		mysqlNodeGroupsTests(nil, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 2, MaxNodeCount: 4, ConnectionPoolSize: 1, MyCnf: "max-connections=500"},
+			{Name: "analytics", NodeCount: 1, MaxNodeCount: 1, ConnectionPoolSize: 4},
+		}, !shouldFail, "valid mysqld groups"),
+		mysqlNodeGroupsTests(nil, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 1, ConnectionPoolSize: 1},
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 1, ConnectionPoolSize: 1},
+		}, shouldFail, "duplicate mysqld group names"),
+		mysqlNodeGroupsTests(nil, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 3, MaxNodeCount: 2, ConnectionPoolSize: 1},
+		}, shouldFail, "mysqld group nodeCount exceeds maxNodeCount"),
+		mysqlNodeGroupsTests(nil, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 1, ConnectionPoolSize: 1, MyCnf: "[mysqld]\n[mysqld]"},
+		}, shouldFail, "mysqld group my.cnf has more than one mysqld section"),
+		mysqlNodeGroupsTests(nil, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 130, ConnectionPoolSize: 2},
+		}, shouldFail, "too many API sections required by the mysqld group"),
+		mysqlNodeGroupsTests([]NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 3, ConnectionPoolSize: 2},
+		}, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 3, MaxNodeCount: 3, ConnectionPoolSize: 1},
+		}, shouldFail, "decreasing the connection pool of a mysqld group is not allowed"),
+		mysqlNodeGroupsTests([]NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 1, MaxNodeCount: 3, ConnectionPoolSize: 1},
+		}, []NdbMysqldGroupSpec{
+			{Name: "oltp", NodeCount: 3, MaxNodeCount: 3, ConnectionPoolSize: 1},
+			{Name: "analytics", NodeCount: 1, MaxNodeCount: 1, ConnectionPoolSize: 1},
+		}, !shouldFail, "allow scaling and adding mysqld groups"),
+
+		dedicatedBinlogServersTests("binlog", 2, !shouldFail, "valid dedicated binlog server pair"),
+		dedicatedBinlogServersTests("", 2, shouldFail, "dedicated binlog servers require a group"),
+		dedicatedBinlogServersTests("binlog", 1, shouldFail, "dedicated binlog servers should be a pair"),
+
+		conflictDetectionTests(NdbConflictRolePrimary, []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionEpoch2, ExceptionsTable: true},
+			{Database: "shop", Table: "stock", Function: NdbConflictFunctionMax, Column: "version"},
+			{Database: "logs", Table: "%", Function: NdbConflictFunctionEpoch2Trans},
+		}, !shouldFail, "valid conflict detection functions"),
+		conflictDetectionTests("", []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionEpoch2},
+		}, shouldFail, "epoch functions require a conflict role"),
+		conflictDetectionTests(NdbConflictRoleSecondary, []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionEpoch, Column: "version"},
+		}, shouldFail, "column is not allowed for epoch functions"),
+		conflictDetectionTests("", []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionOld},
+		}, shouldFail, "column is required for NDB$OLD"),
+		conflictDetectionTests("", []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionMax, Column: "version"},
+			{Database: "shop", Table: "orders", Function: NdbConflictFunctionOld, Column: "version"},
+		}, shouldFail, "duplicate conflict detection tables"),
+		conflictDetectionTests("", []NdbConflictDetectionSpec{
+			{Database: "shop", Table: "order%", Function: NdbConflictFunctionMax, Column: "version", ExceptionsTable: true},
+		}, shouldFail, "exceptions table for wildcard table names"),
+
+		suspendedTests(false, true, 2, 2, !shouldFail, "allow suspending the NdbCluster"),
+		suspendedTests(true, false, 2, 2, !shouldFail, "allow resuming the NdbCluster"),
+		suspendedTests(false, true, 2, 3, shouldFail, "should not update spec while suspending"),
+		suspendedTests(true, true, 2, 3, shouldFail, "should not update spec while suspended"),
+		suspendedTests(true, false, 2, 3, shouldFail, "should not update spec while resuming"),
+
+		tdeSecretNameUpdateTests(2, "tde-secret", "new-tde-secret", !shouldFail, "allow updating the TDE Secret"),
+		tdeSecretNameUpdateTests(2, "", "tde-secret", !shouldFail, "allow enabling TDE"),
+		tdeSecretNameUpdateTests(1, "tde-secret", "tde-secret", !shouldFail, "allow keeping the TDE Secret at redundancyLevel 1"),
+		tdeSecretNameUpdateTests(1, "tde-secret", "new-tde-secret", shouldFail, "should not update the TDE Secret at redundancyLevel 1"),
+		tdeSecretNameUpdateTests(1, "", "tde-secret", shouldFail, "should not enable TDE at redundancyLevel 1"),
+		tdeSecretNameUpdateTests(1, "tde-secret", "", shouldFail, "should not disable TDE at redundancyLevel 1"),
+		{
+			spec: &NdbClusterSpec{
+				RedundancyLevel: 2,
+				DataNode: &NdbDataNodeSpec{
+					NodeCount: 2,
+				},
+				Suspended: true,
+			},
+			shouldFail: shouldFail,
+			explain:    "should not create a suspended NdbCluster",
+		},
 
 		{
 			spec: &NdbClusterSpec{
@@ -277,6 +733,9 @@ func Test_Validation(t *testing.T) {
 			oldNdb := &NdbCluster{
 				Spec: *vc.oldSpec,
 			}
+			if vc.oldStatus != nil {
+				oldNdb.Status = *vc.oldStatus
+			}
 			isValid, errList = oldNdb.IsValidSpecUpdate(ndb)
 		} else {
 			isValid, errList = ndb.HasValidSpec()
@@ -1,7 +1,7 @@
 //go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -11,6 +11,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -43,6 +44,261 @@ func (in *NdbCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackup) DeepCopyInto(out *NdbClusterBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackup.
+func (in *NdbClusterBackup) DeepCopy() *NdbClusterBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupList) DeepCopyInto(out *NdbClusterBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbClusterBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupList.
+func (in *NdbClusterBackupList) DeepCopy() *NdbClusterBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupLocation) DeepCopyInto(out *NdbClusterBackupLocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupLocation.
+func (in *NdbClusterBackupLocation) DeepCopy() *NdbClusterBackupLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupRetentionPolicy) DeepCopyInto(out *NdbClusterBackupRetentionPolicy) {
+	*out = *in
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepFor != nil {
+		in, out := &in.KeepFor, &out.KeepFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupRetentionPolicy.
+func (in *NdbClusterBackupRetentionPolicy) DeepCopy() *NdbClusterBackupRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupSchedule) DeepCopyInto(out *NdbClusterBackupSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupSchedule.
+func (in *NdbClusterBackupSchedule) DeepCopy() *NdbClusterBackupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupScheduleList) DeepCopyInto(out *NdbClusterBackupScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbClusterBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupScheduleList.
+func (in *NdbClusterBackupScheduleList) DeepCopy() *NdbClusterBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbClusterBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupScheduleSpec) DeepCopyInto(out *NdbClusterBackupScheduleSpec) {
+	*out = *in
+	in.Retention.DeepCopyInto(&out.Retention)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupScheduleSpec.
+func (in *NdbClusterBackupScheduleSpec) DeepCopy() *NdbClusterBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupScheduleStatus) DeepCopyInto(out *NdbClusterBackupScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupScheduleStatus.
+func (in *NdbClusterBackupScheduleStatus) DeepCopy() *NdbClusterBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupSpec) DeepCopyInto(out *NdbClusterBackupSpec) {
+	*out = *in
+	if in.BackupId != nil {
+		in, out := &in.BackupId, &out.BackupId
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupSpec.
+func (in *NdbClusterBackupSpec) DeepCopy() *NdbClusterBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterBackupStatus) DeepCopyInto(out *NdbClusterBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]NdbClusterBackupLocation, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterBackupStatus.
+func (in *NdbClusterBackupStatus) DeepCopy() *NdbClusterBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbClusterCondition) DeepCopyInto(out *NdbClusterCondition) {
 	*out = *in
@@ -133,6 +389,85 @@ func (in *NdbClusterPodSpec) DeepCopy() *NdbClusterPodSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterReplicationSpec) DeepCopyInto(out *NdbClusterReplicationSpec) {
+	*out = *in
+	if in.ConflictDetection != nil {
+		in, out := &in.ConflictDetection, &out.ConflictDetection
+		*out = make([]NdbConflictDetectionSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterReplicationSpec.
+func (in *NdbClusterReplicationSpec) DeepCopy() *NdbClusterReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterRestoreNodeStatus) DeepCopyInto(out *NdbClusterRestoreNodeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterRestoreNodeStatus.
+func (in *NdbClusterRestoreNodeStatus) DeepCopy() *NdbClusterRestoreNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterRestoreNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterRestoreSource) DeepCopyInto(out *NdbClusterRestoreSource) {
+	*out = *in
+	if in.DataNodeIds != nil {
+		in, out := &in.DataNodeIds, &out.DataNodeIds
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterRestoreSource.
+func (in *NdbClusterRestoreSource) DeepCopy() *NdbClusterRestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterRestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbClusterRestoreStatus) DeepCopyInto(out *NdbClusterRestoreStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NdbClusterRestoreNodeStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterRestoreStatus.
+func (in *NdbClusterRestoreStatus) DeepCopy() *NdbClusterRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbClusterSpec) DeepCopyInto(out *NdbClusterSpec) {
 	*out = *in
@@ -151,6 +486,28 @@ func (in *NdbClusterSpec) DeepCopyInto(out *NdbClusterSpec) {
 		*out = new(NdbMysqldSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MysqlNodeGroups != nil {
+		in, out := &in.MysqlNodeGroups, &out.MysqlNodeGroups
+		*out = make([]NdbMysqldGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(NdbClusterRestoreSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(NdbClusterTLSSpec)
+		**out = **in
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(NdbClusterReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -174,6 +531,31 @@ func (in *NdbClusterStatus) DeepCopyInto(out *NdbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(NdbClusterRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]NdbTablespaceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedundancyLevelMigration != nil {
+		in, out := &in.RedundancyLevelMigration, &out.RedundancyLevelMigration
+		*out = new(NdbRedundancyLevelMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TDEPasswordRotation != nil {
+		in, out := &in.TDEPasswordRotation, &out.TDEPasswordRotation
+		*out = new(NdbTDEPasswordRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConflictDetection != nil {
+		in, out := &in.ConflictDetection, &out.ConflictDetection
+		*out = new(NdbConflictDetectionStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -188,15 +570,134 @@ func (in *NdbClusterStatus) DeepCopy() *NdbClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbDataNodeSpec) DeepCopyInto(out *NdbDataNodeSpec) {
+func (in *NdbClusterTLSSpec) DeepCopyInto(out *NdbClusterTLSSpec) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = make(map[string]*intstr.IntOrString, len(*in))
-		for key, val := range *in {
-			var outVal *intstr.IntOrString
-			if val == nil {
-				(*out)[key] = nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbClusterTLSSpec.
+func (in *NdbClusterTLSSpec) DeepCopy() *NdbClusterTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbClusterTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbConflictDetectionSpec) DeepCopyInto(out *NdbConflictDetectionSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbConflictDetectionSpec.
+func (in *NdbConflictDetectionSpec) DeepCopy() *NdbConflictDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbConflictDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbConflictDetectionStatus) DeepCopyInto(out *NdbConflictDetectionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbConflictDetectionStatus.
+func (in *NdbConflictDetectionStatus) DeepCopy() *NdbConflictDetectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbConflictDetectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeGroupPodSpec) DeepCopyInto(out *NdbDataNodeGroupPodSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeGroupPodSpec.
+func (in *NdbDataNodeGroupPodSpec) DeepCopy() *NdbDataNodeGroupPodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeGroupPodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeGroupSpec) DeepCopyInto(out *NdbDataNodeGroupSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]*intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			var outVal *intstr.IntOrString
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(intstr.IntOrString)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+		*out = new(NdbDataNodeGroupPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDataNodeGroupSpec.
+func (in *NdbDataNodeGroupSpec) DeepCopy() *NdbDataNodeGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDataNodeGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDataNodeSpec) DeepCopyInto(out *NdbDataNodeSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]*intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			var outVal *intstr.IntOrString
+			if val == nil {
+				(*out)[key] = nil
 			} else {
 				in, out := &val, &outVal
 				*out = new(intstr.IntOrString)
@@ -215,6 +716,18 @@ func (in *NdbDataNodeSpec) DeepCopyInto(out *NdbDataNodeSpec) {
 		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DiskData != nil {
+		in, out := &in.DiskData, &out.DiskData
+		*out = new(NdbDiskDataSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NdbDataNodeGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -228,6 +741,86 @@ func (in *NdbDataNodeSpec) DeepCopy() *NdbDataNodeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDiskDataFile) DeepCopyInto(out *NdbDiskDataFile) {
+	*out = *in
+	out.InitialSize = in.InitialSize.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDiskDataFile.
+func (in *NdbDiskDataFile) DeepCopy() *NdbDiskDataFile {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDiskDataFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbDiskDataSpec) DeepCopyInto(out *NdbDiskDataSpec) {
+	*out = *in
+	if in.PVCSpec != nil {
+		in, out := &in.PVCSpec, &out.PVCSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogfileGroups != nil {
+		in, out := &in.LogfileGroups, &out.LogfileGroups
+		*out = make([]NdbLogfileGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]NdbTablespaceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbDiskDataSpec.
+func (in *NdbDiskDataSpec) DeepCopy() *NdbDiskDataSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbDiskDataSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbLogfileGroupSpec) DeepCopyInto(out *NdbLogfileGroupSpec) {
+	*out = *in
+	if in.UndoBufferSize != nil {
+		in, out := &in.UndoBufferSize, &out.UndoBufferSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.UndoFiles != nil {
+		in, out := &in.UndoFiles, &out.UndoFiles
+		*out = make([]NdbDiskDataFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbLogfileGroupSpec.
+func (in *NdbLogfileGroupSpec) DeepCopy() *NdbLogfileGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbLogfileGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NdbManagementNodeSpec) DeepCopyInto(out *NdbManagementNodeSpec) {
 	*out = *in
@@ -265,42 +858,615 @@ func (in *NdbManagementNodeSpec) DeepCopy() *NdbManagementNodeSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NdbMysqldSpec) DeepCopyInto(out *NdbMysqldSpec) {
+func (in *NdbMySQLDatabase) DeepCopyInto(out *NdbMySQLDatabase) {
 	*out = *in
-	if in.NdbPodSpec != nil {
-		in, out := &in.NdbPodSpec, &out.NdbPodSpec
-		*out = new(NdbClusterPodSpec)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLDatabase.
+func (in *NdbMySQLDatabase) DeepCopy() *NdbMySQLDatabase {
+	if in == nil {
+		return nil
 	}
-	if in.InitScripts != nil {
-		in, out := &in.InitScripts, &out.InitScripts
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
+	out := new(NdbMySQLDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbMySQLDatabase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLDatabaseList) DeepCopyInto(out *NdbMySQLDatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbMySQLDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.PVCSpec != nil {
-		in, out := &in.PVCSpec, &out.PVCSpec
-		*out = new(corev1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLDatabaseList.
+func (in *NdbMySQLDatabaseList) DeepCopy() *NdbMySQLDatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLDatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbMySQLDatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLDatabaseSpec) DeepCopyInto(out *NdbMySQLDatabaseSpec) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldSpec.
-func (in *NdbMysqldSpec) DeepCopy() *NdbMysqldSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLDatabaseSpec.
+func (in *NdbMySQLDatabaseSpec) DeepCopy() *NdbMySQLDatabaseSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NdbMysqldSpec)
+	out := new(NdbMySQLDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLDatabaseStatus) DeepCopyInto(out *NdbMySQLDatabaseStatus) {
+	*out = *in
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLDatabaseStatus.
+func (in *NdbMySQLDatabaseStatus) DeepCopy() *NdbMySQLDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUser) DeepCopyInto(out *NdbMySQLUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUser.
+func (in *NdbMySQLUser) DeepCopy() *NdbMySQLUser {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbMySQLUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUserGrant) DeepCopyInto(out *NdbMySQLUserGrant) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUserGrant.
+func (in *NdbMySQLUserGrant) DeepCopy() *NdbMySQLUserGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUserGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUserList) DeepCopyInto(out *NdbMySQLUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbMySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUserList.
+func (in *NdbMySQLUserList) DeepCopy() *NdbMySQLUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbMySQLUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUserResourceLimits) DeepCopyInto(out *NdbMySQLUserResourceLimits) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUserResourceLimits.
+func (in *NdbMySQLUserResourceLimits) DeepCopy() *NdbMySQLUserResourceLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUserResourceLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUserSpec) DeepCopyInto(out *NdbMySQLUserSpec) {
+	*out = *in
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]NdbMySQLUserGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceLimits != nil {
+		in, out := &in.ResourceLimits, &out.ResourceLimits
+		*out = new(NdbMySQLUserResourceLimits)
+		**out = **in
+	}
+	if in.NdbStoredUser != nil {
+		in, out := &in.NdbStoredUser, &out.NdbStoredUser
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUserSpec.
+func (in *NdbMySQLUserSpec) DeepCopy() *NdbMySQLUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMySQLUserStatus) DeepCopyInto(out *NdbMySQLUserStatus) {
+	*out = *in
+	if in.AppliedGrants != nil {
+		in, out := &in.AppliedGrants, &out.AppliedGrants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMySQLUserStatus.
+func (in *NdbMySQLUserStatus) DeepCopy() *NdbMySQLUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMySQLUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldGroupSpec) DeepCopyInto(out *NdbMysqldGroupSpec) {
+	*out = *in
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+		*out = new(NdbClusterPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PVCSpec != nil {
+		in, out := &in.PVCSpec, &out.PVCSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldGroupSpec.
+func (in *NdbMysqldGroupSpec) DeepCopy() *NdbMysqldGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldSpec) DeepCopyInto(out *NdbMysqldSpec) {
+	*out = *in
+	if in.NdbPodSpec != nil {
+		in, out := &in.NdbPodSpec, &out.NdbPodSpec
+		*out = new(NdbClusterPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PVCSpec != nil {
+		in, out := &in.PVCSpec, &out.PVCSpec
+		*out = new(corev1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(NdbMysqldTLSSpec)
+		**out = **in
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(NdbPasswordRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldSpec.
+func (in *NdbMysqldSpec) DeepCopy() *NdbMysqldSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbMysqldTLSSpec) DeepCopyInto(out *NdbMysqldTLSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbMysqldTLSSpec.
+func (in *NdbMysqldTLSSpec) DeepCopy() *NdbMysqldTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbMysqldTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbPasswordRotationSpec) DeepCopyInto(out *NdbPasswordRotationSpec) {
+	*out = *in
+	if in.RetainOldPasswordFor != nil {
+		in, out := &in.RetainOldPasswordFor, &out.RetainOldPasswordFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbPasswordRotationSpec.
+func (in *NdbPasswordRotationSpec) DeepCopy() *NdbPasswordRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbPasswordRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbRedundancyLevelMigrationStatus) DeepCopyInto(out *NdbRedundancyLevelMigrationStatus) {
+	*out = *in
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]NdbClusterBackupLocation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(NdbClusterRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbRedundancyLevelMigrationStatus.
+func (in *NdbRedundancyLevelMigrationStatus) DeepCopy() *NdbRedundancyLevelMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbRedundancyLevelMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbReplicationChannel) DeepCopyInto(out *NdbReplicationChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbReplicationChannel.
+func (in *NdbReplicationChannel) DeepCopy() *NdbReplicationChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbReplicationChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbReplicationChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbReplicationChannelList) DeepCopyInto(out *NdbReplicationChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NdbReplicationChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbReplicationChannelList.
+func (in *NdbReplicationChannelList) DeepCopy() *NdbReplicationChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbReplicationChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NdbReplicationChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbReplicationChannelSpec) DeepCopyInto(out *NdbReplicationChannelSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbReplicationChannelSpec.
+func (in *NdbReplicationChannelSpec) DeepCopy() *NdbReplicationChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbReplicationChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbReplicationChannelStatus) DeepCopyInto(out *NdbReplicationChannelStatus) {
+	*out = *in
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LagSeconds != nil {
+		in, out := &in.LagSeconds, &out.LagSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbReplicationChannelStatus.
+func (in *NdbReplicationChannelStatus) DeepCopy() *NdbReplicationChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbReplicationChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbReplicationSource) DeepCopyInto(out *NdbReplicationSource) {
+	*out = *in
+	if in.FailoverHosts != nil {
+		in, out := &in.FailoverHosts, &out.FailoverHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbReplicationSource.
+func (in *NdbReplicationSource) DeepCopy() *NdbReplicationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbReplicationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbTDEPasswordRotationStatus) DeepCopyInto(out *NdbTDEPasswordRotationStatus) {
+	*out = *in
+	if in.ReEncryptedNodes != nil {
+		in, out := &in.ReEncryptedNodes, &out.ReEncryptedNodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingNodes != nil {
+		in, out := &in.PendingNodes, &out.PendingNodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbTDEPasswordRotationStatus.
+func (in *NdbTDEPasswordRotationStatus) DeepCopy() *NdbTDEPasswordRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbTDEPasswordRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbTablespaceSpec) DeepCopyInto(out *NdbTablespaceSpec) {
+	*out = *in
+	if in.ExtentSize != nil {
+		in, out := &in.ExtentSize, &out.ExtentSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DataFiles != nil {
+		in, out := &in.DataFiles, &out.DataFiles
+		*out = make([]NdbDiskDataFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbTablespaceSpec.
+func (in *NdbTablespaceSpec) DeepCopy() *NdbTablespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbTablespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NdbTablespaceStatus) DeepCopyInto(out *NdbTablespaceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NdbTablespaceStatus.
+func (in *NdbTablespaceStatus) DeepCopy() *NdbTablespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NdbTablespaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
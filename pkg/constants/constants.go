@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -26,10 +26,61 @@ const (
 	// ClusterResourceTypeLabel is applied to all K8s resources except
 	// pods owned by an NdbCluster resource
 	ClusterResourceTypeLabel = ndbcontroller.GroupName + "/resource-type"
+	// MySQLServerGroupLabel is applied to the MySQL Server StatefulSets,
+	// Services and pods of the groups declared in spec.mysqlNodeGroups
+	MySQLServerGroupLabel = ndbcontroller.GroupName + "/mysqld-group"
+	// MySQLServerRoleLabel is applied to the MySQL Server pods when
+	// the NdbCluster has dedicated binlog servers, to exclude them
+	// from the Service that serves the application traffic
+	MySQLServerRoleLabel = ndbcontroller.GroupName + "/mysqld-role"
+	// BackupScheduleLabel is applied to all the NdbClusterBackup
+	// resources created by an NdbClusterBackupSchedule resource
+	BackupScheduleLabel = ndbcontroller.GroupName + "/backup-schedule"
 )
 
+// Values of the MySQLServerRoleLabel
+const (
+	// MySQLServerRoleApplication is the role of the
+	// MySQL Servers that serve the application traffic
+	MySQLServerRoleApplication = "application"
+	// MySQLServerRoleBinlog is the role of the dedicated binlog servers
+	MySQLServerRoleBinlog = "binlog"
+)
+
+// MySQLUserFinalizer is added to the NdbMySQLUser resources to drop
+// the MySQL user from the MySQL Servers before the resource is deleted
+const MySQLUserFinalizer = ndbcontroller.GroupName + "/mysql-user"
+
+// ReservedMySQLUserNames are the names of the MySQL users created by the
+// MySQL Server and the NDB Operator. They cannot be declared via the
+// NdbMySQLUser resources as the operator depends on them.
+var ReservedMySQLUserNames = []string{
+	"root", "ndb-operator-user", "ndb-replication-user",
+	"mysql.infoschema", "mysql.session", "mysql.sys",
+}
+
+// ReplicationChannelFinalizer is added to the NdbReplicationChannel resources
+// to remove the channel from the replica MySQL Server before the resource is deleted
+const ReplicationChannelFinalizer = ndbcontroller.GroupName + "/replication-channel"
+
 const DataDir = "/var/lib/ndb"
 
+// DiskDataDir is the directory where the dedicated
+// disk data PVC is mounted into the data node pods
+const DiskDataDir = DataDir + "/disk-data"
+
+// TLSDir is the directory that has the private keys and the
+// certificates of the MySQL Cluster nodes when TLS is enabled
+const TLSDir = DataDir + "/tls"
+
+// TLSCADir is the directory where the cluster CA
+// is mounted into the pods when TLS is enabled
+const TLSCADir = DataDir + "/tls-ca"
+
+// MySQLServerTLSDir is the directory where the operator managed
+// MySQL Server certificate is mounted into the MySQL Server pods
+const MySQLServerTLSDir = DataDir + "/mysqld-tls"
+
 const (
 	// MaxNumberOfNodes is the maximum number of nodes in Ndb Cluster
 	MaxNumberOfNodes = 256
@@ -37,10 +88,14 @@ const (
 	// MaxNumberOfDataNodes is the maximum number of nodes in Ndb Cluster
 	MaxNumberOfDataNodes = 144
 
+	// MaxNodeId is the highest nodeId that can be used in a MySQL Cluster
+	MaxNodeId = 255
+
 	// NdbOperatorDedicatedAPINodeId is the dedicated nodeId used by the
 	// Ndb Operator to connect to a Management Server of a MySQL Cluster.
-	// NodeIds 1-146 are reserved for 2 management nodes and
-	// MaxNumberOfDataNodes count of data nodes.
+	// NodeIds 1-146 are reserved for the first 2 management nodes and
+	// MaxNumberOfDataNodes count of data nodes. Any further management
+	// nodes are assigned nodeIds in the descending order from MaxNodeId.
 	NdbOperatorDedicatedAPINodeId = 147
 
 	// NdbNodeTypeAPIStartNodeId is the nodeId of the
@@ -54,6 +109,8 @@ const (
 	ConfigIniKey = "config.ini"
 	// NumOfMySQLServers has the number of MySQL Servers declared in the NdbCluster spec.
 	NumOfMySQLServers = "numOfMySQLServers"
+	// MySQLServerGroups has the number of MySQL Servers of every MySQL Server group declared in the NdbCluster spec.
+	MySQLServerGroups = "mysqlServerGroups"
 	// NdbClusterGeneration stores the generation the config map is based on.
 	NdbClusterGeneration = "ndb.generation"
 	// ManagementLoadBalancer indicates if the load balancer service for management nodes needs to be enabled
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -23,6 +24,17 @@ const (
 	Separator         = "/"
 )
 
+// getNdbClusterPausedMessage returns the message reported in the status of
+// the resources that are not processed, as they depend on the given NdbCluster
+// whose reconciliation has been paused.
+func getNdbClusterPausedMessage(nc *v1.NdbCluster) string {
+	return fmt.Sprintf(MessageNdbClusterPaused, getNamespacedName(nc), v1.PausedAnnotation)
+}
+
+// execInPod executes a command inside a container of a pod. It is
+// a variable to allow the tests to replace it with a fake implementation.
+var execInPod = helpers.ExecInPod
+
 // getNamespacedName returns the name of the object
 // along with the Namespace of form <namespace>/<name>.
 func getNamespacedName(obj metav1.Object) string {
@@ -182,3 +194,24 @@ func getPodErrors(pod *corev1.Pod) (errs []string) {
 	errs = append(errs, checkContainersForError(pod.Status.ContainerStatuses, pod.Name)...)
 	return errs
 }
+
+// hasFinalizer returns true if the given object has the finalizer
+func hasFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFinalizer returns the given finalizers without the given finalizer
+func removeFinalizer(finalizers []string, finalizer string) []string {
+	var result []string
+	for _, f := range finalizers {
+		if f != finalizer {
+			result = append(result, f)
+		}
+	}
+	return result
+}
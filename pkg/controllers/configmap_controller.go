@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,7 @@ import (
 type ConfigMapControlInterface interface {
 	EnsureConfigMap(ctx context.Context, sc *SyncContext) (*corev1.ConfigMap, bool, error)
 	PatchConfigMap(ctx context.Context, sc *SyncContext) (*corev1.ConfigMap, error)
+	MarkDataNodeInitialRestart(ctx context.Context, sc *SyncContext) error
 }
 
 type configMapControl struct {
@@ -142,3 +144,25 @@ func (cmc *configMapControl) PatchConfigMap(
 	klog.Infof("Successfully patched ConfigMap %q", getNamespacedName(cmChg))
 	return result, updateErr
 }
+
+// MarkDataNodeInitialRestart updates the config map to restart the data
+// nodes with the --initial flag without changing the rest of the config.
+func (cmc *configMapControl) MarkDataNodeInitialRestart(ctx context.Context, sc *SyncContext) error {
+	nc := sc.ndb
+	configMapName := nc.GetConfigMapName()
+	cm, err := cmc.getConfigMap(nc.Namespace, configMapName)
+	if err != nil {
+		klog.Errorf("Error retrieving ConfigMap %q : %s", getNamespacedName2(nc.Namespace, configMapName), err)
+		return err
+	}
+
+	updatedCm := cm.DeepCopy()
+	updatedCm.Data[constants.DataNodeInitialRestart] = "true"
+	if _, err = cmc.getConfigMapInterface(nc.Namespace).Update(ctx, updatedCm, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("Failed to update ConfigMap %q : %s", getNamespacedName(cm), err)
+		return err
+	}
+
+	klog.Infof("Updated ConfigMap %q to restart the data nodes with the --initial flag", getNamespacedName(cm))
+	return nil
+}
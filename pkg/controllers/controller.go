@@ -8,8 +8,10 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -18,6 +20,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/events"
@@ -30,6 +33,8 @@ import (
 	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
 	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
 	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/metrics"
+	"github.com/mysql/ndb-operator/pkg/resources"
 )
 
 // Controller is the main controller implementation for Ndb resources
@@ -39,6 +44,8 @@ type Controller struct {
 
 	// NdbCluster Lister
 	ndbsLister ndblisters.NdbClusterLister
+	// NdbClusterBackup Lister
+	ndbBackupsLister ndblisters.NdbClusterBackupLister
 
 	// Controllers for various resources
 	mgmdController           *ndbNodeStatefulSetImpl
@@ -53,6 +60,7 @@ type Controller struct {
 	podLister     corelisters.PodLister
 	pvcLister     corelisters.PersistentVolumeClaimLister
 	serviceLister corelisters.ServiceLister
+	jobLister     batchlisters.JobLister
 
 	// Slice of InformerSynced methods for all the informers used by the controller
 	informerSyncedMethods []cache.InformerSynced
@@ -63,6 +71,9 @@ type Controller struct {
 	workqueue workqueue.RateLimitingInterface
 	// An event recorder for recording Event resources to the Kubernetes API.
 	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
 }
 
 // NewController returns a new Ndb controller
@@ -74,6 +85,7 @@ func NewController(
 
 	// Register for all the required informers
 	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbClusterBackupInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusterBackups()
 	statefulSetInformer := k8sSharedIndexInformer.Apps().V1().StatefulSets()
 	podInformer := k8sSharedIndexInformer.Core().V1().Pods()
 	serviceInformer := k8sSharedIndexInformer.Core().V1().Services()
@@ -81,10 +93,12 @@ func NewController(
 	secretInformer := k8sSharedIndexInformer.Core().V1().Secrets()
 	serviceAccountInformer := k8sSharedIndexInformer.Core().V1().ServiceAccounts()
 	pvcInformer := k8sSharedIndexInformer.Core().V1().PersistentVolumeClaims()
+	jobInformer := k8sSharedIndexInformer.Batch().V1().Jobs()
 
 	// Extract all the InformerSynced methods
 	informerSyncedMethods := []cache.InformerSynced{
 		ndbClusterInformer.Informer().HasSynced,
+		ndbClusterBackupInformer.Informer().HasSynced,
 		statefulSetInformer.Informer().HasSynced,
 		podInformer.Informer().HasSynced,
 		serviceInformer.Informer().HasSynced,
@@ -92,6 +106,7 @@ func NewController(
 		secretInformer.Informer().HasSynced,
 		serviceAccountInformer.Informer().HasSynced,
 		pvcInformer.Informer().HasSynced,
+		jobInformer.Informer().HasSynced,
 	}
 
 	serviceLister := serviceInformer.Lister()
@@ -105,8 +120,10 @@ func NewController(
 		ndbClient:                ndbClient,
 		informerSyncedMethods:    informerSyncedMethods,
 		ndbsLister:               ndbClusterInformer.Lister(),
+		ndbBackupsLister:         ndbClusterBackupInformer.Lister(),
 		podLister:                podInformer.Lister(),
 		pvcLister:                pvcInformer.Lister(),
+		jobLister:                jobInformer.Lister(),
 		configMapController:      NewConfigMapControl(kubernetesClient, configmapLister),
 		serviceController:        NewServiceControl(kubernetesClient, serviceLister),
 		serviceAccountController: NewServiceAccountControl(kubernetesClient, serviceAccountLister),
@@ -116,7 +133,7 @@ func NewController(
 		mgmdController:   newMgmdStatefulSetController(kubernetesClient, statefulSetLister),
 		ndbmtdController: newNdbmtdStatefulSetController(kubernetesClient, statefulSetLister, secretLister),
 		mysqldController: newMySQLDStatefulSetController(
-			kubernetesClient, statefulSetLister, configmapLister),
+			kubernetesClient, statefulSetLister, configmapLister, secretLister),
 	}
 
 	// Setup informer and controller for v1.PDB if K8s Server has the support
@@ -150,6 +167,10 @@ func NewController(
 				klog.Infof("Resource version updated from %s -> %s",
 					oldNdb.ResourceVersion, newNdb.ResourceVersion)
 				klog.Infof("NdbCluster resource %q is added to the queue for reconciliation", ndbKey)
+			} else if oldNdb.IsPaused() != newNdb.IsPaused() {
+				// The reconciliation was paused or resumed via the annotation
+				klog.Infof("Paused annotation of the NdbCluster resource %q was updated", ndbKey)
+				klog.Infof("NdbCluster resource %q is added to the queue for reconciliation", ndbKey)
 			} else if oldNdb.ResourceVersion != newNdb.ResourceVersion {
 				// Spec was not updated but the ResourceVersion changed => Status update
 				klog.V(2).Infof("Status of the NdbCluster resource '%s' was updated", ndbKey)
@@ -326,6 +347,111 @@ func NewController(
 		0,
 	)
 
+	// Set up event handlers for the Jobs that restore a backup
+	jobInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				// Filter out all Jobs not owned by any NdbCluster resources.
+				// The Job labels will have the names of their respective
+				// NdbCluster owners.
+				job := obj.(*batchv1.Job)
+				_, clusterLabelExists := job.GetLabels()[constants.ClusterLabel]
+				return clusterLabelExists
+			},
+
+			Handler: cache.ResourceEventHandlerFuncs{
+				// When a restore Job completes or fails, the
+				// NdbCluster has to be requeued to continue with
+				// the next restore step or to update its status.
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					oldJob := oldObj.(*batchv1.Job)
+					newJob := newObj.(*batchv1.Job)
+
+					if !reflect.DeepEqual(oldJob.Status.Conditions, newJob.Status.Conditions) {
+						controller.extractAndEnqueueNdbCluster(newJob, "Job", "updated")
+					}
+				},
+			},
+		},
+
+		// Set resyncPeriod to 0 to ignore all re-sync events
+		0,
+	)
+
+	// Set up event handler for NdbClusterBackup resource changes
+	ndbClusterBackupInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				// Filter out all NdbClusterBackups not owned by any
+				// NdbCluster resources. The backups taken by the
+				// operator for a redundancyLevel migration will have
+				// the names of their NdbCluster owners as labels.
+				ncb := obj.(*v1.NdbClusterBackup)
+				_, clusterLabelExists := ncb.GetLabels()[constants.ClusterLabel]
+				return clusterLabelExists
+			},
+
+			Handler: cache.ResourceEventHandlerFuncs{
+				// When the backup completes or fails, the NdbCluster
+				// has to be requeued to continue with the migration.
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					oldBackup := oldObj.(*v1.NdbClusterBackup)
+					newBackup := newObj.(*v1.NdbClusterBackup)
+
+					if oldBackup.Status.Phase != newBackup.Status.Phase {
+						controller.extractAndEnqueueNdbCluster(newBackup, "NdbClusterBackup", "updated")
+					}
+				},
+			},
+		},
+
+		// Set resyncPeriod to 0 to ignore all re-sync events
+		0,
+	)
+
+	// Set up event handlers for the root password and the TDE Secret updates
+	secretInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			// When the password in the root password Secret, the TDE Secret
+			// or the replication user Secret, which might not be owned by
+			// the NdbCluster, changes, the NdbCluster has to be requeued to
+			// update the password of the root user, to re-encrypt the data
+			// nodes or to update the password of the replication user.
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldSecret := oldObj.(*corev1.Secret)
+				newSecret := newObj.(*corev1.Secret)
+				if reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+					return
+				}
+
+				ncList, err := controller.ndbsLister.NdbClusters(newSecret.Namespace).List(labels.Everything())
+				if err != nil {
+					klog.Errorf("Failed to list the NdbClusters in namespace %q : %s", newSecret.Namespace, err)
+					return
+				}
+				for _, nc := range ncList {
+					if secretName, _ := resources.GetMySQLRootPasswordSecretName(nc); secretName == newSecret.Name {
+						klog.Infof("Root password Secret %q of NdbCluster %q was updated",
+							getNamespacedName(newSecret), getNdbClusterKey(nc))
+						controller.workqueue.Add(getNdbClusterKey(nc))
+					} else if nc.Spec.TDESecretName == newSecret.Name {
+						klog.Infof("TDE Secret %q of NdbCluster %q was updated",
+							getNamespacedName(newSecret), getNdbClusterKey(nc))
+						controller.workqueue.Add(getNdbClusterKey(nc))
+					} else if secretName, _ := resources.GetReplicationUserPasswordSecretName(nc); nc.Spec.Replication != nil &&
+						secretName == newSecret.Name {
+						klog.Infof("Replication user Secret %q of NdbCluster %q was updated",
+							getNamespacedName(newSecret), getNdbClusterKey(nc))
+						controller.workqueue.Add(getNdbClusterKey(nc))
+					}
+				}
+			},
+		},
+
+		// Set resyncPeriod to 0 to ignore all re-sync events
+		0,
+	)
+
 	return controller
 }
 
@@ -424,7 +550,9 @@ func (c *Controller) processNextWorkItem(ctx context.Context) (continueProcessin
 
 	// Run the syncHandler for the extracted key.
 	klog.Infof("Starting a reconciliation cycle for NdbCluster resource %q", key)
-	sr := c.syncHandler(ctx, key)
+	c.activeSyncs.start(key)
+	sr := c.syncHandler(c.activeSyncs.withHeartbeats(ctx, key), key)
+	c.activeSyncs.finish(key)
 	klog.Infof("Completed a reconciliation cycle for NdbCluster resource %q", key)
 
 	if err := sr.getError(); err != nil {
@@ -454,13 +582,41 @@ func (c *Controller) newSyncContext(ndb *v1.NdbCluster) *SyncContext {
 		kubernetesClient:         c.kubernetesClient,
 		ndbClient:                c.ndbClient,
 		ndbsLister:               c.ndbsLister,
+		ndbBackupsLister:         c.ndbBackupsLister,
 		podLister:                c.podLister,
 		pvcLister:                c.pvcLister,
 		serviceLister:            c.serviceLister,
+		jobLister:                c.jobLister,
 		recorder:                 c.recorder,
 	}
 }
 
+// updatePausedNdbClusterStatus updates the status of an NdbCluster resource
+// whose reconciliation has been paused, based on the current state of its
+// workloads. Nothing else is created or updated by the operator.
+func (c *Controller) updatePausedNdbClusterStatus(
+	ctx context.Context, sc *SyncContext, wasPaused bool) syncResult {
+	nc := sc.ndb
+	if err := sc.retrieveWorkloads(); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	// The MySQL Cluster is still up-to-date if
+	// the spec has not changed since the last sync.
+	sc.syncSuccess = nc.Status.ProcessedGeneration == nc.Generation
+	if _, err := sc.updateNdbClusterStatus(ctx); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	if !wasPaused {
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal,
+			ReasonReconciliationPaused, ActionNone, MessageReconciliationPaused, v1.PausedAnnotation)
+	}
+
+	// Reconciliation will continue once the annotation is removed
+	return finishProcessing()
+}
+
 // syncHandler is the main reconciliation function
 // driving cluster towards desired configuration
 //
@@ -499,6 +655,7 @@ func (c *Controller) syncHandler(ctx context.Context, key string) (result syncRe
 		if apierrors.IsNotFound(err) {
 			// Stop processing if the NdbCluster resource no longer exists
 			klog.Infof("NdbCluster resource %q does not exist anymore", key)
+			metrics.DeleteReconcileMetrics(namespace, name)
 			return finishProcessing()
 		}
 
@@ -506,12 +663,32 @@ func (c *Controller) syncHandler(ctx context.Context, key string) (result syncRe
 		return errorWhileProcessing(err)
 	}
 
+	// Record the reconciliation in the metrics once it is complete
+	defer func(startTime time.Time) {
+		metrics.ObserveReconcile(namespace, name, time.Since(startTime), result.getError())
+	}(time.Now())
+
 	// Create a syncContext with a DeepCopied NdbCluster resource
 	// to prevent the sync method from accidentally mutating the
 	// cache object.
 	nc := ndbOrg.DeepCopy()
 	syncContext := c.newSyncContext(nc)
 
+	wasPaused := nc.HasPausedCondition()
+	if nc.IsPaused() {
+		// The reconciliation has been paused by the user.
+		// Skip the sync but update the observed status.
+		klog.Infof("Reconciliation of NdbCluster resource %q is paused", key)
+		return c.updatePausedNdbClusterStatus(ctx, syncContext, wasPaused)
+	}
+
+	if wasPaused {
+		// The reconciliation was paused until the last sync
+		klog.Infof("Reconciliation of NdbCluster resource %q has been resumed", key)
+		syncContext.recorder.Eventf(nc, nil,
+			corev1.EventTypeNormal, ReasonReconciliationResumed, ActionNone, MessageReconciliationResumed)
+	}
+
 	// Run sync.
 	if result = syncContext.sync(ctx); result.getError() != nil {
 		// The sync step returned an error - no need to update status yet
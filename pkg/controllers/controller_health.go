@@ -0,0 +1,174 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// maxHeartbeatInterval is the duration after which a reconciliation,
+	// that has not reported any heartbeat, is considered to be stuck.
+	// Reconciliations running long operations, like redistributing
+	// the data of a large table, report heartbeats while they wait
+	// and are therefore never considered to be stuck.
+	maxHeartbeatInterval = 15 * time.Minute
+	// heartbeatInterval is the interval at which
+	// keepAlive reports heartbeats during a long operation.
+	heartbeatInterval = time.Minute
+)
+
+// activeSyncs tracks the reconciliations being run by the workers.
+// The zero value is ready to use.
+type activeSyncs struct {
+	mutex sync.Mutex
+	// lastHeartbeats has the time of the last heartbeat reported by
+	// the reconciliations, mapped by the key of the resource being
+	// reconciled. The start of a reconciliation is its first heartbeat.
+	lastHeartbeats map[string]time.Time
+}
+
+// start records the start of the reconciliation of the given resource
+func (as *activeSyncs) start(key string) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	if as.lastHeartbeats == nil {
+		as.lastHeartbeats = make(map[string]time.Time)
+	}
+	as.lastHeartbeats[key] = time.Now()
+}
+
+// heartbeat records that the reconciliation of the
+// given resource is still making progress
+func (as *activeSyncs) heartbeat(key string) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	if _, running := as.lastHeartbeats[key]; running {
+		as.lastHeartbeats[key] = time.Now()
+	}
+}
+
+// finish records the end of the reconciliation of the given resource
+func (as *activeSyncs) finish(key string) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	delete(as.lastHeartbeats, key)
+}
+
+// getStuckSync returns the key of a reconciliation that has not reported
+// any heartbeat for longer than the given duration, and its last heartbeat.
+func (as *activeSyncs) getStuckSync(maxDuration time.Duration) (key string, lastHeartbeat time.Time, found bool) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	for key, lastHeartbeat = range as.lastHeartbeats {
+		if time.Since(lastHeartbeat) > maxDuration {
+			return key, lastHeartbeat, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// check returns an error if any of the reconciliations of the given
+// kind of resource has not reported a heartbeat for longer than
+// maxHeartbeatInterval, which implies that its worker is stuck.
+func (as *activeSyncs) check(kind string) error {
+	if key, lastHeartbeat, found := as.getStuckSync(maxHeartbeatInterval); found {
+		return fmt.Errorf("the reconciliation of %s %q has not made any progress since %s",
+			kind, key, lastHeartbeat.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// heartbeatReporterKey is the context key of the function
+// that reports the heartbeats of the ongoing reconciliation
+type heartbeatReporterKey struct{}
+
+// withHeartbeats returns a copy of the context through which
+// the reconciliation of the given resource reports its heartbeats.
+func (as *activeSyncs) withHeartbeats(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, heartbeatReporterKey{}, func() { as.heartbeat(key) })
+}
+
+// reportHeartbeat reports a heartbeat for the reconciliation
+// running with the given context, if it is being tracked.
+func reportHeartbeat(ctx context.Context) {
+	if report, ok := ctx.Value(heartbeatReporterKey{}).(func()); ok {
+		report()
+	}
+}
+
+// keepAlive reports heartbeats for the reconciliation running with the
+// given context, every heartbeatInterval, until the returned function
+// is called. It should be used around long operations that cannot
+// report heartbeats by themselves.
+func keepAlive(ctx context.Context) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reportHeartbeat(ctx)
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// CheckInformersSynced returns an error if the caches of
+// the informers used by the controller have not synced yet
+func (c *Controller) CheckInformersSynced() error {
+	for _, hasSynced := range c.informerSyncedMethods {
+		if !hasSynced() {
+			return fmt.Errorf("the informer caches of %s have not synced yet", controllerName)
+		}
+	}
+	return nil
+}
+
+// CheckWorkers returns an error if any of the workers has been running
+// a reconciliation that has not made any progress for longer than
+// maxHeartbeatInterval, which implies that the worker is stuck.
+func (c *Controller) CheckWorkers() error {
+	return c.activeSyncs.check("NdbCluster")
+}
+
+// CheckWorkers returns an error if any of the workers of the
+// NdbClusterBackup controller is stuck in a reconciliation.
+func (bc *NdbClusterBackupController) CheckWorkers() error {
+	return bc.activeSyncs.check("NdbClusterBackup")
+}
+
+// CheckWorkers returns an error if any of the workers of the
+// NdbClusterBackupSchedule controller is stuck in a reconciliation.
+func (sc *NdbClusterBackupScheduleController) CheckWorkers() error {
+	return sc.activeSyncs.check("NdbClusterBackupSchedule")
+}
+
+// CheckWorkers returns an error if any of the workers of the
+// NdbMySQLUser controller is stuck in a reconciliation.
+func (uc *NdbMySQLUserController) CheckWorkers() error {
+	return uc.activeSyncs.check("NdbMySQLUser")
+}
+
+// CheckWorkers returns an error if any of the workers of the
+// NdbMySQLDatabase controller is stuck in a reconciliation.
+func (dc *NdbMySQLDatabaseController) CheckWorkers() error {
+	return dc.activeSyncs.check("NdbMySQLDatabase")
+}
+
+// CheckWorkers returns an error if any of the workers of the
+// NdbReplicationChannel controller is stuck in a reconciliation.
+func (rc *NdbReplicationChannelController) CheckWorkers() error {
+	return rc.activeSyncs.check("NdbReplicationChannel")
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckWorkers(t *testing.T) {
+	c := &Controller{}
+	if err := c.CheckWorkers(); err != nil {
+		t.Errorf("Expected idle workers to be healthy but got : %s", err)
+	}
+
+	c.activeSyncs.start("default/example-ndb")
+	if err := c.CheckWorkers(); err != nil {
+		t.Errorf("Expected a worker running a new reconciliation to be healthy but got : %s", err)
+	}
+
+	// Simulate a reconciliation that has not made any progress for too long
+	c.activeSyncs.lastHeartbeats["default/example-ndb"] = time.Now().Add(-2 * maxHeartbeatInterval)
+	if err := c.CheckWorkers(); err == nil {
+		t.Error("Expected a worker running a stuck reconciliation to be reported as unhealthy")
+	}
+
+	// A long reconciliation that reports a heartbeat is not stuck
+	reportHeartbeat(c.activeSyncs.withHeartbeats(context.Background(), "default/example-ndb"))
+	if err := c.CheckWorkers(); err != nil {
+		t.Errorf("Expected a worker running a long reconciliation that reports heartbeats to be healthy but got : %s", err)
+	}
+
+	c.activeSyncs.finish("default/example-ndb")
+	if err := c.CheckWorkers(); err != nil {
+		t.Errorf("Expected the workers to be healthy after the reconciliation finished but got : %s", err)
+	}
+
+	// A heartbeat reported after the reconciliation finished is ignored
+	reportHeartbeat(c.activeSyncs.withHeartbeats(context.Background(), "default/example-ndb"))
+	if len(c.activeSyncs.lastHeartbeats) != 0 {
+		t.Error("Expected a heartbeat of a finished reconciliation to be ignored")
+	}
+}
+
+func TestCheckWorkersOfNdbClusterBackupController(t *testing.T) {
+	bc := &NdbClusterBackupController{}
+	bc.activeSyncs.start("default/example-backup")
+	bc.activeSyncs.lastHeartbeats["default/example-backup"] = time.Now().Add(-2 * maxHeartbeatInterval)
+	if err := bc.CheckWorkers(); err == nil {
+		t.Error("Expected a worker running a stuck NdbClusterBackup reconciliation to be reported as unhealthy")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
 	klog "k8s.io/klog/v2"
 
 	ndbcontroller "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -341,6 +342,8 @@ func filterInformerActions(actions []core.Action) []core.Action {
 		if len(action.GetNamespace()) == 0 &&
 			(action.Matches("list", "ndbclusters") ||
 				action.Matches("watch", "ndbclusters") ||
+				action.Matches("list", "ndbclusterbackups") ||
+				action.Matches("watch", "ndbclusterbackups") ||
 				action.Matches("list", "pods") ||
 				action.Matches("watch", "pods") ||
 				action.Matches("list", "configmaps") ||
@@ -357,6 +360,8 @@ func filterInformerActions(actions []core.Action) []core.Action {
 				action.Matches("watch", "statefulsets") ||
 				action.Matches("list", "secrets") ||
 				action.Matches("watch", "secrets") ||
+				action.Matches("list", "jobs") ||
+				action.Matches("watch", "jobs") ||
 				action.Matches("list", "validatingwebhookconfigurations")) {
 			//klog.Infof("Filtering +%v", action)
 			continue
@@ -515,3 +520,30 @@ func TestCreatesCluster(t *testing.T) {
 	// The reconciliation loop ends here.
 	f.runControllerAndValidateActions(ndb, false, nil)
 }
+
+func TestPausedCluster(t *testing.T) {
+	ns := metav1.NamespaceDefault
+	ndb := testutils.NewTestNdb(ns, "test", 2)
+	ndb.Annotations = map[string]string{ndbcontroller.PausedAnnotation: "true"}
+
+	f := newFixture(t, ndb)
+	defer f.close()
+	f.newController()
+	f.c.recorder = events.NewFakeRecorder(10)
+
+	// None of the resources are created - only the status is updated
+	f.expectNdbClusterStatusUpdateAction(ns, "mysql.oracle.com", "v1", "ndbclusters")
+	f.runControllerAndValidateActions(ndb, false, nil)
+
+	nc, err := f.ndbclient.MysqlV1().NdbClusters(ns).Get(context.TODO(), ndb.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbCluster : %s", err)
+	}
+	if !nc.HasPausedCondition() {
+		t.Errorf("Expected the status to have the %s condition set but got %+v",
+			ndbcontroller.NdbClusterPaused, nc.Status.Conditions)
+	}
+	if !hasRecordedEvent(f.c.recorder, ReasonReconciliationPaused) {
+		t.Errorf("Expected a %s event", ReasonReconciliationPaused)
+	}
+}
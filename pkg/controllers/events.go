@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -28,6 +28,16 @@ const (
 	// ReasonInSync is the reason used for an Event when the MySQL Cluster
 	// is already in sync with the spec of the Ndb object.
 	ReasonInSync = "InSync"
+	// ReasonReconciliationPaused is the reason used for an Event when the
+	// reconciliation of the Ndb object is paused via an annotation.
+	ReasonReconciliationPaused = "ReconciliationPaused"
+	// ReasonReconciliationResumed is the reason used for an Event when
+	// the paused reconciliation of the Ndb object is resumed.
+	ReasonReconciliationResumed = "ReconciliationResumed"
+	// ReasonNdbClusterPaused is the reason used for an Event when a resource
+	// that depends on an Ndb object is not processed as the reconciliation
+	// of the Ndb object is paused via an annotation.
+	ReasonNdbClusterPaused = "NdbClusterPaused"
 
 	// ActionNone is the action used for an Event when the operator does nothing.
 	ActionNone = "None"
@@ -47,6 +57,291 @@ const (
 	// MessageInSync is the message used for an Event when the MySQL Cluster
 	// is already in sync with the spec of the Ndb object.
 	MessageInSync = "MySQL Cluster is in sync with the Ndb object"
+	// MessageReconciliationPaused is the message used for an Event when the
+	// reconciliation of the Ndb object is paused via an annotation.
+	MessageReconciliationPaused = "Reconciliation has been paused via the %s annotation"
+	// MessageReconciliationResumed is the message used for an Event when
+	// the paused reconciliation of the Ndb object is resumed.
+	MessageReconciliationResumed = "Reconciliation has been resumed"
+	// MessageNdbClusterPaused is the message used for an Event when a resource
+	// that depends on an Ndb object is not processed as the reconciliation
+	// of the Ndb object is paused via an annotation.
+	MessageNdbClusterPaused = "Reconciliation of NdbCluster %q has been paused via the %s annotation"
+)
+
+// Events recorded for the NdbClusterBackup resources
+const (
+	// ReasonBackupStarted is the reason used for an Event when
+	// the MySQL Cluster data nodes start the requested backup.
+	ReasonBackupStarted = "BackupStarted"
+	// ReasonBackupCompleted is the reason used for an Event when
+	// the requested backup is successfully completed.
+	ReasonBackupCompleted = "BackupCompleted"
+	// ReasonBackupFailed is the reason used for an Event when the
+	// requested backup fails to start, is aborted or times out.
+	ReasonBackupFailed = "BackupFailed"
+
+	// ActionBackup is the action used for the Events
+	// recorded for the NdbClusterBackup resources.
+	ActionBackup = "Backup"
+
+	// MessageBackupStarted is the message used for an Event when
+	// the MySQL Cluster data nodes start the requested backup.
+	MessageBackupStarted = "Backup %d of NdbCluster %q started"
+	// MessageBackupCompleted is the message used for an Event when
+	// the requested backup is successfully completed.
+	MessageBackupCompleted = "Backup %d of NdbCluster %q completed"
+)
+
+// Events recorded for the NdbClusterBackupSchedule resources
+const (
+	// ReasonBackupScheduled is the reason used for an Event
+	// when a new NdbClusterBackup is created by the schedule.
+	ReasonBackupScheduled = "BackupScheduled"
+	// ReasonBackupSkipped is the reason used for an Event when a
+	// scheduled backup is skipped as the previous one is still running.
+	ReasonBackupSkipped = "BackupSkipped"
+	// ReasonBackupPruned is the reason used for an Event when an
+	// NdbClusterBackup and its backup files are deleted as per the
+	// retention policy of the schedule.
+	ReasonBackupPruned = "BackupPruned"
+	// ReasonInvalidSchedule is the reason used for an Event
+	// when the schedule of the NdbClusterBackupSchedule is invalid.
+	ReasonInvalidSchedule = "InvalidSchedule"
+
+	// ActionSchedule is the action used for the Events
+	// recorded for the NdbClusterBackupSchedule resources.
+	ActionSchedule = "Schedule"
+
+	// MessageBackupScheduled is the message used for an Event
+	// when a new NdbClusterBackup is created by the schedule.
+	MessageBackupScheduled = "Created NdbClusterBackup %q"
+	// MessageBackupSkipped is the message used for an Event when a
+	// scheduled backup is skipped as the previous one is still running.
+	MessageBackupSkipped = "Skipped the backup scheduled at %s as NdbClusterBackup %q is still running"
+	// MessageBackupPruned is the message used for an Event when an
+	// NdbClusterBackup and its backup files are deleted as per the
+	// retention policy of the schedule.
+	MessageBackupPruned = "Deleted NdbClusterBackup %q and its backup files"
+)
+
+// Events recorded for the rotation of the MySQL root and NDB Operator user passwords
+const (
+	// ReasonPasswordRotated is the reason used for an Event
+	// when the password of a MySQL user is rotated.
+	ReasonPasswordRotated = "PasswordRotated"
+	// ReasonOldPasswordDiscarded is the reason used for an Event when
+	// the old password of a rotated MySQL user is discarded.
+	ReasonOldPasswordDiscarded = "OldPasswordDiscarded"
+
+	// ActionRotate is the action used for the Events
+	// recorded for the rotation of the passwords.
+	ActionRotate = "Rotate"
+
+	// MessagePasswordRotated is the message used for an Event
+	// when the password of a MySQL user is rotated.
+	MessagePasswordRotated = "Password of the %s was rotated, the old password will be discarded at %s"
+	// MessageOldPasswordDiscarded is the message used for an Event when
+	// the old password of a rotated MySQL user is discarded.
+	MessageOldPasswordDiscarded = "Old password of the %s was discarded"
+)
+
+// Events recorded for the NdbMySQLUser and NdbMySQLDatabase resources
+const (
+	// ReasonMySQLUserSynced is the reason used for an Event when the
+	// MySQL user is created or updated as per the NdbMySQLUser spec.
+	ReasonMySQLUserSynced = "MySQLUserSynced"
+	// ReasonMySQLUserSyncFailed is the reason used for an Event when the
+	// MySQL user cannot be created or updated as per the NdbMySQLUser spec.
+	ReasonMySQLUserSyncFailed = "MySQLUserSyncFailed"
+	// ReasonMySQLUserDrifted is the reason used for an Event when the
+	// grants of the MySQL user are found to have been modified outside
+	// the operator.
+	ReasonMySQLUserDrifted = "MySQLUserDrifted"
+	// ReasonMySQLUserRejected is the reason used for an Event when the
+	// NdbMySQLUser declares a user reserved for the MySQL Server or the
+	// NDB Operator.
+	ReasonMySQLUserRejected = "MySQLUserRejected"
+	// ReasonMySQLDatabaseSynced is the reason used for an Event when the
+	// database is created or altered as per the NdbMySQLDatabase spec.
+	ReasonMySQLDatabaseSynced = "MySQLDatabaseSynced"
+	// ReasonMySQLDatabaseSyncFailed is the reason used for an Event when the
+	// database cannot be created or altered as per the NdbMySQLDatabase spec.
+	ReasonMySQLDatabaseSyncFailed = "MySQLDatabaseSyncFailed"
+	// ReasonMySQLDatabaseDrifted is the reason used for an Event when the
+	// database is found to have been dropped or altered outside the operator.
+	ReasonMySQLDatabaseDrifted = "MySQLDatabaseDrifted"
+
+	// ActionReconcile is the action used for the Events recorded
+	// for the NdbMySQLUser and NdbMySQLDatabase resources.
+	ActionReconcile = "Reconcile"
+
+	// MessageMySQLUserSynced is the message used for an Event when the
+	// MySQL user is created or updated as per the NdbMySQLUser spec.
+	MessageMySQLUserSynced = "MySQL user %s was successfully synced up to match the spec"
+	// MessageMySQLUserDrifted is the message used for an Event when the
+	// grants of the MySQL user are found to have been modified outside
+	// the operator.
+	MessageMySQLUserDrifted = "Grants of MySQL user %s were modified outside the operator and will be reapplied"
+	// MessageMySQLUserRejected is the message used for an Event when the
+	// NdbMySQLUser declares a user reserved for the MySQL Server or the
+	// NDB Operator.
+	MessageMySQLUserRejected = "MySQL user %q is reserved for the MySQL Server and the NDB Operator and will not be managed"
+	// MessageMySQLDatabaseSynced is the message used for an Event when the
+	// database is created or altered as per the NdbMySQLDatabase spec.
+	MessageMySQLDatabaseSynced = "Database %q was successfully synced up to match the spec"
+	// MessageMySQLDatabaseDrifted is the message used for an Event when the
+	// database is found to have been dropped or altered outside the operator.
+	MessageMySQLDatabaseDrifted = "Database %q was dropped or altered outside the operator and has been recreated"
+)
+
+// Events recorded for the restore of the backup specified in spec.restoreFrom
+const (
+	// ReasonRestoreStarted is the reason used for an Event when
+	// a step of the restore is started.
+	ReasonRestoreStarted = "RestoreStarted"
+	// ReasonRestoreCompleted is the reason used for an Event
+	// when the backup is completely restored.
+	ReasonRestoreCompleted = "RestoreCompleted"
+	// ReasonRestoreFailed is the reason used for an Event
+	// when a step of the restore fails.
+	ReasonRestoreFailed = "RestoreFailed"
+
+	// ActionRestore is the action used for the Events
+	// recorded for the restore of a backup.
+	ActionRestore = "Restore"
+
+	// MessageRestoreStarted is the message used for an Event when
+	// a step of the restore is started.
+	MessageRestoreStarted = "Started Job %q to restore backup %d"
+	// MessageRestoreCompleted is the message used for an Event
+	// when the backup is completely restored.
+	MessageRestoreCompleted = "Backup %d was successfully restored"
+	// MessageRestoreFailed is the message used for an Event
+	// when a step of the restore fails.
+	MessageRestoreFailed = "Job %q failed to restore backup %d"
+)
+
+// Events recorded for the migration of the MySQL Cluster to a new redundancyLevel
+const (
+	// ReasonRedundancyLevelMigrationStarted is the reason used for an Event
+	// when the migration to a new redundancyLevel is started.
+	ReasonRedundancyLevelMigrationStarted = "RedundancyLevelMigrationStarted"
+	// ReasonRedundancyLevelMigrationCompleted is the reason used for an
+	// Event when the migration to a new redundancyLevel is completed.
+	ReasonRedundancyLevelMigrationCompleted = "RedundancyLevelMigrationCompleted"
+	// ReasonRedundancyLevelMigrationAborted is the reason used for an Event
+	// when the migration to a new redundancyLevel is aborted by the user.
+	ReasonRedundancyLevelMigrationAborted = "RedundancyLevelMigrationAborted"
+	// ReasonRedundancyLevelMigrationFailed is the reason used for an Event
+	// when the backup or the restore of the backup fails during the migration.
+	ReasonRedundancyLevelMigrationFailed = "RedundancyLevelMigrationFailed"
+
+	// ActionMigrate is the action used for the Events recorded
+	// for the migration of the MySQL Cluster to a new redundancyLevel.
+	ActionMigrate = "Migrate"
+
+	// MessageRedundancyLevelMigrationStarted is the message used for an
+	// Event when the migration to a new redundancyLevel is started.
+	MessageRedundancyLevelMigrationStarted = "Started migrating the MySQL Cluster from redundancyLevel %d to %d"
+	// MessageRedundancyLevelMigrationCompleted is the message used for an
+	// Event when the migration to a new redundancyLevel is completed.
+	MessageRedundancyLevelMigrationCompleted = "MySQL Cluster was successfully migrated to redundancyLevel %d"
+	// MessageRedundancyLevelMigrationAborted is the message used for an Event
+	// when the migration to a new redundancyLevel is aborted by the user.
+	MessageRedundancyLevelMigrationAborted = "Migration to redundancyLevel %d was aborted"
+)
+
+// Events recorded for the rotation of the TDE file system password
+const (
+	// ReasonTDEPasswordRotationStarted is the reason used for an Event when
+	// the data nodes start re-encrypting their file systems with a new password.
+	ReasonTDEPasswordRotationStarted = "TDEPasswordRotationStarted"
+	// ReasonTDEPasswordRotationCompleted is the reason used for an Event when
+	// all the data nodes have re-encrypted their file systems with the new password.
+	ReasonTDEPasswordRotationCompleted = "TDEPasswordRotationCompleted"
+	// ReasonTDEPasswordRotationBlocked is the reason used for an Event when
+	// the password has changed but the data nodes cannot be re-encrypted.
+	ReasonTDEPasswordRotationBlocked = "TDEPasswordRotationBlocked"
+
+	// MessageTDEPasswordRotationStarted is the message used for an Event when
+	// the data nodes start re-encrypting their file systems with a new password.
+	MessageTDEPasswordRotationStarted = "Password in Secret %q has changed, " +
+		"restarting the data nodes with the --initial flag to re-encrypt their file systems"
+	// MessageTDEPasswordRotationCompleted is the message used for an Event when
+	// all the data nodes have re-encrypted their file systems with the new password.
+	MessageTDEPasswordRotationCompleted = "File systems of all the data nodes were re-encrypted with the password in Secret %q"
+	// MessageTDEPasswordRotationBlocked is the message used for an Event when
+	// the password has changed but the data nodes cannot be re-encrypted.
+	MessageTDEPasswordRotationBlocked = "Password in Secret %q cannot be changed as the data nodes of " +
+		"a MySQL Cluster with redundancyLevel 1 cannot be re-encrypted without losing their data, " +
+		"restore the previous password"
+)
+
+// Events recorded for the NdbReplicationChannel resources
+const (
+	// ReasonReplicationChannelStarted is the reason used for an Event when the
+	// replication channel is (re)started in the replica MySQL Server.
+	ReasonReplicationChannelStarted = "ReplicationChannelStarted"
+	// ReasonReplicationChannelSetupFailed is the reason used for an Event when
+	// the replication channel cannot be started in the replica MySQL Server.
+	ReasonReplicationChannelSetupFailed = "ReplicationChannelSetupFailed"
+	// ReasonReplicationChannelUnhealthy is the reason used for an Event
+	// when the receiver or the applier thread of the channel stops.
+	ReasonReplicationChannelUnhealthy = "ReplicationChannelUnhealthy"
+	// ReasonReplicationChannelHealthy is the reason used for an Event when
+	// both the threads of an unhealthy channel are running again.
+	ReasonReplicationChannelHealthy = "ReplicationChannelHealthy"
+	// ReasonReplicationChannelFailover is the reason used for an Event when the
+	// channel is switched to another binlog MySQL Server of the source.
+	ReasonReplicationChannelFailover = "ReplicationChannelFailover"
+
+	// ActionReplicate is the action used for the Events
+	// recorded for the NdbReplicationChannel resources.
+	ActionReplicate = "Replicate"
+
+	// MessageReplicationChannelStarted is the message used for an Event when the
+	// replication channel is (re)started in the replica MySQL Server.
+	MessageReplicationChannelStarted = "Started replicating from %s after epoch %d"
+	// MessageReplicationChannelUnhealthy is the message used for an Event
+	// when the receiver or the applier thread of the channel stops.
+	MessageReplicationChannelUnhealthy = "Replication from %s has stopped : %s"
+	// MessageReplicationChannelHealthy is the message used for an Event when
+	// both the threads of an unhealthy channel are running again.
+	MessageReplicationChannelHealthy = "Replication from %s is running again"
+	// MessageReplicationChannelFailover is the message used for an Event when the
+	// channel is switched to another binlog MySQL Server of the source.
+	MessageReplicationChannelFailover = "Switching replication from %s to %s : %s"
+)
+
+// Events recorded for the suspension of the NdbCluster via spec.suspended
+const (
+	// ReasonSuspending is the reason used for an Event when the
+	// operator starts stopping the MySQL Cluster nodes.
+	ReasonSuspending = "Suspending"
+	// ReasonSuspended is the reason used for an Event when
+	// all the MySQL Cluster nodes have been stopped.
+	ReasonSuspended = "Suspended"
+	// ReasonResuming is the reason used for an Event when the
+	// operator starts the nodes of a suspended MySQL Cluster.
+	ReasonResuming = "Resuming"
+
+	// ActionSuspend is the action used for the Events
+	// recorded for the suspension of the NdbCluster.
+	ActionSuspend = "Suspend"
+	// ActionResume is the action used for the Events
+	// recorded for the resumption of the NdbCluster.
+	ActionResume = "Resume"
+
+	// MessageSuspending is the message used for an Event when the
+	// operator starts stopping the MySQL Cluster nodes.
+	MessageSuspending = "Stopping all the MySQL Cluster nodes as spec.suspended is set"
+	// MessageSuspended is the message used for an Event when
+	// all the MySQL Cluster nodes have been stopped.
+	MessageSuspended = "All the MySQL Cluster nodes have been stopped"
+	// MessageResuming is the message used for an Event when the
+	// operator starts the nodes of a suspended MySQL Cluster.
+	MessageResuming = "Starting the MySQL Cluster nodes via a system restart"
 )
 
 // reporting controller for the events
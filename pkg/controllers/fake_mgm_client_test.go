@@ -0,0 +1,171 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+// fakeMgmServer holds the state of a fake Management Server
+// shared by all the fakeMgmClients connected to it.
+type fakeMgmServer struct {
+	lock sync.Mutex
+	// clusterStatus is returned by GetStatus
+	clusterStatus mgmapi.ClusterStatus
+	// lastBackupId is the id of the last started backup
+	lastBackupId int
+	// backupEvents are delivered to the backup event listeners
+	backupEvents chan *mgmapi.BackupEvent
+	// calls has the commands executed by the clients, e.g. "AbortBackup 1"
+	calls []string
+	// connections is the number of clients connected via newMgmClient
+	connections int
+}
+
+// newFakeMgmServer returns a fakeMgmServer with the given cluster status
+// and makes newMgmClient connect to it until the end of the test.
+func newFakeMgmServer(t *testing.T, clusterStatus mgmapi.ClusterStatus) *fakeMgmServer {
+	t.Helper()
+
+	fms := &fakeMgmServer{
+		clusterStatus: clusterStatus,
+		backupEvents:  make(chan *mgmapi.BackupEvent, 10),
+	}
+
+	orgNewMgmClient := newMgmClient
+	newMgmClient = func(context.Context, kubernetes.Interface, *v1.NdbCluster, ...int) (mgmapi.MgmClient, error) {
+		fms.lock.Lock()
+		fms.connections++
+		fms.lock.Unlock()
+		return &fakeMgmClient{fms: fms, disconnected: make(chan struct{})}, nil
+	}
+	t.Cleanup(func() { newMgmClient = orgNewMgmClient })
+
+	return fms
+}
+
+// recordCall records the given command in the calls
+func (fms *fakeMgmServer) recordCall(format string, a ...interface{}) {
+	fms.lock.Lock()
+	defer fms.lock.Unlock()
+	fms.calls = append(fms.calls, fmt.Sprintf(format, a...))
+}
+
+// hasCall returns true if the given command was executed by any client
+func (fms *fakeMgmServer) hasCall(call string) bool {
+	fms.lock.Lock()
+	defer fms.lock.Unlock()
+	for _, c := range fms.calls {
+		if c == call {
+			return true
+		}
+	}
+	return false
+}
+
+// fakeMgmClient is a fake mgmapi.MgmClient connected to a fakeMgmServer.
+// The methods not implemented here panic when called.
+type fakeMgmClient struct {
+	mgmapi.MgmClient
+	fms          *fakeMgmServer
+	disconnected chan struct{}
+	once         sync.Once
+}
+
+func (fmc *fakeMgmClient) Disconnect() {
+	fmc.once.Do(func() { close(fmc.disconnected) })
+}
+
+func (fmc *fakeMgmClient) GetStatus() (mgmapi.ClusterStatus, error) {
+	fmc.fms.lock.Lock()
+	defer fmc.fms.lock.Unlock()
+	return fmc.fms.clusterStatus, nil
+}
+
+func (fmc *fakeMgmClient) StopNodes(nodeIds []int) error {
+	fmc.fms.lock.Lock()
+	for _, nodeId := range nodeIds {
+		fmc.fms.clusterStatus[nodeId].IsConnected = false
+	}
+	fmc.fms.lock.Unlock()
+
+	fmc.fms.recordCall("StopNodes %v", nodeIds)
+	return nil
+}
+
+func (fmc *fakeMgmClient) CreateNodeGroup(nodeIds []int) (int, error) {
+	fmc.fms.lock.Lock()
+	nodeGroup := 0
+	for _, ns := range fmc.fms.clusterStatus {
+		if ns.IsDataNode() && ns.NodeGroup >= nodeGroup && ns.NodeGroup != mgmapi.NodeGroupNewDisconnectedDataNode {
+			nodeGroup = ns.NodeGroup + 1
+		}
+	}
+	for _, nodeId := range nodeIds {
+		fmc.fms.clusterStatus[nodeId].NodeGroup = nodeGroup
+	}
+	fmc.fms.lock.Unlock()
+
+	fmc.fms.recordCall("CreateNodeGroup %v", nodeIds)
+	return nodeGroup, nil
+}
+
+func (fmc *fakeMgmClient) StartBackup(backupId int) (int, error) {
+	fmc.fms.lock.Lock()
+	if backupId == 0 {
+		backupId = fmc.fms.lastBackupId + 1
+	}
+	fmc.fms.lastBackupId = backupId
+	fmc.fms.lock.Unlock()
+
+	fmc.fms.recordCall("StartBackup %d", backupId)
+	return backupId, nil
+}
+
+func (fmc *fakeMgmClient) AbortBackup(backupId int) error {
+	fmc.fms.recordCall("AbortBackup %d", backupId)
+	return nil
+}
+
+func (fmc *fakeMgmClient) ListenBackupEvents() error {
+	return nil
+}
+
+func (fmc *fakeMgmClient) ReadBackupEvent(timeout time.Duration) (*mgmapi.BackupEvent, error) {
+	select {
+	case event := <-fmc.fms.backupEvents:
+		return event, nil
+	case <-time.After(timeout):
+		// Same error as a read from a connection past its deadline
+		return nil, os.ErrDeadlineExceeded
+	case <-fmc.disconnected:
+		return nil, errors.New("use of closed network connection")
+	}
+}
+
+// fakeExecInPod makes execInPod call the given function, with the pod
+// name and the command, instead of executing the command in the pod.
+func fakeExecInPod(t *testing.T, exec func(podName string, cmd []string) (string, error)) {
+	t.Helper()
+
+	orgExecInPod := execInPod
+	execInPod = func(_ context.Context, _ kubernetes.Interface, _ *restclient.Config,
+		_, podName, _ string, cmd []string) (string, error) {
+		return exec(podName, cmd)
+	}
+	t.Cleanup(func() { execInPod = orgExecInPod })
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+)
+
+// newFakeMySQLServer starts a fake MySQL Server, that answers the queries via
+// the given handler, at the given host and makes the controllers connect to
+// it until the end of the test. An empty host matches all the hosts.
+func newFakeMySQLServer(t *testing.T, host string, handler testutils.FakeSQLHandler) *testutils.FakeSQLServer {
+	t.Helper()
+
+	orgDriverName := mysqlclient.SQLDriverName
+	mysqlclient.SQLDriverName = testutils.FakeSQLDriverName
+	t.Cleanup(func() {
+		testutils.StopFakeSQLServers()
+		mysqlclient.SQLDriverName = orgDriverName
+	})
+
+	return testutils.NewFakeSQLServer(host, handler)
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
+)
+
+// newMgmClient connects to the Management Servers of the given NdbCluster.
+// If TLS is enabled via spec.tls, the connection is made over TLS using a
+// client certificate signed by the cluster CA. It is a variable to allow
+// the tests to replace it with a fake Management Server client.
+var newMgmClient = func(ctx context.Context, client kubernetes.Interface,
+	nc *v1.NdbCluster, desiredNodeId ...int) (mgmapi.MgmClient, error) {
+
+	var caSecret *corev1.Secret
+	if nc.Spec.TLS != nil {
+		secretName, _ := ndbtls.GetCASecretName(nc)
+		var err error
+		caSecret, err = client.CoreV1().Secrets(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("Failed to retrieve the CA Secret %q : %s", secretName, err)
+			return nil, err
+		}
+	}
+
+	return ndbtls.NewMgmClient(nc, caSecret, desiredNodeId...)
+}
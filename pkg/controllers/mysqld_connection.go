@@ -0,0 +1,56 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// errNoMySQLServers is returned when the NdbCluster has no MySQL Servers
+var errNoMySQLServers = errors.New("NdbCluster has no MySQL Servers")
+
+// connectToMySQLServer opens a connection, as the NDB Operator user,
+// to the first MySQL Server of the given NdbCluster. errNoMySQLServers
+// is returned if the NdbCluster has no MySQL Servers.
+func connectToMySQLServer(
+	ctx context.Context, kubernetesClient kubernetes.Interface,
+	sfsetLister appslisters.StatefulSetLister, nc *v1.NdbCluster) (*sql.DB, error) {
+
+	mysqldSfset, err := sfsetLister.StatefulSets(nc.Namespace).Get(nc.GetWorkloadName(constants.NdbNodeTypeMySQLD))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errNoMySQLServers
+		}
+		return nil, err
+	}
+
+	if mysqldSfset.Spec.Replicas == nil || *mysqldSfset.Spec.Replicas == 0 {
+		return nil, errNoMySQLServers
+	}
+
+	if mysqldSfset.Status.ReadyReplicas == 0 {
+		return nil, fmt.Errorf("MySQL Servers of NdbCluster %q are not ready yet", getNamespacedName(nc))
+	}
+
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(kubernetesClient).ExtractPassword(
+		ctx, nc.Namespace, resources.GetMySQLNDBOperatorPasswordSecretName(nc))
+	if err != nil {
+		return nil, err
+	}
+
+	return mysqlclient.ConnectToStatefulSet(mysqldSfset, "", operatorPassword)
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	"github.com/mysql/ndb-operator/pkg/cron"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+const (
+	// rootPasswordVersion is the annotation key which stores the resource
+	// version of the root password Secret applied to the Root user
+	rootPasswordVersion = ndbcontroller.GroupName + "/root-password-version"
+	// rootPasswordRetainedUntil is the annotation key which stores the
+	// time until which the old password of the Root user is retained
+	rootPasswordRetainedUntil = ndbcontroller.GroupName + "/root-password-retained-until"
+
+	rootUserDesc        = "MySQL root user"
+	ndbOperatorUserDesc = "NDB Operator user"
+)
+
+// passwordRotationDue returns true if the password in the given Secret
+// has to be rotated as per the password rotation schedule of the NdbCluster
+func passwordRotationDue(schedule string, secret *corev1.Secret, now time.Time) bool {
+	if schedule == "" {
+		return false
+	}
+
+	// The schedule has already been validated
+	cronSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return false
+	}
+
+	nextRotationTime := cronSchedule.Next(resources.GetPasswordRotationTime(secret).UTC())
+	return !nextRotationTime.IsZero() && !nextRotationTime.After(now)
+}
+
+// reconcilePasswords rotates the passwords of the Root user and the NDB
+// Operator user when they are due as per the password rotation schedule,
+// applies any new password set in the root password Secret to the Root
+// user and discards the old passwords once their retention period is over.
+func (mssc *mysqldStatefulSetController) reconcilePasswords(ctx context.Context, sc *SyncContext) syncResult {
+	mysqldSfset := sc.mysqldSfset
+	if mysqldSfset == nil || *mysqldSfset.Spec.Replicas == 0 {
+		// Nothing to do as the MySQL Servers do not exist
+		return continueProcessing()
+	}
+
+	if _, exists := mysqldSfset.GetAnnotations()[rootHost]; !exists {
+		// Root user has not been created yet
+		return continueProcessing()
+	}
+
+	now := time.Now()
+	if sr := mssc.reconcileNDBOperatorPassword(ctx, sc, now); sr.stopSync() {
+		return sr
+	}
+
+	return mssc.reconcileRootPassword(ctx, sc, now)
+}
+
+// reconcileRootPassword updates the password of the Root user when the
+// password in the root password Secret changes, retaining the old password
+// for a while, and then discards the old password.
+func (mssc *mysqldStatefulSetController) reconcileRootPassword(
+	ctx context.Context, sc *SyncContext, now time.Time) syncResult {
+	nc := sc.ndb
+	mysqldSfset := sc.mysqldSfset
+
+	secretName, customSecret := resources.GetMySQLRootPasswordSecretName(nc)
+	// Read the Secret from the API Server as the cached
+	// Secret might not have the recently rotated password
+	secretInterface := mssc.client.CoreV1().Secrets(nc.Namespace)
+	secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("Failed to retrieve Secret %q : %s", secretName, err)
+		return errorWhileProcessing(err)
+	}
+
+	if !customSecret && passwordRotationDue(nc.GetPasswordRotationSchedule(), secret, now) {
+		// Generate a new password. It is applied to the Root user
+		// below as the resource version of the Secret changes.
+		klog.Infof("Generating a new password in Secret %q as per the password rotation schedule",
+			getNamespacedName(secret))
+		secret, err = secretInterface.Update(
+			ctx, resources.RotateSecretPassword(secret, false, now), metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to update Secret %q : %s", secretName, err)
+			return errorWhileProcessing(err)
+		}
+	}
+
+	annotations := mysqldSfset.GetAnnotations()
+	updatedMysqldSfset := mysqldSfset.DeepCopy()
+	appliedVersion, exists := annotations[rootPasswordVersion]
+	if !exists {
+		// The Root user was created with the current password
+		updatedMysqldSfset.Annotations[rootPasswordVersion] = secret.ResourceVersion
+		return mssc.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset)
+	}
+
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(mssc.client).ExtractPassword(
+		ctx, nc.Namespace, resources.GetMySQLNDBOperatorPasswordSecretName(nc))
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	rootAccount := mysqlclient.RootUserAccount(annotations[rootHost])
+	if appliedVersion != secret.ResourceVersion {
+		// The password in the Secret has changed
+		err = execWithPassword(mysqldSfset, 0, operatorPassword, func(db *sql.DB) error {
+			return mysqlclient.RotatePassword(ctx, db, rootAccount, string(secret.Data[corev1.BasicAuthPasswordKey]))
+		})
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		retainedUntil := now.Add(nc.GetOldPasswordRetentionDuration()).UTC()
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonPasswordRotated, ActionRotate,
+			MessagePasswordRotated, rootUserDesc, retainedUntil.Format(time.RFC3339))
+		updatedMysqldSfset.Annotations[rootPasswordVersion] = secret.ResourceVersion
+		updatedMysqldSfset.Annotations[rootPasswordRetainedUntil] = retainedUntil.Format(time.RFC3339)
+		return mssc.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset)
+	}
+
+	retainedUntilString, exists := annotations[rootPasswordRetainedUntil]
+	if !exists {
+		// No old password to discard
+		return continueProcessing()
+	}
+
+	if retainedUntil, err := time.Parse(time.RFC3339, retainedUntilString); err == nil && now.Before(retainedUntil) {
+		// Old password has to be retained for some more time
+		return continueProcessing()
+	}
+
+	err = execWithPassword(mysqldSfset, 0, operatorPassword, func(db *sql.DB) error {
+		return mysqlclient.DiscardOldPassword(ctx, db, rootAccount)
+	})
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal,
+		ReasonOldPasswordDiscarded, ActionRotate, MessageOldPasswordDiscarded, rootUserDesc)
+	delete(updatedMysqldSfset.Annotations, rootPasswordRetainedUntil)
+	return mssc.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset)
+}
+
+// reconcileNDBOperatorPassword rotates the password of the NDB Operator
+// user as per the password rotation schedule and then discards the old
+// password once its retention period is over. The old password is kept
+// in the NDB Operator password Secret, along with the new password, until
+// it is discarded, so that the operator can always connect to the MySQL
+// Servers even if the rotation is interrupted.
+func (mssc *mysqldStatefulSetController) reconcileNDBOperatorPassword(
+	ctx context.Context, sc *SyncContext, now time.Time) syncResult {
+	nc := sc.ndb
+
+	secretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	// Read the Secret from the API Server as the cached
+	// Secret might not have the recently rotated password
+	secretInterface := mssc.client.CoreV1().Secrets(nc.Namespace)
+	secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("Failed to retrieve Secret %q : %s", secretName, err)
+		return errorWhileProcessing(err)
+	}
+
+	retainedPassword, rotationInProgress := secret.Data[resources.RetainedPasswordKey]
+	if !rotationInProgress {
+		if !passwordRotationDue(nc.GetPasswordRotationSchedule(), secret, now) {
+			// Nothing to do
+			return continueProcessing()
+		}
+
+		// Store the new password along with the current one
+		// before updating the NDB Operator user accounts.
+		klog.Infof("Generating a new password in Secret %q as per the password rotation schedule",
+			getNamespacedName(secret))
+		secret, err = secretInterface.Update(
+			ctx, resources.RotateSecretPassword(secret, true, now), metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to update Secret %q : %s", secretName, err)
+			return errorWhileProcessing(err)
+		}
+		retainedPassword = secret.Data[resources.RetainedPasswordKey]
+	}
+
+	mysqldSfsets, err := mssc.getAllMySQLServerStatefulSets(sc)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	newPassword := string(secret.Data[corev1.BasicAuthPasswordKey])
+	retainedUntil := resources.GetPasswordRotationTime(secret).Add(nc.GetOldPasswordRetentionDuration()).UTC()
+	if db, err := mysqlclient.ConnectToStatefulSet(sc.mysqldSfset, "", newPassword); err == nil {
+		// The new password has already been applied to the NDB Operator user
+		db.Close()
+	} else {
+		// Update the local NDB Operator user accounts in all the MySQL
+		// Servers first, and then the NDB Operator user account used by
+		// the operator, so that a failed rotation is attempted again.
+		for _, mysqldSfset := range mysqldSfsets {
+			for podOrdinal := int32(0); podOrdinal < *mysqldSfset.Spec.Replicas; podOrdinal++ {
+				err = execWithPassword(mysqldSfset, podOrdinal, string(retainedPassword), func(db *sql.DB) error {
+					accounts, err := mysqlclient.GetLocalNDBOperatorAccounts(ctx, db)
+					if err != nil {
+						return err
+					}
+					for _, account := range accounts {
+						if err = mysqlclient.RotatePassword(ctx, db, account, newPassword); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					return errorWhileProcessing(err)
+				}
+			}
+		}
+
+		// The NDB Operator user account is shared by all the MySQL Servers
+		err = execWithPassword(sc.mysqldSfset, 0, string(retainedPassword), func(db *sql.DB) error {
+			return mysqlclient.RotatePassword(ctx, db, mysqlclient.CurrentUserAccount, newPassword)
+		})
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonPasswordRotated, ActionRotate,
+			MessagePasswordRotated, ndbOperatorUserDesc, retainedUntil.Format(time.RFC3339))
+	}
+
+	if now.Before(retainedUntil) {
+		// Old password has to be retained for some more time
+		return continueProcessing()
+	}
+
+	// Discard the old passwords of all the NDB Operator user accounts
+	for _, mysqldSfset := range mysqldSfsets {
+		for podOrdinal := int32(0); podOrdinal < *mysqldSfset.Spec.Replicas; podOrdinal++ {
+			err = execWithPassword(mysqldSfset, podOrdinal, newPassword, func(db *sql.DB) error {
+				accounts, err := mysqlclient.GetLocalNDBOperatorAccounts(ctx, db)
+				if err != nil {
+					return err
+				}
+				if podOrdinal == 0 && mysqldSfset == sc.mysqldSfset {
+					accounts = append(accounts, mysqlclient.CurrentUserAccount)
+				}
+				for _, account := range accounts {
+					if err = mysqlclient.DiscardOldPassword(ctx, db, account); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+
+	// Remove the old password from the Secret
+	updatedSecret := secret.DeepCopy()
+	delete(updatedSecret.Data, resources.RetainedPasswordKey)
+	if _, err = secretInterface.Update(ctx, updatedSecret, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("Failed to update Secret %q : %s", secretName, err)
+		return errorWhileProcessing(err)
+	}
+
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal,
+		ReasonOldPasswordDiscarded, ActionRotate, MessageOldPasswordDiscarded, ndbOperatorUserDesc)
+	return continueProcessing()
+}
+
+// execWithPassword connects to the MySQL Server pod with the given ordinal
+// as the NDB Operator user, using the given password, and calls fn
+func execWithPassword(mysqldSfset *appsv1.StatefulSet,
+	podOrdinal int32, ndbOperatorPassword string, fn func(db *sql.DB) error) error {
+	db, err := mysqlclient.ConnectToStatefulSetPod(mysqldSfset, podOrdinal, "", ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(db)
+}
+
+// getAllMySQLServerStatefulSets returns the StatefulSets of the MySQL
+// Servers declared in spec.mysqlNode and spec.mysqlNodeGroups
+func (mssc *mysqldStatefulSetController) getAllMySQLServerStatefulSets(sc *SyncContext) ([]*appsv1.StatefulSet, error) {
+	nc := sc.ndb
+	mysqldSfsets := []*appsv1.StatefulSet{sc.mysqldSfset}
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		mysqldSfset, err := mssc.statefulSetLister.StatefulSets(nc.Namespace).Get(
+			nc.GetMySQLServerGroupWorkloadName(mysqldGroup.Name))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// Group has no MySQL Servers
+				continue
+			}
+			return nil, err
+		}
+		mysqldSfsets = append(mysqldSfsets, mysqldSfset)
+	}
+
+	return mysqldSfsets, nil
+}
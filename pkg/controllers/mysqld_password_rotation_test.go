@@ -0,0 +1,57 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+func TestPasswordRotationDue(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 4, 10, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-secret",
+			CreationTimestamp: metav1.NewTime(createdAt),
+		},
+		Data: map[string][]byte{
+			corev1.BasicAuthPasswordKey: []byte("password"),
+		},
+	}
+
+	// Daily at 02:00 UTC
+	schedule := "0 2 * * *"
+	if passwordRotationDue("", secret, createdAt.Add(48*time.Hour)) {
+		t.Error("Rotation should not be due without a schedule")
+	}
+	if passwordRotationDue(schedule, secret, createdAt.Add(time.Hour)) {
+		t.Error("Rotation should not be due before the next scheduled time")
+	}
+
+	now := createdAt.Add(17 * time.Hour)
+	if !passwordRotationDue(schedule, secret, now) {
+		t.Fatal("Rotation should be due after the next scheduled time")
+	}
+
+	// Once rotated, the next rotation should be based on the rotation time
+	rotatedSecret := resources.RotateSecretPassword(secret, true, now)
+	if string(rotatedSecret.Data[resources.RetainedPasswordKey]) != "password" {
+		t.Error("Current password was not retained in the rotated Secret")
+	}
+	if string(rotatedSecret.Data[corev1.BasicAuthPasswordKey]) == "password" {
+		t.Error("Password was not changed in the rotated Secret")
+	}
+	if passwordRotationDue(schedule, rotatedSecret, now.Add(time.Hour)) {
+		t.Error("Rotation should not be due right after a rotation")
+	}
+	if !passwordRotationDue(schedule, rotatedSecret, now.Add(24*time.Hour)) {
+		t.Error("Rotation should be due a day after the previous rotation")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// replicationUserSecretVersion is the annotation key which stores the
+// resource version of the Secret whose password has been applied to
+// the replication user.
+const replicationUserSecretVersion = ndbcontroller.GroupName + "/replication-user-secret-version"
+
+// reconcileReplicationUser creates the replication user, via which the
+// replica NdbClusters read the binary logs, in the binlog MySQL Servers
+// and updates its password whenever the password in the Secret changes.
+func (mssc *mysqldStatefulSetController) reconcileReplicationUser(ctx context.Context, sc *SyncContext) syncResult {
+	nc := sc.ndb
+	if nc.Spec.Replication == nil {
+		// NdbCluster does not take part in the replication
+		return continueProcessing()
+	}
+
+	binlogController := mssc
+	if groupName := nc.Spec.Replication.BinlogMySQLServerGroup; groupName != "" {
+		binlogController = mssc.groupController(groupName)
+	}
+	binlogSfset := sc.getBinlogMySQLServerStatefulSet()
+
+	if binlogSfset == nil || binlogSfset.Status.ReadyReplicas == 0 {
+		// The binlog MySQL Servers are not running yet.
+		// The user will be created once they are ready.
+		return continueProcessing()
+	}
+
+	secretClient := NewMySQLUserPasswordSecretInterface(sc.kubeClientset())
+	secret, err := secretClient.EnsureReplicationUserPassword(ctx, nc)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	if binlogSfset.GetAnnotations()[replicationUserSecretVersion] == secret.ResourceVersion {
+		// The replication user is up-to-date
+		return continueProcessing()
+	}
+
+	operatorPassword, err := secretClient.ExtractPassword(
+		ctx, nc.Namespace, resources.GetMySQLNDBOperatorPasswordSecretName(nc))
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	db, err := mysqlclient.ConnectToStatefulSet(binlogSfset, mysqlclient.DbMySQL, operatorPassword)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer db.Close()
+
+	password := string(secret.Data[corev1.BasicAuthPasswordKey])
+	if err = mysqlclient.ReconcileReplicationUser(ctx, db, password); err != nil {
+		klog.Errorf("Failed to reconcile the replication user of NdbCluster %q", getNamespacedName(nc))
+		return errorWhileProcessing(err)
+	}
+
+	// Mark the password as applied
+	updatedSfset := binlogSfset.DeepCopy()
+	if updatedSfset.Annotations == nil {
+		updatedSfset.Annotations = make(map[string]string)
+	}
+	updatedSfset.Annotations[replicationUserSecretVersion] = secret.ResourceVersion
+	return binlogController.patchStatefulSet(ctx, binlogSfset, updatedSfset)
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -7,14 +7,19 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/mysqlclient"
 	"github.com/mysql/ndb-operator/pkg/resources"
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 	listersappsv1 "k8s.io/client-go/listers/apps/v1"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
@@ -27,24 +32,84 @@ const (
 	// rootUserGeneration is the annotation key which stores the NdbCluster
 	// generation whose spec has been applied to the Root user.
 	rootUserGeneration = ndbcontroller.GroupName + "/root-user-generation"
+	// requireSecureTransport is the annotation key which stores if the Root
+	// user and the NDB Operator user are required to use encrypted connections
+	requireSecureTransport = ndbcontroller.GroupName + "/require-secure-transport"
 )
 
 type mysqldStatefulSetController struct {
 	ndbNodeStatefulSetImpl
+	configmapLister listerscorev1.ConfigMapLister
+	secretLister    listerscorev1.SecretLister
+	// groupName is the name of the MySQL Server group, declared
+	// in spec.mysqlNodeGroups, handled by the controller. It is
+	// empty for the MySQL Servers declared in spec.mysqlNode.
+	groupName string
 }
 
 // NewMySQLDStatefulSetController creates a new mysqldStatefulSetController
 func newMySQLDStatefulSetController(
 	client kubernetes.Interface,
 	statefulSetLister listersappsv1.StatefulSetLister,
-	configmapLister listerscorev1.ConfigMapLister) *mysqldStatefulSetController {
+	configmapLister listerscorev1.ConfigMapLister,
+	secretLister listerscorev1.SecretLister) *mysqldStatefulSetController {
+	return newMySQLDGroupStatefulSetController(client, statefulSetLister, configmapLister, secretLister, "")
+}
+
+// newMySQLDGroupStatefulSetController creates a new mysqldStatefulSetController
+// for the given MySQL Server group declared in spec.mysqlNodeGroups
+func newMySQLDGroupStatefulSetController(
+	client kubernetes.Interface,
+	statefulSetLister listersappsv1.StatefulSetLister,
+	configmapLister listerscorev1.ConfigMapLister,
+	secretLister listerscorev1.SecretLister,
+	groupName string) *mysqldStatefulSetController {
 	return &mysqldStatefulSetController{
-		ndbNodeStatefulSetImpl{
+		ndbNodeStatefulSetImpl: ndbNodeStatefulSetImpl{
 			client:             client,
 			statefulSetLister:  statefulSetLister,
-			ndbNodeStatefulset: statefulset.NewMySQLdStatefulSet(configmapLister),
+			ndbNodeStatefulset: statefulset.NewMySQLdGroupStatefulSet(configmapLister, secretLister, groupName),
 		},
+		configmapLister: configmapLister,
+		secretLister:    secretLister,
+		groupName:       groupName,
+	}
+}
+
+// groupController returns a mysqldStatefulSetController for the given MySQL Server group
+func (mssc *mysqldStatefulSetController) groupController(groupName string) *mysqldStatefulSetController {
+	return newMySQLDGroupStatefulSetController(
+		mssc.client, mssc.statefulSetLister, mssc.configmapLister, mssc.secretLister, groupName)
+}
+
+// GetGroupStatefulSets retrieves the StatefulSets of the MySQL
+// Server groups owned by the given NdbCluster resource
+func (mssc *mysqldStatefulSetController) GetGroupStatefulSets(
+	sc *SyncContext) (map[string]*appsv1.StatefulSet, error) {
+	nc := sc.ndb
+	selector := labels.SelectorFromSet(nc.GetCompleteLabels(map[string]string{
+		constants.ClusterResourceTypeLabel: mssc.GetTypeName() + "-statefulset",
+	}))
+	groupLabelExists, _ := labels.NewRequirement(constants.MySQLServerGroupLabel, selection.Exists, nil)
+	selector = selector.Add(*groupLabelExists)
+
+	sfsets, err := mssc.statefulSetLister.StatefulSets(nc.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the MySQL Server group StatefulSets for NdbCluster %q : %s",
+			getNamespacedName(nc), err)
+		return nil, err
 	}
+
+	groupSfsets := make(map[string]*appsv1.StatefulSet)
+	for _, sfset := range sfsets {
+		// Verify ownership
+		if err = sc.isOwnedByNdbCluster(sfset); err != nil {
+			return nil, err
+		}
+		groupSfsets[sfset.Labels[constants.MySQLServerGroupLabel]] = sfset
+	}
+
+	return groupSfsets, nil
 }
 
 // HandleScaleDown scales down the MySQL Server StatefulSet if it has been requested
@@ -52,11 +117,48 @@ func newMySQLDStatefulSetController(
 // in the management and data nodes, i.e., before any new config is applied to the
 // management and data nodes. This is to ensure that during a scale down, the MySQL
 // Servers are shutdown before a possible reduction in the number of API sections in
-// the config.
+// the config. The StatefulSets of the MySQL Server groups are scaled down similarly,
+// and the StatefulSets of the groups that have been removed from the spec are deleted.
 func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc *SyncContext) syncResult {
 
+	if sr := mssc.handleScaleDown(ctx, sc, sc.mysqldSfset, sc.configSummary.NumOfMySQLServers); sr.stopSync() {
+		return sr
+	}
+
+	// Handle the groups in a deterministic order
+	groupNames := make([]string, 0, len(sc.mysqldGroupSfsets))
+	for groupName := range sc.mysqldGroupSfsets {
+		groupNames = append(groupNames, groupName)
+	}
+	sort.Strings(groupNames)
+
+	for _, groupName := range groupNames {
+		groupSfset := sc.mysqldGroupSfsets[groupName]
+		groupController := mssc.groupController(groupName)
+		groupSummary, exists := sc.configSummary.MySQLServerGroups[groupName]
+		if !exists {
+			// The group has been removed from the spec. Delete the StatefulSet.
+			klog.Infof("MySQL Server group %q has been removed from the NdbCluster spec", groupName)
+			if err := groupController.deleteStatefulSet(ctx, groupSfset, sc); err != nil {
+				return errorWhileProcessing(err)
+			}
+			// reconciliation will continue once the statefulset has been deleted
+			return finishProcessing()
+		}
+
+		if sr := groupController.handleScaleDown(ctx, sc, groupSfset, groupSummary.NumOfMySQLServers); sr.stopSync() {
+			return sr
+		}
+	}
+
+	return continueProcessing()
+}
+
+// handleScaleDown scales down the given MySQL Server StatefulSet to mysqldNodeCount
+func (mssc *mysqldStatefulSetController) handleScaleDown(
+	ctx context.Context, sc *SyncContext, mysqldSfset *appsv1.StatefulSet, mysqldNodeCount int32) syncResult {
+
 	nc := sc.ndb
-	mysqldSfset := sc.mysqldSfset
 
 	if mysqldSfset == nil {
 		// Nothing to scale down
@@ -64,7 +166,7 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 	}
 	NdbGeneration := sc.configSummary.NdbClusterGeneration
 	// StatefulSet exists
-	if !sc.isStatefulsetUpdated(sc.mysqldSfset, NdbGeneration, Complete) {
+	if !sc.isStatefulsetUpdated(mysqldSfset, NdbGeneration, Complete) {
 		// Previous StatefulSet update is not complete yet.
 		// Finish processing. Reconciliation will
 		// continue once the StatefulSet update has been
@@ -73,7 +175,6 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 	}
 
 	// Handle any scale down
-	mysqldNodeCount := sc.configSummary.NumOfMySQLServers
 	if mysqldSfset.Status.Replicas <= mysqldNodeCount {
 		// No scale down requested or, it has been processed already
 		// Continue processing rest of sync loop
@@ -81,6 +182,16 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 	}
 
 	// scale down requested
+	if mysqldNodeCount == 0 && mssc.groupName != "" {
+		// scale down the group to 0 servers, i.e., delete the statefulset
+		if err := mssc.deleteStatefulSet(ctx, mysqldSfset, sc); err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		// reconciliation will continue once the statefulset has been deleted
+		return finishProcessing()
+	}
+
 	if mysqldNodeCount == 0 {
 		// The StatefulSet has to be deleted
 		// Delete the root user first.
@@ -104,7 +215,9 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 		annotations := mysqldSfset.GetAnnotations()
 		secretName := annotations[statefulset.RootPasswordSecret]
 
-		if secretClient.IsControlledBy(ctx, secretName, nc) {
+		// The MySQL Server groups also use the root password secret, so
+		// delete it only if there are no groups declared in the spec.
+		if len(nc.Spec.MysqlNodeGroups) == 0 && secretClient.IsControlledBy(ctx, secretName, nc) {
 			// The given NdbCluster is set as the Owner of the secret,
 			// which implies that this was created by the operator.
 			err := secretClient.Delete(ctx, mysqldSfset.Namespace, secretName)
@@ -136,15 +249,41 @@ func (mssc *mysqldStatefulSetController) HandleScaleDown(ctx context.Context, sc
 
 // ReconcileStatefulSet compares the MySQL Server spec defined in NdbCluster resource
 // and applies any changes to the statefulset if required. This method is called after
-// the new config has been ensured in both Management and Data Nodes.
+// the new config has been ensured in both Management and Data Nodes. The StatefulSets
+// of the MySQL Server groups are created or updated in the order they are declared.
 func (mssc *mysqldStatefulSetController) ReconcileStatefulSet(ctx context.Context, sc *SyncContext) syncResult {
-	mysqldSfset := sc.mysqldSfset
+	cs := sc.configSummary
+	if sr := mssc.reconcileStatefulSet(ctx, sc, sc.mysqldSfset, cs.NumOfMySQLServers); sr.stopSync() {
+		return sr
+	}
+
+	for _, mysqldGroup := range sc.ndb.Spec.MysqlNodeGroups {
+		groupSummary, exists := cs.MySQLServerGroups[mysqldGroup.Name]
+		if !exists {
+			// The group has not been added to the config yet
+			continue
+		}
+
+		groupController := mssc.groupController(mysqldGroup.Name)
+		if sr := groupController.reconcileStatefulSet(
+			ctx, sc, sc.mysqldGroupSfsets[mysqldGroup.Name], groupSummary.NumOfMySQLServers); sr.stopSync() {
+			return sr
+		}
+	}
+
+	return continueProcessing()
+}
+
+// reconcileStatefulSet creates or patches the given MySQL Server StatefulSet
+// if required. The StatefulSet is created only if mysqldNodeCount is not 0.
+func (mssc *mysqldStatefulSetController) reconcileStatefulSet(
+	ctx context.Context, sc *SyncContext, mysqldSfset *appsv1.StatefulSet, mysqldNodeCount int32) syncResult {
 	cs := sc.configSummary
 	nc := sc.ndb
 
 	if mysqldSfset == nil {
 		// statefulset doesn't exist yet
-		if cs.NumOfMySQLServers == 0 {
+		if mysqldNodeCount == 0 {
 			// the current state is in sync with expectation
 			return continueProcessing()
 		}
@@ -173,9 +312,28 @@ func (mssc *mysqldStatefulSetController) ReconcileStatefulSet(ctx context.Contex
 	// to be complete (i.e. no previous updates still being applied) by HandleScaleDown.
 	// Check if the statefulset has the recent config generation.
 	if workloadHasConfigGeneration(mysqldSfset, cs.NdbClusterGeneration) {
-		// Statefulset upto date
-		klog.Info("All MySQL Servers are up-to-date and ready")
-		return continueProcessing()
+		// Check if the MySQL Servers use the recent certificate
+		certVersion, err := statefulset.GetMySQLServerCertVersion(nc, mssc.secretLister)
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+
+		if certVersion == mysqldSfset.Spec.Template.Annotations[statefulset.LastAppliedMySQLServerCertVersion] {
+			// Statefulset upto date
+			klog.Infof("All MySQL Servers of the StatefulSet %q are up-to-date and ready", getNamespacedName(mysqldSfset))
+
+			// All the pods have the recent labels. Update the
+			// Service, if required, to select them via the labels.
+			if err = sc.serviceController.patchServiceSelector(ctx, sc, mssc.ndbNodeStatefulset); err != nil {
+				return errorWhileProcessing(err)
+			}
+			return continueProcessing()
+		}
+
+		// The MySQL Server certificate has been renewed.
+		// Patch the StatefulSet to restart the MySQL Servers.
+		klog.Infof("MySQL Servers of the StatefulSet %q will be restarted to use the renewed certificate",
+			getNamespacedName(mysqldSfset))
 	}
 
 	// Statefulset has to be patched
@@ -248,11 +406,22 @@ func (mssc *mysqldStatefulSetController) reconcileRootUser(ctx context.Context,
 		}
 	}
 
+	// Update the users if the requirement for encrypted connections has changed
+	tlsSpec := nc.GetMySQLServerTLSSpec()
+	requireTLS := tlsSpec != nil && tlsSpec.RequireSecureTransport
+	if requireTLS != (annotations[requireSecureTransport] == "true") {
+		if err := mysqlclient.UpdateRequireSecureTransport(mysqldSfset, newRootHost, requireTLS, operatorPassword); err != nil {
+			klog.Errorf("Failed to update the TLS requirement of the root user")
+			return errorWhileProcessing(err)
+		}
+	}
+
 	// Successfully applied the changes to root user
 	// Patch the StatefulSet to mark the changes as done
 	updatedMysqldSfset := mysqldSfset.DeepCopy()
 	annotations = updatedMysqldSfset.Annotations
 	annotations[rootHost] = newRootHost
+	annotations[requireSecureTransport] = strconv.FormatBool(requireTLS)
 	annotations[rootUserGeneration] = fmt.Sprintf("%d", recentNdbGen)
 	return mssc.patchStatefulSet(ctx, mysqldSfset, updatedMysqldSfset)
 }
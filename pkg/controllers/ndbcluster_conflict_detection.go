@@ -0,0 +1,100 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// getBinlogMySQLServerStatefulSet returns the StatefulSet of the binlog
+// MySQL Servers or nil if the NdbCluster doesn't take part in the replication.
+func (sc *SyncContext) getBinlogMySQLServerStatefulSet() *appsv1.StatefulSet {
+	replication := sc.ndb.Spec.Replication
+	if replication == nil {
+		return nil
+	}
+
+	if groupName := replication.BinlogMySQLServerGroup; groupName != "" {
+		return sc.mysqldGroupSfsets[groupName]
+	}
+	return sc.mysqldSfset
+}
+
+// reconcileConflictDetection stores the conflict detection functions declared
+// in spec.replication.conflictDetection in the mysql.ndb_replication table, via
+// the binlog MySQL Server, and creates the exceptions tables. It also collects
+// the conflict counters of the binlog MySQL Server.
+func (sc *SyncContext) reconcileConflictDetection(ctx context.Context) syncResult {
+	nc := sc.ndb
+	var conflictDetectionDeclared bool
+	if nc.Spec.Replication != nil {
+		conflictDetectionDeclared = len(nc.Spec.Replication.ConflictDetection) != 0
+	}
+
+	if !conflictDetectionDeclared && nc.Status.ConflictDetection == nil {
+		// Conflict detection was never configured by the operator. Leave any
+		// rows added to the mysql.ndb_replication table by the user as is.
+		return continueProcessing()
+	}
+
+	binlogSfset := sc.getBinlogMySQLServerStatefulSet()
+	if binlogSfset == nil {
+		// The NdbCluster doesn't take part in the replication anymore
+		binlogSfset = sc.mysqldSfset
+	}
+
+	if binlogSfset == nil || binlogSfset.Status.ReadyReplicas == 0 {
+		// The binlog MySQL Servers are not running yet.
+		// The functions will be stored once they are ready.
+		return continueProcessing()
+	}
+
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(sc.kubeClientset()).ExtractPassword(
+		ctx, nc.Namespace, resources.GetMySQLNDBOperatorPasswordSecretName(nc))
+	if err != nil {
+		klog.Errorf("Failed to extract ndb operator password from the secret")
+		return errorWhileProcessing(err)
+	}
+
+	db, err := mysqlclient.ConnectToStatefulSet(binlogSfset, "", operatorPassword)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer db.Close()
+
+	var conflictDetection []v1.NdbConflictDetectionSpec
+	if conflictDetectionDeclared {
+		conflictDetection = nc.Spec.Replication.ConflictDetection
+	}
+	if err = mysqlclient.ReconcileConflictDetection(ctx, db, conflictDetection); err != nil {
+		klog.Errorf("Failed to reconcile the conflict detection functions of NdbCluster %q : %s",
+			getNamespacedName(nc), err)
+		return errorWhileProcessing(err)
+	}
+
+	if !conflictDetectionDeclared {
+		// All the conflict functions have been removed.
+		// The conflict counters will be removed from the status.
+		sc.conflictFunctionsRemoved = true
+		return continueProcessing()
+	}
+
+	counters, err := mysqlclient.GetConflictCounters(ctx, db)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	counters.MySQLServer = fmt.Sprintf("%s-0", binlogSfset.Name)
+	sc.conflictDetectionStatus = counters
+
+	return continueProcessing()
+}
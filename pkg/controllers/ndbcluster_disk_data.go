@@ -0,0 +1,117 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// diskDataReconciled returns true if the disk data objects declared in the
+// current generation of the NdbCluster spec have already been reconciled.
+func diskDataReconciled(nc *v1.NdbCluster) bool {
+	if nc.Status.ProcessedGeneration != nc.Generation {
+		// The spec has changed since the last successful sync
+		return false
+	}
+
+	// The disk space usage of all the tablespaces is
+	// recorded in the status once they are reconciled.
+	tablespaces := nc.Spec.DataNode.DiskData.Tablespaces
+	if len(nc.Status.Tablespaces) != len(tablespaces) {
+		return false
+	}
+	for i := range tablespaces {
+		if nc.Status.Tablespaces[i].Name != tablespaces[i].Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reconcileDiskData creates the logfile groups and the tablespaces declared
+// in spec.dataNode.diskData, via a MySQL Server, and adds any new undo log and
+// data files to them. It also collects the disk space usage of the tablespaces.
+// Nothing is done if the disk data objects have already been reconciled.
+func (sc *SyncContext) reconcileDiskData(ctx context.Context) syncResult {
+	nc := sc.ndb
+	diskData := nc.Spec.DataNode.DiskData
+	if diskData == nil ||
+		(len(diskData.LogfileGroups) == 0 && len(diskData.Tablespaces) == 0) {
+		// No disk data objects declared
+		return continueProcessing()
+	}
+
+	if diskDataReconciled(nc) {
+		// Disk data objects are up-to-date
+		return continueProcessing()
+	}
+
+	if sc.mysqldSfset == nil {
+		// The validation requires atleast one MySQL Server when disk data
+		// objects are declared. So, the StatefulSet is yet to be created.
+		// Retry later as the generation cannot be processed without them.
+		return errorWhileProcessing(fmt.Errorf(
+			"cannot create the disk data objects of NdbCluster %q as there are no MySQL Servers",
+			getNamespacedName(nc)))
+	}
+
+	// Extract ndb operator mysql user password.
+	operatorSecretName := resources.GetMySQLNDBOperatorPasswordSecretName(nc)
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(sc.kubeClientset()).ExtractPassword(
+		ctx, nc.Namespace, operatorSecretName)
+	if err != nil {
+		klog.Errorf("Failed to extract ndb operator password from the secret")
+		return errorWhileProcessing(err)
+	}
+
+	// Connect to the 0th MySQL Pod to create the disk data objects
+	db, err := mysqlclient.ConnectToStatefulSet(sc.mysqldSfset, "", operatorPassword)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer db.Close()
+
+	// Creating the undo log and data files can take long.
+	// Report heartbeats until they are all created.
+	defer keepAlive(ctx)()
+
+	// The logfile groups have to be created before the tablespaces that use them
+	for i := range diskData.LogfileGroups {
+		if err = mysqlclient.ReconcileLogfileGroup(ctx, db, &diskData.LogfileGroups[i]); err != nil {
+			klog.Errorf("Failed to create logfile group %q : %s", diskData.LogfileGroups[i].Name, err)
+			return errorWhileProcessing(err)
+		}
+	}
+
+	tablespaceStatus := make([]v1.NdbTablespaceStatus, 0, len(diskData.Tablespaces))
+	for i := range diskData.Tablespaces {
+		ts := &diskData.Tablespaces[i]
+		if err = mysqlclient.ReconcileTablespace(ctx, db, ts); err != nil {
+			klog.Errorf("Failed to create tablespace %q : %s", ts.Name, err)
+			return errorWhileProcessing(err)
+		}
+
+		freeExtents, totalExtents, err := mysqlclient.GetTablespaceExtents(ctx, db, ts.Name)
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+		tablespaceStatus = append(tablespaceStatus, v1.NdbTablespaceStatus{
+			Name:         ts.Name,
+			FreeExtents:  freeExtents,
+			TotalExtents: totalExtents,
+		})
+	}
+	sc.tablespaceStatus = tablespaceStatus
+
+	return continueProcessing()
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/metrics"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+const (
+	// ndbClusterMetricsSubsystem is the subsystem of the NdbCluster metrics
+	ndbClusterMetricsSubsystem = "ndbcluster"
+	// ndbClusterMetricsTimeout is the time allowed to collect the metrics of an NdbCluster
+	ndbClusterMetricsTimeout = 10 * time.Second
+)
+
+var (
+	ndbClusterUpDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, ndbClusterMetricsSubsystem, "up"),
+		"1 if the status of the MySQL Cluster could be retrieved from its Management Server, 0 otherwise.",
+		[]string{"namespace", "name"}, nil)
+
+	ndbClusterConnectedNodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, ndbClusterMetricsSubsystem, "connected_nodes"),
+		"Number of MySQL Cluster nodes of the given type connected to the MySQL Cluster.",
+		[]string{"namespace", "name", "node_type"}, nil)
+
+	ndbClusterNodeGroupsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, ndbClusterMetricsSubsystem, "node_groups"),
+		"Number of node groups in the MySQL Cluster.",
+		[]string{"namespace", "name"}, nil)
+
+	ndbClusterNodeVersionsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, ndbClusterMetricsSubsystem, "node_software_versions"),
+		"Number of connected MySQL Cluster nodes of the given type running the given software version.",
+		[]string{"namespace", "name", "node_type", "version"}, nil)
+)
+
+// getNodeTypeLabel returns the node_type label value of the given node
+func getNodeTypeLabel(nodeStatus *mgmapi.NodeStatus) string {
+	switch {
+	case nodeStatus.IsMgmNode():
+		return "management"
+	case nodeStatus.IsDataNode():
+		return "data"
+	default:
+		return "api"
+	}
+}
+
+// ndbClusterCollector is a prometheus.Collector that exports, on every
+// scrape, the status of the MySQL Clusters managed by the operator, as
+// reported by their Management Servers, and the resource usage of their
+// data nodes, as reported by the ndbinfo tables.
+type ndbClusterCollector struct {
+	kubernetesClient  kubernetes.Interface
+	ndbsLister        ndblisters.NdbClusterLister
+	statefulSetLister appslisters.StatefulSetLister
+}
+
+// NewNdbClusterCollector returns a new prometheus.Collector that exports the NdbCluster metrics
+func NewNdbClusterCollector(
+	kubernetesClient kubernetes.Interface,
+	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) prometheus.Collector {
+	return &ndbClusterCollector{
+		kubernetesClient:  kubernetesClient,
+		ndbsLister:        ndbSharedIndexInformer.Mysql().V1().NdbClusters().Lister(),
+		statefulSetLister: k8sSharedIndexInformer.Apps().V1().StatefulSets().Lister(),
+	}
+}
+
+// Describe sends the descriptors of all the metrics exported by the collector
+func (ncc *ndbClusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ndbClusterUpDesc
+	ch <- ndbClusterConnectedNodesDesc
+	ch <- ndbClusterNodeGroupsDesc
+	ch <- ndbClusterNodeVersionsDesc
+	describeNdbInfoMetrics(ch)
+}
+
+// Collect retrieves the status of all the NdbClusters in parallel and sends their metrics
+func (ncc *ndbClusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ndbClusters, err := ncc.ndbsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list the NdbClusters to collect their metrics : %s", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, nc := range ndbClusters {
+		wg.Add(1)
+		go func(nc *v1.NdbCluster) {
+			defer wg.Done()
+			ncc.collectNdbClusterMetrics(nc, ch)
+		}(nc)
+	}
+	wg.Wait()
+}
+
+// collectNdbClusterMetrics sends the metrics of the given NdbCluster
+func (ncc *ndbClusterCollector) collectNdbClusterMetrics(nc *v1.NdbCluster, ch chan<- prometheus.Metric) {
+	if nc.Spec.Suspended {
+		// The Management Servers are not running
+		ch <- prometheus.MustNewConstMetric(ndbClusterUpDesc, prometheus.GaugeValue, 0, nc.Namespace, nc.Name)
+		return
+	}
+
+	clusterStatus, err := ncc.getClusterStatus(nc)
+	if err != nil {
+		klog.V(2).Infof("Failed to retrieve the status of NdbCluster %q to collect its metrics : %s",
+			getNamespacedName(nc), err)
+		ch <- prometheus.MustNewConstMetric(ndbClusterUpDesc, prometheus.GaugeValue, 0, nc.Namespace, nc.Name)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(ndbClusterUpDesc, prometheus.GaugeValue, 1, nc.Namespace, nc.Name)
+	sendClusterStatusMetrics(nc, clusterStatus, ch)
+
+	// The data nodes are running. Read their resource usage from ndbinfo.
+	ncc.collectNdbInfoMetrics(nc, ch)
+}
+
+// sendClusterStatusMetrics sends the metrics derived from the given status of the MySQL Cluster
+func sendClusterStatusMetrics(nc *v1.NdbCluster, clusterStatus mgmapi.ClusterStatus, ch chan<- prometheus.Metric) {
+	connectedNodes := map[string]int{"management": 0, "data": 0, "api": 0}
+	nodeVersions := make(map[[2]string]int)
+	nodeGroups := make(map[int]bool)
+	for _, nodeStatus := range clusterStatus {
+		nodeType := getNodeTypeLabel(nodeStatus)
+		if nodeStatus.IsDataNode() &&
+			nodeStatus.NodeGroup >= 0 && nodeStatus.NodeGroup != mgmapi.NodeGroupNewDisconnectedDataNode {
+			// Data node belongs to a valid node group
+			nodeGroups[nodeStatus.NodeGroup] = true
+		}
+
+		if nodeStatus.IsConnected {
+			connectedNodes[nodeType]++
+			nodeVersions[[2]string{nodeType, nodeStatus.SoftwareVersion}]++
+		}
+	}
+
+	for nodeType, count := range connectedNodes {
+		ch <- prometheus.MustNewConstMetric(ndbClusterConnectedNodesDesc,
+			prometheus.GaugeValue, float64(count), nc.Namespace, nc.Name, nodeType)
+	}
+
+	ch <- prometheus.MustNewConstMetric(ndbClusterNodeGroupsDesc,
+		prometheus.GaugeValue, float64(len(nodeGroups)), nc.Namespace, nc.Name)
+
+	for key, count := range nodeVersions {
+		ch <- prometheus.MustNewConstMetric(ndbClusterNodeVersionsDesc,
+			prometheus.GaugeValue, float64(count), nc.Namespace, nc.Name, key[0], key[1])
+	}
+}
+
+// getClusterStatus retrieves the status of the MySQL Cluster from its Management Server
+func (ncc *ndbClusterCollector) getClusterStatus(nc *v1.NdbCluster) (mgmapi.ClusterStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ndbClusterMetricsTimeout)
+	defer cancel()
+
+	mgmClient, err := newMgmClient(ctx, ncc.kubernetesClient, nc)
+	if err != nil {
+		return nil, err
+	}
+	defer mgmClient.Disconnect()
+
+	return mgmClient.GetStatus()
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+)
+
+// clusterStatusCollector collects the metrics
+// derived from a fixed MySQL Cluster status
+type clusterStatusCollector struct {
+	clusterStatus mgmapi.ClusterStatus
+}
+
+func (csc *clusterStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(csc, ch)
+}
+
+func (csc *clusterStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	sendClusterStatusMetrics(testutils.NewTestNdb("default", "example-ndb", 2), csc.clusterStatus, ch)
+}
+
+func Test_sendClusterStatusMetrics(t *testing.T) {
+	clusterStatus := mgmapi.ClusterStatus{
+		1: {NodeId: 1, NodeType: mgmapi.NodeTypeMGM, IsConnected: true, NodeGroup: -1, SoftwareVersion: "8.3.0"},
+		2: {NodeId: 2, NodeType: mgmapi.NodeTypeMGM, IsConnected: false, NodeGroup: -1},
+		3: {NodeId: 3, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 0, SoftwareVersion: "8.3.0"},
+		4: {NodeId: 4, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 0, SoftwareVersion: "8.3.0"},
+		5: {NodeId: 5, NodeType: mgmapi.NodeTypeNDB, IsConnected: false, NodeGroup: 1},
+		6: {NodeId: 6, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 1, SoftwareVersion: "8.0.36"},
+		// Data node slot added to the config but not inducted into the MySQL Cluster yet
+		7: {NodeId: 7, NodeType: mgmapi.NodeTypeNDB, IsConnected: false,
+			NodeGroup: mgmapi.NodeGroupNewDisconnectedDataNode},
+		145: {NodeId: 145, NodeType: mgmapi.NodeTypeAPI, IsConnected: true, NodeGroup: -1, SoftwareVersion: "8.3.0"},
+		146: {NodeId: 146, NodeType: mgmapi.NodeTypeAPI, IsConnected: false, NodeGroup: -1},
+	}
+
+	expected := `
+# HELP ndb_operator_ndbcluster_connected_nodes Number of MySQL Cluster nodes of the given type connected to the MySQL Cluster.
+# TYPE ndb_operator_ndbcluster_connected_nodes gauge
+ndb_operator_ndbcluster_connected_nodes{name="example-ndb",namespace="default",node_type="api"} 1
+ndb_operator_ndbcluster_connected_nodes{name="example-ndb",namespace="default",node_type="data"} 3
+ndb_operator_ndbcluster_connected_nodes{name="example-ndb",namespace="default",node_type="management"} 1
+# HELP ndb_operator_ndbcluster_node_groups Number of node groups in the MySQL Cluster.
+# TYPE ndb_operator_ndbcluster_node_groups gauge
+ndb_operator_ndbcluster_node_groups{name="example-ndb",namespace="default"} 2
+# HELP ndb_operator_ndbcluster_node_software_versions Number of connected MySQL Cluster nodes of the given type running the given software version.
+# TYPE ndb_operator_ndbcluster_node_software_versions gauge
+ndb_operator_ndbcluster_node_software_versions{name="example-ndb",namespace="default",node_type="api",version="8.3.0"} 1
+ndb_operator_ndbcluster_node_software_versions{name="example-ndb",namespace="default",node_type="data",version="8.0.36"} 1
+ndb_operator_ndbcluster_node_software_versions{name="example-ndb",namespace="default",node_type="data",version="8.3.0"} 2
+ndb_operator_ndbcluster_node_software_versions{name="example-ndb",namespace="default",node_type="management",version="8.3.0"} 1
+`
+
+	collector := &clusterStatusCollector{clusterStatus: clusterStatus}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+// ndbInfoCollector collects the metrics derived
+// from a fixed resource usage of the data nodes
+type ndbInfoCollector struct {
+	usage *mysqlclient.NdbInfoResourceUsage
+}
+
+func (nic *ndbInfoCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(nic, ch)
+}
+
+func (nic *ndbInfoCollector) Collect(ch chan<- prometheus.Metric) {
+	sendNdbInfoMetrics(testutils.NewTestNdb("default", "example-ndb", 2), nic.usage, ch)
+}
+
+func Test_sendNdbInfoMetrics(t *testing.T) {
+	usage := &mysqlclient.NdbInfoResourceUsage{
+		MemoryUsage: []mysqlclient.NdbInfoMemoryUsage{
+			{NodeId: 3, MemoryType: "Data memory", Used: 1048576, Total: 104857600},
+		},
+		LogSpaces: []mysqlclient.NdbInfoLogUsage{
+			{NodeId: 3, LogType: "REDO", LogId: 0, LogPart: 1, Used: 4096, Total: 67108864},
+		},
+		DiskPageBuffer: []mysqlclient.NdbInfoDiskPageBuffer{
+			{NodeId: 3, PagesRead: 12, PageRequestsDirectReturn: 100},
+		},
+		ThreadStat: []mysqlclient.NdbInfoThreadStat{
+			{NodeId: 3, ThreadNo: 1, ThreadName: "ldm", Signals: 5000, UserTime: 2500000},
+		},
+	}
+
+	expected := `
+# HELP ndb_operator_datanode_memory_used_bytes Memory of the given type used by the data node, from ndbinfo.memoryusage.
+# TYPE ndb_operator_datanode_memory_used_bytes gauge
+ndb_operator_datanode_memory_used_bytes{memory_type="Data memory",name="example-ndb",namespace="default",node_id="3"} 1.048576e+06
+# HELP ndb_operator_datanode_log_space_total_bytes Log space available to the data node, from ndbinfo.logspaces.
+# TYPE ndb_operator_datanode_log_space_total_bytes gauge
+ndb_operator_datanode_log_space_total_bytes{log_id="0",log_part="1",log_type="REDO",name="example-ndb",namespace="default",node_id="3"} 6.7108864e+07
+# HELP ndb_operator_datanode_disk_page_buffer_pages_read_total Pages read from disk into the disk page buffer, from ndbinfo.diskpagebuffer.
+# TYPE ndb_operator_datanode_disk_page_buffer_pages_read_total counter
+ndb_operator_datanode_disk_page_buffer_pages_read_total{name="example-ndb",namespace="default",node_id="3"} 12
+# HELP ndb_operator_datanode_thread_cpu_user_seconds_total User CPU time used by the data node thread, from ndbinfo.threadstat.
+# TYPE ndb_operator_datanode_thread_cpu_user_seconds_total counter
+ndb_operator_datanode_thread_cpu_user_seconds_total{name="example-ndb",namespace="default",node_id="3",thread_name="ldm",thread_no="1"} 2.5
+`
+
+	collector := &ndbInfoCollector{usage: usage}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"ndb_operator_datanode_memory_used_bytes",
+		"ndb_operator_datanode_log_space_total_bytes",
+		"ndb_operator_datanode_disk_page_buffer_pages_read_total",
+		"ndb_operator_datanode_thread_cpu_user_seconds_total"); err != nil {
+		t.Error(err)
+	}
+}
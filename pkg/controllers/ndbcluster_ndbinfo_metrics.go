@@ -0,0 +1,166 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/metrics"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+)
+
+// dataNodeMetricsSubsystem is the subsystem of the data node metrics read from ndbinfo
+const dataNodeMetricsSubsystem = "datanode"
+
+// newDataNodeDesc returns the descriptor of a data node metric, labelled
+// by the NdbCluster, the node id of the data node and the given labels.
+func newDataNodeDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, dataNodeMetricsSubsystem, name), help,
+		append([]string{"namespace", "name", "node_id"}, labels...), nil)
+}
+
+var (
+	// ndbinfo.memoryusage
+	dataNodeMemoryUsedDesc = newDataNodeDesc("memory_used_bytes",
+		"Memory of the given type used by the data node, from ndbinfo.memoryusage.", "memory_type")
+	dataNodeMemoryTotalDesc = newDataNodeDesc("memory_total_bytes",
+		"Memory of the given type available to the data node, from ndbinfo.memoryusage.", "memory_type")
+
+	// ndbinfo.logspaces
+	dataNodeLogSpaceUsedDesc = newDataNodeDesc("log_space_used_bytes",
+		"Log space used by the data node, from ndbinfo.logspaces.", "log_type", "log_id", "log_part")
+	dataNodeLogSpaceTotalDesc = newDataNodeDesc("log_space_total_bytes",
+		"Log space available to the data node, from ndbinfo.logspaces.", "log_type", "log_id", "log_part")
+
+	// ndbinfo.logbuffers
+	dataNodeLogBufferUsedDesc = newDataNodeDesc("log_buffer_used_bytes",
+		"Log buffer space used by the data node, from ndbinfo.logbuffers.", "log_type", "log_id", "log_part")
+	dataNodeLogBufferTotalDesc = newDataNodeDesc("log_buffer_total_bytes",
+		"Log buffer space available to the data node, from ndbinfo.logbuffers.", "log_type", "log_id", "log_part")
+
+	// ndbinfo.diskpagebuffer
+	dataNodePagesWrittenDesc = newDataNodeDesc("disk_page_buffer_pages_written_total",
+		"Pages written to disk by the disk page buffer, from ndbinfo.diskpagebuffer.")
+	dataNodePagesWrittenLCPDesc = newDataNodeDesc("disk_page_buffer_pages_written_lcp_total",
+		"Pages written to disk by local checkpoints, from ndbinfo.diskpagebuffer.")
+	dataNodePagesReadDesc = newDataNodeDesc("disk_page_buffer_pages_read_total",
+		"Pages read from disk into the disk page buffer, from ndbinfo.diskpagebuffer.")
+	dataNodeLogWaitsDesc = newDataNodeDesc("disk_page_buffer_log_waits_total",
+		"Page writes that waited for the undo log to be flushed, from ndbinfo.diskpagebuffer.")
+	dataNodePageRequestsDirectReturnDesc = newDataNodeDesc("disk_page_buffer_page_requests_direct_return_total",
+		"Page requests served immediately from the disk page buffer, from ndbinfo.diskpagebuffer.")
+	dataNodePageRequestsWaitQueueDesc = newDataNodeDesc("disk_page_buffer_page_requests_wait_queue_total",
+		"Page requests that waited for a page already being read from disk, from ndbinfo.diskpagebuffer.")
+	dataNodePageRequestsWaitIODesc = newDataNodeDesc("disk_page_buffer_page_requests_wait_io_total",
+		"Page requests that required a page to be read from disk, from ndbinfo.diskpagebuffer.")
+
+	// ndbinfo.threadstat
+	dataNodeThreadLoopsDesc = newDataNodeDesc("thread_loops_total",
+		"Loops executed by the data node thread, from ndbinfo.threadstat.", "thread_no", "thread_name")
+	dataNodeThreadSignalsDesc = newDataNodeDesc("thread_signals_executed_total",
+		"Signals executed by the data node thread, from ndbinfo.threadstat.", "thread_no", "thread_name")
+	dataNodeThreadWaitsDesc = newDataNodeDesc("thread_waits_total",
+		"Waits for new signals by the data node thread, from ndbinfo.threadstat.", "thread_no", "thread_name")
+	dataNodeThreadUserCPUDesc = newDataNodeDesc("thread_cpu_user_seconds_total",
+		"User CPU time used by the data node thread, from ndbinfo.threadstat.", "thread_no", "thread_name")
+	dataNodeThreadSystemCPUDesc = newDataNodeDesc("thread_cpu_system_seconds_total",
+		"System CPU time used by the data node thread, from ndbinfo.threadstat.", "thread_no", "thread_name")
+)
+
+// describeNdbInfoMetrics sends the descriptors of all the data node metrics read from ndbinfo
+func describeNdbInfoMetrics(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		dataNodeMemoryUsedDesc, dataNodeMemoryTotalDesc,
+		dataNodeLogSpaceUsedDesc, dataNodeLogSpaceTotalDesc,
+		dataNodeLogBufferUsedDesc, dataNodeLogBufferTotalDesc,
+		dataNodePagesWrittenDesc, dataNodePagesWrittenLCPDesc, dataNodePagesReadDesc, dataNodeLogWaitsDesc,
+		dataNodePageRequestsDirectReturnDesc, dataNodePageRequestsWaitQueueDesc, dataNodePageRequestsWaitIODesc,
+		dataNodeThreadLoopsDesc, dataNodeThreadSignalsDesc, dataNodeThreadWaitsDesc,
+		dataNodeThreadUserCPUDesc, dataNodeThreadSystemCPUDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// collectNdbInfoMetrics reads the resource usage of the data nodes of
+// the given NdbCluster from the ndbinfo tables, via a MySQL Server,
+// and sends them as metrics. Nothing is sent if the NdbCluster has
+// no MySQL Servers or if they are not ready.
+func (ncc *ndbClusterCollector) collectNdbInfoMetrics(nc *v1.NdbCluster, ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), ndbClusterMetricsTimeout)
+	defer cancel()
+
+	db, err := connectToMySQLServer(ctx, ncc.kubernetesClient, ncc.statefulSetLister, nc)
+	if err != nil {
+		if err != errNoMySQLServers {
+			klog.V(2).Infof("Failed to connect to the MySQL Server of NdbCluster %q to collect its metrics : %s",
+				getNamespacedName(nc), err)
+		}
+		return
+	}
+	defer db.Close()
+
+	usage, err := mysqlclient.GetNdbInfoResourceUsage(ctx, db)
+	if err != nil {
+		klog.V(2).Infof("Failed to read the ndbinfo tables of NdbCluster %q to collect its metrics : %s",
+			getNamespacedName(nc), err)
+		return
+	}
+
+	sendNdbInfoMetrics(nc, usage, ch)
+}
+
+// sendNdbInfoMetrics sends the metrics derived from the given resource usage of the data nodes
+func sendNdbInfoMetrics(nc *v1.NdbCluster, usage *mysqlclient.NdbInfoResourceUsage, ch chan<- prometheus.Metric) {
+	send := func(desc *prometheus.Desc, valueType prometheus.ValueType, value float64, nodeId int, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, valueType, value,
+			append([]string{nc.Namespace, nc.Name, strconv.Itoa(nodeId)}, labels...)...)
+	}
+
+	for _, mu := range usage.MemoryUsage {
+		send(dataNodeMemoryUsedDesc, prometheus.GaugeValue, float64(mu.Used), mu.NodeId, mu.MemoryType)
+		send(dataNodeMemoryTotalDesc, prometheus.GaugeValue, float64(mu.Total), mu.NodeId, mu.MemoryType)
+	}
+
+	for _, ls := range usage.LogSpaces {
+		labels := []string{ls.LogType, strconv.Itoa(ls.LogId), strconv.Itoa(ls.LogPart)}
+		send(dataNodeLogSpaceUsedDesc, prometheus.GaugeValue, float64(ls.Used), ls.NodeId, labels...)
+		send(dataNodeLogSpaceTotalDesc, prometheus.GaugeValue, float64(ls.Total), ls.NodeId, labels...)
+	}
+
+	for _, lb := range usage.LogBuffers {
+		labels := []string{lb.LogType, strconv.Itoa(lb.LogId), strconv.Itoa(lb.LogPart)}
+		send(dataNodeLogBufferUsedDesc, prometheus.GaugeValue, float64(lb.Used), lb.NodeId, labels...)
+		send(dataNodeLogBufferTotalDesc, prometheus.GaugeValue, float64(lb.Total), lb.NodeId, labels...)
+	}
+
+	for _, dpb := range usage.DiskPageBuffer {
+		send(dataNodePagesWrittenDesc, prometheus.CounterValue, float64(dpb.PagesWritten), dpb.NodeId)
+		send(dataNodePagesWrittenLCPDesc, prometheus.CounterValue, float64(dpb.PagesWrittenLCP), dpb.NodeId)
+		send(dataNodePagesReadDesc, prometheus.CounterValue, float64(dpb.PagesRead), dpb.NodeId)
+		send(dataNodeLogWaitsDesc, prometheus.CounterValue, float64(dpb.LogWaits), dpb.NodeId)
+		send(dataNodePageRequestsDirectReturnDesc,
+			prometheus.CounterValue, float64(dpb.PageRequestsDirectReturn), dpb.NodeId)
+		send(dataNodePageRequestsWaitQueueDesc,
+			prometheus.CounterValue, float64(dpb.PageRequestsWaitQueue), dpb.NodeId)
+		send(dataNodePageRequestsWaitIODesc, prometheus.CounterValue, float64(dpb.PageRequestsWaitIO), dpb.NodeId)
+	}
+
+	for _, ts := range usage.ThreadStat {
+		labels := []string{strconv.Itoa(ts.ThreadNo), ts.ThreadName}
+		send(dataNodeThreadLoopsDesc, prometheus.CounterValue, float64(ts.Loops), ts.NodeId, labels...)
+		send(dataNodeThreadSignalsDesc, prometheus.CounterValue, float64(ts.Signals), ts.NodeId, labels...)
+		send(dataNodeThreadWaitsDesc, prometheus.CounterValue, float64(ts.Waits), ts.NodeId, labels...)
+		// The CPU times are reported in microseconds
+		send(dataNodeThreadUserCPUDesc, prometheus.CounterValue, float64(ts.UserTime)/1e6, ts.NodeId, labels...)
+		send(dataNodeThreadSystemCPUDesc, prometheus.CounterValue, float64(ts.SystemTime)/1e6, ts.NodeId, labels...)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// getRedundancyLevelMigrationBackupName returns the name of the NdbClusterBackup
+// that backs up the MySQL Cluster before it is migrated to a new redundancyLevel.
+func getRedundancyLevelMigrationBackupName(nc *v1.NdbCluster) string {
+	return fmt.Sprintf("%s-redundancy-migration-%d", nc.Name, nc.Generation)
+}
+
+// reconcileRedundancyLevelMigration migrates the MySQL Cluster to the
+// redundancyLevel specified in the spec. The data is first backed up via
+// an NdbClusterBackup. All the data nodes are then stopped, and the config
+// map is patched with the new redundancyLevel, which starts them again via
+// an initial system restart. The backup is finally restored into the MySQL
+// Cluster. The progress is tracked in status.redundancyLevelMigration, and
+// the migration can be aborted, by reverting spec.redundancyLevel, until
+// the backup completes.
+func (sc *SyncContext) reconcileRedundancyLevelMigration(ctx context.Context) syncResult {
+	nc := sc.ndb
+	cs := sc.configSummary
+	migration := nc.Status.RedundancyLevelMigration.DeepCopy()
+
+	if cs.RedundancyLevel == nc.Spec.RedundancyLevel {
+		if migration == nil {
+			// No migration has been requested so far
+			return continueProcessing()
+		}
+
+		if nc.CanAbortRedundancyLevelMigration() && migration.FromRedundancyLevel == cs.RedundancyLevel {
+			// The spec.redundancyLevel was reverted before the initial system restart
+			migration.Phase = v1.NdbRedundancyLevelMigrationPhaseAborted
+			migration.Message = fmt.Sprintf(MessageRedundancyLevelMigrationAborted, migration.ToRedundancyLevel)
+			sc.redundancyLevelMigration = migration
+			klog.Infof("Migration of NdbCluster %q to redundancyLevel %d was aborted",
+				getNamespacedName(nc), migration.ToRedundancyLevel)
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRedundancyLevelMigrationAborted,
+				ActionMigrate, MessageRedundancyLevelMigrationAborted, migration.ToRedundancyLevel)
+			return continueProcessing()
+		}
+
+		switch migration.Phase {
+		case v1.NdbRedundancyLevelMigrationPhaseInitialSystemRestart:
+			// The config map has the new redundancyLevel and
+			// the data nodes have been started with it.
+			if sc.dataNodeSfSet.Status.ReadyReplicas != cs.NumOfDataNodes {
+				klog.Infof("Waiting for the data nodes to complete the initial system restart")
+				return finishProcessing()
+			}
+
+			migration.Phase = v1.NdbRedundancyLevelMigrationPhaseRestoring
+			migration.Message = fmt.Sprintf("Restoring backup %d", migration.BackupId)
+			sc.redundancyLevelMigration = migration
+			return sc.restoreRedundancyLevelMigrationBackup(ctx, migration)
+
+		case v1.NdbRedundancyLevelMigrationPhaseRestoring:
+			// The operator was interrupted during the restore
+			sc.redundancyLevelMigration = migration
+			return sc.restoreRedundancyLevelMigrationBackup(ctx, migration)
+		}
+
+		// The migration has either completed or failed during the restore
+		return continueProcessing()
+	}
+
+	// The spec.redundancyLevel differs from the config
+	if migration == nil ||
+		migration.ToRedundancyLevel != nc.Spec.RedundancyLevel ||
+		migration.Phase == v1.NdbRedundancyLevelMigrationPhaseCompleted ||
+		migration.Phase == v1.NdbRedundancyLevelMigrationPhaseAborted {
+		// Start a new migration
+		migration = &v1.NdbRedundancyLevelMigrationStatus{
+			FromRedundancyLevel: cs.RedundancyLevel,
+			ToRedundancyLevel:   nc.Spec.RedundancyLevel,
+			Phase:               v1.NdbRedundancyLevelMigrationPhaseBackingUp,
+			BackupName:          getRedundancyLevelMigrationBackupName(nc),
+		}
+		klog.Infof("Migrating NdbCluster %q from redundancyLevel %d to %d",
+			getNamespacedName(nc), cs.RedundancyLevel, nc.Spec.RedundancyLevel)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRedundancyLevelMigrationStarted, ActionMigrate,
+			MessageRedundancyLevelMigrationStarted, cs.RedundancyLevel, nc.Spec.RedundancyLevel)
+
+		if !nc.HasBackupsRetainedAcrossInitialRestart() {
+			// The backup would be lost during the initial system restart
+			migration.Phase = v1.NdbRedundancyLevelMigrationPhaseFailed
+			migration.Message = fmt.Sprintf("The backup files written to %q are not retained across "+
+				"the initial system restart. Use a data node PVC and a BackupDataDir inside it.",
+				nc.GetDataNodeBackupDataDir())
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonRedundancyLevelMigrationFailed,
+				ActionMigrate, migration.Message)
+		}
+	}
+	sc.redundancyLevelMigration = migration
+
+	switch migration.Phase {
+	case v1.NdbRedundancyLevelMigrationPhaseFailed:
+		// The migration failed before the initial system restart.
+		// Do not retry it unless the migration is requested again.
+		klog.Errorf("Migration of NdbCluster %q to redundancyLevel %d has failed : %s",
+			getNamespacedName(nc), migration.ToRedundancyLevel, migration.Message)
+		return finishProcessing()
+
+	case v1.NdbRedundancyLevelMigrationPhaseBackingUp:
+		if sr := sc.backupBeforeRedundancyLevelMigration(ctx, migration); sr.stopSync() {
+			return sr
+		}
+	}
+
+	// The backup is complete. Restart the data nodes with the new redundancyLevel.
+	return sc.startInitialSystemRestart(ctx)
+}
+
+// backupBeforeRedundancyLevelMigration backs up the MySQL Cluster via an
+// NdbClusterBackup and waits for it to complete. The migration is moved to
+// the InitialSystemRestart phase, and the NdbCluster status is updated, once
+// the backup completes, as the migration cannot be aborted beyond this point.
+func (sc *SyncContext) backupBeforeRedundancyLevelMigration(
+	ctx context.Context, migration *v1.NdbRedundancyLevelMigrationStatus) syncResult {
+	nc := sc.ndb
+	ncb, err := sc.ndbBackupsLister.NdbClusterBackups(nc.Namespace).Get(migration.BackupName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to retrieve NdbClusterBackup %q : %s",
+				getNamespacedName2(nc.Namespace, migration.BackupName), err)
+			return errorWhileProcessing(err)
+		}
+
+		// Start the backup
+		ncb = &v1.NdbClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            migration.BackupName,
+				Namespace:       nc.Namespace,
+				Labels:          nc.GetLabels(),
+				OwnerReferences: nc.GetOwnerReferences(),
+			},
+			Spec: v1.NdbClusterBackupSpec{
+				ClusterName: nc.Name,
+			},
+		}
+		if _, err = sc.ndbClientset().MysqlV1().NdbClusterBackups(nc.Namespace).Create(
+			ctx, ncb, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.Errorf("Failed to create NdbClusterBackup %q : %s", getNamespacedName(ncb), err)
+			return errorWhileProcessing(err)
+		}
+
+		klog.Infof("Created NdbClusterBackup %q to back up the data before the migration", getNamespacedName(ncb))
+		migration.Message = fmt.Sprintf("Waiting for NdbClusterBackup %q to complete", ncb.Name)
+		// The NdbCluster will be requeued once the backup completes
+		return finishProcessing()
+	}
+
+	if err = sc.isOwnedByNdbCluster(ncb); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	switch ncb.Status.Phase {
+	case v1.NdbClusterBackupPhaseCompleted:
+		// Backup completed
+	case v1.NdbClusterBackupPhaseFailed:
+		migration.Phase = v1.NdbRedundancyLevelMigrationPhaseFailed
+		migration.Message = fmt.Sprintf("NdbClusterBackup %q failed : %s", ncb.Name, ncb.Status.Message)
+		klog.Errorf("Migration of NdbCluster %q to redundancyLevel %d failed : %s",
+			getNamespacedName(nc), migration.ToRedundancyLevel, migration.Message)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonRedundancyLevelMigrationFailed,
+			ActionMigrate, migration.Message)
+		return finishProcessing()
+	default:
+		klog.Infof("Waiting for NdbClusterBackup %q to complete", getNamespacedName(ncb))
+		migration.Message = fmt.Sprintf("Waiting for NdbClusterBackup %q to complete", ncb.Name)
+		return finishProcessing()
+	}
+
+	// Note down the backup and persist the start of the initial
+	// system restart before stopping any of the data nodes.
+	migration.BackupId = ncb.Status.BackupId
+	migration.BackupLocations = ncb.Status.Locations
+	migration.Phase = v1.NdbRedundancyLevelMigrationPhaseInitialSystemRestart
+	migration.Message = fmt.Sprintf(
+		"Restarting all the data nodes with redundancyLevel %d", migration.ToRedundancyLevel)
+	if _, err = sc.updateNdbClusterStatus(ctx); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	return continueProcessing()
+}
+
+// startInitialSystemRestart stops all the data nodes and scales down the data
+// node StatefulSet to 0 replicas, and then patches the config map with the new
+// redundancyLevel. The data node StatefulSet is scaled up again, with all the
+// data nodes starting together with the --initial flag, by the later syncs
+// once the Management Servers have been restarted with the new config.
+func (sc *SyncContext) startInitialSystemRestart(ctx context.Context) syncResult {
+	ndbmtdSfset := sc.dataNodeSfSet
+	if *(ndbmtdSfset.Spec.Replicas) != 0 {
+		stoppedNodeIds, err := sc.stopRunningDataNodes(ctx)
+		if err != nil {
+			return errorWhileProcessing(err)
+		}
+		if len(stoppedNodeIds) != 0 {
+			klog.Infof("Stopped all the data nodes %v to change the redundancyLevel", stoppedNodeIds)
+		}
+
+		// Delete all the data node pods. The config map is patched in a
+		// later sync if the StatefulSet controller has to process this first.
+		updatedSfset := ndbmtdSfset.DeepCopy()
+		var replicas int32
+		updatedSfset.Spec.Replicas = &replicas
+		if sr := sc.ndbmtdController.patchStatefulSet(ctx, ndbmtdSfset, updatedSfset); sr.stopSync() {
+			return sr
+		}
+	}
+
+	// Patch the config map with the new redundancyLevel
+	if _, err := sc.patchConfigMap(ctx); err != nil {
+		klog.Errorf("Failed to patch the ConfigMap. Error : %v", err)
+		return errorWhileProcessing(err)
+	}
+
+	// The data nodes will be started with the new
+	// config once the Management Servers are updated.
+	return finishProcessing()
+}
+
+// restoreRedundancyLevelMigrationBackup restores the backup taken before the
+// initial system restart by running the restore steps, one after the other,
+// as Jobs. Every Job reads the backup files from the PVC of the data node
+// that wrote them. The restore progress is derived from the Jobs, allowing
+// the restore to be resumed after an operator restart.
+func (sc *SyncContext) restoreRedundancyLevelMigrationBackup(
+	ctx context.Context, migration *v1.NdbRedundancyLevelMigrationStatus) syncResult {
+	nc := sc.ndb
+	restoreSource := &v1.NdbClusterRestoreSource{
+		BackupId: migration.BackupId,
+	}
+	locations := make(map[int32]*v1.NdbClusterBackupLocation)
+	for i := range migration.BackupLocations {
+		location := &migration.BackupLocations[i]
+		restoreSource.DataNodeIds = append(restoreSource.DataNodeIds, location.NodeId)
+		locations[location.NodeId] = location
+	}
+
+	// Generate the restore status from the Jobs
+	restoreStatus := newRestoreStatus(migration.BackupId, restoreSource.DataNodeIds)
+	migration.Restore = restoreStatus
+	failedJobName, sr := sc.runRestoreJobs(ctx, getRestoreSteps(restoreSource), restoreStatus,
+		func(rs restoreStep) *batchv1.Job {
+			return resources.NewMigrationRestoreJob(nc, rs.step, migration.BackupId, locations[rs.nodeId])
+		})
+	if failedJobName != "" {
+		return sc.failRedundancyLevelMigration(migration)
+	}
+	if sr.stopSync() {
+		return sr
+	}
+
+	// All the restore steps have completed
+	migration.Phase = v1.NdbRedundancyLevelMigrationPhaseCompleted
+	migration.Message = fmt.Sprintf(MessageRedundancyLevelMigrationCompleted, migration.ToRedundancyLevel)
+	klog.Infof("NdbCluster %q was successfully migrated to redundancyLevel %d",
+		getNamespacedName(nc), migration.ToRedundancyLevel)
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRedundancyLevelMigrationCompleted,
+		ActionMigrate, MessageRedundancyLevelMigrationCompleted, migration.ToRedundancyLevel)
+	return continueProcessing()
+}
+
+// failRedundancyLevelMigration marks the migration as failed as one of the
+// restore Jobs has failed. The MySQL Cluster continues to run with the new
+// redundancyLevel, and the backup has to be restored manually.
+func (sc *SyncContext) failRedundancyLevelMigration(migration *v1.NdbRedundancyLevelMigrationStatus) syncResult {
+	nc := sc.ndb
+	migration.Phase = v1.NdbRedundancyLevelMigrationPhaseFailed
+	migration.Message = fmt.Sprintf("Failed to restore backup %d after changing the redundancyLevel "+
+		"to %d. The backup files are retained in the data node PVCs to be restored manually : %s",
+		migration.BackupId, migration.ToRedundancyLevel, migration.Restore.Message)
+	klog.Errorf("Migration of NdbCluster %q to redundancyLevel %d failed : %s",
+		getNamespacedName(nc), migration.ToRedundancyLevel, migration.Restore.Message)
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonRedundancyLevelMigrationFailed,
+		ActionMigrate, migration.Message)
+
+	// Continue reconciling the rest of the MySQL Cluster
+	return continueProcessing()
+}
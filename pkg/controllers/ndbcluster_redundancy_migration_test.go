@@ -0,0 +1,406 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+)
+
+func newTestMigrationNdbCluster(redundancyLevel int32) *v1.NdbCluster {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test-migration", 2)
+	nc.UID = "test-migration-uid"
+	nc.Generation = 2
+	nc.Spec.RedundancyLevel = redundancyLevel
+	nc.Spec.DataNode.PVCSpec = &corev1.PersistentVolumeClaimSpec{}
+	return nc
+}
+
+// newTestMigrationStatus returns the status of a migration from redundancyLevel 1
+// to 2, in the given phase, whose backup was taken by the data nodes 3 and 4
+func newTestMigrationStatus(
+	nc *v1.NdbCluster, phase v1.NdbRedundancyLevelMigrationPhase) *v1.NdbRedundancyLevelMigrationStatus {
+	migration := &v1.NdbRedundancyLevelMigrationStatus{
+		FromRedundancyLevel: 1,
+		ToRedundancyLevel:   2,
+		Phase:               phase,
+		BackupName:          getRedundancyLevelMigrationBackupName(nc),
+		BackupId:            5,
+	}
+	for i, nodeId := range []int32{3, 4} {
+		podName := fmt.Sprintf("%s-%d", nc.GetWorkloadName(constants.NdbNodeTypeNdbmtd), i)
+		migration.BackupLocations = append(migration.BackupLocations, v1.NdbClusterBackupLocation{
+			NodeId:                    nodeId,
+			PodName:                   podName,
+			PersistentVolumeClaimName: statefulset.GetDataNodePVCName(podName),
+			Path:                      ndbconfig.GetDataNodeBackupPath(nc, 5),
+		})
+	}
+	return migration
+}
+
+// newTestMigrationBackup returns the NdbClusterBackup taken
+// for the migration of the given NdbCluster, in the given phase.
+func newTestMigrationBackup(nc *v1.NdbCluster, phase v1.NdbClusterBackupPhase) *v1.NdbClusterBackup {
+	return &v1.NdbClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getRedundancyLevelMigrationBackupName(nc),
+			Namespace:       nc.Namespace,
+			Labels:          nc.GetLabels(),
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: v1.NdbClusterBackupSpec{
+			ClusterName: nc.Name,
+		},
+		Status: v1.NdbClusterBackupStatus{
+			Phase:   phase,
+			Message: "backup aborted",
+		},
+	}
+}
+
+// runMigrationSync runs reconcileRedundancyLevelMigration once, with the given
+// redundancyLevel in the config, and returns the ndb client used and the result
+func runMigrationSync(t *testing.T, nc *v1.NdbCluster, configRedundancyLevel int32,
+	backups ...runtime.Object) (*fake.Clientset, *SyncContext, syncResult) {
+	t.Helper()
+
+	ndbClient := fake.NewSimpleClientset(backups...)
+	ndbIf := ndbinformers.NewSharedInformerFactory(ndbClient, 0)
+	backupInformer := ndbIf.Mysql().V1().NdbClusterBackups()
+	backupLister := backupInformer.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, backupInformer.Informer().HasSynced); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	sc := &SyncContext{
+		ndb: nc,
+		configSummary: &ndbconfig.ConfigSummary{
+			RedundancyLevel: configRedundancyLevel,
+		},
+		ndbClient:        ndbClient,
+		ndbBackupsLister: backupLister,
+		recorder:         newEventRecorder(k8sfake.NewSimpleClientset()),
+	}
+
+	return ndbClient, sc, sc.reconcileRedundancyLevelMigration(context.TODO())
+}
+
+func TestRedundancyLevelMigrationStartsWithBackup(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	ndbClient, sc, sr := runMigrationSync(t, nc, 1)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+
+	backupName := getRedundancyLevelMigrationBackupName(nc)
+	ncb, err := ndbClient.MysqlV1().NdbClusterBackups(nc.Namespace).Get(
+		context.TODO(), backupName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the NdbClusterBackup %q to be created : %s", backupName, err)
+	}
+	if !metav1.IsControlledBy(ncb, nc) || ncb.Spec.ClusterName != nc.Name {
+		t.Errorf("NdbClusterBackup %q does not belong to the NdbCluster", backupName)
+	}
+
+	migration := sc.redundancyLevelMigration
+	if migration == nil ||
+		migration.Phase != v1.NdbRedundancyLevelMigrationPhaseBackingUp ||
+		migration.FromRedundancyLevel != 1 || migration.ToRedundancyLevel != 2 {
+		t.Errorf("Unexpected migration status : %+v", migration)
+	}
+}
+
+func TestRedundancyLevelMigrationBackupFailureAndAbort(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	_, sc, sr := runMigrationSync(t, nc, 1, newTestMigrationBackup(nc, v1.NdbClusterBackupPhaseFailed))
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	if sc.redundancyLevelMigration.Phase != v1.NdbRedundancyLevelMigrationPhaseFailed {
+		t.Fatalf("Expected the migration to fail but got %+v", sc.redundancyLevelMigration)
+	}
+
+	// A failed migration should not be retried
+	nc.Status.RedundancyLevelMigration = sc.redundancyLevelMigration
+	if _, _, sr = runMigrationSync(t, nc, 1); !sr.stopSync() {
+		t.Error("Expected the sync to stop after a failed migration")
+	}
+
+	// Reverting the redundancyLevel should abort the migration
+	nc.Spec.RedundancyLevel = 1
+	_, sc, sr = runMigrationSync(t, nc, 1)
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the abort but got %v", sr.getError())
+	}
+	if sc.redundancyLevelMigration.Phase != v1.NdbRedundancyLevelMigrationPhaseAborted {
+		t.Errorf("Expected the migration to be aborted but got %+v", sc.redundancyLevelMigration)
+	}
+}
+
+func TestRedundancyLevelMigrationRequiresRetainedBackups(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	nc.Spec.DataNode.PVCSpec = nil
+	ndbClient, sc, sr := runMigrationSync(t, nc, 1)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	if sc.redundancyLevelMigration.Phase != v1.NdbRedundancyLevelMigrationPhaseFailed {
+		t.Errorf("Expected the migration to fail without a data node PVC but got %+v", sc.redundancyLevelMigration)
+	}
+	if backups, _ := ndbClient.MysqlV1().NdbClusterBackups(nc.Namespace).List(
+		context.TODO(), metav1.ListOptions{}); len(backups.Items) != 0 {
+		t.Error("Expected no backup to be taken when the backup files cannot be retained")
+	}
+}
+
+// fakeConfigMapControl calls onPatch, instead of patching the
+// config map, to verify the state of the MySQL Cluster at that point.
+type fakeConfigMapControl struct {
+	ConfigMapControlInterface
+	onPatch func()
+}
+
+func (fcmc *fakeConfigMapControl) PatchConfigMap(context.Context, *SyncContext) (*corev1.ConfigMap, error) {
+	fcmc.onPatch()
+	return nil, nil
+}
+
+func TestRedundancyLevelMigrationInitialSystemRestart(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	nc.Status.RedundancyLevelMigration = newTestMigrationStatus(nc, v1.NdbRedundancyLevelMigrationPhaseInitialSystemRestart)
+	sc, k8sClient := newTestDataNodeSyncContext(t, 2, nil)
+	sc.ndb = nc
+	sc.configSummary.RedundancyLevel = 1
+	sc.configSummary.NdbClusterGeneration = 1
+	clusterStatus := newTestClusterStatus()
+	delete(clusterStatus, 5)
+	delete(clusterStatus, 6)
+	fms := newFakeMgmServer(t, clusterStatus)
+
+	// The config map should be patched with the new redundancyLevel
+	// only after all the data nodes have been stopped and their pods
+	// deleted, so that they all restart together with the new config.
+	configMapPatched := false
+	sc.configMapController = &fakeConfigMapControl{
+		onPatch: func() {
+			configMapPatched = true
+			if !fms.hasCall("StopNodes [3 4]") {
+				t.Error("Config map patched before stopping the data nodes")
+			}
+			if replicas := *getTestDataNodeStatefulSet(t, sc, k8sClient).Spec.Replicas; replicas != 0 {
+				t.Errorf("Config map patched while the data node StatefulSet has %d replicas", replicas)
+			}
+		},
+	}
+
+	sr := sc.reconcileRedundancyLevelMigration(context.TODO())
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	if !configMapPatched {
+		t.Fatal("Expected the config map to be patched with the new redundancyLevel")
+	}
+
+	// The data nodes are starting with the new redundancyLevel
+	sc.configSummary.RedundancyLevel = 2
+	sc.dataNodeSfSet = getTestDataNodeStatefulSet(t, sc, k8sClient)
+	sc.dataNodeSfSet.Status.ReadyReplicas = 1
+	if sr = sc.reconcileRedundancyLevelMigration(context.TODO()); !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to wait for the data nodes but got %v", sr.getError())
+	}
+	if sc.redundancyLevelMigration.Phase != v1.NdbRedundancyLevelMigrationPhaseInitialSystemRestart {
+		t.Errorf("Expected the migration to wait for the initial system restart but got %+v",
+			sc.redundancyLevelMigration)
+	}
+}
+
+// newTestMigrationRestoreJob returns the migration restore
+// Job of the given step with the given finished condition
+func newTestMigrationRestoreJob(nc *v1.NdbCluster, step resources.RestoreStep,
+	nodeId int32, conditionType batchv1.JobConditionType) *batchv1.Job {
+	migration := newTestMigrationStatus(nc, v1.NdbRedundancyLevelMigrationPhaseRestoring)
+	job := resources.NewMigrationRestoreJob(nc, step, migration.BackupId, &migration.BackupLocations[nodeId-3])
+	if conditionType != "" {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:   conditionType,
+				Status: corev1.ConditionTrue,
+			},
+		}
+	}
+	return job
+}
+
+// runMigrationRestoreSync runs reconcileRedundancyLevelMigration once, after the
+// data nodes have restarted with the new redundancyLevel, with the given Jobs,
+// and returns the k8s client used and the result
+func runMigrationRestoreSync(t *testing.T, nc *v1.NdbCluster,
+	jobs ...runtime.Object) (*k8sfake.Clientset, *SyncContext, syncResult) {
+	t.Helper()
+
+	k8sClient := k8sfake.NewSimpleClientset(jobs...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	jobInformer := k8sIf.Batch().V1().Jobs()
+	jobLister := jobInformer.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	k8sIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, jobInformer.Informer().HasSynced); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	sc := &SyncContext{
+		ndb: nc,
+		configSummary: &ndbconfig.ConfigSummary{
+			NumOfDataNodes:  nc.Spec.DataNode.NodeCount,
+			RedundancyLevel: nc.Spec.RedundancyLevel,
+		},
+		dataNodeSfSet:    newTestStatefulSet(nc.Spec.DataNode.NodeCount),
+		kubernetesClient: k8sClient,
+		jobLister:        jobLister,
+		recorder:         newEventRecorder(k8sClient),
+	}
+
+	return k8sClient, sc, sc.reconcileRedundancyLevelMigration(context.TODO())
+}
+
+func TestRedundancyLevelMigrationRestoreStartsWithMetadata(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	nc.Status.RedundancyLevelMigration = newTestMigrationStatus(nc, v1.NdbRedundancyLevelMigrationPhaseInitialSystemRestart)
+	k8sClient, sc, sr := runMigrationRestoreSync(t, nc)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	migration := sc.redundancyLevelMigration
+	if migration.Phase != v1.NdbRedundancyLevelMigrationPhaseRestoring ||
+		migration.Restore == nil || migration.Restore.Phase != v1.NdbClusterRestorePhaseMetadata {
+		t.Fatalf("Expected the metadata to be restored but got %+v", migration)
+	}
+
+	// The Job should read the backup files from the
+	// data node PVC, on the K8s node of the data node pod
+	jobName := resources.GetMigrationRestoreJobName(nc, resources.RestoreStepMetadata, 5, 3)
+	job, err := k8sClient.BatchV1().Jobs(nc.Namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the Job %q to be created : %s", jobName, err)
+	}
+	location := migration.BackupLocations[0]
+	podSpec := job.Spec.Template.Spec
+	if claim := podSpec.Volumes[0].PersistentVolumeClaim; claim == nil ||
+		claim.ClaimName != location.PersistentVolumeClaimName {
+		t.Errorf("Expected the Job to mount the PVC %q but got %+v",
+			location.PersistentVolumeClaimName, podSpec.Volumes[0])
+	}
+	if !strings.Contains(strings.Join(podSpec.Containers[0].Command, " "),
+		"--backup-path=/var/lib/ndb/backup/BACKUP/BACKUP-5") {
+		t.Errorf("Unexpected restore command : %v", podSpec.Containers[0].Command)
+	}
+	if affinity := podSpec.Affinity; affinity == nil || affinity.PodAffinity == nil ||
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].
+			LabelSelector.MatchLabels["statefulset.kubernetes.io/pod-name"] != location.PodName {
+		t.Errorf("Expected the Job to run along with the pod %q but got %+v", location.PodName, affinity)
+	}
+}
+
+func TestRedundancyLevelMigrationRestoreProgress(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	nc.Status.RedundancyLevelMigration = newTestMigrationStatus(nc, v1.NdbRedundancyLevelMigrationPhaseRestoring)
+
+	// The restore resumes from the Jobs after an operator restart
+	_, sc, sr := runMigrationRestoreSync(t, nc,
+		newTestMigrationRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepData, 4, ""),
+	)
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	restoreStatus := sc.redundancyLevelMigration.Restore
+	if restoreStatus.Phase != v1.NdbClusterRestorePhaseData ||
+		restoreStatus.Nodes[0].Phase != v1.NdbClusterRestoreNodePhaseRestored ||
+		restoreStatus.Nodes[1].Phase != v1.NdbClusterRestoreNodePhaseRestoring {
+		t.Errorf("Unexpected restore status : %+v", restoreStatus)
+	}
+
+	// All the restore steps have completed
+	_, sc, sr = runMigrationRestoreSync(t, nc,
+		newTestMigrationRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepData, 4, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepIndexes, 3, batchv1.JobComplete),
+	)
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the restore but got %v", sr.getError())
+	}
+	if migration := sc.redundancyLevelMigration; migration.Phase != v1.NdbRedundancyLevelMigrationPhaseCompleted ||
+		migration.Restore.Phase != v1.NdbClusterRestorePhaseCompleted {
+		t.Errorf("Expected the migration to complete but got %+v", migration)
+	}
+}
+
+func TestRedundancyLevelMigrationRestoreFailure(t *testing.T) {
+	nc := newTestMigrationNdbCluster(2)
+	nc.Status.RedundancyLevelMigration = newTestMigrationStatus(nc, v1.NdbRedundancyLevelMigrationPhaseRestoring)
+	k8sClient, sc, sr := runMigrationRestoreSync(t, nc,
+		newTestMigrationRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestMigrationRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobFailed),
+	)
+
+	// The rest of the MySQL Cluster should still be reconciled
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the failure but got %v", sr.getError())
+	}
+	migration := sc.redundancyLevelMigration
+	if migration.Phase != v1.NdbRedundancyLevelMigrationPhaseFailed ||
+		migration.Restore.Phase != v1.NdbClusterRestorePhaseFailed ||
+		migration.Restore.Nodes[0].Phase != v1.NdbClusterRestoreNodePhaseFailed {
+		t.Errorf("Expected the migration to fail but got %+v", migration)
+	}
+	jobName := resources.GetMigrationRestoreJobName(nc, resources.RestoreStepData, 5, 4)
+	if _, err := k8sClient.BatchV1().Jobs(nc.Namespace).Get(
+		context.TODO(), jobName, metav1.GetOptions{}); err == nil {
+		t.Errorf("Expected the Job %q not to be created after the failure", jobName)
+	}
+
+	// A failed restore should not be retried
+	nc.Status.RedundancyLevelMigration = migration
+	k8sClient, _, sr = runMigrationRestoreSync(t, nc)
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after a failed migration but got %v", sr.getError())
+	}
+	if jobs, _ := k8sClient.BatchV1().Jobs(nc.Namespace).List(
+		context.TODO(), metav1.ListOptions{}); len(jobs.Items) != 0 {
+		t.Errorf("Expected the failed restore not to be retried but got the Jobs %v", jobs.Items)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// restoreStep is a single ndb_restore run required
+// to restore the backup specified in spec.restoreFrom
+type restoreStep struct {
+	step   resources.RestoreStep
+	nodeId int32
+	phase  v1.NdbClusterRestorePhase
+}
+
+// getRestoreSteps returns the steps required to restore the backup
+// specified in spec.restoreFrom in the order they have to be run.
+func getRestoreSteps(restoreFrom *v1.NdbClusterRestoreSource) []restoreStep {
+	// The metadata is restored and the indexes are rebuilt
+	// using the backup files of the first data node.
+	firstNodeId := restoreFrom.DataNodeIds[0]
+	steps := []restoreStep{
+		{resources.RestoreStepMetadata, firstNodeId, v1.NdbClusterRestorePhaseMetadata},
+	}
+	for _, nodeId := range restoreFrom.DataNodeIds {
+		steps = append(steps, restoreStep{resources.RestoreStepData, nodeId, v1.NdbClusterRestorePhaseData})
+	}
+	return append(steps,
+		restoreStep{resources.RestoreStepIndexes, firstNodeId, v1.NdbClusterRestorePhaseIndexes})
+}
+
+// getJobFinishedCondition returns the JobComplete or the JobFailed
+// condition type if the given Job has finished running.
+func getJobFinishedCondition(job *batchv1.Job) (batchv1.JobConditionType, bool) {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return condition.Type, true
+		}
+	}
+	return "", false
+}
+
+// setRestoreNodePhase updates the phase of the given data node in the restore status
+func setRestoreNodePhase(
+	restoreStatus *v1.NdbClusterRestoreStatus, nodeId int32, phase v1.NdbClusterRestoreNodePhase) {
+	for i := range restoreStatus.Nodes {
+		if restoreStatus.Nodes[i].NodeId == nodeId {
+			restoreStatus.Nodes[i].Phase = phase
+			return
+		}
+	}
+}
+
+// reconcileRestore restores the backup specified in spec.restoreFrom into
+// the MySQL Cluster by running the restore steps, one after the other, as
+// Jobs. The sync is continued only after all the steps have completed so
+// that the MySQL Servers are started only after the backup is restored.
+func (sc *SyncContext) reconcileRestore(ctx context.Context) syncResult {
+	nc := sc.ndb
+	restoreFrom := nc.Spec.RestoreFrom
+	if restoreFrom == nil {
+		// Nothing to restore
+		return continueProcessing()
+	}
+
+	if !nc.IsRestoreInProgress() {
+		if nc.Status.Restore.Phase == v1.NdbClusterRestorePhaseFailed {
+			// The restore failed earlier. Do not start the MySQL Servers
+			// on a partially restored MySQL Cluster.
+			klog.Errorf("Restore of backup %d into NdbCluster %q has failed : %s",
+				restoreFrom.BackupId, getNamespacedName(nc), nc.Status.Restore.Message)
+			return finishProcessing()
+		}
+
+		// Backup has already been restored
+		return continueProcessing()
+	}
+
+	// Generate the restore status from the Jobs
+	restoreStatus := newRestoreStatus(restoreFrom.BackupId, restoreFrom.DataNodeIds)
+	sc.restoreStatus = restoreStatus
+	failedJobName, sr := sc.runRestoreJobs(ctx, getRestoreSteps(restoreFrom), restoreStatus,
+		func(rs restoreStep) *batchv1.Job {
+			return resources.NewRestoreJob(nc, rs.step, rs.nodeId)
+		})
+	if failedJobName != "" {
+		klog.Errorf("Restore of backup %d into NdbCluster %q failed : %s",
+			restoreFrom.BackupId, getNamespacedName(nc), restoreStatus.Message)
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonRestoreFailed, ActionRestore,
+			MessageRestoreFailed, failedJobName, restoreFrom.BackupId)
+		return sr
+	}
+	if sr.stopSync() {
+		return sr
+	}
+
+	// All the restore steps have completed
+	klog.Infof("Backup %d was successfully restored into NdbCluster %q",
+		restoreFrom.BackupId, getNamespacedName(nc))
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRestoreCompleted, ActionRestore,
+		MessageRestoreCompleted, restoreFrom.BackupId)
+	return continueProcessing()
+}
+
+// newRestoreStatus returns the status of a restore, of the backup with
+// the given id, that has not restored the data of any of the data nodes yet
+func newRestoreStatus(backupId int32, dataNodeIds []int32) *v1.NdbClusterRestoreStatus {
+	restoreStatus := &v1.NdbClusterRestoreStatus{
+		BackupId: backupId,
+	}
+	for _, nodeId := range dataNodeIds {
+		restoreStatus.Nodes = append(restoreStatus.Nodes, v1.NdbClusterRestoreNodeStatus{
+			NodeId: nodeId,
+			Phase:  v1.NdbClusterRestoreNodePhasePending,
+		})
+	}
+	return restoreStatus
+}
+
+// runRestoreJobs runs the given restore steps, one after the other, via the
+// Jobs returned by newJob, and updates the restoreStatus from those Jobs.
+// As the progress is derived from the Jobs, the restore resumes from the
+// step it was at when the operator restarts. The returned syncResult
+// continues the sync only once all the steps have completed. If a step
+// has failed, the name of its Job is returned and the restore is stopped
+// as ndb_restore cannot resume a failed run.
+func (sc *SyncContext) runRestoreJobs(ctx context.Context, steps []restoreStep,
+	restoreStatus *v1.NdbClusterRestoreStatus,
+	newJob func(rs restoreStep) *batchv1.Job) (failedJobName string, sr syncResult) {
+	nc := sc.ndb
+	backupId := restoreStatus.BackupId
+	for _, rs := range steps {
+		restoreStatus.Phase = rs.phase
+		if rs.step == resources.RestoreStepData {
+			setRestoreNodePhase(restoreStatus, rs.nodeId, v1.NdbClusterRestoreNodePhaseRestoring)
+		}
+
+		newRestoreJob := newJob(rs)
+		jobName := newRestoreJob.Name
+		job, err := sc.jobLister.Jobs(nc.Namespace).Get(jobName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to retrieve the Job %q : %s", getNamespacedName2(nc.Namespace, jobName), err)
+				return "", errorWhileProcessing(err)
+			}
+
+			// Start the restore step
+			if _, err = sc.kubernetesClient.BatchV1().Jobs(nc.Namespace).Create(
+				ctx, newRestoreJob, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				klog.Errorf("Failed to create the Job %q : %s", getNamespacedName(newRestoreJob), err)
+				return "", errorWhileProcessing(err)
+			}
+
+			klog.Infof("Created Job %q to restore backup %d", getNamespacedName(newRestoreJob), backupId)
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonRestoreStarted, ActionRestore,
+				MessageRestoreStarted, jobName, backupId)
+			restoreStatus.Message = getRestoreStepMessage(rs, backupId)
+
+			// The NdbCluster will be requeued once the Job completes
+			return "", finishProcessing()
+		}
+
+		if err = sc.isOwnedByNdbCluster(job); err != nil {
+			return "", errorWhileProcessing(err)
+		}
+
+		conditionType, finished := getJobFinishedCondition(job)
+		if !finished {
+			// The restore step is still running
+			klog.Infof("Waiting for the Job %q to complete", getNamespacedName(job))
+			restoreStatus.Message = getRestoreStepMessage(rs, backupId)
+			return "", finishProcessing()
+		}
+
+		if conditionType == batchv1.JobFailed {
+			restoreStatus.Phase = v1.NdbClusterRestorePhaseFailed
+			if rs.step == resources.RestoreStepData {
+				setRestoreNodePhase(restoreStatus, rs.nodeId, v1.NdbClusterRestoreNodePhaseFailed)
+			}
+			restoreStatus.Message = fmt.Sprintf(MessageRestoreFailed, jobName, backupId)
+			return jobName, finishProcessing()
+		}
+
+		// Restore step completed
+		if rs.step == resources.RestoreStepData {
+			setRestoreNodePhase(restoreStatus, rs.nodeId, v1.NdbClusterRestoreNodePhaseRestored)
+		}
+	}
+
+	// All the restore steps have completed
+	restoreStatus.Phase = v1.NdbClusterRestorePhaseCompleted
+	restoreStatus.Message = fmt.Sprintf(MessageRestoreCompleted, backupId)
+	return "", continueProcessing()
+}
+
+// getRestoreStepMessage returns the restore status message for the given step
+func getRestoreStepMessage(rs restoreStep, backupId int32) string {
+	switch rs.step {
+	case resources.RestoreStepMetadata:
+		return fmt.Sprintf("Restoring the metadata of backup %d", backupId)
+	case resources.RestoreStepData:
+		return fmt.Sprintf("Restoring the data of backup %d written by data node %d", backupId, rs.nodeId)
+	default:
+		return fmt.Sprintf("Rebuilding the indexes of the tables restored from backup %d", backupId)
+	}
+}
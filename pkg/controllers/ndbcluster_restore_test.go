@@ -0,0 +1,156 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+func newTestRestoreNdbCluster() *v1.NdbCluster {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test-restore", 2)
+	nc.UID = "test-restore-uid"
+	nc.Spec.RestoreFrom = &v1.NdbClusterRestoreSource{
+		BackupId:                  3,
+		DataNodeIds:               []int32{3, 4},
+		PersistentVolumeClaimName: "backup-pvc",
+	}
+	return nc
+}
+
+// newTestRestoreJob returns the Job of the given restore step with the given finished condition
+func newTestRestoreJob(nc *v1.NdbCluster, step resources.RestoreStep,
+	nodeId int32, conditionType batchv1.JobConditionType) *batchv1.Job {
+	job := resources.NewRestoreJob(nc, step, nodeId)
+	if conditionType != "" {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:   conditionType,
+				Status: corev1.ConditionTrue,
+			},
+		}
+	}
+	return job
+}
+
+// runRestoreSync runs reconcileRestore once and returns the k8s client used and the result
+func runRestoreSync(t *testing.T, nc *v1.NdbCluster,
+	jobs ...runtime.Object) (*k8sfake.Clientset, *SyncContext, syncResult) {
+	t.Helper()
+
+	k8sClient := k8sfake.NewSimpleClientset(jobs...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	jobInformer := k8sIf.Batch().V1().Jobs()
+	jobLister := jobInformer.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	k8sIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, jobInformer.Informer().HasSynced); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	sc := &SyncContext{
+		ndb:              nc,
+		kubernetesClient: k8sClient,
+		jobLister:        jobLister,
+		recorder:         newEventRecorder(k8sClient),
+	}
+
+	return k8sClient, sc, sc.reconcileRestore(context.TODO())
+}
+
+func TestReconcileRestoreStartsWithMetadata(t *testing.T) {
+	nc := newTestRestoreNdbCluster()
+	k8sClient, sc, sr := runRestoreSync(t, nc)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+
+	jobName := resources.GetRestoreJobName(nc, resources.RestoreStepMetadata, 3)
+	if _, err := k8sClient.BatchV1().Jobs(nc.Namespace).Get(
+		context.TODO(), jobName, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the Job %q to be created : %s", jobName, err)
+	}
+
+	if sc.restoreStatus == nil || sc.restoreStatus.Phase != v1.NdbClusterRestorePhaseMetadata {
+		t.Errorf("Expected the restore phase to be %q but got %+v",
+			v1.NdbClusterRestorePhaseMetadata, sc.restoreStatus)
+	}
+}
+
+func TestReconcileRestoreProgress(t *testing.T) {
+	nc := newTestRestoreNdbCluster()
+	_, sc, sr := runRestoreSync(t, nc,
+		newTestRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepData, 4, ""),
+	)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+
+	restoreStatus := sc.restoreStatus
+	if restoreStatus.Phase != v1.NdbClusterRestorePhaseData {
+		t.Errorf("Expected the restore phase to be %q but got %q",
+			v1.NdbClusterRestorePhaseData, restoreStatus.Phase)
+	}
+	if restoreStatus.Nodes[0].Phase != v1.NdbClusterRestoreNodePhaseRestored ||
+		restoreStatus.Nodes[1].Phase != v1.NdbClusterRestoreNodePhaseRestoring {
+		t.Errorf("Unexpected data node restore status : %+v", restoreStatus.Nodes)
+	}
+}
+
+func TestReconcileRestoreCompletionAndFailure(t *testing.T) {
+	nc := newTestRestoreNdbCluster()
+	_, sc, sr := runRestoreSync(t, nc,
+		newTestRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepData, 4, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepIndexes, 3, batchv1.JobComplete),
+	)
+
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the restore but got %v", sr.getError())
+	}
+	if sc.restoreStatus.Phase != v1.NdbClusterRestorePhaseCompleted {
+		t.Errorf("Expected the restore phase to be %q but got %q",
+			v1.NdbClusterRestorePhaseCompleted, sc.restoreStatus.Phase)
+	}
+
+	nc = newTestRestoreNdbCluster()
+	_, sc, sr = runRestoreSync(t, nc,
+		newTestRestoreJob(nc, resources.RestoreStepMetadata, 3, batchv1.JobComplete),
+		newTestRestoreJob(nc, resources.RestoreStepData, 3, batchv1.JobFailed),
+	)
+
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	if sc.restoreStatus.Phase != v1.NdbClusterRestorePhaseFailed ||
+		sc.restoreStatus.Nodes[0].Phase != v1.NdbClusterRestoreNodePhaseFailed {
+		t.Errorf("Expected the restore to fail but got %+v", sc.restoreStatus)
+	}
+
+	// A failed restore should not be retried
+	nc.Status.Restore = sc.restoreStatus
+	if _, _, sr = runRestoreSync(t, nc); !sr.stopSync() {
+		t.Error("Expected the sync to stop after a failed restore")
+	}
+}
@@ -1,14 +1,18 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package controllers
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
@@ -25,10 +29,31 @@ func statusEqual(oldStatus *v1.NdbClusterStatus, newStatus *v1.NdbClusterStatus)
 		oldStatus.ReadyDataNodes == newStatus.ReadyDataNodes &&
 		oldStatus.ReadyMySQLServers == newStatus.ReadyMySQLServers &&
 		oldStatus.GeneratedRootPasswordSecretName == newStatus.GeneratedRootPasswordSecretName &&
-		// TODO: Improve this comparison when more conditions are added
-		oldStatus.Conditions[0].Status == newStatus.Conditions[0].Status &&
-		oldStatus.Conditions[0].Reason == newStatus.Conditions[0].Reason &&
-		oldStatus.Conditions[0].Message == newStatus.Conditions[0].Message
+		reflect.DeepEqual(oldStatus.Restore, newStatus.Restore) &&
+		reflect.DeepEqual(oldStatus.Tablespaces, newStatus.Tablespaces) &&
+		reflect.DeepEqual(oldStatus.RedundancyLevelMigration, newStatus.RedundancyLevelMigration) &&
+		reflect.DeepEqual(oldStatus.TDEPasswordRotation, newStatus.TDEPasswordRotation) &&
+		reflect.DeepEqual(oldStatus.ConflictDetection, newStatus.ConflictDetection) &&
+		oldStatus.Suspended == newStatus.Suspended &&
+		conditionsEqual(oldStatus.Conditions, newStatus.Conditions)
+}
+
+// conditionsEqual checks if the given two lists of conditions are
+// equal. The LastTransitionTime of the conditions is not compared.
+func conditionsEqual(oldConditions, newConditions []v1.NdbClusterCondition) bool {
+	if len(oldConditions) != len(newConditions) {
+		return false
+	}
+
+	for i := range oldConditions {
+		if oldConditions[i].Type != newConditions[i].Type ||
+			oldConditions[i].Status != newConditions[i].Status ||
+			oldConditions[i].Reason != newConditions[i].Reason ||
+			oldConditions[i].Message != newConditions[i].Message {
+			return false
+		}
+	}
+	return true
 }
 
 // calculateNdbClusterStatus generates the current status for the NdbCluster in SyncContext
@@ -68,9 +93,58 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 			}
 		}
 	}
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		numOfMySQLServersRequired += mysqldGroup.NodeCount
+		if groupSfset, exists := sc.mysqldGroupSfsets[mysqldGroup.Name]; exists {
+			numOfReadyMySQLNodes += groupSfset.Status.ReadyReplicas
+		}
+	}
 	status.ReadyMySQLServers = fmt.Sprintf(
 		"Ready:%d/%d", numOfReadyMySQLNodes, numOfMySQLServersRequired)
 
+	// Restore progress of the backup specified in spec.restoreFrom
+	if sc.restoreStatus != nil {
+		status.Restore = sc.restoreStatus
+	} else if nc.Status.Restore != nil {
+		// Restore is not in progress. Retain the final restore status.
+		status.Restore = nc.Status.Restore.DeepCopy()
+	}
+
+	// Disk space usage of the tablespaces
+	if sc.tablespaceStatus != nil {
+		status.Tablespaces = sc.tablespaceStatus
+	} else if nc.Status.Tablespaces != nil {
+		// Disk data objects were not reconciled during this sync. Retain the last known usage.
+		status.Tablespaces = nc.Status.DeepCopy().Tablespaces
+	}
+
+	// Progress of the migration to the redundancyLevel specified in the spec
+	if sc.redundancyLevelMigration != nil {
+		status.RedundancyLevelMigration = sc.redundancyLevelMigration.DeepCopy()
+	} else if nc.Status.RedundancyLevelMigration != nil {
+		// Migration was not reconciled during this sync. Retain the last known status.
+		status.RedundancyLevelMigration = nc.Status.RedundancyLevelMigration.DeepCopy()
+	}
+
+	// Progress of the re-encryption of the data nodes with a new TDE password
+	if sc.tdePasswordRotation != nil {
+		status.TDEPasswordRotation = sc.tdePasswordRotation.DeepCopy()
+	} else if nc.Status.TDEPasswordRotation != nil {
+		// Rotation was not reconciled during this sync. Retain the last known status.
+		status.TDEPasswordRotation = nc.Status.TDEPasswordRotation.DeepCopy()
+	}
+
+	// Conflict counters of the binlog MySQL Server
+	if sc.conflictDetectionStatus != nil {
+		status.ConflictDetection = sc.conflictDetectionStatus
+	} else if nc.Status.ConflictDetection != nil && !sc.conflictFunctionsRemoved {
+		// Conflict functions were not reconciled during this sync. Retain the last known counters.
+		status.ConflictDetection = nc.Status.ConflictDetection.DeepCopy()
+	}
+
+	// All the MySQL Cluster nodes have been stopped as requested by spec.suspended
+	status.Suspended = sc.isSuspended()
+
 	// Set processedGeneration and upToDate condition
 	upToDateCondition := v1.NdbClusterCondition{
 		Type:               v1.NdbClusterUpToDate,
@@ -94,6 +168,50 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 			klog.Errorf("One or more pods owned by the ndbcluster resource %q are failing : \n%s", getNamespacedName(nc), errMsgs)
 			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonError
 			upToDateCondition.Message = strings.Join(errMsgs, "\n")
+		} else if nc.IsPaused() {
+			// The spec update will be applied once the reconciliation is resumed
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonPaused
+			upToDateCondition.Message = fmt.Sprintf(
+				"NdbCluster spec generation %d will be applied once the reconciliation is resumed", nc.Generation)
+		} else if restore := status.Restore; restore != nil &&
+			restore.Phase == v1.NdbClusterRestorePhaseFailed {
+			// The backup specified in spec.restoreFrom could not be restored
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonError
+			upToDateCondition.Message = restore.Message
+		} else if restore != nil && restore.Phase != v1.NdbClusterRestorePhaseCompleted {
+			// The backup specified in spec.restoreFrom is being restored
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonRestore
+			upToDateCondition.Message = restore.Message
+		} else if migration := status.RedundancyLevelMigration; migration != nil &&
+			migration.Phase == v1.NdbRedundancyLevelMigrationPhaseFailed {
+			// The backup or the restore of the backup failed during the migration
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonError
+			upToDateCondition.Message = migration.Message
+		} else if migration != nil &&
+			migration.Phase != v1.NdbRedundancyLevelMigrationPhaseCompleted &&
+			migration.Phase != v1.NdbRedundancyLevelMigrationPhaseAborted {
+			// The MySQL Cluster is being migrated to the new redundancyLevel
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonRedundancyLevelMigration
+			upToDateCondition.Message = migration.Message
+		} else if rotation := status.TDEPasswordRotation; rotation != nil &&
+			rotation.Phase == v1.NdbTDEPasswordRotationPhaseInProgress {
+			// The data nodes are being re-encrypted with the new TDE password
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonTDEPasswordRotation
+			upToDateCondition.Message = rotation.Message
+		} else if nc.Spec.Suspended {
+			// The MySQL Cluster nodes are being stopped
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonSuspend
+			upToDateCondition.Message = "MySQL Cluster nodes are being stopped as spec.suspended is set"
+		} else if sc.isResumeInProgress() {
+			// The MySQL Cluster nodes are being started again
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonResume
+			upToDateCondition.Message = "MySQL Cluster nodes are being started via a system restart"
+		} else if sc.isFullRestartInProgress() {
+			// All the data nodes have been stopped to apply the spec update
+			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonFullRestart
+			upToDateCondition.Message = fmt.Sprintf(
+				"MySQL Cluster is unavailable as all the data nodes are being "+
+					"restarted to apply NdbCluster spec generation %d", nc.Generation)
 		} else if nc.Generation == 1 {
 			// The MySQL Cluster nodes are being started for the first time
 			upToDateCondition.Reason = v1.NdbClusterUptoDateReasonISR
@@ -105,7 +223,295 @@ func (sc *SyncContext) calculateNdbClusterStatus() *v1.NdbClusterStatus {
 				"NdbCluster spec generation %d is being applied to the MySQL Cluster", nc.Generation)
 		}
 	}
-	status.Conditions = append(status.Conditions, upToDateCondition)
+	// Set the paused condition
+	pausedCondition := v1.NdbClusterCondition{
+		Type:               v1.NdbClusterPaused,
+		LastTransitionTime: metav1.Now(),
+	}
+	if nc.IsPaused() {
+		pausedCondition.Status = corev1.ConditionTrue
+		pausedCondition.Reason = v1.NdbClusterPausedReasonAnnotation
+		pausedCondition.Message = fmt.Sprintf(
+			"Reconciliation has been paused via the %s annotation", v1.PausedAnnotation)
+	} else {
+		pausedCondition.Status = corev1.ConditionFalse
+		pausedCondition.Reason = v1.NdbClusterPausedReasonReconciling
+		pausedCondition.Message = "NdbCluster is being reconciled by the operator"
+	}
+	status.Conditions = append(status.Conditions, upToDateCondition, pausedCondition,
+		sc.getAvailableCondition(), sc.getDegradedCondition(), sc.getProgressingCondition())
+	retainLastTransitionTimes(nc.Status.Conditions, status.Conditions)
 
 	return status
 }
+
+// retainLastTransitionTimes copies the LastTransitionTime of the old
+// conditions into the new conditions of the same type whose Status has
+// not changed, so that it records when the Status last changed.
+func retainLastTransitionTimes(oldConditions, newConditions []v1.NdbClusterCondition) {
+	for i := range newConditions {
+		for _, oldCondition := range oldConditions {
+			if oldCondition.Type == newConditions[i].Type {
+				if oldCondition.Status == newConditions[i].Status {
+					newConditions[i].LastTransitionTime = oldCondition.LastTransitionTime
+				}
+				break
+			}
+		}
+	}
+}
+
+// getClusterStatus retrieves the status of the MySQL Cluster nodes via the
+// given Management Server client and records it in the SyncContext, so
+// that the status updates done during the reconciliation can reuse it.
+func (sc *SyncContext) getClusterStatus(mgmClient mgmapi.MgmClient) (mgmapi.ClusterStatus, error) {
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	sc.clusterStatus = clusterStatus
+	sc.clusterStatusRetrieved = true
+	return clusterStatus, nil
+}
+
+// retrieveClusterStatus retrieves the status of the MySQL Cluster nodes
+// from the Management Server into the SyncContext, unless it has already
+// been retrieved during this reconciliation. The status is left nil if the
+// Management Servers are not ready or if the retrieval fails.
+func (sc *SyncContext) retrieveClusterStatus(ctx context.Context) {
+	if sc.clusterStatusRetrieved {
+		// Reuse the status retrieved by the sync or an earlier status update
+		return
+	}
+	sc.clusterStatusRetrieved = true
+
+	if sc.isSuspended() {
+		// All the MySQL Cluster nodes have been stopped
+		return
+	}
+
+	if sc.mgmdNodeSfset == nil || sc.mgmdNodeSfset.Status.ReadyReplicas == 0 {
+		// None of the Management Servers are ready
+		return
+	}
+
+	mgmClient, err := newMgmClient(ctx, sc.kubernetesClient, sc.ndb)
+	if err != nil {
+		klog.Warningf("Failed to connect to the Management Server of NdbCluster %q to retrieve its status : %s",
+			getNamespacedName(sc.ndb), err)
+		return
+	}
+	defer mgmClient.Disconnect()
+
+	if _, err = sc.getClusterStatus(mgmClient); err != nil {
+		klog.Warningf("Failed to retrieve the status of the MySQL Cluster of NdbCluster %q : %s",
+			getNamespacedName(sc.ndb), err)
+	}
+}
+
+// getDisconnectedNodes returns the sorted node ids of the Management and
+// Data Nodes that are disconnected, and the sorted ids of the node groups
+// that have no connected data node, as per the given ClusterStatus. The
+// data nodes that have not been inducted into a node group yet are ignored.
+func getDisconnectedNodes(clusterStatus mgmapi.ClusterStatus) (disconnectedNodeIds, unavailableNodeGroups []int) {
+	// Map of node groups to whether they have a connected data node
+	nodeGroupAvailable := make(map[int]bool)
+	for nodeId, nodeStatus := range clusterStatus {
+		if nodeStatus.IsDataNode() {
+			if nodeStatus.NodeGroup == mgmapi.NodeGroupNewDisconnectedDataNode ||
+				nodeStatus.NodeGroup == mgmapi.NodeGroupNewConnectedDataNode {
+				// New data node that is not part of any node group yet
+				continue
+			}
+			nodeGroupAvailable[nodeStatus.NodeGroup] =
+				nodeGroupAvailable[nodeStatus.NodeGroup] || nodeStatus.IsConnected
+			if nodeStatus.IsConnected {
+				continue
+			}
+		} else if !nodeStatus.IsMgmNode() || nodeStatus.IsConnected {
+			// API node or a connected Management node
+			continue
+		}
+
+		disconnectedNodeIds = append(disconnectedNodeIds, nodeId)
+	}
+
+	for nodeGroup, available := range nodeGroupAvailable {
+		if !available {
+			unavailableNodeGroups = append(unavailableNodeGroups, nodeGroup)
+		}
+	}
+
+	sort.Ints(disconnectedNodeIds)
+	sort.Ints(unavailableNodeGroups)
+	return disconnectedNodeIds, unavailableNodeGroups
+}
+
+// getAvailableCondition returns the NdbClusterAvailable condition, which
+// is True if every node group of the MySQL Cluster has a connected data
+// node and at least one MySQL Server is ready, if any are required.
+func (sc *SyncContext) getAvailableCondition() v1.NdbClusterCondition {
+	nc := sc.ndb
+	availableCondition := v1.NdbClusterCondition{
+		Type:               v1.NdbClusterAvailable,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	if sc.isSuspended() {
+		// All the MySQL Cluster nodes have been stopped
+		availableCondition.Status = corev1.ConditionFalse
+		availableCondition.Reason = v1.NdbClusterAvailableReasonSuspended
+		availableCondition.Message = "MySQL Cluster nodes have been stopped as spec.suspended is set"
+		return availableCondition
+	}
+
+	if sc.dataNodeSfSet == nil || sc.dataNodeSfSet.Status.ReadyReplicas == 0 {
+		// The MySQL Cluster is starting up or is being fully restarted
+		availableCondition.Status = corev1.ConditionFalse
+		availableCondition.Reason = v1.NdbClusterAvailableReasonUnavailable
+		availableCondition.Message = "None of the data nodes are ready"
+		return availableCondition
+	}
+
+	if sc.clusterStatus == nil {
+		availableCondition.Status = corev1.ConditionUnknown
+		availableCondition.Reason = v1.NdbClusterAvailableReasonUnknown
+		availableCondition.Message = "Unable to retrieve the status of the MySQL Cluster from the Management Server"
+		return availableCondition
+	}
+
+	if _, unavailableNodeGroups := getDisconnectedNodes(sc.clusterStatus); len(unavailableNodeGroups) != 0 {
+		// Some data is inaccessible
+		availableCondition.Status = corev1.ConditionFalse
+		availableCondition.Reason = v1.NdbClusterAvailableReasonUnavailable
+		availableCondition.Message = fmt.Sprintf(
+			"Node groups %v have no connected data nodes", unavailableNodeGroups)
+		return availableCondition
+	}
+
+	// Check if any of the MySQL Servers can serve queries
+	numOfMySQLServersRequired := nc.GetMySQLServerNodeCount()
+	numOfReadyMySQLServers := int32(0)
+	if sc.mysqldSfset != nil {
+		numOfReadyMySQLServers = sc.mysqldSfset.Status.ReadyReplicas
+	}
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		numOfMySQLServersRequired += mysqldGroup.NodeCount
+		if groupSfset, exists := sc.mysqldGroupSfsets[mysqldGroup.Name]; exists {
+			numOfReadyMySQLServers += groupSfset.Status.ReadyReplicas
+		}
+	}
+	if numOfMySQLServersRequired > 0 && numOfReadyMySQLServers == 0 {
+		availableCondition.Status = corev1.ConditionFalse
+		availableCondition.Reason = v1.NdbClusterAvailableReasonUnavailable
+		availableCondition.Message = "None of the MySQL Servers are ready"
+		return availableCondition
+	}
+
+	availableCondition.Status = corev1.ConditionTrue
+	availableCondition.Reason = v1.NdbClusterAvailableReasonAvailable
+	availableCondition.Message = "MySQL Cluster is available to serve queries"
+	return availableCondition
+}
+
+// getDegradedCondition returns the NdbClusterDegraded condition, which is
+// True if some of the Management or Data Nodes are disconnected but every
+// node group still has a connected data node.
+func (sc *SyncContext) getDegradedCondition() v1.NdbClusterCondition {
+	degradedCondition := v1.NdbClusterCondition{
+		Type:               v1.NdbClusterDegraded,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	if sc.isSuspended() {
+		// All the MySQL Cluster nodes have been stopped
+		degradedCondition.Status = corev1.ConditionFalse
+		degradedCondition.Reason = v1.NdbClusterDegradedReasonSuspended
+		degradedCondition.Message = "MySQL Cluster nodes have been stopped as spec.suspended is set"
+		return degradedCondition
+	}
+
+	if sc.clusterStatus == nil {
+		degradedCondition.Status = corev1.ConditionUnknown
+		degradedCondition.Reason = v1.NdbClusterDegradedReasonUnknown
+		degradedCondition.Message = "Unable to retrieve the status of the MySQL Cluster from the Management Server"
+		return degradedCondition
+	}
+
+	disconnectedNodeIds, unavailableNodeGroups := getDisconnectedNodes(sc.clusterStatus)
+	if len(unavailableNodeGroups) != 0 {
+		// The MySQL Cluster is not degraded but unavailable
+		degradedCondition.Status = corev1.ConditionFalse
+		degradedCondition.Reason = v1.NdbClusterDegradedReasonUnavailable
+		degradedCondition.Message = fmt.Sprintf(
+			"MySQL Cluster is unavailable as node groups %v have no connected data nodes", unavailableNodeGroups)
+	} else if len(disconnectedNodeIds) != 0 {
+		degradedCondition.Status = corev1.ConditionTrue
+		degradedCondition.Reason = v1.NdbClusterDegradedReasonNodesDisconnected
+		degradedCondition.Message = fmt.Sprintf(
+			"Nodes %v are disconnected but every node group has a connected data node", disconnectedNodeIds)
+	} else {
+		degradedCondition.Status = corev1.ConditionFalse
+		degradedCondition.Reason = v1.NdbClusterDegradedReasonAllNodesConnected
+		degradedCondition.Message = "All the Management and Data Nodes are connected"
+	}
+	return degradedCondition
+}
+
+// getProgressingCondition returns the NdbClusterProgressing condition,
+// which is True if the MySQL Cluster nodes are being restarted or new
+// data nodes are being added to the MySQL Cluster.
+func (sc *SyncContext) getProgressingCondition() v1.NdbClusterCondition {
+	nc := sc.ndb
+	progressingCondition := v1.NdbClusterCondition{
+		Type:               v1.NdbClusterProgressing,
+		LastTransitionTime: metav1.Now(),
+		Status:             corev1.ConditionTrue,
+	}
+
+	if sc.isFullRestartInProgress() {
+		// All the data nodes are being restarted together
+		progressingCondition.Reason = v1.NdbClusterProgressingReasonFullRestart
+		progressingCondition.Message = "All the data nodes are being restarted"
+		return progressingCondition
+	}
+
+	if sfset := sc.dataNodeSfSet; sfset != nil {
+		_, suspended := sfset.Annotations[SuspendedReplicas]
+		if !suspended && *(sfset.Spec.Replicas) < nc.Spec.DataNode.NodeCount {
+			// The new data nodes are yet to be started
+			progressingCondition.Reason = v1.NdbClusterProgressingReasonAddNode
+			progressingCondition.Message = fmt.Sprintf(
+				"Data nodes are being added to scale the MySQL Cluster to %d data nodes", nc.Spec.DataNode.NodeCount)
+			return progressingCondition
+		}
+	}
+
+	for _, nodeStatus := range sc.clusterStatus {
+		if nodeStatus.IsDataNode() && (nodeStatus.NodeGroup == mgmapi.NodeGroupNewDisconnectedDataNode ||
+			nodeStatus.NodeGroup == mgmapi.NodeGroupNewConnectedDataNode) {
+			// The new data nodes are yet to be inducted into a node group
+			progressingCondition.Reason = v1.NdbClusterProgressingReasonAddNode
+			progressingCondition.Message = fmt.Sprintf(
+				"Data nodes are being added to scale the MySQL Cluster to %d data nodes", nc.Spec.DataNode.NodeCount)
+			return progressingCondition
+		}
+	}
+
+	for _, workload := range sc.getWorkloadsInStopOrder() {
+		sfset := workload.sfset
+		if sfset.Status.ObservedGeneration < sfset.Generation ||
+			sfset.Status.UpdatedReplicas < *(sfset.Spec.Replicas) {
+			// The pods of the StatefulSet are being updated
+			progressingCondition.Reason = v1.NdbClusterProgressingReasonRollingRestart
+			progressingCondition.Message = fmt.Sprintf(
+				"Pods of the StatefulSet %q are being restarted to apply the update", getNamespacedName(sfset))
+			return progressingCondition
+		}
+	}
+
+	progressingCondition.Status = corev1.ConditionFalse
+	progressingCondition.Reason = v1.NdbClusterProgressingReasonNoRollout
+	progressingCondition.Message = "No rolling restart or add node is in progress"
+	return progressingCondition
+}
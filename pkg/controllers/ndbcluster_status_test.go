@@ -0,0 +1,280 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+// newTestStatefulSet returns a StatefulSet with the given replicas, all of them updated and ready
+func newTestStatefulSet(replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        replicas,
+			ReadyReplicas:   replicas,
+			UpdatedReplicas: replicas,
+		},
+	}
+}
+
+// newTestClusterStatus returns the status of a MySQL Cluster with 2 Management
+// Nodes, 4 Data Nodes in 2 node groups and 2 API nodes, all of them connected
+func newTestClusterStatus() mgmapi.ClusterStatus {
+	return mgmapi.ClusterStatus{
+		1:   {NodeId: 1, NodeType: mgmapi.NodeTypeMGM, IsConnected: true, NodeGroup: -1},
+		2:   {NodeId: 2, NodeType: mgmapi.NodeTypeMGM, IsConnected: true, NodeGroup: -1},
+		3:   {NodeId: 3, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 0},
+		4:   {NodeId: 4, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 0},
+		5:   {NodeId: 5, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 1},
+		6:   {NodeId: 6, NodeType: mgmapi.NodeTypeNDB, IsConnected: true, NodeGroup: 1},
+		145: {NodeId: 145, NodeType: mgmapi.NodeTypeAPI, IsConnected: true, NodeGroup: -1},
+		146: {NodeId: 146, NodeType: mgmapi.NodeTypeAPI, IsConnected: false, NodeGroup: -1},
+	}
+}
+
+func Test_NdbClusterHealthConditions(t *testing.T) {
+	tests := []struct {
+		name string
+		// modify updates the SyncContext of a healthy MySQL Cluster
+		modify                    func(sc *SyncContext)
+		expectedAvailableStatus   corev1.ConditionStatus
+		expectedAvailableReason   string
+		expectedDegradedStatus    corev1.ConditionStatus
+		expectedDegradedReason    string
+		expectedProgressingStatus corev1.ConditionStatus
+		expectedProgressingReason string
+	}{
+		{
+			name:                      "healthy MySQL Cluster",
+			modify:                    func(sc *SyncContext) {},
+			expectedAvailableStatus:   corev1.ConditionTrue,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonAvailable,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonAllNodesConnected,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+		{
+			name: "data node and management node disconnected",
+			modify: func(sc *SyncContext) {
+				sc.clusterStatus[2].IsConnected = false
+				sc.clusterStatus[4].IsConnected = false
+			},
+			expectedAvailableStatus:   corev1.ConditionTrue,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonAvailable,
+			expectedDegradedStatus:    corev1.ConditionTrue,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonNodesDisconnected,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+		{
+			name: "node group without connected data nodes",
+			modify: func(sc *SyncContext) {
+				sc.clusterStatus[5].IsConnected = false
+				sc.clusterStatus[6].IsConnected = false
+			},
+			expectedAvailableStatus:   corev1.ConditionFalse,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonUnavailable,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonUnavailable,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+		{
+			name: "no MySQL Servers ready",
+			modify: func(sc *SyncContext) {
+				sc.mysqldSfset.Status.ReadyReplicas = 0
+			},
+			expectedAvailableStatus:   corev1.ConditionFalse,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonUnavailable,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonAllNodesConnected,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+		{
+			name: "cluster status unavailable",
+			modify: func(sc *SyncContext) {
+				sc.clusterStatus = nil
+			},
+			expectedAvailableStatus:   corev1.ConditionUnknown,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonUnknown,
+			expectedDegradedStatus:    corev1.ConditionUnknown,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonUnknown,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+		{
+			name: "rolling restart of the data nodes",
+			modify: func(sc *SyncContext) {
+				// Data node 4 is being restarted with the new pod spec
+				sc.dataNodeSfSet.Status.UpdatedReplicas = 1
+				sc.dataNodeSfSet.Status.ReadyReplicas = 3
+				sc.clusterStatus[4].IsConnected = false
+			},
+			expectedAvailableStatus:   corev1.ConditionTrue,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonAvailable,
+			expectedDegradedStatus:    corev1.ConditionTrue,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonNodesDisconnected,
+			expectedProgressingStatus: corev1.ConditionTrue,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonRollingRestart,
+		},
+		{
+			name: "data nodes being added",
+			modify: func(sc *SyncContext) {
+				sc.ndb.Spec.DataNode.NodeCount = 6
+				sc.clusterStatus[7] = &mgmapi.NodeStatus{
+					NodeId: 7, NodeType: mgmapi.NodeTypeNDB, NodeGroup: mgmapi.NodeGroupNewDisconnectedDataNode}
+				sc.clusterStatus[8] = &mgmapi.NodeStatus{
+					NodeId: 8, NodeType: mgmapi.NodeTypeNDB, NodeGroup: mgmapi.NodeGroupNewDisconnectedDataNode}
+			},
+			expectedAvailableStatus:   corev1.ConditionTrue,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonAvailable,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonAllNodesConnected,
+			expectedProgressingStatus: corev1.ConditionTrue,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonAddNode,
+		},
+		{
+			name: "full restart of the data nodes",
+			modify: func(sc *SyncContext) {
+				sc.dataNodeSfSet.Annotations = map[string]string{FullRestartInProgress: "true"}
+				sc.dataNodeSfSet.Status.ReadyReplicas = 0
+				for nodeId := 3; nodeId <= 6; nodeId++ {
+					sc.clusterStatus[nodeId].IsConnected = false
+				}
+			},
+			expectedAvailableStatus:   corev1.ConditionFalse,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonUnavailable,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonUnavailable,
+			expectedProgressingStatus: corev1.ConditionTrue,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonFullRestart,
+		},
+		{
+			name: "suspended MySQL Cluster",
+			modify: func(sc *SyncContext) {
+				sc.ndb.Spec.Suspended = true
+				for _, sfset := range []*appsv1.StatefulSet{sc.mgmdNodeSfset, sc.dataNodeSfSet, sc.mysqldSfset} {
+					sfset.Annotations = map[string]string{SuspendedReplicas: "2"}
+					*(sfset.Spec.Replicas) = 0
+					sfset.Status = appsv1.StatefulSetStatus{}
+				}
+				sc.clusterStatus = nil
+			},
+			expectedAvailableStatus:   corev1.ConditionFalse,
+			expectedAvailableReason:   v1.NdbClusterAvailableReasonSuspended,
+			expectedDegradedStatus:    corev1.ConditionFalse,
+			expectedDegradedReason:    v1.NdbClusterDegradedReasonSuspended,
+			expectedProgressingStatus: corev1.ConditionFalse,
+			expectedProgressingReason: v1.NdbClusterProgressingReasonNoRollout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := &SyncContext{
+				ndb:           testutils.NewTestNdb("default", "example-ndb", 4),
+				mgmdNodeSfset: newTestStatefulSet(2),
+				dataNodeSfSet: newTestStatefulSet(4),
+				mysqldSfset:   newTestStatefulSet(4),
+				clusterStatus: newTestClusterStatus(),
+				// required to list the workloads of the NdbCluster
+				ndbmtdController: &ndbmtdStatefulSetController{},
+				mysqldController: &mysqldStatefulSetController{},
+			}
+			tt.modify(sc)
+
+			for _, tc := range []struct {
+				condition      v1.NdbClusterCondition
+				expectedStatus corev1.ConditionStatus
+				expectedReason string
+			}{
+				{sc.getAvailableCondition(), tt.expectedAvailableStatus, tt.expectedAvailableReason},
+				{sc.getDegradedCondition(), tt.expectedDegradedStatus, tt.expectedDegradedReason},
+				{sc.getProgressingCondition(), tt.expectedProgressingStatus, tt.expectedProgressingReason},
+			} {
+				if tc.condition.Status != tc.expectedStatus || tc.condition.Reason != tc.expectedReason {
+					t.Errorf("%s condition : expected status %q with reason %q but got %q with reason %q (%s)",
+						tc.condition.Type, tc.expectedStatus, tc.expectedReason,
+						tc.condition.Status, tc.condition.Reason, tc.condition.Message)
+				}
+			}
+		})
+	}
+}
+
+func Test_RetainLastTransitionTimes(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	oldConditions := []v1.NdbClusterCondition{
+		{Type: v1.NdbClusterAvailable, Status: corev1.ConditionTrue, LastTransitionTime: lastTransitionTime},
+		{Type: v1.NdbClusterDegraded, Status: corev1.ConditionFalse, LastTransitionTime: lastTransitionTime},
+	}
+
+	now := metav1.Now()
+	newConditions := []v1.NdbClusterCondition{
+		{Type: v1.NdbClusterAvailable, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		{Type: v1.NdbClusterDegraded, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		{Type: v1.NdbClusterProgressing, Status: corev1.ConditionFalse, LastTransitionTime: now},
+	}
+	retainLastTransitionTimes(oldConditions, newConditions)
+
+	if !newConditions[0].LastTransitionTime.Equal(&lastTransitionTime) {
+		t.Errorf("Expected the LastTransitionTime of the unchanged %s condition to be retained", newConditions[0].Type)
+	}
+	for _, condition := range newConditions[1:] {
+		if !condition.LastTransitionTime.Equal(&now) {
+			t.Errorf("Expected the LastTransitionTime of the %s condition to be updated", condition.Type)
+		}
+	}
+}
+
+func Test_RetrieveClusterStatusReusesSyncStatus(t *testing.T) {
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+	sc := &SyncContext{
+		ndb:           testutils.NewTestNdb("default", "example-ndb", 4),
+		mgmdNodeSfset: newTestStatefulSet(2),
+	}
+
+	// The status retrieved by the sync is reused by the status updates
+	mgmClient, _ := newMgmClient(context.TODO(), nil, sc.ndb)
+	if _, err := sc.getClusterStatus(mgmClient); err != nil {
+		t.Fatalf("Unexpected error retrieving the cluster status : %s", err)
+	}
+	sc.retrieveClusterStatus(context.TODO())
+	sc.retrieveClusterStatus(context.TODO())
+	if fms.connections != 1 {
+		t.Errorf("Expected the status updates to reuse the cluster status retrieved by the sync but got %d connections",
+			fms.connections)
+	}
+	if sc.clusterStatus == nil {
+		t.Error("Expected the cluster status retrieved by the sync to be retained")
+	}
+
+	// The status is retrieved only once when the sync has not retrieved it
+	sc = &SyncContext{
+		ndb:           testutils.NewTestNdb("default", "example-ndb", 4),
+		mgmdNodeSfset: newTestStatefulSet(2),
+	}
+	sc.retrieveClusterStatus(context.TODO())
+	sc.retrieveClusterStatus(context.TODO())
+	if fms.connections != 2 {
+		t.Errorf("Expected the cluster status to be retrieved once per reconciliation but got %d connections",
+			fms.connections-1)
+	}
+}
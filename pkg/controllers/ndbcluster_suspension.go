@@ -0,0 +1,236 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
+)
+
+// SuspendedReplicas is the annotation key, set on a StatefulSet scaled
+// down to suspend the NdbCluster, that holds the number of replicas the
+// StatefulSet had before. It is removed once the StatefulSet is scaled up.
+const SuspendedReplicas = ndbcontroller.GroupName + "/suspended-replicas"
+
+// suspendableWorkload is a StatefulSet that is scaled down to
+// suspend the NdbCluster, along with the controller handling it
+type suspendableWorkload struct {
+	sfset      *appsv1.StatefulSet
+	controller *ndbNodeStatefulSetImpl
+}
+
+// getWorkloadsInStopOrder returns the StatefulSets of the NdbCluster in the
+// order they are stopped - the MySQL Servers first, then the data nodes and
+// finally the Management Servers. They are started in the reverse order.
+func (sc *SyncContext) getWorkloadsInStopOrder() []suspendableWorkload {
+	var workloads []suspendableWorkload
+	if sc.mysqldSfset != nil {
+		workloads = append(workloads, suspendableWorkload{
+			sfset:      sc.mysqldSfset,
+			controller: &sc.mysqldController.ndbNodeStatefulSetImpl,
+		})
+	}
+
+	for _, mysqldGroup := range sc.ndb.Spec.MysqlNodeGroups {
+		if groupSfset, exists := sc.mysqldGroupSfsets[mysqldGroup.Name]; exists {
+			workloads = append(workloads, suspendableWorkload{
+				sfset:      groupSfset,
+				controller: &sc.mysqldController.groupController(mysqldGroup.Name).ndbNodeStatefulSetImpl,
+			})
+		}
+	}
+
+	if sc.dataNodeSfSet != nil {
+		workloads = append(workloads, suspendableWorkload{
+			sfset:      sc.dataNodeSfSet,
+			controller: &sc.ndbmtdController.ndbNodeStatefulSetImpl,
+		})
+	}
+
+	if sc.mgmdNodeSfset != nil {
+		workloads = append(workloads, suspendableWorkload{
+			sfset:      sc.mgmdNodeSfset,
+			controller: sc.mgmdController,
+		})
+	}
+
+	return workloads
+}
+
+// isSuspended returns true if all the StatefulSets of the
+// NdbCluster have been scaled down and have no pods left
+func (sc *SyncContext) isSuspended() bool {
+	if sc.mgmdNodeSfset == nil || sc.dataNodeSfSet == nil {
+		return false
+	}
+
+	for _, workload := range sc.getWorkloadsInStopOrder() {
+		if _, suspended := workload.sfset.Annotations[SuspendedReplicas]; !suspended ||
+			workload.sfset.Status.Replicas != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isResumeInProgress returns true if the NdbCluster is being resumed,
+// i.e. spec.suspended has been unset but some of the StatefulSets have
+// not been scaled up yet.
+func (sc *SyncContext) isResumeInProgress() bool {
+	if sc.ndb.Spec.Suspended {
+		return false
+	}
+
+	for _, workload := range sc.getWorkloadsInStopOrder() {
+		if _, suspended := workload.sfset.Annotations[SuspendedReplicas]; suspended {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileSuspension suspends the NdbCluster if spec.suspended is set, and
+// resumes it if spec.suspended has been unset. Nothing else is reconciled
+// while the NdbCluster is suspended.
+func (sc *SyncContext) reconcileSuspension(ctx context.Context) syncResult {
+	if sc.ndb.Spec.Suspended {
+		return sc.suspend(ctx)
+	}
+
+	if sc.isResumeInProgress() {
+		return sc.resume(ctx)
+	}
+
+	// NdbCluster is not suspended
+	return continueProcessing()
+}
+
+// suspend gracefully shuts down the MySQL Cluster by scaling down the
+// StatefulSets one at a time, in the order returned by
+// getWorkloadsInStopOrder. The data nodes are stopped together via the
+// Management Server before their StatefulSet is scaled down. The PVCs
+// are retained by the StatefulSets.
+func (sc *SyncContext) suspend(ctx context.Context) syncResult {
+	nc := sc.ndb
+	for i, workload := range sc.getWorkloadsInStopOrder() {
+		sfset := workload.sfset
+		if _, suspended := sfset.Annotations[SuspendedReplicas]; suspended {
+			if sfset.Status.Replicas != 0 {
+				// Reconciliation will continue once all the pods have terminated
+				klog.Infof("Waiting for the pods of the StatefulSet %q to terminate", getNamespacedName(sfset))
+				return finishProcessing()
+			}
+			continue
+		}
+
+		if i == 0 {
+			// None of the StatefulSets have been scaled down yet
+			klog.Infof("Suspending NdbCluster %q", getNamespacedName(nc))
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonSuspending, ActionSuspend, MessageSuspending)
+		}
+
+		if sfset == sc.dataNodeSfSet {
+			// Stop all the data nodes together to shut down the MySQL Cluster cleanly
+			stoppedNodeIds, err := sc.stopRunningDataNodes(ctx)
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+			klog.Infof("Stopped the data nodes %v to suspend the NdbCluster", stoppedNodeIds)
+		}
+
+		// Scale down the StatefulSet and remember its replicas
+		updatedSfset := sfset.DeepCopy()
+		if updatedSfset.Annotations == nil {
+			updatedSfset.Annotations = make(map[string]string)
+		}
+		updatedSfset.Annotations[SuspendedReplicas] = strconv.FormatInt(int64(*(sfset.Spec.Replicas)), 10)
+		updatedSfset.Spec.Replicas = new(int32)
+		klog.Infof("Scaling down the StatefulSet %q to suspend the NdbCluster", getNamespacedName(sfset))
+		if sr := workload.controller.patchStatefulSet(ctx, sfset, updatedSfset); sr.stopSync() {
+			return sr
+		}
+
+		// Only the annotation was added as the StatefulSet had no replicas
+	}
+
+	// All the MySQL Cluster nodes have been stopped
+	if !nc.Status.Suspended {
+		klog.Infof("All the MySQL Cluster nodes of NdbCluster %q have been stopped", getNamespacedName(nc))
+		sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonSuspended, ActionSuspend, MessageSuspended)
+	}
+
+	// Record the generation, that suspended the NdbCluster, in the config
+	// map. The config itself is not changed as no other spec change is
+	// allowed along with spec.suspended.
+	patched, err := sc.patchConfigMap(ctx)
+	if patched {
+		return finishProcessing()
+	} else if err != nil {
+		klog.Errorf("Failed to patch the ConfigMap. Error : %v", err)
+		return errorWhileProcessing(err)
+	}
+
+	// NdbCluster is suspended and in sync with the spec
+	sc.syncSuccess = true
+	return finishProcessing()
+}
+
+// resume starts the nodes of a suspended MySQL Cluster by scaling up the
+// StatefulSets, in the reverse order of getWorkloadsInStopOrder, to the
+// replicas they had before the suspension. The next StatefulSet is scaled
+// up only after all the pods of the previous one are ready. All the data
+// nodes are started together, and hence they start via a system restart.
+func (sc *SyncContext) resume(ctx context.Context) syncResult {
+	nc := sc.ndb
+	workloads := sc.getWorkloadsInStopOrder()
+	for i := len(workloads) - 1; i >= 0; i-- {
+		sfset := workloads[i].sfset
+		suspendedReplicas, suspended := sfset.Annotations[SuspendedReplicas]
+		if !suspended {
+			// StatefulSet has already been scaled up
+			if !statefulsetReady(sfset) {
+				// Reconciliation will continue once all the pods are ready
+				klog.Infof("Waiting for the pods of the StatefulSet %q to become ready", getNamespacedName(sfset))
+				return finishProcessing()
+			}
+			continue
+		}
+
+		if i == len(workloads)-1 {
+			// None of the StatefulSets have been scaled up yet
+			klog.Infof("Resuming NdbCluster %q", getNamespacedName(nc))
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonResuming, ActionResume, MessageResuming)
+		}
+
+		replicas, err := strconv.ParseInt(suspendedReplicas, 10, 32)
+		if err != nil {
+			klog.Errorf("StatefulSet %q has an invalid %s annotation : %s",
+				getNamespacedName(sfset), SuspendedReplicas, err)
+			return errorWhileProcessing(err)
+		}
+
+		// Scale up the StatefulSet to its replicas before the suspension
+		updatedSfset := sfset.DeepCopy()
+		delete(updatedSfset.Annotations, SuspendedReplicas)
+		updatedSfset.Spec.Replicas = new(int32)
+		*(updatedSfset.Spec.Replicas) = int32(replicas)
+		klog.Infof("Scaling up the StatefulSet %q to %d replicas to resume the NdbCluster",
+			getNamespacedName(sfset), replicas)
+		if sr := workloads[i].controller.patchStatefulSet(ctx, sfset, updatedSfset); sr.stopSync() {
+			return sr
+		}
+
+		// Only the annotation was removed as the StatefulSet has no replicas
+	}
+
+	// All the StatefulSets have been scaled up
+	return continueProcessing()
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+)
+
+// reconcileTDEPasswordRotation re-encrypts the data node file systems when
+// the password in the TDE Secret changes. The config map is first updated to
+// restart the data nodes with the --initial flag, after which the data node
+// StatefulSet is patched with the new password by reconcileDataNodeStatefulSet,
+// and the update is rolled out by ensureDataNodePodVersion, one data node per
+// node group at a time. Every data node rewrites its files with the new
+// password during the initial restart. The data nodes that have been
+// re-encrypted are tracked in status.tdePasswordRotation.
+func (sc *SyncContext) reconcileTDEPasswordRotation(ctx context.Context) syncResult {
+	nc := sc.ndb
+	cs := sc.configSummary
+	ndbmtdSfset := sc.dataNodeSfSet
+
+	if cs.TDEPasswordSecretName == "" || cs.TDEPasswordSecretName != nc.Spec.TDESecretName {
+		// Either TDE is not enabled or the TDE Secret itself is being
+		// changed via the spec, which restarts all the data nodes with
+		// the --initial flag when the new spec is applied.
+		return continueProcessing()
+	}
+
+	appliedVersion, exists := ndbmtdSfset.Spec.Template.Annotations[statefulset.LastAppliedTDEPasswordVersion]
+	if !exists {
+		// The StatefulSet was created by an older version of
+		// the operator. The annotation will be added when the
+		// StatefulSet is patched with the next spec update.
+		return continueProcessing()
+	}
+
+	secret, err := sc.ndbmtdController.secretLister.Secrets(nc.Namespace).Get(cs.TDEPasswordSecretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Errorf("TDE Secret %q does not exist", getNamespacedName2(nc.Namespace, cs.TDEPasswordSecretName))
+		}
+		return errorWhileProcessing(err)
+	}
+
+	rotation := nc.Status.TDEPasswordRotation.DeepCopy()
+	if appliedVersion != statefulset.GetTDEPasswordVersion(secret) {
+		// The password in the Secret differs from the one used by the data nodes
+		if cs.RedundancyLevel == 1 {
+			// Every data node is restarted with the --initial flag to
+			// re-encrypt its file system, but with a single replica there
+			// is no other data node to recover the data from. Stop the
+			// sync to not patch the data nodes with the new password.
+			err = fmt.Errorf(MessageTDEPasswordRotationBlocked, secret.Name)
+			klog.Errorf("Cannot rotate the TDE password of NdbCluster %q : %s", getNamespacedName(nc), err)
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeWarning, ReasonTDEPasswordRotationBlocked,
+				ActionRotate, MessageTDEPasswordRotationBlocked, secret.Name)
+			return errorWhileProcessing(err)
+		}
+
+		if rotation == nil ||
+			rotation.Phase == v1.NdbTDEPasswordRotationPhaseCompleted ||
+			rotation.SecretResourceVersion != secret.ResourceVersion {
+			// Start a new rotation
+			rotation = &v1.NdbTDEPasswordRotationStatus{
+				SecretResourceVersion: secret.ResourceVersion,
+				Phase:                 v1.NdbTDEPasswordRotationPhaseInProgress,
+				Message:               "Waiting for the data nodes to be restarted with the new password",
+			}
+			for i := int32(0); i < cs.NumOfDataNodes; i++ {
+				rotation.PendingNodes = append(rotation.PendingNodes, i+cs.DataNodeStartNodeId)
+			}
+			klog.Infof("Password in the TDE Secret %q has changed", getNamespacedName(secret))
+			sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonTDEPasswordRotationStarted,
+				ActionRotate, MessageTDEPasswordRotationStarted, secret.Name)
+		}
+		sc.tdePasswordRotation = rotation
+
+		if !cs.DataNodeInitialRestart {
+			// Persist the start of the rotation before
+			// updating the config map to trigger it.
+			if _, err = sc.updateNdbClusterStatus(ctx); err != nil {
+				return errorWhileProcessing(err)
+			}
+
+			if err = sc.configMapController.MarkDataNodeInitialRestart(ctx, sc); err != nil {
+				return errorWhileProcessing(err)
+			}
+
+			// The data node StatefulSet will be patched
+			// with the new password in the next sync.
+			return finishProcessing()
+		}
+
+		// Patch the data node StatefulSet with the new
+		// password in reconcileDataNodeStatefulSet.
+		sc.tdePasswordUpdatePending = true
+		return continueProcessing()
+	}
+
+	if rotation == nil || rotation.Phase == v1.NdbTDEPasswordRotationPhaseCompleted {
+		// No rotation is in progress
+		return continueProcessing()
+	}
+	sc.tdePasswordRotation = rotation
+
+	// The data nodes are being restarted with the new password.
+	// Note down the data nodes that have been re-encrypted.
+	rotation.ReEncryptedNodes = nil
+	rotation.PendingNodes = nil
+	for i := int32(0); i < *(ndbmtdSfset.Spec.Replicas); i++ {
+		nodeId := i + cs.DataNodeStartNodeId
+		ndbmtdPodName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, i)
+		pod, err := sc.podLister.Pods(nc.Namespace).Get(ndbmtdPodName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to retrieve pod %q : %s", getNamespacedName2(nc.Namespace, ndbmtdPodName), err)
+			return errorWhileProcessing(err)
+		}
+
+		if pod != nil && isTDEPasswordRotatedInPod(pod, ndbmtdSfset.Status.UpdateRevision) {
+			rotation.ReEncryptedNodes = append(rotation.ReEncryptedNodes, nodeId)
+		} else {
+			rotation.PendingNodes = append(rotation.PendingNodes, nodeId)
+		}
+	}
+
+	if len(rotation.PendingNodes) != 0 {
+		rotation.Message = fmt.Sprintf("Re-encrypted the file systems of %d out of %d data nodes",
+			len(rotation.ReEncryptedNodes), *(ndbmtdSfset.Spec.Replicas))
+		return continueProcessing()
+	}
+
+	// All the data nodes have been re-encrypted. The --initial flag will be
+	// removed from the data nodes when the config map is patched at the end
+	// of this sync.
+	rotation.Phase = v1.NdbTDEPasswordRotationPhaseCompleted
+	rotation.Message = fmt.Sprintf(MessageTDEPasswordRotationCompleted, secret.Name)
+	klog.Infof("Data nodes of NdbCluster %q were re-encrypted with the new TDE password", getNamespacedName(nc))
+	sc.recorder.Eventf(nc, nil, corev1.EventTypeNormal, ReasonTDEPasswordRotationCompleted,
+		ActionRotate, MessageTDEPasswordRotationCompleted, secret.Name)
+	return continueProcessing()
+}
+
+// isTDEPasswordRotatedInPod returns true if the given data node pod
+// has been restarted with the given revision and is ready again.
+func isTDEPasswordRotatedInPod(pod *corev1.Pod, updateRevision string) bool {
+	if pod.GetLabels()["controller-revision-hash"] != updateRevision {
+		return false
+	}
+
+	podReadyCondition := getPodCondition(pod, corev1.PodReady)
+	return podReadyCondition != nil && podReadyCondition.Status == corev1.ConditionTrue
+}
+
+// isTDEPasswordRotationInProgress returns true if the data node
+// file systems are being re-encrypted with a new TDE password.
+func (sc *SyncContext) isTDEPasswordRotationInProgress() bool {
+	return sc.tdePasswordRotation != nil &&
+		sc.tdePasswordRotation.Phase == v1.NdbTDEPasswordRotationPhaseInProgress
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+)
+
+const testTDESecretName = "test-tde-secret"
+
+func newTestTDESecret(password, resourceVersion string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            testTDESecretName,
+			Namespace:       metav1.NamespaceDefault,
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string][]byte{
+			corev1.BasicAuthPasswordKey: []byte(password),
+		},
+	}
+}
+
+// newTestTDEDataNodeStatefulSet returns a data node StatefulSet
+// whose pods use the password in the given TDE Secret.
+func newTestTDEDataNodeStatefulSet(nc *v1.NdbCluster, tdeSecret *corev1.Secret) *appsv1.StatefulSet {
+	replicas := nc.Spec.DataNode.NodeCount
+	sfset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nc.GetWorkloadName(constants.NdbNodeTypeNdbmtd),
+			Namespace: nc.Namespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.StatefulSetStatus{
+			UpdateRevision: "rev-2",
+		},
+	}
+	sfset.Spec.Template.Annotations = map[string]string{
+		statefulset.LastAppliedTDEPasswordVersion: statefulset.GetTDEPasswordVersion(tdeSecret),
+	}
+	return sfset
+}
+
+// newTestDataNodePod returns a data node pod with the given ordinal and revision
+func newTestDataNodePod(sfset *appsv1.StatefulSet, ordinal int, revision string, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", sfset.Name, ordinal),
+			Namespace: sfset.Namespace,
+			Labels: map[string]string{
+				"controller-revision-hash": revision,
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: readyStatus},
+			},
+		},
+	}
+}
+
+// runTDEPasswordRotationSync runs reconcileTDEPasswordRotation once with
+// the given TDE Secret, data node StatefulSet and pods, and returns the
+// kubernetes client used and the result.
+func runTDEPasswordRotationSync(t *testing.T, nc *v1.NdbCluster, dataNodeInitialRestart bool,
+	tdeSecret *corev1.Secret, sfset *appsv1.StatefulSet, pods ...*corev1.Pod) (
+	*k8sfake.Clientset, *SyncContext, syncResult) {
+	t.Helper()
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nc.GetConfigMapName(),
+			Namespace: nc.Namespace,
+		},
+		Data: map[string]string{
+			constants.DataNodeInitialRestart: fmt.Sprintf("%v", dataNodeInitialRestart),
+		},
+	}
+	k8sClient := k8sfake.NewSimpleClientset(cm)
+
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := secretIndexer.Add(tdeSecret); err != nil {
+		t.Fatal(err)
+	}
+	cmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := cmIndexer.Add(cm); err != nil {
+		t.Fatal(err)
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sc := &SyncContext{
+		ndb:           nc,
+		dataNodeSfSet: sfset,
+		configSummary: &ndbconfig.ConfigSummary{
+			NumOfDataNodes:         nc.Spec.DataNode.NodeCount,
+			DataNodeStartNodeId:    3,
+			RedundancyLevel:        nc.Spec.RedundancyLevel,
+			TDEPasswordSecretName:  testTDESecretName,
+			DataNodeInitialRestart: dataNodeInitialRestart,
+		},
+		ndbmtdController: newNdbmtdStatefulSetController(
+			k8sClient, nil, listerscorev1.NewSecretLister(secretIndexer)),
+		configMapController: NewConfigMapControl(k8sClient, listerscorev1.NewConfigMapLister(cmIndexer)),
+		kubernetesClient:    k8sClient,
+		ndbClient:           fake.NewSimpleClientset(nc),
+		podLister:           listerscorev1.NewPodLister(podIndexer),
+		recorder:            events.NewFakeRecorder(10),
+	}
+
+	return k8sClient, sc, sc.reconcileTDEPasswordRotation(context.TODO())
+}
+
+func TestTDEPasswordRotationStart(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test-tde", 2)
+	nc.Spec.TDESecretName = testTDESecretName
+	sfset := newTestTDEDataNodeStatefulSet(nc, newTestTDESecret("old-password", "1"))
+
+	// The password has not changed
+	if _, sc, sr := runTDEPasswordRotationSync(
+		t, nc, false, newTestTDESecret("old-password", "2"), sfset); sr.stopSync() || sc.tdePasswordRotation != nil {
+		t.Fatal("Rotation should not be started when only the Secret metadata has changed")
+	}
+
+	// The password has changed
+	newSecret := newTestTDESecret("new-password", "3")
+	k8sClient, sc, sr := runTDEPasswordRotationSync(t, nc, false, newSecret, sfset)
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+
+	rotation := sc.tdePasswordRotation
+	if rotation == nil ||
+		rotation.Phase != v1.NdbTDEPasswordRotationPhaseInProgress ||
+		rotation.SecretResourceVersion != "3" ||
+		!reflect.DeepEqual(rotation.PendingNodes, []int32{3, 4}) {
+		t.Errorf("Unexpected rotation status : %+v", rotation)
+	}
+
+	cm, err := k8sClient.CoreV1().ConfigMaps(nc.Namespace).Get(
+		context.TODO(), nc.GetConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve the config map : %s", err)
+	}
+	if cm.Data[constants.DataNodeInitialRestart] != "true" {
+		t.Error("Config map was not updated to restart the data nodes with the --initial flag")
+	}
+
+	// The data node StatefulSet should be patched in the next sync
+	nc.Status.TDEPasswordRotation = rotation
+	if _, sc, sr = runTDEPasswordRotationSync(t, nc, true, newSecret, sfset); sr.stopSync() {
+		t.Fatalf("Expected the sync to continue but got %v", sr.getError())
+	}
+	if !sc.tdePasswordUpdatePending {
+		t.Error("Expected the data node StatefulSet to be patched with the new password")
+	}
+}
+
+func TestTDEPasswordRotationProgress(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test-tde", 2)
+	nc.Spec.TDESecretName = testTDESecretName
+	secret := newTestTDESecret("new-password", "3")
+	sfset := newTestTDEDataNodeStatefulSet(nc, secret)
+	nc.Status.TDEPasswordRotation = &v1.NdbTDEPasswordRotationStatus{
+		SecretResourceVersion: "3",
+		Phase:                 v1.NdbTDEPasswordRotationPhaseInProgress,
+		PendingNodes:          []int32{3, 4},
+	}
+
+	// Only the first data node has been restarted
+	_, sc, sr := runTDEPasswordRotationSync(t, nc, true, secret, sfset,
+		newTestDataNodePod(sfset, 0, "rev-2", true),
+		newTestDataNodePod(sfset, 1, "rev-1", true))
+	if sr.stopSync() {
+		t.Fatalf("Expected the sync to continue but got %v", sr.getError())
+	}
+	rotation := sc.tdePasswordRotation
+	if rotation.Phase != v1.NdbTDEPasswordRotationPhaseInProgress ||
+		!reflect.DeepEqual(rotation.ReEncryptedNodes, []int32{3}) ||
+		!reflect.DeepEqual(rotation.PendingNodes, []int32{4}) {
+		t.Errorf("Unexpected rotation status : %+v", rotation)
+	}
+
+	// The second data node has been restarted but is not ready yet
+	_, sc, _ = runTDEPasswordRotationSync(t, nc, true, secret, sfset,
+		newTestDataNodePod(sfset, 0, "rev-2", true),
+		newTestDataNodePod(sfset, 1, "rev-2", false))
+	if !reflect.DeepEqual(sc.tdePasswordRotation.PendingNodes, []int32{4}) {
+		t.Errorf("Unexpected rotation status : %+v", sc.tdePasswordRotation)
+	}
+
+	// All the data nodes have been re-encrypted
+	_, sc, _ = runTDEPasswordRotationSync(t, nc, true, secret, sfset,
+		newTestDataNodePod(sfset, 0, "rev-2", true),
+		newTestDataNodePod(sfset, 1, "rev-2", true))
+	rotation = sc.tdePasswordRotation
+	if rotation.Phase != v1.NdbTDEPasswordRotationPhaseCompleted ||
+		!reflect.DeepEqual(rotation.ReEncryptedNodes, []int32{3, 4}) ||
+		len(rotation.PendingNodes) != 0 {
+		t.Errorf("Unexpected rotation status : %+v", rotation)
+	}
+}
+
+func TestTDEPasswordRotationAtRedundancyLevel1(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "test-tde", 2)
+	nc.Spec.TDESecretName = testTDESecretName
+	nc.Spec.RedundancyLevel = 1
+	sfset := newTestTDEDataNodeStatefulSet(nc, newTestTDESecret("old-password", "1"))
+
+	// The data nodes cannot be re-encrypted without losing their data
+	k8sClient, sc, sr := runTDEPasswordRotationSync(t, nc, false, newTestTDESecret("new-password", "2"), sfset)
+	if !sr.stopSync() || sr.getError() == nil {
+		t.Fatal("Expected the sync to stop with an error when the password changes at redundancyLevel 1")
+	}
+	if sc.tdePasswordRotation != nil || sc.tdePasswordUpdatePending {
+		t.Errorf("Expected the rotation not to be started but got %+v", sc.tdePasswordRotation)
+	}
+	cm, err := k8sClient.CoreV1().ConfigMaps(nc.Namespace).Get(
+		context.TODO(), nc.GetConfigMapName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve the config map : %s", err)
+	}
+	if cm.Data[constants.DataNodeInitialRestart] != "false" {
+		t.Error("Expected the data nodes not to be restarted with the --initial flag")
+	}
+	if !hasRecordedEvent(sc.recorder, ReasonTDEPasswordRotationBlocked) {
+		t.Errorf("Expected a %s event", ReasonTDEPasswordRotationBlocked)
+	}
+
+	// The sync continues once the previous password is restored
+	if _, _, sr = runTDEPasswordRotationSync(t, nc, false, newTestTDESecret("old-password", "3"), sfset); sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the password is restored but got %v", sr.getError())
+	}
+}
@@ -0,0 +1,658 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+)
+
+// backupWatch tracks a running backup in the background
+// until the Management Server reports its outcome.
+type backupWatch struct {
+	backupId int
+	// done is closed once the watch ends. The
+	// following fields are set before that.
+	done chan struct{}
+	// event is the BackupCompleted or the BackupAborted event of the backup
+	event *mgmapi.BackupEvent
+	// timedOut is set if the backup didn't complete before its deadline
+	timedOut bool
+	// err is set if the backup events could not be read
+	err error
+}
+
+// NdbClusterBackupController is the controller implementation for
+// the NdbClusterBackup resources. It starts a backup of the MySQL
+// Cluster via the Management Server, waits for it to complete and
+// records the outcome in the NdbClusterBackup status.
+type NdbClusterBackupController struct {
+	kubernetesClient kubernetes.Interface
+	ndbClient        ndbclientset.Interface
+	restConfig       *restclient.Config
+
+	// backupWatches tracks the running backups, keyed
+	// by the names of their NdbClusterBackup resources.
+	// The workers do not block while the backups run.
+	backupWatches     map[string]*backupWatch
+	backupWatchesLock sync.Mutex
+
+	// NdbCluster and NdbClusterBackup Listers
+	ndbsLister       ndblisters.NdbClusterLister
+	ndbBackupsLister ndblisters.NdbClusterBackupLister
+
+	// Slice of InformerSynced methods for all the informers used by the controller
+	informerSyncedMethods []cache.InformerSynced
+
+	// A rate limited workqueue for queueing the NdbClusterBackup
+	// resource keys on receiving an event.
+	workqueue workqueue.RateLimitingInterface
+	// An event recorder for recording Event resources to the Kubernetes API.
+	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
+}
+
+// NewNdbClusterBackupController returns a new NdbClusterBackup controller
+func NewNdbClusterBackupController(
+	kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface,
+	restConfig *restclient.Config,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *NdbClusterBackupController {
+
+	// Register for all the required informers
+	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbClusterBackupInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusterBackups()
+
+	controller := &NdbClusterBackupController{
+		kubernetesClient: kubernetesClient,
+		ndbClient:        ndbClient,
+		restConfig:       restConfig,
+		backupWatches:    make(map[string]*backupWatch),
+		ndbsLister:       ndbClusterInformer.Lister(),
+		ndbBackupsLister: ndbClusterBackupInformer.Lister(),
+		informerSyncedMethods: []cache.InformerSynced{
+			ndbClusterInformer.Informer().HasSynced,
+			ndbClusterBackupInformer.Informer().HasSynced,
+		},
+		workqueue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "NdbClusterBackups"),
+		recorder: newEventRecorder(kubernetesClient),
+	}
+
+	// Set up event handler for NdbClusterBackup resource changes.
+	// The backups are taken only once, so only the additions
+	// and the periodic resyncs of unfinished backups are handled.
+	ndbClusterBackupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ncb := obj.(*v1.NdbClusterBackup)
+			if ncb.IsFinished() {
+				return
+			}
+			key := getNamespacedName(ncb)
+			klog.Infof("New NdbClusterBackup resource added : %q, queueing it for reconciliation", key)
+			controller.workqueue.Add(key)
+		},
+
+		UpdateFunc: func(old, new interface{}) {
+			ncb := new.(*v1.NdbClusterBackup)
+			if ncb.IsFinished() || old.(*v1.NdbClusterBackup).ResourceVersion != ncb.ResourceVersion {
+				// Either the backup is done or this is an update
+				// made by the controller itself. Nothing to do.
+				return
+			}
+			controller.workqueue.Add(getNamespacedName(ncb))
+		},
+	})
+
+	// Set up event handler for the NdbCluster resources to process the
+	// unfinished backups when the reconciliation of their NdbCluster is resumed.
+	ndbClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldNc := old.(*v1.NdbCluster)
+			newNc := new.(*v1.NdbCluster)
+			if !oldNc.IsPaused() || newNc.IsPaused() {
+				return
+			}
+
+			ncbList, err := controller.ndbBackupsLister.NdbClusterBackups(newNc.Namespace).List(labels.Everything())
+			if err != nil {
+				klog.Errorf("Failed to list the NdbClusterBackups in namespace %q : %s", newNc.Namespace, err)
+				return
+			}
+			for _, ncb := range ncbList {
+				if ncb.Spec.ClusterName == newNc.Name && !ncb.IsFinished() {
+					controller.workqueue.Add(getNamespacedName(ncb))
+				}
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run starts the workers that process the NdbClusterBackup resources.
+// It will block until ctx is cancelled, at which point it will shut down
+// the workqueue and wait for the workers to finish processing their
+// current work items.
+func (bc *NdbClusterBackupController) Run(ctx context.Context, threadiness int) error {
+	defer utilruntime.HandleCrash()
+	defer bc.workqueue.ShutDown()
+
+	klog.Info("Starting NdbClusterBackup controller")
+
+	// Wait for the caches to be synced before starting workers
+	if ok := cache.WaitForNamedCacheSync(
+		controllerName, ctx.Done(), bc.informerSyncedMethods...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	// Launch worker go routines to process NdbClusterBackup resources
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for bc.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	klog.Info("Started NdbClusterBackup workers")
+	<-ctx.Done()
+	klog.Info("Shutting down NdbClusterBackup workers")
+
+	return nil
+}
+
+// processNextWorkItem reads a single work item off the
+// workqueue and processes it, by calling the syncHandler.
+func (bc *NdbClusterBackupController) processNextWorkItem(ctx context.Context) bool {
+	item, shutdown := bc.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer bc.workqueue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		bc.workqueue.Forget(item)
+		klog.Error(debug.InternalError(fmt.Errorf("expected string in workqueue but got %#v", item)))
+		return true
+	}
+
+	bc.activeSyncs.start(key)
+	sr := bc.syncHandler(bc.activeSyncs.withHeartbeats(ctx, key), key)
+	bc.activeSyncs.finish(key)
+	if err := sr.getError(); err != nil {
+		klog.Infof("Reconciliation of NdbClusterBackup resource %q failed : %s", key, err)
+		klog.Info("Re-queuing resource to retry reconciliation after error")
+		bc.workqueue.AddRateLimited(key)
+		return true
+	}
+
+	bc.workqueue.Forget(item)
+	return true
+}
+
+// syncHandler takes the backup requested by the NdbClusterBackup
+// resource with the given key and records the outcome in its status.
+func (bc *NdbClusterBackupController) syncHandler(ctx context.Context, key string) syncResult {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return finishProcessing()
+	}
+
+	// Check if the backup is being watched by an earlier sync
+	watch := bc.getBackupWatch(key)
+	if watch != nil {
+		select {
+		case <-watch.done:
+			// Outcome is known. Record it below.
+			bc.deleteBackupWatch(key)
+		default:
+			// Backup is still running. The watch will
+			// queue the resource again once it is done.
+			return finishProcessing()
+		}
+	}
+
+	ncbOrg, err := bc.ndbBackupsLister.NdbClusterBackups(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("NdbClusterBackup resource %q does not exist anymore", key)
+			return finishProcessing()
+		}
+		klog.Errorf("Failed to retrieve NdbClusterBackup resource %q", key)
+		return errorWhileProcessing(err)
+	}
+
+	if ncbOrg.IsFinished() {
+		// Backup has already been taken
+		return finishProcessing()
+	}
+
+	// Work on a copy to avoid mutating the cache
+	ncb := ncbOrg.DeepCopy()
+
+	nc, err := bc.ndbsLister.NdbClusters(namespace).Get(ncb.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return bc.markBackupFailed(ctx, ncb,
+				fmt.Sprintf("NdbCluster %q does not exist", getNamespacedName2(namespace, ncb.Spec.ClusterName)))
+		}
+		klog.Errorf("Failed to retrieve NdbCluster resource %q", getNamespacedName2(namespace, ncb.Spec.ClusterName))
+		return errorWhileProcessing(err)
+	}
+
+	if watch != nil && watch.backupId == int(ncb.Status.BackupId) {
+		// The watch of the running backup has ended
+		return bc.recordBackupOutcome(ctx, ncb, nc, watch)
+	}
+
+	if nc.IsPaused() {
+		// The backup is started, or its tracking is resumed, once
+		// the reconciliation of the NdbCluster is resumed.
+		if message := getNdbClusterPausedMessage(nc); ncb.Status.Message != message {
+			ncb.Status.Message = message
+			if err = bc.updateBackupStatus(ctx, ncb); err != nil {
+				return errorWhileProcessing(err)
+			}
+			bc.recorder.Eventf(ncb, nil, corev1.EventTypeNormal, ReasonNdbClusterPaused, ActionNone, message)
+		}
+		return finishProcessing()
+	}
+
+	// Subscribe to the backup events before starting the backup
+	// to ensure that the completion event is not missed.
+	eventListener, err := newMgmClient(ctx, bc.kubernetesClient, nc)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	if err = eventListener.ListenBackupEvents(); err != nil {
+		klog.Errorf("Failed to subscribe to the backup events of NdbCluster %q : %s", getNamespacedName(nc), err)
+		eventListener.Disconnect()
+		return errorWhileProcessing(err)
+	}
+
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseRunning {
+		if sr := bc.startBackup(ctx, ncb, nc); sr.stopSync() {
+			eventListener.Disconnect()
+			return sr
+		}
+	} else {
+		// The backup was started in an earlier sync but the controller
+		// stopped tracking it, probably due to an operator restart. The
+		// events sent in the meantime are lost, so look for the outcome
+		// in the cluster logs of the Management Servers before waiting.
+		klog.Infof("Resuming the tracking of the backup %d of NdbCluster %q",
+			ncb.Status.BackupId, getNamespacedName(nc))
+		event, err := bc.findBackupOutcome(ctx, nc, int(ncb.Status.BackupId))
+		if err != nil {
+			eventListener.Disconnect()
+			return errorWhileProcessing(err)
+		}
+
+		if event != nil {
+			// Backup ended while the controller was not tracking it
+			eventListener.Disconnect()
+			return bc.recordBackupOutcome(ctx, ncb, nc, &backupWatch{event: event})
+		}
+	}
+
+	bc.startBackupWatch(ctx, key, ncb, eventListener)
+	return finishProcessing()
+}
+
+// startBackup starts the backup and marks the NdbClusterBackup as running
+func (bc *NdbClusterBackupController) startBackup(
+	ctx context.Context, ncb *v1.NdbClusterBackup, nc *v1.NdbCluster) syncResult {
+
+	mgmClient, err := newMgmClient(ctx, bc.kubernetesClient, nc)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	defer mgmClient.Disconnect()
+
+	// Start the backup only if the MySQL Cluster is healthy
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		klog.Errorf("Error getting cluster status from management server: %s", err)
+		return errorWhileProcessing(err)
+	}
+	if !clusterStatus.IsHealthy() {
+		return errorWhileProcessing(
+			fmt.Errorf("cannot start backup as the MySQL Cluster of NdbCluster %q is not healthy",
+				getNamespacedName(nc)))
+	}
+
+	requestedBackupId := 0
+	if ncb.Spec.BackupId != nil {
+		requestedBackupId = int(*ncb.Spec.BackupId)
+	}
+
+	backupId, err := mgmClient.StartBackup(requestedBackupId)
+	if err != nil {
+		klog.Errorf("Failed to start backup of NdbCluster %q : %s", getNamespacedName(nc), err)
+		return bc.markBackupFailed(ctx, ncb, "Backup failed to start : "+err.Error())
+	}
+
+	klog.Infof("Started backup %d of NdbCluster %q", backupId, getNamespacedName(nc))
+	now := metav1.Now()
+	ncb.Status.Phase = v1.NdbClusterBackupPhaseRunning
+	ncb.Status.BackupId = int32(backupId)
+	ncb.Status.StartTime = &now
+	ncb.Status.Message = ""
+	if err = bc.updateBackupStatus(ctx, ncb); err != nil {
+		// The backup has started but the status could not be
+		// recorded. Abort the backup to avoid an untracked backup.
+		if abortErr := mgmClient.AbortBackup(backupId); abortErr != nil {
+			klog.Errorf("Failed to abort backup %d of NdbCluster %q : %s", backupId, getNamespacedName(nc), abortErr)
+		}
+		return errorWhileProcessing(err)
+	}
+
+	bc.recorder.Eventf(ncb, nil, corev1.EventTypeNormal,
+		ReasonBackupStarted, ActionBackup, MessageBackupStarted, backupId, nc.Name)
+	return continueProcessing()
+}
+
+// getBackupWatch returns the watch of the backup with the given key, if any
+func (bc *NdbClusterBackupController) getBackupWatch(key string) *backupWatch {
+	bc.backupWatchesLock.Lock()
+	defer bc.backupWatchesLock.Unlock()
+	return bc.backupWatches[key]
+}
+
+// deleteBackupWatch removes the watch of the backup with the given key
+func (bc *NdbClusterBackupController) deleteBackupWatch(key string) {
+	bc.backupWatchesLock.Lock()
+	defer bc.backupWatchesLock.Unlock()
+	delete(bc.backupWatches, key)
+}
+
+// startBackupWatch starts a go routine that reads the events sent to the
+// given eventListener until the running backup completes, is aborted or
+// runs past its deadline. The NdbClusterBackup is then queued again so
+// that a worker can record the outcome.
+func (bc *NdbClusterBackupController) startBackupWatch(
+	ctx context.Context, key string, ncb *v1.NdbClusterBackup, eventListener mgmapi.MgmClient) {
+
+	watch := &backupWatch{
+		backupId: int(ncb.Status.BackupId),
+		done:     make(chan struct{}),
+	}
+	bc.backupWatchesLock.Lock()
+	bc.backupWatches[key] = watch
+	bc.backupWatchesLock.Unlock()
+
+	startTime := time.Now()
+	if ncb.Status.StartTime != nil {
+		startTime = ncb.Status.StartTime.Time
+	}
+	deadline := startTime.Add(time.Duration(ncb.GetTimeoutSeconds()) * time.Second)
+
+	go func() {
+		defer func() {
+			eventListener.Disconnect()
+			close(watch.done)
+			bc.workqueue.Add(key)
+		}()
+
+		// Unblock the event read if the controller is stopped
+		go func() {
+			select {
+			case <-ctx.Done():
+				eventListener.Disconnect()
+			case <-watch.done:
+			}
+		}()
+
+		for {
+			event, err := eventListener.ReadBackupEvent(time.Until(deadline))
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					// Backup didn't complete in time
+					watch.timedOut = true
+					return
+				}
+				watch.err = err
+				return
+			}
+
+			if event.BackupId == watch.backupId &&
+				(event.Type == mgmapi.BackupCompleted || event.Type == mgmapi.BackupAborted) {
+				watch.event = event
+				return
+			}
+		}
+	}()
+}
+
+// findBackupOutcome looks for the outcome of the backup with the
+// given id in the cluster logs of the Management Servers. It returns
+// nil if none of the logs have it, i.e. the backup is still running.
+func (bc *NdbClusterBackupController) findBackupOutcome(
+	ctx context.Context, nc *v1.NdbCluster, backupId int) (*mgmapi.BackupEvent, error) {
+
+	// Every Management Server writes the events to its own cluster
+	// log, but a Management Server that was down when the backup
+	// ended would have missed them. So, check all of them.
+	cmd := []string{"sh", "-c", fmt.Sprintf("grep -h 'Backup %d started from' %s 2>/dev/null || true",
+		backupId, ndbconfig.GetManagementNodeClusterLogFiles())}
+	mgmdWorkloadName := nc.GetWorkloadName(constants.NdbNodeTypeMgmd)
+	for i := int32(0); i < nc.GetManagementNodeCount(); i++ {
+		podName := fmt.Sprintf("%s-%d", mgmdWorkloadName, i)
+		clusterLog, err := execInPod(ctx, bc.kubernetesClient, bc.restConfig,
+			nc.Namespace, podName, statefulset.GetMgmdContainerName(), cmd)
+		if err != nil {
+			klog.Errorf("Failed to read the cluster log of the Management Server %q : %s",
+				getNamespacedName2(nc.Namespace, podName), err)
+			return nil, err
+		}
+
+		if event := mgmapi.FindBackupOutcomeInClusterLog(clusterLog, backupId); event != nil {
+			return event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// recordBackupOutcome records the outcome of the backup
+// tracked by the given watch in the NdbClusterBackup status.
+func (bc *NdbClusterBackupController) recordBackupOutcome(
+	ctx context.Context, ncb *v1.NdbClusterBackup, nc *v1.NdbCluster, watch *backupWatch) syncResult {
+
+	backupId := int(ncb.Status.BackupId)
+	switch {
+	case watch.err != nil:
+		// Resume tracking the backup in the next sync
+		klog.Errorf("Failed to read the backup events of NdbCluster %q : %s", getNamespacedName(nc), watch.err)
+		return errorWhileProcessing(watch.err)
+
+	case watch.timedOut:
+		klog.Errorf("Backup %d of NdbCluster %q timed out", backupId, getNamespacedName(nc))
+		bc.abortBackup(ctx, nc, backupId)
+		return bc.markBackupFailed(ctx, ncb,
+			fmt.Sprintf("Backup did not complete within %d seconds", ncb.GetTimeoutSeconds()))
+
+	case watch.event.Type == mgmapi.BackupCompleted:
+		return bc.markBackupCompleted(ctx, ncb, nc, watch.event)
+
+	default:
+		klog.Errorf("Backup %d of NdbCluster %q aborted with error %d",
+			backupId, getNamespacedName(nc), watch.event.Error)
+		return bc.markBackupFailed(ctx, ncb,
+			fmt.Sprintf("Backup was aborted by the data nodes with error %d", watch.event.Error))
+	}
+}
+
+// abortBackup aborts the backup with the given id
+func (bc *NdbClusterBackupController) abortBackup(ctx context.Context, nc *v1.NdbCluster, backupId int) {
+	mgmClient, err := newMgmClient(ctx, bc.kubernetesClient, nc)
+	if err != nil {
+		return
+	}
+	defer mgmClient.Disconnect()
+
+	if err = mgmClient.AbortBackup(backupId); err != nil {
+		klog.Errorf("Failed to abort backup %d of NdbCluster %q : %s", backupId, getNamespacedName(nc), err)
+	}
+}
+
+// getBackupLocations returns the locations of the backup
+// files written by the data nodes of the MySQL Cluster.
+func (bc *NdbClusterBackupController) getBackupLocations(
+	ctx context.Context, nc *v1.NdbCluster, backupId int32) ([]v1.NdbClusterBackupLocation, error) {
+	mgmClient, err := newMgmClient(ctx, bc.kubernetesClient, nc)
+	if err != nil {
+		return nil, err
+	}
+	defer mgmClient.Disconnect()
+
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		klog.Errorf("Error getting cluster status from management server: %s", err)
+		return nil, err
+	}
+
+	var locations []v1.NdbClusterBackupLocation
+	backupPath := ndbconfig.GetDataNodeBackupPath(nc, backupId)
+	dataNodeStartNodeId := clusterStatus.GetDataNodeStartNodeId()
+	for nodeId, nodeStatus := range clusterStatus {
+		if !nodeStatus.IsDataNode() || !nodeStatus.IsConnected {
+			// Only the connected data nodes take part in the backup
+			continue
+		}
+
+		// Data node with nodeId 'i' runs in a pod with ordinal index 'i-dataNodeStartNodeId'
+		podName := fmt.Sprintf("%s-%d",
+			nc.GetWorkloadName(constants.NdbNodeTypeNdbmtd), nodeId-dataNodeStartNodeId)
+		location := v1.NdbClusterBackupLocation{
+			NodeId:  int32(nodeId),
+			PodName: podName,
+			Path:    backupPath,
+		}
+		if nc.Spec.DataNode.PVCSpec != nil {
+			location.PersistentVolumeClaimName = statefulset.GetDataNodePVCName(podName)
+		}
+		locations = append(locations, location)
+	}
+
+	// Sort the locations to keep the status stable
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].NodeId < locations[j].NodeId
+	})
+
+	return locations, nil
+}
+
+// markBackupCompleted records the details of the completed backup in the status
+func (bc *NdbClusterBackupController) markBackupCompleted(
+	ctx context.Context, ncb *v1.NdbClusterBackup, nc *v1.NdbCluster, event *mgmapi.BackupEvent) syncResult {
+
+	klog.Infof("Backup %d of NdbCluster %q completed", event.BackupId, getNamespacedName(nc))
+
+	locations, err := bc.getBackupLocations(ctx, nc, ncb.Status.BackupId)
+	if err != nil {
+		// The completion event cannot be received again.
+		// So, record the outcome without the locations.
+		klog.Errorf("Failed to retrieve the locations of the backup %d : %s", event.BackupId, err)
+	}
+
+	now := metav1.Now()
+	ncb.Status.Phase = v1.NdbClusterBackupPhaseCompleted
+	ncb.Status.StartGCP = int64(event.StartGCP)
+	ncb.Status.StopGCP = int64(event.StopGCP)
+	ncb.Status.NumOfRecords = int64(event.NumOfRecords)
+	ncb.Status.NumOfBytes = int64(event.NumOfBytes)
+	ncb.Status.CompletionTime = &now
+	ncb.Status.Locations = locations
+	ncb.Status.Message = fmt.Sprintf("Backup completed and is consistent with the global checkpoint %d", event.StopGCP)
+	if err = bc.updateBackupStatus(ctx, ncb); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	bc.recorder.Eventf(ncb, nil, corev1.EventTypeNormal,
+		ReasonBackupCompleted, ActionBackup, MessageBackupCompleted, event.BackupId, nc.Name)
+	return finishProcessing()
+}
+
+// markBackupFailed marks the backup as failed with the given message
+func (bc *NdbClusterBackupController) markBackupFailed(
+	ctx context.Context, ncb *v1.NdbClusterBackup, message string) syncResult {
+
+	now := metav1.Now()
+	ncb.Status.Phase = v1.NdbClusterBackupPhaseFailed
+	ncb.Status.CompletionTime = &now
+	ncb.Status.Message = message
+	if err := bc.updateBackupStatus(ctx, ncb); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	bc.recorder.Eventf(ncb, nil, corev1.EventTypeWarning, ReasonBackupFailed, ActionBackup, message)
+	return finishProcessing()
+}
+
+// updateBackupStatus updates the status of the given NdbClusterBackup resource
+func (bc *NdbClusterBackupController) updateBackupStatus(ctx context.Context, ncb *v1.NdbClusterBackup) error {
+	status := ncb.Status.DeepCopy()
+	ncbInterface := bc.ndbClient.MysqlV1().NdbClusterBackups(ncb.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		status.DeepCopyInto(&ncb.Status)
+		updatedNcb, updateErr := ncbInterface.UpdateStatus(ctx, ncb, metav1.UpdateOptions{})
+		if updateErr == nil {
+			updatedNcb.DeepCopyInto(ncb)
+			return nil
+		}
+
+		// Get the latest version of the NdbClusterBackup to retry the update
+		latestNcb, getErr := ncbInterface.Get(ctx, ncb.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get NdbClusterBackup resource during status update %q: %v",
+				getNamespacedName(ncb), getErr)
+			return getErr
+		}
+		latestNcb.DeepCopyInto(ncb)
+
+		return updateErr
+	})
+
+	if err != nil {
+		klog.Errorf("Failed to update the status of NdbClusterBackup resource %q : %v",
+			getNamespacedName(ncb), err)
+	}
+
+	return err
+}
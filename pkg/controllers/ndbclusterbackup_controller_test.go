@@ -0,0 +1,319 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	informers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+// newTestNdbClusterBackup returns a NdbClusterBackup of the NdbCluster with the given name
+func newTestNdbClusterBackup(clusterName string) *v1.NdbClusterBackup {
+	return &v1.NdbClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1.NdbClusterBackupSpec{
+			ClusterName: clusterName,
+		},
+	}
+}
+
+// newTestNdbClusterBackupController returns a NdbClusterBackupController
+// with its informer caches synced with the given objects.
+func newTestNdbClusterBackupController(
+	t *testing.T, objects ...runtime.Object) (*NdbClusterBackupController, *fake.Clientset) {
+	t.Helper()
+
+	ndbClient := fake.NewSimpleClientset(objects...)
+	ndbIf := informers.NewSharedInformerFactory(ndbClient, 0)
+	bc := NewNdbClusterBackupController(k8sfake.NewSimpleClientset(), ndbClient, nil, ndbIf)
+	t.Cleanup(bc.workqueue.ShutDown)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, bc.informerSyncedMethods...); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	return bc, ndbClient
+}
+
+// syncTestNdbClusterBackup runs the syncHandler for the given NdbClusterBackup
+// and returns the NdbClusterBackup as updated by the controller.
+func syncTestNdbClusterBackup(t *testing.T, bc *NdbClusterBackupController,
+	ndbClient *fake.Clientset, ncb *v1.NdbClusterBackup) *v1.NdbClusterBackup {
+	t.Helper()
+
+	if err := bc.syncHandler(context.TODO(), getNamespacedName(ncb)).getError(); err != nil {
+		t.Fatalf("Unexpected error during sync : %s", err)
+	}
+
+	ncb, err := ndbClient.MysqlV1().NdbClusterBackups(ncb.Namespace).Get(
+		context.TODO(), ncb.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbClusterBackup : %s", err)
+	}
+
+	// Wait for the informer cache to receive the updates for the next sync
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		cachedNcb, err := bc.ndbBackupsLister.NdbClusterBackups(ncb.Namespace).Get(ncb.Name)
+		return err == nil && reflect.DeepEqual(cachedNcb.Status, ncb.Status), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to wait for the NdbClusterBackup cache to be updated : %s", err)
+	}
+
+	return ncb
+}
+
+// waitForBackupWatch waits for the watch of the given NdbClusterBackup to end
+func waitForBackupWatch(t *testing.T, bc *NdbClusterBackupController, ncb *v1.NdbClusterBackup) {
+	t.Helper()
+
+	watch := bc.getBackupWatch(getNamespacedName(ncb))
+	if watch == nil {
+		t.Fatal("Expected the running backup to be watched")
+	}
+
+	select {
+	case <-watch.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the backup watch to end")
+	}
+}
+
+func TestNdbClusterBackupOfMissingNdbCluster(t *testing.T) {
+	ncb := newTestNdbClusterBackup("missing-cluster")
+	bc, ndbClient := newTestNdbClusterBackupController(t, ncb)
+
+	// The backup should be marked as failed as the NdbCluster doesn't exist
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseFailed {
+		t.Errorf("Expected backup phase %q but got %q", v1.NdbClusterBackupPhaseFailed, ncb.Status.Phase)
+	}
+	if ncb.Status.CompletionTime == nil {
+		t.Error("Expected the completion time of the failed backup to be set")
+	}
+}
+
+func TestNdbClusterBackupCompletion(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 4)
+	ncb := newTestNdbClusterBackup(nc.Name)
+	bc, ndbClient := newTestNdbClusterBackupController(t, nc, ncb)
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+
+	// First sync starts the backup and doesn't block for its completion
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseRunning || ncb.Status.BackupId != 1 {
+		t.Fatalf("Expected backup 1 to be running but got phase %q and backup id %d",
+			ncb.Status.Phase, ncb.Status.BackupId)
+	}
+	if !fms.hasCall("StartBackup 1") {
+		t.Fatal("Expected the backup to be started via the Management Server")
+	}
+
+	// A resync while the backup is running should not do anything
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseRunning {
+		t.Fatalf("Expected the backup to be still running but got phase %q", ncb.Status.Phase)
+	}
+
+	// Events of other backups should be ignored
+	fms.backupEvents <- &mgmapi.BackupEvent{Type: mgmapi.BackupCompleted, BackupId: 2}
+	fms.backupEvents <- &mgmapi.BackupEvent{
+		Type: mgmapi.BackupCompleted, BackupId: 1, StartGCP: 100, StopGCP: 105, NumOfRecords: 10, NumOfBytes: 1024}
+	waitForBackupWatch(t, bc, ncb)
+
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseCompleted {
+		t.Fatalf("Expected the backup to be completed but got phase %q : %s", ncb.Status.Phase, ncb.Status.Message)
+	}
+	if ncb.Status.StopGCP != 105 || ncb.Status.NumOfRecords != 10 || ncb.Status.NumOfBytes != 1024 {
+		t.Errorf("Backup details not recorded in the status : %+v", ncb.Status)
+	}
+	if len(ncb.Status.Locations) != 4 {
+		t.Errorf("Expected the locations of the backup in all 4 data nodes but got %d", len(ncb.Status.Locations))
+	}
+	if bc.getBackupWatch(getNamespacedName(ncb)) != nil {
+		t.Error("Expected the watch of the completed backup to be removed")
+	}
+}
+
+func TestNdbClusterBackupAbort(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 4)
+	ncb := newTestNdbClusterBackup(nc.Name)
+	bc, ndbClient := newTestNdbClusterBackupController(t, nc, ncb)
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	fms.backupEvents <- &mgmapi.BackupEvent{Type: mgmapi.BackupAborted, BackupId: 1, Error: 1323}
+	waitForBackupWatch(t, bc, ncb)
+
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseFailed {
+		t.Fatalf("Expected the backup to have failed but got phase %q", ncb.Status.Phase)
+	}
+	if !strings.Contains(ncb.Status.Message, "1323") {
+		t.Errorf("Expected the abort error in the status message but got %q", ncb.Status.Message)
+	}
+}
+
+func TestNdbClusterBackupTimeout(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 4)
+	ncb := newTestNdbClusterBackup(nc.Name)
+	ncb.Spec.TimeoutSeconds = 1
+	bc, ndbClient := newTestNdbClusterBackupController(t, nc, ncb)
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	waitForBackupWatch(t, bc, ncb)
+
+	// The backup should be aborted and marked as failed
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseFailed {
+		t.Fatalf("Expected the backup to have failed but got phase %q", ncb.Status.Phase)
+	}
+	if !fms.hasCall("AbortBackup 1") {
+		t.Error("Expected the timed out backup to be aborted")
+	}
+}
+
+func TestNdbClusterBackupResume(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 4)
+	// The backup was started before an operator restart, and the
+	// operator was down until after the backup's deadline.
+	startTime := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	runningBackup := newTestNdbClusterBackup(nc.Name)
+	runningBackup.Status = v1.NdbClusterBackupStatus{
+		Phase:     v1.NdbClusterBackupPhaseRunning,
+		BackupId:  7,
+		StartTime: &startTime,
+	}
+
+	tests := []struct {
+		name          string
+		clusterLog    string
+		expectedPhase v1.NdbClusterBackupPhase
+		expectAbort   bool
+	}{
+		{
+			name: "backup completed while the operator was down",
+			clusterLog: "2024-03-01 10:00:05 [MgmtSrvr] INFO     -- Node 3: Backup 7 started from node 1 completed. " +
+				"StartGCP: 1200 StopGCP: 1203 #Records: 2059 #LogRecords: 0 Data: 52044 bytes Log: 0 bytes\n",
+			expectedPhase: v1.NdbClusterBackupPhaseCompleted,
+		},
+		{
+			name: "backup aborted while the operator was down",
+			clusterLog: "2024-03-01 10:00:05 [MgmtSrvr] ALERT    -- Node 3: " +
+				"Backup 7 started from 1 has been aborted. Error: 1323\n",
+			expectedPhase: v1.NdbClusterBackupPhaseFailed,
+		},
+		{
+			name:          "backup still running past its deadline",
+			clusterLog:    "2024-03-01 10:00:00 [MgmtSrvr] INFO     -- Node 3: Backup 7 started from node 1\n",
+			expectedPhase: v1.NdbClusterBackupPhaseFailed,
+			expectAbort:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ncb := runningBackup.DeepCopy()
+			bc, ndbClient := newTestNdbClusterBackupController(t, nc, ncb)
+			fms := newFakeMgmServer(t, newTestClusterStatus())
+			var readLogsFrom []string
+			fakeExecInPod(t, func(podName string, cmd []string) (string, error) {
+				readLogsFrom = append(readLogsFrom, podName)
+				if podName == "example-ndb-mgmd-1" {
+					// Only the second Management Server was running when the backup ended
+					return tt.clusterLog, nil
+				}
+				return "", nil
+			})
+
+			ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+			if tt.expectAbort {
+				// Backup is being watched and the deadline has passed
+				waitForBackupWatch(t, bc, ncb)
+				ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+			}
+
+			if len(readLogsFrom) != 2 {
+				t.Errorf("Expected the cluster logs of both the Management Servers to be read but got %v", readLogsFrom)
+			}
+			if ncb.Status.Phase != tt.expectedPhase {
+				t.Errorf("Expected backup phase %q but got %q : %s", tt.expectedPhase, ncb.Status.Phase, ncb.Status.Message)
+			}
+			if fms.hasCall("AbortBackup 7") != tt.expectAbort {
+				t.Errorf("Expected the backup to be aborted : %v", tt.expectAbort)
+			}
+			if fms.hasCall("StartBackup 7") || fms.hasCall("StartBackup 1") {
+				t.Error("Expected the running backup not to be started again")
+			}
+		})
+	}
+}
+
+func TestNdbClusterBackupOfPausedNdbCluster(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 4)
+	nc.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+	ncb := newTestNdbClusterBackup(nc.Name)
+	bc, ndbClient := newTestNdbClusterBackupController(t, nc, ncb)
+	bc.recorder = events.NewFakeRecorder(10)
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+
+	// The backup is not started while the reconciliation is paused
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != "" || !strings.Contains(ncb.Status.Message, "has been paused") {
+		t.Errorf("Expected the status to report the paused NdbCluster but got %+v", ncb.Status)
+	}
+	if fms.hasCall("StartBackup 1") {
+		t.Error("Expected the backup not to be started while the NdbCluster is paused")
+	}
+	if !hasRecordedEvent(bc.recorder, ReasonNdbClusterPaused) {
+		t.Errorf("Expected a %s event", ReasonNdbClusterPaused)
+	}
+
+	// The backup is queued again, and started, once the reconciliation is resumed
+	nc.Annotations = nil
+	if _, err := ndbClient.MysqlV1().NdbClusters(nc.Namespace).Update(
+		context.TODO(), nc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update NdbCluster : %s", err)
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		cachedNc, err := bc.ndbsLister.NdbClusters(nc.Namespace).Get(nc.Name)
+		return err == nil && !cachedNc.IsPaused() && bc.workqueue.Len() == 1, nil
+	}); err != nil {
+		t.Fatal("Expected the backup to be queued once the NdbCluster is resumed")
+	}
+
+	ncb = syncTestNdbClusterBackup(t, bc, ndbClient, ncb)
+	if ncb.Status.Phase != v1.NdbClusterBackupPhaseRunning || ncb.Status.Message != "" {
+		t.Errorf("Expected the backup to be running but got %+v", ncb.Status)
+	}
+	if !fms.hasCall("StartBackup 1") {
+		t.Error("Expected the backup to be started via the Management Server")
+	}
+}
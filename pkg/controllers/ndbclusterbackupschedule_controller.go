@@ -0,0 +1,638 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/cron"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+)
+
+// NdbClusterBackupScheduleController is the controller implementation
+// for the NdbClusterBackupSchedule resources. It creates NdbClusterBackup
+// resources as per the schedule and deletes the old backups, along with
+// their backup files in the data node pods, as per the retention policy.
+type NdbClusterBackupScheduleController struct {
+	kubernetesClient kubernetes.Interface
+	ndbClient        ndbclientset.Interface
+	// restConfig is used to execute commands in the data node pods
+	restConfig *restclient.Config
+
+	// NdbCluster, NdbClusterBackup and NdbClusterBackupSchedule Listers
+	ndbsLister               ndblisters.NdbClusterLister
+	ndbBackupsLister         ndblisters.NdbClusterBackupLister
+	ndbBackupSchedulesLister ndblisters.NdbClusterBackupScheduleLister
+
+	// K8s Listers to check the data node pods, and their
+	// PVCs, that store the files of the backups being deleted
+	podLister corelisters.PodLister
+	pvcLister corelisters.PersistentVolumeClaimLister
+
+	// Slice of InformerSynced methods for all the informers used by the controller
+	informerSyncedMethods []cache.InformerSynced
+
+	// A rate limited workqueue for queueing the NdbClusterBackupSchedule
+	// resource keys on receiving an event or when a backup is due.
+	workqueue workqueue.RateLimitingInterface
+	// An event recorder for recording Event resources to the Kubernetes API.
+	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
+}
+
+// NewNdbClusterBackupScheduleController returns a new NdbClusterBackupSchedule controller
+func NewNdbClusterBackupScheduleController(
+	kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface,
+	restConfig *restclient.Config,
+	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *NdbClusterBackupScheduleController {
+
+	// Register for all the required informers
+	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbClusterBackupInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusterBackups()
+	ndbClusterBackupScheduleInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusterBackupSchedules()
+	podInformer := k8sSharedIndexInformer.Core().V1().Pods()
+	pvcInformer := k8sSharedIndexInformer.Core().V1().PersistentVolumeClaims()
+
+	controller := &NdbClusterBackupScheduleController{
+		kubernetesClient:         kubernetesClient,
+		ndbClient:                ndbClient,
+		restConfig:               restConfig,
+		ndbsLister:               ndbClusterInformer.Lister(),
+		ndbBackupsLister:         ndbClusterBackupInformer.Lister(),
+		ndbBackupSchedulesLister: ndbClusterBackupScheduleInformer.Lister(),
+		podLister:                podInformer.Lister(),
+		pvcLister:                pvcInformer.Lister(),
+		informerSyncedMethods: []cache.InformerSynced{
+			ndbClusterInformer.Informer().HasSynced,
+			ndbClusterBackupInformer.Informer().HasSynced,
+			ndbClusterBackupScheduleInformer.Informer().HasSynced,
+			podInformer.Informer().HasSynced,
+			pvcInformer.Informer().HasSynced,
+		},
+		workqueue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "NdbClusterBackupSchedules"),
+		recorder: newEventRecorder(kubernetesClient),
+	}
+
+	// Set up event handler for NdbClusterBackupSchedule resource changes
+	ndbClusterBackupScheduleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key := getNamespacedName(obj.(*v1.NdbClusterBackupSchedule))
+			klog.Infof("New NdbClusterBackupSchedule resource added : %q, queueing it for reconciliation", key)
+			controller.workqueue.Add(key)
+		},
+
+		UpdateFunc: func(old, new interface{}) {
+			controller.workqueue.Add(getNamespacedName(new.(*v1.NdbClusterBackupSchedule)))
+		},
+	})
+
+	// Set up event handler for the NdbCluster resources to resume the
+	// schedules when their NdbCluster, or its reconciliation, is resumed.
+	ndbClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldNc := old.(*v1.NdbCluster)
+			newNc := new.(*v1.NdbCluster)
+			if oldNc.Spec.Suspended == newNc.Spec.Suspended && oldNc.IsPaused() == newNc.IsPaused() {
+				return
+			}
+
+			ncbsList, err := controller.ndbBackupSchedulesLister.NdbClusterBackupSchedules(
+				newNc.Namespace).List(labels.Everything())
+			if err != nil {
+				klog.Errorf("Failed to list the NdbClusterBackupSchedules in namespace %q : %s", newNc.Namespace, err)
+				return
+			}
+			for _, ncbs := range ncbsList {
+				if ncbs.Spec.ClusterName == newNc.Name {
+					controller.workqueue.Add(getNamespacedName(ncbs))
+				}
+			}
+		},
+	})
+
+	// Set up event handler for the NdbClusterBackup resources to prune
+	// the old backups when a backup created by a schedule finishes.
+	ndbClusterBackupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldNcb := old.(*v1.NdbClusterBackup)
+			newNcb := new.(*v1.NdbClusterBackup)
+			if oldNcb.IsFinished() || !newNcb.IsFinished() {
+				// Backup has not finished just now
+				return
+			}
+
+			ownerRef := metav1.GetControllerOf(newNcb)
+			if ownerRef == nil || ownerRef.Kind != "NdbClusterBackupSchedule" {
+				// Backup was not created by a schedule
+				return
+			}
+
+			controller.workqueue.Add(getNamespacedName2(newNcb.Namespace, ownerRef.Name))
+		},
+	})
+
+	return controller
+}
+
+// Run starts the workers that process the NdbClusterBackupSchedule
+// resources. It will block until ctx is cancelled, at which point it
+// will shut down the workqueue and wait for the workers to finish
+// processing their current work items.
+func (sc *NdbClusterBackupScheduleController) Run(ctx context.Context, threadiness int) error {
+	defer utilruntime.HandleCrash()
+	defer sc.workqueue.ShutDown()
+
+	klog.Info("Starting NdbClusterBackupSchedule controller")
+
+	// Wait for the caches to be synced before starting workers
+	if ok := cache.WaitForNamedCacheSync(
+		controllerName, ctx.Done(), sc.informerSyncedMethods...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	// Launch worker go routines to process NdbClusterBackupSchedule resources
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for sc.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	klog.Info("Started NdbClusterBackupSchedule workers")
+	<-ctx.Done()
+	klog.Info("Shutting down NdbClusterBackupSchedule workers")
+
+	return nil
+}
+
+// processNextWorkItem reads a single work item off the
+// workqueue and processes it, by calling the syncHandler.
+func (sc *NdbClusterBackupScheduleController) processNextWorkItem(ctx context.Context) bool {
+	item, shutdown := sc.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer sc.workqueue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		sc.workqueue.Forget(item)
+		klog.Error(debug.InternalError(fmt.Errorf("expected string in workqueue but got %#v", item)))
+		return true
+	}
+
+	sc.activeSyncs.start(key)
+	requeueAfter, sr := sc.syncHandler(sc.activeSyncs.withHeartbeats(ctx, key), key)
+	sc.activeSyncs.finish(key)
+	if err := sr.getError(); err != nil {
+		klog.Infof("Reconciliation of NdbClusterBackupSchedule resource %q failed : %s", key, err)
+		klog.Info("Re-queuing resource to retry reconciliation after error")
+		sc.workqueue.AddRateLimited(key)
+		return true
+	}
+
+	sc.workqueue.Forget(item)
+	if requeueAfter > 0 {
+		// Process the schedule again when the next backup is due
+		// or when a retained backup expires, whichever is earlier.
+		sc.workqueue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+// syncHandler creates the backups that are due as per the schedule and
+// prunes the old backups as per the retention policy. It returns the
+// duration after which the schedule has to be processed again.
+func (sc *NdbClusterBackupScheduleController) syncHandler(
+	ctx context.Context, key string) (time.Duration, syncResult) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return 0, finishProcessing()
+	}
+
+	ncbsOrg, err := sc.ndbBackupSchedulesLister.NdbClusterBackupSchedules(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The backups created by the schedule will be
+			// garbage collected by K8s as they are owned by it.
+			klog.Infof("NdbClusterBackupSchedule resource %q does not exist anymore", key)
+			return 0, finishProcessing()
+		}
+		klog.Errorf("Failed to retrieve NdbClusterBackupSchedule resource %q", key)
+		return 0, errorWhileProcessing(err)
+	}
+
+	// Work on a copy to avoid mutating the cache
+	ncbs := ncbsOrg.DeepCopy()
+
+	schedule, err := cron.Parse(ncbs.Spec.Schedule)
+	if err != nil {
+		message := fmt.Sprintf("Invalid schedule %q : %s", ncbs.Spec.Schedule, err)
+		if ncbs.Status.Message != message {
+			sc.recorder.Eventf(ncbs, nil, corev1.EventTypeWarning, ReasonInvalidSchedule, ActionSchedule, message)
+			ncbs.Status.Message = message
+			ncbs.Status.NextScheduleTime = nil
+			if err = sc.updateScheduleStatus(ctx, ncbs); err != nil {
+				return 0, errorWhileProcessing(err)
+			}
+		}
+		// Nothing more to do until the schedule is updated
+		return 0, finishProcessing()
+	}
+	ncbs.Status.Message = ""
+
+	nc, err := sc.ndbsLister.NdbClusters(namespace).Get(ncbs.Spec.ClusterName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to retrieve NdbCluster resource %q", getNamespacedName2(namespace, ncbs.Spec.ClusterName))
+		return 0, errorWhileProcessing(err)
+	}
+	if nc != nil && (nc.Spec.Suspended || nc.IsPaused()) {
+		// Neither take nor prune any backups as the data nodes are not
+		// running or must not be touched. The latest missed backup is
+		// taken once the NdbCluster, or its reconciliation, is resumed.
+		if nc.IsPaused() {
+			ncbs.Status.Message = getNdbClusterPausedMessage(nc)
+			if ncbsOrg.Status.Message != ncbs.Status.Message {
+				sc.recorder.Eventf(ncbs, nil, corev1.EventTypeNormal,
+					ReasonNdbClusterPaused, ActionNone, ncbs.Status.Message)
+			}
+		} else {
+			ncbs.Status.Message = fmt.Sprintf("NdbCluster %q is suspended", getNamespacedName(nc))
+		}
+		ncbs.Status.NextScheduleTime = nil
+		if !reflect.DeepEqual(ncbsOrg.Status, ncbs.Status) {
+			if err = sc.updateScheduleStatus(ctx, ncbs); err != nil {
+				return 0, errorWhileProcessing(err)
+			}
+		}
+		// The schedule is queued again when the NdbCluster is resumed
+		return 0, finishProcessing()
+	}
+
+	// Retrieve all the backups created by the schedule
+	selector := labels.SelectorFromSet(map[string]string{constants.BackupScheduleLabel: ncbs.Name})
+	backupList, err := sc.ndbBackupsLister.NdbClusterBackups(namespace).List(selector)
+	if err != nil {
+		klog.Errorf("Failed to list the NdbClusterBackups of schedule %q : %s", key, err)
+		return 0, errorWhileProcessing(err)
+	}
+	var backups []*v1.NdbClusterBackup
+	for _, ncb := range backupList {
+		if metav1.IsControlledBy(ncb, ncbs) {
+			backups = append(backups, ncb)
+		}
+	}
+
+	now := time.Now().UTC()
+	nextScheduleTime, sr := sc.createScheduledBackup(ctx, ncbs, schedule, backups, now)
+	if sr.stopSync() {
+		return 0, sr
+	}
+
+	nextExpiryTime, pruneErr := sc.pruneBackups(ctx, ncbs, backups, now)
+
+	if !reflect.DeepEqual(ncbsOrg.Status, ncbs.Status) {
+		if err = sc.updateScheduleStatus(ctx, ncbs); err != nil {
+			return 0, errorWhileProcessing(err)
+		}
+	}
+
+	if pruneErr != nil {
+		return 0, errorWhileProcessing(pruneErr)
+	}
+
+	// Requeue the schedule to be processed when the next backup
+	// is due or when the next retained backup expires.
+	var requeueAfter time.Duration
+	for _, t := range []time.Time{nextScheduleTime, nextExpiryTime} {
+		if t.IsZero() {
+			continue
+		}
+		if d := t.Sub(now); requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	return requeueAfter, finishProcessing()
+}
+
+// createScheduledBackup creates a new NdbClusterBackup if a backup
+// is due as per the schedule. It returns the time the next backup
+// is due. A zero time is returned if the schedule is suspended.
+func (sc *NdbClusterBackupScheduleController) createScheduledBackup(
+	ctx context.Context, ncbs *v1.NdbClusterBackupSchedule,
+	schedule *cron.Schedule, backups []*v1.NdbClusterBackup, now time.Time) (time.Time, syncResult) {
+
+	if ncbs.Spec.Suspend {
+		// No backups are due. The latest missed
+		// backup will be taken once resumed.
+		ncbs.Status.NextScheduleTime = nil
+		return time.Time{}, continueProcessing()
+	}
+
+	// Find the most recent schedule time that has been missed
+	lastScheduleTime := ncbs.CreationTimestamp.Time
+	if ncbs.Status.LastScheduleTime != nil {
+		lastScheduleTime = ncbs.Status.LastScheduleTime.Time
+	}
+	var missedScheduleTime time.Time
+	nextScheduleTime := schedule.Next(lastScheduleTime.UTC())
+	for !nextScheduleTime.IsZero() && !nextScheduleTime.After(now) {
+		missedScheduleTime = nextScheduleTime
+		nextScheduleTime = schedule.Next(nextScheduleTime)
+	}
+
+	if nextScheduleTime.IsZero() {
+		ncbs.Status.NextScheduleTime = nil
+	} else {
+		ncbs.Status.NextScheduleTime = &metav1.Time{Time: nextScheduleTime}
+	}
+
+	if missedScheduleTime.IsZero() {
+		// No backup is due yet
+		return nextScheduleTime, continueProcessing()
+	}
+
+	ncbs.Status.LastScheduleTime = &metav1.Time{Time: missedScheduleTime}
+	// Name is unique for every schedule time
+	backupName := ncbs.Name + "-" + missedScheduleTime.Format("20060102-1504")
+
+	// Skip the backup if a previous backup is still running.
+	// The MySQL Cluster allows only one backup at a time.
+	for _, ncb := range backups {
+		if !ncb.IsFinished() && ncb.Name != backupName {
+			klog.Infof("Skipping the backup of schedule %q as the NdbClusterBackup %q is still running",
+				getNamespacedName(ncbs), ncb.Name)
+			sc.recorder.Eventf(ncbs, nil, corev1.EventTypeWarning, ReasonBackupSkipped, ActionSchedule,
+				MessageBackupSkipped, missedScheduleTime.Format(time.RFC3339), ncb.Name)
+			return nextScheduleTime, continueProcessing()
+		}
+	}
+
+	timeoutSeconds := ncbs.GetBackupTimeoutSeconds()
+	ncb := &v1.NdbClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            backupName,
+			Namespace:       ncbs.Namespace,
+			Labels:          map[string]string{constants.BackupScheduleLabel: ncbs.Name},
+			OwnerReferences: ncbs.GetOwnerReferences(),
+		},
+		Spec: v1.NdbClusterBackupSpec{
+			ClusterName:    ncbs.Spec.ClusterName,
+			TimeoutSeconds: timeoutSeconds,
+		},
+	}
+
+	_, err := sc.ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).Create(ctx, ncb, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		// Backup will be created when the schedule is processed again.
+		// AlreadyExists is ignored as the backup might have been created
+		// in an earlier sync whose status update failed.
+		klog.Errorf("Failed to create NdbClusterBackup %q : %s", getNamespacedName(ncb), err)
+		return time.Time{}, errorWhileProcessing(err)
+	}
+
+	klog.Infof("Created NdbClusterBackup %q as per the schedule %q", getNamespacedName(ncb), getNamespacedName(ncbs))
+	sc.recorder.Eventf(ncbs, nil, corev1.EventTypeNormal,
+		ReasonBackupScheduled, ActionSchedule, MessageBackupScheduled, ncb.Name)
+	ncbs.Status.LastBackupName = ncb.Name
+	return nextScheduleTime, continueProcessing()
+}
+
+// pruneBackups deletes the backups that are not retained by the retention
+// policy along with their backup files. It returns the time the next retained
+// backup expires, or a zero time if none of the retained backups expire.
+func (sc *NdbClusterBackupScheduleController) pruneBackups(
+	ctx context.Context, ncbs *v1.NdbClusterBackupSchedule,
+	backups []*v1.NdbClusterBackup, now time.Time) (time.Time, error) {
+
+	var completedBackups, failedBackups []*v1.NdbClusterBackup
+	for _, ncb := range backups {
+		switch ncb.Status.Phase {
+		case v1.NdbClusterBackupPhaseCompleted:
+			completedBackups = append(completedBackups, ncb)
+		case v1.NdbClusterBackupPhaseFailed:
+			failedBackups = append(failedBackups, ncb)
+		}
+	}
+
+	if len(completedBackups) == 0 {
+		// Nothing to prune until a backup completes
+		return time.Time{}, nil
+	}
+
+	// Sort the completed backups, newest first
+	sort.Slice(completedBackups, func(i, j int) bool {
+		return completedBackups[j].CreationTimestamp.Before(&completedBackups[i].CreationTimestamp)
+	})
+	latestBackup := completedBackups[0]
+	ncbs.Status.LastCompletedBackupName = latestBackup.Name
+
+	// The failed backups older than the latest completed backup are not needed anymore
+	var backupsToPrune []*v1.NdbClusterBackup
+	for _, ncb := range failedBackups {
+		if ncb.CreationTimestamp.Before(&latestBackup.CreationTimestamp) {
+			backupsToPrune = append(backupsToPrune, ncb)
+		}
+	}
+
+	// The latest completed backup is always retained
+	retention := ncbs.Spec.Retention
+	var nextExpiryTime time.Time
+	for i, ncb := range completedBackups[1:] {
+		if retention.KeepLast != nil && int32(i+1) >= *retention.KeepLast {
+			backupsToPrune = append(backupsToPrune, ncb)
+			continue
+		}
+
+		if retention.KeepFor != nil && ncb.Status.CompletionTime != nil {
+			expiryTime := ncb.Status.CompletionTime.Add(retention.KeepFor.Duration)
+			if !expiryTime.After(now) {
+				backupsToPrune = append(backupsToPrune, ncb)
+				continue
+			}
+
+			if nextExpiryTime.IsZero() || expiryTime.Before(nextExpiryTime) {
+				nextExpiryTime = expiryTime
+			}
+		}
+	}
+
+	var errs []error
+	for _, ncb := range backupsToPrune {
+		if err := sc.deleteBackup(ctx, ncbs, ncb); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return nextExpiryTime, errors.Join(errs...)
+}
+
+// deleteBackup deletes the backup files from the
+// data node pods and then the NdbClusterBackup resource.
+func (sc *NdbClusterBackupScheduleController) deleteBackup(
+	ctx context.Context, ncbs *v1.NdbClusterBackupSchedule, ncb *v1.NdbClusterBackup) error {
+
+	if ncb.Status.Phase == v1.NdbClusterBackupPhaseCompleted {
+		// The data nodes delete the files of
+		// the aborted backups by themselves.
+		if err := sc.deleteBackupFiles(ctx, ncb); err != nil {
+			klog.Errorf("Failed to delete the files of NdbClusterBackup %q : %s", getNamespacedName(ncb), err)
+			return err
+		}
+	}
+
+	err := sc.ndbClient.MysqlV1().NdbClusterBackups(ncb.Namespace).Delete(ctx, ncb.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete NdbClusterBackup %q : %s", getNamespacedName(ncb), err)
+		return err
+	}
+
+	klog.Infof("Deleted NdbClusterBackup %q as per the retention policy of schedule %q",
+		getNamespacedName(ncb), getNamespacedName(ncbs))
+	sc.recorder.Eventf(ncbs, nil, corev1.EventTypeNormal,
+		ReasonBackupPruned, ActionSchedule, MessageBackupPruned, ncb.Name)
+	return nil
+}
+
+// deleteBackupFiles deletes the BACKUP-<id> directories written by the data nodes
+func (sc *NdbClusterBackupScheduleController) deleteBackupFiles(
+	ctx context.Context, ncb *v1.NdbClusterBackup) error {
+
+	if len(ncb.Status.Locations) == 0 {
+		return nil
+	}
+
+	if _, err := sc.ndbsLister.NdbClusters(ncb.Namespace).Get(ncb.Spec.ClusterName); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The NdbCluster, and hence the data node pods
+			// and their PVCs, do not exist anymore.
+			return nil
+		}
+		return err
+	}
+
+	// Deleting a large backup can take long.
+	defer keepAlive(ctx)()
+
+	expectedDirName := fmt.Sprintf("BACKUP-%d", ncb.Status.BackupId)
+	for _, location := range ncb.Status.Locations {
+		if filepath.Base(location.Path) != expectedDirName {
+			// Safeguard against deleting any other directory
+			return fmt.Errorf("location %q of backup %d has unexpected format", location.Path, ncb.Status.BackupId)
+		}
+
+		if filesExist, err := sc.backupLocationExists(ncb, location); err != nil {
+			return err
+		} else if !filesExist {
+			klog.Infof("Files of backup %d in pod %q do not exist anymore",
+				ncb.Status.BackupId, getNamespacedName2(ncb.Namespace, location.PodName))
+			continue
+		}
+
+		_, err := execInPod(ctx, sc.kubernetesClient, sc.restConfig,
+			ncb.Namespace, location.PodName, statefulset.GetDataNodeContainerName(),
+			[]string{"rm", "-rf", location.Path})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backupLocationExists returns false if the files written to the given
+// location have been deleted along with the data node pod and its PVC. An
+// error is returned if the pod does not exist but its PVC still does, as the
+// files can be deleted only after the pod has been started again.
+func (sc *NdbClusterBackupScheduleController) backupLocationExists(
+	ncb *v1.NdbClusterBackup, location v1.NdbClusterBackupLocation) (bool, error) {
+	_, err := sc.podLister.Pods(ncb.Namespace).Get(location.PodName)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err == nil, err
+	}
+
+	if location.PersistentVolumeClaimName == "" {
+		// The files were stored in the pod, which does not exist anymore
+		return false, nil
+	}
+
+	_, err = sc.pvcLister.PersistentVolumeClaims(ncb.Namespace).Get(location.PersistentVolumeClaimName)
+	if apierrors.IsNotFound(err) {
+		// The PVC storing the files has been deleted
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return false, fmt.Errorf("cannot delete the files of backup %d as the data node pod %q does not exist",
+		ncb.Status.BackupId, getNamespacedName2(ncb.Namespace, location.PodName))
+}
+
+// updateScheduleStatus updates the status of the given NdbClusterBackupSchedule resource
+func (sc *NdbClusterBackupScheduleController) updateScheduleStatus(
+	ctx context.Context, ncbs *v1.NdbClusterBackupSchedule) error {
+	status := ncbs.Status.DeepCopy()
+	ncbsInterface := sc.ndbClient.MysqlV1().NdbClusterBackupSchedules(ncbs.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		status.DeepCopyInto(&ncbs.Status)
+		updatedNcbs, updateErr := ncbsInterface.UpdateStatus(ctx, ncbs, metav1.UpdateOptions{})
+		if updateErr == nil {
+			updatedNcbs.DeepCopyInto(ncbs)
+			return nil
+		}
+
+		// Get the latest version of the NdbClusterBackupSchedule to retry the update
+		latestNcbs, getErr := ncbsInterface.Get(ctx, ncbs.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get NdbClusterBackupSchedule resource during status update %q: %v",
+				getNamespacedName(ncbs), getErr)
+			return getErr
+		}
+		latestNcbs.DeepCopyInto(ncbs)
+
+		return updateErr
+	})
+
+	if err != nil {
+		klog.Errorf("Failed to update the status of NdbClusterBackupSchedule resource %q : %v",
+			getNamespacedName(ncbs), err)
+	}
+
+	return err
+}
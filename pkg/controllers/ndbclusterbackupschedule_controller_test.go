@@ -0,0 +1,322 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	informers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+func newTestBackupSchedule(schedule string, created time.Time) *v1.NdbClusterBackupSchedule {
+	return &v1.NdbClusterBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-schedule",
+			Namespace:         metav1.NamespaceDefault,
+			UID:               "test-schedule-uid",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1.NdbClusterBackupScheduleSpec{
+			ClusterName: "test-cluster",
+			Schedule:    schedule,
+		},
+	}
+}
+
+// runBackupScheduleSync syncs the given schedule once and
+// returns the ndbClient used and the requeue duration
+func runBackupScheduleSync(t *testing.T, ncbs *v1.NdbClusterBackupSchedule,
+	objects ...runtime.Object) (*fake.Clientset, time.Duration) {
+	t.Helper()
+
+	// Split the K8s objects from the NDB objects
+	k8sObjects := []runtime.Object{}
+	ndbObjects := []runtime.Object{ncbs}
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *corev1.Pod, *corev1.PersistentVolumeClaim:
+			k8sObjects = append(k8sObjects, obj)
+		default:
+			ndbObjects = append(ndbObjects, obj)
+		}
+	}
+
+	k8sClient := k8sfake.NewSimpleClientset(k8sObjects...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	ndbClient := fake.NewSimpleClientset(ndbObjects...)
+	ndbIf := informers.NewSharedInformerFactory(ndbClient, 0)
+	sc := NewNdbClusterBackupScheduleController(k8sClient, ndbClient, nil, k8sIf, ndbIf)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	k8sIf.Start(stopCh)
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, sc.informerSyncedMethods...); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	requeueAfter, sr := sc.syncHandler(context.TODO(), getNamespacedName(ncbs))
+	if err := sr.getError(); err != nil {
+		t.Fatalf("Unexpected error during sync : %s", err)
+	}
+
+	return ndbClient, requeueAfter
+}
+
+func TestNdbClusterBackupScheduleCreatesDueBackup(t *testing.T) {
+	ncbs := newTestBackupSchedule("* * * * *", time.Now().Add(-2*time.Minute))
+	ndbClient, requeueAfter := runBackupScheduleSync(t, ncbs)
+
+	if requeueAfter <= 0 || requeueAfter > time.Minute {
+		t.Errorf("Expected the schedule to be requeued within a minute but got %s", requeueAfter)
+	}
+
+	backups, err := ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 1 {
+		t.Fatalf("Expected 1 NdbClusterBackup to be created but got %d", len(backups.Items))
+	}
+
+	ncb := backups.Items[0]
+	if ncb.Spec.ClusterName != ncbs.Spec.ClusterName {
+		t.Errorf("Expected backup of NdbCluster %q but got %q", ncbs.Spec.ClusterName, ncb.Spec.ClusterName)
+	}
+	if ncb.Labels[constants.BackupScheduleLabel] != ncbs.Name || !metav1.IsControlledBy(&ncb, ncbs) {
+		t.Error("Expected the NdbClusterBackup to be labelled and owned by the schedule")
+	}
+
+	ncbs, err = ndbClient.MysqlV1().NdbClusterBackupSchedules(ncbs.Namespace).Get(
+		context.TODO(), ncbs.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbClusterBackupSchedule : %s", err)
+	}
+	if ncbs.Status.LastBackupName != ncb.Name || ncbs.Status.LastScheduleTime == nil {
+		t.Errorf("Expected the status to record the backup %q but got %+v", ncb.Name, ncbs.Status)
+	}
+}
+
+func TestNdbClusterBackupScheduleRetention(t *testing.T) {
+	now := time.Now()
+	ncbs := newTestBackupSchedule("@daily", now.Add(-10*24*time.Hour))
+	ncbs.Spec.Suspend = true
+	keepLast := int32(2)
+	ncbs.Spec.Retention.KeepLast = &keepLast
+
+	newBackup := func(age int, phase v1.NdbClusterBackupPhase) *v1.NdbClusterBackup {
+		created := metav1.NewTime(now.Add(-time.Duration(age) * 24 * time.Hour))
+		return &v1.NdbClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("%s-%d", ncbs.Name, age),
+				Namespace:         ncbs.Namespace,
+				Labels:            map[string]string{constants.BackupScheduleLabel: ncbs.Name},
+				OwnerReferences:   ncbs.GetOwnerReferences(),
+				CreationTimestamp: created,
+			},
+			Spec: v1.NdbClusterBackupSpec{
+				ClusterName: ncbs.Spec.ClusterName,
+			},
+			Status: v1.NdbClusterBackupStatus{
+				Phase:          phase,
+				CompletionTime: &created,
+			},
+		}
+	}
+
+	ndbClient, _ := runBackupScheduleSync(t, ncbs,
+		newBackup(1, v1.NdbClusterBackupPhaseCompleted),
+		newBackup(2, v1.NdbClusterBackupPhaseFailed),
+		newBackup(3, v1.NdbClusterBackupPhaseCompleted),
+		newBackup(4, v1.NdbClusterBackupPhaseCompleted),
+		newBackup(5, v1.NdbClusterBackupPhaseCompleted),
+	)
+
+	backups, err := ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+
+	// Only the latest two completed backups should be retained
+	retained := make(map[string]bool)
+	for _, ncb := range backups.Items {
+		retained[ncb.Name] = true
+	}
+	if len(retained) != 2 || !retained[ncbs.Name+"-1"] || !retained[ncbs.Name+"-3"] {
+		t.Errorf("Expected backups %s-1 and %s-3 to be retained but got %v", ncbs.Name, ncbs.Name, retained)
+	}
+}
+
+func TestNdbClusterBackupScheduleRetentionOfBackupOnRemovedPods(t *testing.T) {
+	now := time.Now()
+	ncbs := newTestBackupSchedule("@daily", now.Add(-10*24*time.Hour))
+	ncbs.Spec.Suspend = true
+	keepLast := int32(1)
+	ncbs.Spec.Retention.KeepLast = &keepLast
+	nc := testutils.NewTestNdb(ncbs.Namespace, ncbs.Spec.ClusterName, 2)
+
+	newBackup := func(age int, locations ...v1.NdbClusterBackupLocation) *v1.NdbClusterBackup {
+		created := metav1.NewTime(now.Add(-time.Duration(age) * 24 * time.Hour))
+		return &v1.NdbClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("%s-%d", ncbs.Name, age),
+				Namespace:         ncbs.Namespace,
+				Labels:            map[string]string{constants.BackupScheduleLabel: ncbs.Name},
+				OwnerReferences:   ncbs.GetOwnerReferences(),
+				CreationTimestamp: created,
+			},
+			Spec: v1.NdbClusterBackupSpec{
+				ClusterName: ncbs.Spec.ClusterName,
+			},
+			Status: v1.NdbClusterBackupStatus{
+				Phase:          v1.NdbClusterBackupPhaseCompleted,
+				BackupId:       int32(age),
+				CompletionTime: &created,
+				Locations:      locations,
+			},
+		}
+	}
+	newLocation := func(podName, pvcName string) v1.NdbClusterBackupLocation {
+		return v1.NdbClusterBackupLocation{
+			PodName:                   podName,
+			PersistentVolumeClaimName: pvcName,
+			Path:                      "/var/lib/ndb/data/BACKUP/BACKUP-2",
+		}
+	}
+	newObjectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: ncbs.Namespace}
+	}
+
+	// Only the pod test-cluster-ndbmtd-0 still exists. The pod
+	// test-cluster-ndbmtd-1 had no PVC and the PVC of the pod
+	// test-cluster-ndbmtd-2 has been deleted along with it.
+	var podsExecuted []string
+	fakeExecInPod(t, func(podName string, cmd []string) (string, error) {
+		podsExecuted = append(podsExecuted, podName)
+		return "", nil
+	})
+	ndbClient, _ := runBackupScheduleSync(t, ncbs, nc,
+		&corev1.Pod{ObjectMeta: newObjectMeta("test-cluster-ndbmtd-0")},
+		&corev1.PersistentVolumeClaim{ObjectMeta: newObjectMeta("ndb-pvc-test-cluster-ndbmtd-0")},
+		newBackup(1),
+		newBackup(2,
+			newLocation("test-cluster-ndbmtd-0", "ndb-pvc-test-cluster-ndbmtd-0"),
+			newLocation("test-cluster-ndbmtd-1", ""),
+			newLocation("test-cluster-ndbmtd-2", "ndb-pvc-test-cluster-ndbmtd-2")),
+	)
+
+	if len(podsExecuted) != 1 || podsExecuted[0] != "test-cluster-ndbmtd-0" {
+		t.Errorf("Expected the backup files to be deleted only from the existing pod but got %v", podsExecuted)
+	}
+
+	backups, err := ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 1 || backups.Items[0].Name != ncbs.Name+"-1" {
+		t.Errorf("Expected only the backup %s-1 to be retained but got %d backups", ncbs.Name, len(backups.Items))
+	}
+}
+
+func TestNdbClusterBackupScheduleOfSuspendedNdbCluster(t *testing.T) {
+	ncbs := newTestBackupSchedule("* * * * *", time.Now().Add(-2*time.Minute))
+	nc := testutils.NewTestNdb(ncbs.Namespace, ncbs.Spec.ClusterName, 2)
+	nc.Spec.Suspended = true
+
+	// No backups are taken while the NdbCluster is suspended
+	ndbClient, requeueAfter := runBackupScheduleSync(t, ncbs, nc)
+	backups, err := ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 0 {
+		t.Errorf("Expected no NdbClusterBackups while the NdbCluster is suspended but got %d", len(backups.Items))
+	}
+	if requeueAfter != 0 {
+		t.Errorf("Expected the schedule not to be requeued while the NdbCluster is suspended but got %s",
+			requeueAfter)
+	}
+	ncbs, err = ndbClient.MysqlV1().NdbClusterBackupSchedules(ncbs.Namespace).Get(
+		context.TODO(), ncbs.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbClusterBackupSchedule : %s", err)
+	}
+	if ncbs.Status.NextScheduleTime != nil || !strings.Contains(ncbs.Status.Message, "is suspended") {
+		t.Errorf("Expected the status to report the suspended NdbCluster but got %+v", ncbs.Status)
+	}
+
+	// The latest missed backup is taken once the NdbCluster is resumed
+	nc.Spec.Suspended = false
+	ndbClient, _ = runBackupScheduleSync(t, ncbs, nc)
+	backups, err = ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 1 {
+		t.Errorf("Expected 1 NdbClusterBackup to be created after the resume but got %d", len(backups.Items))
+	}
+	ncbs, err = ndbClient.MysqlV1().NdbClusterBackupSchedules(ncbs.Namespace).Get(
+		context.TODO(), ncbs.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbClusterBackupSchedule : %s", err)
+	}
+	if ncbs.Status.NextScheduleTime == nil || ncbs.Status.Message != "" {
+		t.Errorf("Expected the status to report the next backup but got %+v", ncbs.Status)
+	}
+}
+
+func TestNdbClusterBackupScheduleOfPausedNdbCluster(t *testing.T) {
+	ncbs := newTestBackupSchedule("* * * * *", time.Now().Add(-2*time.Minute))
+	nc := testutils.NewTestNdb(ncbs.Namespace, ncbs.Spec.ClusterName, 2)
+	nc.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+
+	// No backups are taken while the reconciliation is paused
+	ndbClient, requeueAfter := runBackupScheduleSync(t, ncbs, nc)
+	backups, err := ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 0 {
+		t.Errorf("Expected no NdbClusterBackups while the NdbCluster is paused but got %d", len(backups.Items))
+	}
+	if requeueAfter != 0 {
+		t.Errorf("Expected the schedule not to be requeued while the NdbCluster is paused but got %s",
+			requeueAfter)
+	}
+	ncbs, err = ndbClient.MysqlV1().NdbClusterBackupSchedules(ncbs.Namespace).Get(
+		context.TODO(), ncbs.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbClusterBackupSchedule : %s", err)
+	}
+	if ncbs.Status.NextScheduleTime != nil || !strings.Contains(ncbs.Status.Message, "has been paused") {
+		t.Errorf("Expected the status to report the paused NdbCluster but got %+v", ncbs.Status)
+	}
+
+	// The latest missed backup is taken once the reconciliation is resumed
+	nc.Annotations = nil
+	ndbClient, _ = runBackupScheduleSync(t, ncbs, nc)
+	backups, err = ndbClient.MysqlV1().NdbClusterBackups(ncbs.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list NdbClusterBackups : %s", err)
+	}
+	if len(backups.Items) != 1 {
+		t.Errorf("Expected 1 NdbClusterBackup to be created after the resume but got %d", len(backups.Items))
+	}
+}
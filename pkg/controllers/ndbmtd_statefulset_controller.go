@@ -1,4 +1,4 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -6,6 +6,7 @@ package controllers
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -23,22 +24,27 @@ import (
 
 type ndbmtdStatefulSetController struct {
 	ndbNodeStatefulSetImpl
+	secretLister listerscorev1.SecretLister
 }
 
 // newNdbmtdStatefulSetController creates a new ndbmtdStatefulSetController
 func newNdbmtdStatefulSetController(client kubernetes.Interface,
 	statefulSetLister listerappsv1.StatefulSetLister, secretLister listerscorev1.SecretLister) *ndbmtdStatefulSetController {
 	return &ndbmtdStatefulSetController{
-		ndbNodeStatefulSetImpl{
+		ndbNodeStatefulSetImpl: ndbNodeStatefulSetImpl{
 			client:             client,
 			statefulSetLister:  statefulSetLister,
 			ndbNodeStatefulset: statefulset.NewNdbmtdStatefulSet(secretLister),
 		},
+		secretLister: secretLister,
 	}
 }
 
 const (
 	AddNodeOnlineInProgress = ndbcontroller.GroupName + "/add-node-online-in-progress"
+	// FullRestartInProgress is set when all the data nodes have been
+	// stopped to apply a spec update via the FullRestart update strategy.
+	FullRestartInProgress = ndbcontroller.GroupName + "/full-restart-in-progress"
 )
 
 // startNewDataNodes scales up the ndbmtd statefulset to start the new data nodes
@@ -61,16 +67,16 @@ func (nssc *ndbmtdStatefulSetController) startNewDataNodes(ctx context.Context,
 }
 
 // createNodeGroups inducts the new data nodes into the MySQL Cluster by creating nodegroups on them.
-func (nssc *ndbmtdStatefulSetController) createNodeGroups(sc *SyncContext) syncResult {
+func (nssc *ndbmtdStatefulSetController) createNodeGroups(ctx context.Context, sc *SyncContext) syncResult {
 	// Connect to the Management Server
-	mgmClient, err := mgmapi.NewMgmClient(sc.ndb.GetConnectstring())
+	mgmClient, err := newMgmClient(ctx, sc.kubernetesClient, sc.ndb)
 	if err != nil {
 		klog.Errorf("Failed to connect to Management Server : %s", err)
 		return errorWhileProcessing(err)
 	}
 	defer mgmClient.Disconnect()
 
-	clusterStatus, err := mgmClient.GetStatus()
+	clusterStatus, err := sc.getClusterStatus(mgmClient)
 	if err != nil {
 		klog.Errorf("Error getting cluster status from management server: %s", err)
 		return errorWhileProcessing(err)
@@ -137,6 +143,9 @@ func (nssc *ndbmtdStatefulSetController) reorgNdbTables(ctx context.Context, sc
 
 	// Run reorg and optimize for all tables, one by one.
 	klog.Infof("Redistributing NDB data among all data nodes, including the new ones")
+	// Redistributing the data of large tables can take hours.
+	// Report heartbeats until all the tables are reorganized.
+	defer keepAlive(ctx)()
 	var tableSchema, tableName string
 	for rows.Next() {
 		if err = rows.Scan(&tableSchema, &tableName); err != nil {
@@ -154,32 +163,13 @@ func (nssc *ndbmtdStatefulSetController) reorgNdbTables(ctx context.Context, sc
 			klog.Errorf("Query '%s' failed : %s", query, err)
 			return errorWhileProcessing(err)
 		}
-		tableFullName := fmt.Sprintf("%s.%s", tableSchema, tableName)
 		if distributedNodeCount == sc.configSummary.NumOfDataNodes {
 			// Table already reorganized
-			klog.Infof("Table %q has already been redistributed", tableFullName)
+			klog.Infof("Table %q has already been redistributed", tableSchema+"."+tableName)
 			continue
 		}
 
-		// Run ALTER TABLE ... ALGORITHM=INPLACE, REORGANIZE PARTITION
-		query = fmt.Sprintf("ALTER TABLE %s ALGORITHM=INPLACE, REORGANIZE PARTITION", tableFullName)
-		klog.Infof("Running '%s'", query)
-		if _, err = mysqlClient.ExecContext(ctx, query); err != nil {
-			klog.Errorf("Query '%s' failed : %s", query, err)
-			return errorWhileProcessing(err)
-		}
-
-		// Run OPTIMIZE TABLE
-		query = fmt.Sprintf("OPTIMIZE TABLE %s", tableFullName)
-		klog.Infof("Running '%s'", query)
-		row = mysqlClient.QueryRowContext(ctx, query)
-		var dummy, result, msg string
-		if err = row.Scan(&dummy, &dummy, &result, &msg); err == nil && result == "error" {
-			err = errors.New(msg)
-		}
-
-		if err != nil {
-			klog.Errorf("Query '%s' failed : %s", query, err)
+		if err = reorganizeNdbTable(ctx, mysqlClient, tableSchema, tableName); err != nil {
 			return errorWhileProcessing(err)
 		}
 	}
@@ -189,6 +179,37 @@ func (nssc *ndbmtdStatefulSetController) reorgNdbTables(ctx context.Context, sc
 	return continueProcessing()
 }
 
+// reorganizeNdbTable redistributes the data of the given NDB table among
+// the nodegroups by running REORGANIZE PARTITION and then OPTIMIZE TABLE.
+func reorganizeNdbTable(ctx context.Context, mysqlClient *sql.DB, tableSchema, tableName string) error {
+	tableFullName := mysqlclient.QuoteIdentifier(tableSchema) + "." + mysqlclient.QuoteIdentifier(tableName)
+
+	// Run ALTER TABLE ... ALGORITHM=INPLACE, REORGANIZE PARTITION
+	query := fmt.Sprintf("ALTER TABLE %s ALGORITHM=INPLACE, REORGANIZE PARTITION", tableFullName)
+	klog.Infof("Running '%s'", query)
+	if _, err := mysqlClient.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Query '%s' failed : %s", query, err)
+		return err
+	}
+
+	// Run OPTIMIZE TABLE
+	query = fmt.Sprintf("OPTIMIZE TABLE %s", tableFullName)
+	klog.Infof("Running '%s'", query)
+	row := mysqlClient.QueryRowContext(ctx, query)
+	var dummy, result, msg string
+	err := row.Scan(&dummy, &dummy, &result, &msg)
+	if err == nil && result == "error" {
+		err = errors.New(msg)
+	}
+
+	if err != nil {
+		klog.Errorf("Query '%s' failed : %s", query, err)
+		return err
+	}
+
+	return nil
+}
+
 // handleAddNodeOnline scales up the data node statefulset and
 // then creates nodegroups for the newly started data nodes.
 func (nssc *ndbmtdStatefulSetController) handleAddNodeOnline(ctx context.Context, sc *SyncContext) syncResult {
@@ -205,7 +226,7 @@ func (nssc *ndbmtdStatefulSetController) handleAddNodeOnline(ctx context.Context
 	}
 
 	// Create node groups
-	if sr := nssc.createNodeGroups(sc); sr.stopSync() {
+	if sr := nssc.createNodeGroups(ctx, sc); sr.stopSync() {
 		return sr
 	}
 
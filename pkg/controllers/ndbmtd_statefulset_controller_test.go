@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// newTestDataNodeSyncContext returns a SyncContext of an NdbCluster whose
+// MySQL Cluster runs the given number of data nodes, from nodeId 3 onwards,
+// in nodegroups of 2. The data node StatefulSet has the given annotations.
+func newTestDataNodeSyncContext(t *testing.T,
+	numOfDataNodes int32, annotations map[string]string) (*SyncContext, *k8sfake.Clientset) {
+	t.Helper()
+
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", numOfDataNodes)
+	dataNodeSfset := newTestStatefulSet(numOfDataNodes)
+	dataNodeSfset.ObjectMeta = metav1.ObjectMeta{
+		Name:        "example-ndb-ndbmtd",
+		Namespace:   nc.Namespace,
+		Annotations: annotations,
+	}
+	mysqldSfset := newTestStatefulSet(1)
+	mysqldSfset.ObjectMeta = metav1.ObjectMeta{Name: "example-ndb-mysqld", Namespace: nc.Namespace}
+
+	operatorPasswordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMySQLNDBOperatorPasswordSecretName(nc),
+			Namespace: nc.Namespace,
+		},
+		Data: map[string][]byte{corev1.BasicAuthPasswordKey: []byte("password")},
+	}
+
+	k8sClient := k8sfake.NewSimpleClientset(dataNodeSfset, operatorPasswordSecret)
+	return &SyncContext{
+		ndb: nc,
+		configSummary: &ndbconfig.ConfigSummary{
+			NumOfDataNodes:      numOfDataNodes,
+			DataNodeStartNodeId: 3,
+			RedundancyLevel:     2,
+		},
+		dataNodeSfSet:    dataNodeSfset,
+		mysqldSfset:      mysqldSfset,
+		kubernetesClient: k8sClient,
+		ndbmtdController: newNdbmtdStatefulSetController(k8sClient, nil, nil),
+		recorder:         events.NewFakeRecorder(10),
+	}, k8sClient
+}
+
+// getTestDataNodeStatefulSet returns the data node StatefulSet stored in the fake client
+func getTestDataNodeStatefulSet(t *testing.T, sc *SyncContext, k8sClient *k8sfake.Clientset) *appsv1.StatefulSet {
+	t.Helper()
+
+	sfset, err := k8sClient.AppsV1().StatefulSets(sc.dataNodeSfSet.Namespace).Get(
+		context.TODO(), sc.dataNodeSfSet.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve the data node StatefulSet : %s", err)
+	}
+	return sfset
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+)
+
+// NdbMySQLDatabaseController is the controller implementation for the
+// NdbMySQLDatabase resources. It creates and alters the databases, via
+// the NDB Operator user, in the MySQL Servers of the NdbClusters and
+// periodically recreates the databases dropped outside the operator.
+// The databases are never dropped by the controller.
+type NdbMySQLDatabaseController struct {
+	kubernetesClient kubernetes.Interface
+	ndbClient        ndbclientset.Interface
+
+	// NdbCluster and NdbMySQLDatabase Listers
+	ndbsLister              ndblisters.NdbClusterLister
+	ndbMySQLDatabasesLister ndblisters.NdbMySQLDatabaseLister
+
+	// StatefulSet Lister to retrieve the MySQL Server StatefulSets
+	statefulSetLister appslisters.StatefulSetLister
+
+	// Slice of InformerSynced methods for all the informers used by the controller
+	informerSyncedMethods []cache.InformerSynced
+
+	// A rate limited workqueue for queueing the NdbMySQLDatabase
+	// resource keys on receiving an event or when a drift check is due.
+	workqueue workqueue.RateLimitingInterface
+	// An event recorder for recording Event resources to the Kubernetes API.
+	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
+}
+
+// NewNdbMySQLDatabaseController returns a new NdbMySQLDatabase controller
+func NewNdbMySQLDatabaseController(
+	kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface,
+	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *NdbMySQLDatabaseController {
+
+	// Register for all the required informers
+	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbMySQLDatabaseInformer := ndbSharedIndexInformer.Mysql().V1().NdbMySQLDatabases()
+	statefulSetInformer := k8sSharedIndexInformer.Apps().V1().StatefulSets()
+
+	controller := &NdbMySQLDatabaseController{
+		kubernetesClient:        kubernetesClient,
+		ndbClient:               ndbClient,
+		ndbsLister:              ndbClusterInformer.Lister(),
+		ndbMySQLDatabasesLister: ndbMySQLDatabaseInformer.Lister(),
+		statefulSetLister:       statefulSetInformer.Lister(),
+		informerSyncedMethods: []cache.InformerSynced{
+			ndbClusterInformer.Informer().HasSynced,
+			ndbMySQLDatabaseInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+		},
+		workqueue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "NdbMySQLDatabases"),
+		recorder: newEventRecorder(kubernetesClient),
+	}
+
+	// Set up event handler for NdbMySQLDatabase resource changes
+	ndbMySQLDatabaseInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key := getNamespacedName(obj.(*v1.NdbMySQLDatabase))
+			klog.Infof("New NdbMySQLDatabase resource added : %q, queueing it for reconciliation", key)
+			controller.workqueue.Add(key)
+		},
+
+		UpdateFunc: func(old, new interface{}) {
+			oldNmd := old.(*v1.NdbMySQLDatabase)
+			newNmd := new.(*v1.NdbMySQLDatabase)
+			if oldNmd.Generation == newNmd.Generation {
+				// Periodic resync or a status update - the
+				// drift checks are done by the requeues
+				return
+			}
+			controller.workqueue.Add(getNamespacedName(newNmd))
+		},
+	})
+
+	return controller
+}
+
+// Run starts the workers that process the NdbMySQLDatabase resources.
+// It will block until ctx is cancelled, at which point it will shut
+// down the workqueue and wait for the workers to finish processing
+// their current work items.
+func (dc *NdbMySQLDatabaseController) Run(ctx context.Context, threadiness int) error {
+	defer utilruntime.HandleCrash()
+	defer dc.workqueue.ShutDown()
+
+	klog.Info("Starting NdbMySQLDatabase controller")
+
+	// Wait for the caches to be synced before starting workers
+	if ok := cache.WaitForNamedCacheSync(
+		controllerName, ctx.Done(), dc.informerSyncedMethods...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	// Launch worker go routines to process NdbMySQLDatabase resources
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for dc.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	klog.Info("Started NdbMySQLDatabase workers")
+	<-ctx.Done()
+	klog.Info("Shutting down NdbMySQLDatabase workers")
+
+	return nil
+}
+
+// processNextWorkItem reads a single work item off the
+// workqueue and processes it, by calling the syncHandler.
+func (dc *NdbMySQLDatabaseController) processNextWorkItem(ctx context.Context) bool {
+	item, shutdown := dc.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer dc.workqueue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		dc.workqueue.Forget(item)
+		klog.Error(debug.InternalError(fmt.Errorf("expected string in workqueue but got %#v", item)))
+		return true
+	}
+
+	dc.activeSyncs.start(key)
+	requeueAfter, sr := dc.syncHandler(dc.activeSyncs.withHeartbeats(ctx, key), key)
+	dc.activeSyncs.finish(key)
+	if err := sr.getError(); err != nil {
+		klog.Infof("Reconciliation of NdbMySQLDatabase resource %q failed : %s", key, err)
+		klog.Info("Re-queuing resource to retry reconciliation after error")
+		dc.workqueue.AddRateLimited(key)
+		return true
+	}
+
+	dc.workqueue.Forget(item)
+	if requeueAfter > 0 {
+		dc.workqueue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+// syncHandler creates or alters the database as per the NdbMySQLDatabase
+// spec. It returns the duration after which the database has to be
+// processed again.
+func (dc *NdbMySQLDatabaseController) syncHandler(ctx context.Context, key string) (time.Duration, syncResult) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return 0, finishProcessing()
+	}
+
+	nmdOrg, err := dc.ndbMySQLDatabasesLister.NdbMySQLDatabases(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The database is not dropped
+			klog.Infof("NdbMySQLDatabase resource %q does not exist anymore", key)
+			return 0, finishProcessing()
+		}
+		klog.Errorf("Failed to retrieve NdbMySQLDatabase resource %q", key)
+		return 0, errorWhileProcessing(err)
+	}
+
+	// Work on a copy to avoid mutating the cache
+	nmd := nmdOrg.DeepCopy()
+
+	sr := dc.reconcileDatabase(ctx, nmd)
+
+	if !reflect.DeepEqual(nmdOrg.Status, nmd.Status) {
+		if err = dc.updateDatabaseStatus(ctx, nmd); err != nil {
+			return 0, errorWhileProcessing(err)
+		}
+	}
+
+	if sr.stopSync() {
+		if err = sr.getError(); err != nil {
+			return 0, sr
+		}
+		// The database could not be reconciled as the NdbCluster
+		// is not available yet. Retry after some time.
+		return mysqlObjectRetryInterval, finishProcessing()
+	}
+
+	// Check the database for drifts periodically
+	return mysqlObjectDriftCheckInterval, finishProcessing()
+}
+
+// reconcileDatabase creates or alters the database if it doesn't match
+// the spec. The status of the given NdbMySQLDatabase is updated in-place
+// and has to be persisted by the caller.
+func (dc *NdbMySQLDatabaseController) reconcileDatabase(ctx context.Context, nmd *v1.NdbMySQLDatabase) syncResult {
+	nc, err := dc.ndbsLister.NdbClusters(nmd.Namespace).Get(nmd.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			nmd.Status.Message = fmt.Sprintf("NdbCluster %q does not exist",
+				getNamespacedName2(nmd.Namespace, nmd.Spec.ClusterName))
+			return finishProcessing()
+		}
+		return errorWhileProcessing(err)
+	}
+
+	if nc.IsPaused() {
+		// The MySQL Cluster must not be touched until
+		// the reconciliation of the NdbCluster is resumed.
+		if message := getNdbClusterPausedMessage(nc); nmd.Status.Message != message {
+			nmd.Status.Message = message
+			dc.recorder.Eventf(nmd, nil, corev1.EventTypeNormal, ReasonNdbClusterPaused, ActionNone, message)
+		}
+		return finishProcessing()
+	}
+
+	db, err := connectToMySQLServer(ctx, dc.kubernetesClient, dc.statefulSetLister, nc)
+	if err != nil {
+		nmd.Status.Message = fmt.Sprintf("Failed to connect to the MySQL Servers of NdbCluster %q : %s",
+			getNamespacedName(nc), err)
+		return finishProcessing()
+	}
+	defer db.Close()
+
+	databaseName := nmd.Spec.DatabaseName
+	updated, err := mysqlclient.ReconcileDatabase(ctx, db, nmd)
+	if err != nil {
+		nmd.Status.Message = fmt.Sprintf("Failed to sync database %q : %s", databaseName, err)
+		dc.recorder.Eventf(nmd, nil, corev1.EventTypeWarning,
+			ReasonMySQLDatabaseSyncFailed, ActionReconcile, nmd.Status.Message)
+		return errorWhileProcessing(err)
+	}
+
+	now := metav1.Now()
+	if updated {
+		if nmd.Generation == nmd.Status.ProcessedGeneration {
+			// Spec has already been applied, but the database
+			// had been dropped or altered outside the operator.
+			klog.Infof("Database %q, declared by NdbMySQLDatabase %q, was modified outside the operator",
+				databaseName, getNamespacedName(nmd))
+			dc.recorder.Eventf(nmd, nil, corev1.EventTypeWarning,
+				ReasonMySQLDatabaseDrifted, ActionReconcile, MessageMySQLDatabaseDrifted, databaseName)
+			nmd.Status.LastDriftTime = &now
+		} else {
+			klog.Infof("Database %q was synced with NdbMySQLDatabase %q", databaseName, getNamespacedName(nmd))
+			dc.recorder.Eventf(nmd, nil, corev1.EventTypeNormal,
+				ReasonMySQLDatabaseSynced, ActionReconcile, MessageMySQLDatabaseSynced, databaseName)
+		}
+	}
+
+	if updated || nmd.Generation != nmd.Status.ProcessedGeneration {
+		nmd.Status.ProcessedGeneration = nmd.Generation
+		nmd.Status.LastAppliedTime = &now
+	}
+	nmd.Status.Message = ""
+	return continueProcessing()
+}
+
+// updateDatabaseStatus updates the status of the given NdbMySQLDatabase resource
+func (dc *NdbMySQLDatabaseController) updateDatabaseStatus(ctx context.Context, nmd *v1.NdbMySQLDatabase) error {
+	status := nmd.Status.DeepCopy()
+	nmdInterface := dc.ndbClient.MysqlV1().NdbMySQLDatabases(nmd.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		status.DeepCopyInto(&nmd.Status)
+		updatedNmd, updateErr := nmdInterface.UpdateStatus(ctx, nmd, metav1.UpdateOptions{})
+		if updateErr == nil {
+			updatedNmd.DeepCopyInto(nmd)
+			return nil
+		}
+
+		// Get the latest version of the NdbMySQLDatabase to retry the update
+		latestNmd, getErr := nmdInterface.Get(ctx, nmd.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get NdbMySQLDatabase resource during status update %q: %v",
+				getNamespacedName(nmd), getErr)
+			return getErr
+		}
+		latestNmd.DeepCopyInto(nmd)
+
+		return updateErr
+	})
+
+	if err != nil {
+		klog.Errorf("Failed to update the status of NdbMySQLDatabase resource %q : %v",
+			getNamespacedName(nmd), err)
+	}
+
+	return err
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	informers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+// newTestNdbMySQLDatabase returns an NdbMySQLDatabase that
+// declares the database 'shop' in the given NdbCluster
+func newTestNdbMySQLDatabase(nc *v1.NdbCluster) *v1.NdbMySQLDatabase {
+	return &v1.NdbMySQLDatabase{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "shop",
+			Namespace:  nc.Namespace,
+			Generation: 1,
+		},
+		Spec: v1.NdbMySQLDatabaseSpec{
+			ClusterName:  nc.Name,
+			DatabaseName: "shop",
+			CharacterSet: "utf8mb4",
+		},
+	}
+}
+
+// newFakeSchemataHandler returns a fake MySQL Server handler that reports
+// the given character set of the database, or no database if it is empty.
+func newFakeSchemataHandler(charset string) testutils.FakeSQLHandler {
+	return func(query string, _ []driver.Value) ([]string, [][]driver.Value, error) {
+		if !strings.Contains(query, ".SCHEMATA") {
+			return nil, nil, nil
+		}
+
+		columns := []string{"DEFAULT_CHARACTER_SET_NAME", "DEFAULT_COLLATION_NAME"}
+		if charset == "" {
+			return columns, nil, nil
+		}
+		return columns, [][]driver.Value{{charset, charset + "_general_ci"}}, nil
+	}
+}
+
+// syncTestNdbMySQLDatabase runs the syncHandler of a new NdbMySQLDatabaseController,
+// whose informer caches are synced with the given objects, for the given
+// NdbMySQLDatabase and returns the controller, the requeue duration and the
+// updated NdbMySQLDatabase.
+func syncTestNdbMySQLDatabase(t *testing.T, nmd *v1.NdbMySQLDatabase, k8sObjects []runtime.Object,
+	ndbObjects ...runtime.Object) (*NdbMySQLDatabaseController, time.Duration, *v1.NdbMySQLDatabase) {
+	t.Helper()
+
+	k8sClient := k8sfake.NewSimpleClientset(k8sObjects...)
+	ndbClient := fake.NewSimpleClientset(append(ndbObjects, nmd)...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	ndbIf := informers.NewSharedInformerFactory(ndbClient, 0)
+	dc := NewNdbMySQLDatabaseController(k8sClient, ndbClient, k8sIf, ndbIf)
+	dc.recorder = events.NewFakeRecorder(10)
+	t.Cleanup(dc.workqueue.ShutDown)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	k8sIf.Start(stopCh)
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, dc.informerSyncedMethods...); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	requeueAfter, sr := dc.syncHandler(context.TODO(), getNamespacedName(nmd))
+	if sr.getError() != nil {
+		t.Fatalf("Unexpected error during sync : %s", sr.getError())
+	}
+
+	nmd, err := ndbClient.MysqlV1().NdbMySQLDatabases(nmd.Namespace).Get(context.TODO(), nmd.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbMySQLDatabase : %s", err)
+	}
+	return dc, requeueAfter, nmd
+}
+
+func TestNdbMySQLDatabaseCreation(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	mysqld := newFakeMySQLServer(t, "", newFakeSchemataHandler(""))
+
+	dc, requeueAfter, nmd := syncTestNdbMySQLDatabase(t,
+		newTestNdbMySQLDatabase(nc), newTestMySQLServerObjects(nc), nc)
+
+	expected := []string{"CREATE DATABASE IF NOT EXISTS `shop` CHARACTER SET utf8mb4"}
+	if statements := executedStatements(mysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if requeueAfter != mysqlObjectDriftCheckInterval {
+		t.Errorf("Expected the database to be checked for drifts after %s but got %s",
+			mysqlObjectDriftCheckInterval, requeueAfter)
+	}
+	if nmd.Status.ProcessedGeneration != nmd.Generation || nmd.Status.LastAppliedTime == nil ||
+		nmd.Status.LastDriftTime != nil || nmd.Status.Message != "" {
+		t.Errorf("Expected the status to record the created database but got %+v", nmd.Status)
+	}
+	if !hasRecordedEvent(dc.recorder, ReasonMySQLDatabaseSynced) {
+		t.Errorf("Expected a %s event", ReasonMySQLDatabaseSynced)
+	}
+}
+
+func TestNdbMySQLDatabaseDrift(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nmd := newTestNdbMySQLDatabase(nc)
+	nmd.Status.ProcessedGeneration = nmd.Generation
+
+	// The database is up-to-date
+	mysqld := newFakeMySQLServer(t, "", newFakeSchemataHandler("utf8mb4"))
+	dc, _, syncedNmd := syncTestNdbMySQLDatabase(t, nmd, newTestMySQLServerObjects(nc), nc)
+	if statements := executedStatements(mysqld); len(statements) != 0 {
+		t.Errorf("Expected the up-to-date database not to be altered but got %q", statements)
+	}
+	if syncedNmd.Status.LastDriftTime != nil || hasRecordedEvent(dc.recorder, ReasonMySQLDatabaseDrifted) {
+		t.Error("Expected no drift to be reported for the up-to-date database")
+	}
+
+	// The database was altered outside the operator
+	mysqld = newFakeMySQLServer(t, "", newFakeSchemataHandler("latin1"))
+	dc, _, syncedNmd = syncTestNdbMySQLDatabase(t, nmd, newTestMySQLServerObjects(nc), nc)
+	expected := []string{"ALTER DATABASE `shop` CHARACTER SET utf8mb4"}
+	if statements := executedStatements(mysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if syncedNmd.Status.LastDriftTime == nil {
+		t.Error("Expected the drift to be recorded in the status")
+	}
+	if !hasRecordedEvent(dc.recorder, ReasonMySQLDatabaseDrifted) {
+		t.Errorf("Expected a %s event", ReasonMySQLDatabaseDrifted)
+	}
+}
+
+func TestNdbMySQLDatabaseOfMissingNdbCluster(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	mysqld := newFakeMySQLServer(t, "", newFakeSchemataHandler(""))
+
+	_, requeueAfter, nmd := syncTestNdbMySQLDatabase(t, newTestNdbMySQLDatabase(nc), nil)
+
+	if queries := mysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries without the NdbCluster but got %q", queries)
+	}
+	if requeueAfter != mysqlObjectRetryInterval {
+		t.Errorf("Expected the database to be retried after %s but got %s", mysqlObjectRetryInterval, requeueAfter)
+	}
+	if !strings.Contains(nmd.Status.Message, "does not exist") {
+		t.Errorf("Expected the status to report the missing NdbCluster but got %q", nmd.Status.Message)
+	}
+}
+
+func TestNdbMySQLDatabaseOfPausedNdbCluster(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nc.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+	mysqld := newFakeMySQLServer(t, "", newFakeSchemataHandler(""))
+
+	// The database is not created while the reconciliation is paused
+	dc, requeueAfter, nmd := syncTestNdbMySQLDatabase(t,
+		newTestNdbMySQLDatabase(nc), newTestMySQLServerObjects(nc), nc)
+	if queries := mysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is paused but got %q", queries)
+	}
+	if requeueAfter != mysqlObjectRetryInterval {
+		t.Errorf("Expected the database to be retried after %s but got %s", mysqlObjectRetryInterval, requeueAfter)
+	}
+	if nmd.Status.ProcessedGeneration != 0 || !strings.Contains(nmd.Status.Message, "has been paused") {
+		t.Errorf("Expected the status to report the paused NdbCluster but got %+v", nmd.Status)
+	}
+	if !hasRecordedEvent(dc.recorder, ReasonNdbClusterPaused) {
+		t.Errorf("Expected a %s event", ReasonNdbClusterPaused)
+	}
+
+	// The database is created once the reconciliation is resumed
+	nc.Annotations = nil
+	_, _, nmd = syncTestNdbMySQLDatabase(t, nmd, newTestMySQLServerObjects(nc), nc)
+	expected := []string{"CREATE DATABASE IF NOT EXISTS `shop` CHARACTER SET utf8mb4"}
+	if statements := executedStatements(mysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if nmd.Status.ProcessedGeneration != nmd.Generation || nmd.Status.Message != "" {
+		t.Errorf("Expected the status to record the created database but got %+v", nmd.Status)
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+)
+
+const (
+	// mysqlObjectDriftCheckInterval is the interval at which the MySQL
+	// users and databases are checked for changes made outside the operator
+	mysqlObjectDriftCheckInterval = 5 * time.Minute
+	// mysqlObjectRetryInterval is the interval after which the reconciliation
+	// of a MySQL user or database is retried when the NdbCluster, or one of
+	// the resources it depends on, is not available yet
+	mysqlObjectRetryInterval = 30 * time.Second
+)
+
+// NdbMySQLUserController is the controller implementation for the
+// NdbMySQLUser resources. It creates and updates the MySQL users, via
+// the NDB Operator user, in the MySQL Servers of the NdbClusters and
+// periodically reapplies the grants that are modified outside the operator.
+type NdbMySQLUserController struct {
+	kubernetesClient kubernetes.Interface
+	ndbClient        ndbclientset.Interface
+
+	// NdbCluster and NdbMySQLUser Listers
+	ndbsLister          ndblisters.NdbClusterLister
+	ndbMySQLUsersLister ndblisters.NdbMySQLUserLister
+
+	// K8s Listers
+	statefulSetLister appslisters.StatefulSetLister
+	secretLister      corelisters.SecretLister
+
+	// Slice of InformerSynced methods for all the informers used by the controller
+	informerSyncedMethods []cache.InformerSynced
+
+	// A rate limited workqueue for queueing the NdbMySQLUser
+	// resource keys on receiving an event or when a drift check is due.
+	workqueue workqueue.RateLimitingInterface
+	// An event recorder for recording Event resources to the Kubernetes API.
+	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
+}
+
+// NewNdbMySQLUserController returns a new NdbMySQLUser controller
+func NewNdbMySQLUserController(
+	kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface,
+	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *NdbMySQLUserController {
+
+	// Register for all the required informers
+	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbMySQLUserInformer := ndbSharedIndexInformer.Mysql().V1().NdbMySQLUsers()
+	statefulSetInformer := k8sSharedIndexInformer.Apps().V1().StatefulSets()
+	secretInformer := k8sSharedIndexInformer.Core().V1().Secrets()
+
+	controller := &NdbMySQLUserController{
+		kubernetesClient:    kubernetesClient,
+		ndbClient:           ndbClient,
+		ndbsLister:          ndbClusterInformer.Lister(),
+		ndbMySQLUsersLister: ndbMySQLUserInformer.Lister(),
+		statefulSetLister:   statefulSetInformer.Lister(),
+		secretLister:        secretInformer.Lister(),
+		informerSyncedMethods: []cache.InformerSynced{
+			ndbClusterInformer.Informer().HasSynced,
+			ndbMySQLUserInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+			secretInformer.Informer().HasSynced,
+		},
+		workqueue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "NdbMySQLUsers"),
+		recorder: newEventRecorder(kubernetesClient),
+	}
+
+	// Set up event handler for NdbMySQLUser resource changes
+	ndbMySQLUserInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key := getNamespacedName(obj.(*v1.NdbMySQLUser))
+			klog.Infof("New NdbMySQLUser resource added : %q, queueing it for reconciliation", key)
+			controller.workqueue.Add(key)
+		},
+
+		UpdateFunc: func(old, new interface{}) {
+			oldNmu := old.(*v1.NdbMySQLUser)
+			newNmu := new.(*v1.NdbMySQLUser)
+			if oldNmu.ResourceVersion == newNmu.ResourceVersion {
+				// Periodic resync - the drift checks are done by the requeues
+				return
+			}
+			controller.workqueue.Add(getNamespacedName(newNmu))
+		},
+	})
+
+	// Set up event handler for the Secrets to update
+	// the passwords of the users when they change.
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret := old.(*corev1.Secret)
+			newSecret := new.(*corev1.Secret)
+			if oldSecret.ResourceVersion == newSecret.ResourceVersion {
+				return
+			}
+
+			nmuList, err := controller.ndbMySQLUsersLister.NdbMySQLUsers(newSecret.Namespace).List(labels.Everything())
+			if err != nil {
+				klog.Errorf("Failed to list the NdbMySQLUsers in namespace %q : %s", newSecret.Namespace, err)
+				return
+			}
+			for _, nmu := range nmuList {
+				if nmu.Spec.PasswordSecretName == newSecret.Name {
+					controller.workqueue.Add(getNamespacedName(nmu))
+				}
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run starts the workers that process the NdbMySQLUser resources. It
+// will block until ctx is cancelled, at which point it will shut down
+// the workqueue and wait for the workers to finish processing their
+// current work items.
+func (uc *NdbMySQLUserController) Run(ctx context.Context, threadiness int) error {
+	defer utilruntime.HandleCrash()
+	defer uc.workqueue.ShutDown()
+
+	klog.Info("Starting NdbMySQLUser controller")
+
+	// Wait for the caches to be synced before starting workers
+	if ok := cache.WaitForNamedCacheSync(
+		controllerName, ctx.Done(), uc.informerSyncedMethods...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	// Launch worker go routines to process NdbMySQLUser resources
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for uc.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	klog.Info("Started NdbMySQLUser workers")
+	<-ctx.Done()
+	klog.Info("Shutting down NdbMySQLUser workers")
+
+	return nil
+}
+
+// processNextWorkItem reads a single work item off the
+// workqueue and processes it, by calling the syncHandler.
+func (uc *NdbMySQLUserController) processNextWorkItem(ctx context.Context) bool {
+	item, shutdown := uc.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer uc.workqueue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		uc.workqueue.Forget(item)
+		klog.Error(debug.InternalError(fmt.Errorf("expected string in workqueue but got %#v", item)))
+		return true
+	}
+
+	uc.activeSyncs.start(key)
+	requeueAfter, sr := uc.syncHandler(uc.activeSyncs.withHeartbeats(ctx, key), key)
+	uc.activeSyncs.finish(key)
+	if err := sr.getError(); err != nil {
+		klog.Infof("Reconciliation of NdbMySQLUser resource %q failed : %s", key, err)
+		klog.Info("Re-queuing resource to retry reconciliation after error")
+		uc.workqueue.AddRateLimited(key)
+		return true
+	}
+
+	uc.workqueue.Forget(item)
+	if requeueAfter > 0 {
+		uc.workqueue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+// syncHandler creates or updates the MySQL user as per the NdbMySQLUser
+// spec, or drops it if the NdbMySQLUser is being deleted. It returns the
+// duration after which the user has to be processed again.
+func (uc *NdbMySQLUserController) syncHandler(ctx context.Context, key string) (time.Duration, syncResult) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return 0, finishProcessing()
+	}
+
+	nmuOrg, err := uc.ndbMySQLUsersLister.NdbMySQLUsers(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("NdbMySQLUser resource %q does not exist anymore", key)
+			return 0, finishProcessing()
+		}
+		klog.Errorf("Failed to retrieve NdbMySQLUser resource %q", key)
+		return 0, errorWhileProcessing(err)
+	}
+
+	// Work on a copy to avoid mutating the cache
+	nmu := nmuOrg.DeepCopy()
+
+	if nmu.DeletionTimestamp != nil {
+		if nc, err := uc.ndbsLister.NdbClusters(namespace).Get(nmu.Spec.ClusterName); err == nil && nc.IsPaused() {
+			// The user is dropped once the reconciliation
+			// of the NdbCluster is resumed.
+			return mysqlObjectRetryInterval, finishProcessing()
+		}
+		return 0, uc.dropUser(ctx, nmu)
+	}
+
+	if nmu.HasReservedUserName() {
+		// The reserved users are rejected by the webhook, but the
+		// NdbMySQLUser might have been created before it was enabled.
+		// Such users are never modified and are not retried either
+		// until the spec is changed.
+		nmu.Status.Message = fmt.Sprintf(MessageMySQLUserRejected, nmu.Spec.UserName)
+		if !reflect.DeepEqual(nmuOrg.Status, nmu.Status) {
+			uc.recorder.Eventf(nmu, nil, corev1.EventTypeWarning,
+				ReasonMySQLUserRejected, ActionReconcile, nmu.Status.Message)
+			if err = uc.updateUserStatus(ctx, nmu); err != nil {
+				return 0, errorWhileProcessing(err)
+			}
+		}
+		return 0, finishProcessing()
+	}
+
+	if !hasFinalizer(nmu, constants.MySQLUserFinalizer) {
+		nmu.Finalizers = append(nmu.Finalizers, constants.MySQLUserFinalizer)
+		updatedNmu, err := uc.ndbClient.MysqlV1().NdbMySQLUsers(namespace).Update(ctx, nmu, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to add finalizer to NdbMySQLUser %q : %s", key, err)
+			return 0, errorWhileProcessing(err)
+		}
+		nmu = updatedNmu
+	}
+
+	sr := uc.reconcileUser(ctx, nmu)
+
+	if !reflect.DeepEqual(nmuOrg.Status, nmu.Status) {
+		if err = uc.updateUserStatus(ctx, nmu); err != nil {
+			return 0, errorWhileProcessing(err)
+		}
+	}
+
+	if sr.stopSync() {
+		if err = sr.getError(); err != nil {
+			return 0, sr
+		}
+		// The user could not be reconciled as a resource it depends on
+		// is not available yet. Retry after some time.
+		return mysqlObjectRetryInterval, finishProcessing()
+	}
+
+	// Check the user for drifts periodically
+	return mysqlObjectDriftCheckInterval, finishProcessing()
+}
+
+// reconcileUser creates or updates the MySQL user if the spec or the
+// password has changed, or if the grants of the user have been modified
+// outside the operator. The status of the given NdbMySQLUser is updated
+// in-place and has to be persisted by the caller.
+func (uc *NdbMySQLUserController) reconcileUser(ctx context.Context, nmu *v1.NdbMySQLUser) syncResult {
+	nc, err := uc.ndbsLister.NdbClusters(nmu.Namespace).Get(nmu.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			nmu.Status.Message = fmt.Sprintf("NdbCluster %q does not exist",
+				getNamespacedName2(nmu.Namespace, nmu.Spec.ClusterName))
+			return finishProcessing()
+		}
+		return errorWhileProcessing(err)
+	}
+
+	if nc.IsPaused() {
+		// The MySQL Cluster must not be touched until
+		// the reconciliation of the NdbCluster is resumed.
+		if message := getNdbClusterPausedMessage(nc); nmu.Status.Message != message {
+			nmu.Status.Message = message
+			uc.recorder.Eventf(nmu, nil, corev1.EventTypeNormal, ReasonNdbClusterPaused, ActionNone, message)
+		}
+		return finishProcessing()
+	}
+
+	passwordSecret, err := uc.secretLister.Secrets(nmu.Namespace).Get(nmu.Spec.PasswordSecretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			nmu.Status.Message = fmt.Sprintf("Secret %q does not exist",
+				getNamespacedName2(nmu.Namespace, nmu.Spec.PasswordSecretName))
+			return finishProcessing()
+		}
+		return errorWhileProcessing(err)
+	}
+
+	password, exists := passwordSecret.Data[corev1.BasicAuthPasswordKey]
+	if !exists || len(password) == 0 {
+		nmu.Status.Message = fmt.Sprintf("Secret %q has no %q key",
+			getNamespacedName(passwordSecret), corev1.BasicAuthPasswordKey)
+		return finishProcessing()
+	}
+
+	db, err := connectToMySQLServer(ctx, uc.kubernetesClient, uc.statefulSetLister, nc)
+	if err != nil {
+		nmu.Status.Message = fmt.Sprintf("Failed to connect to the MySQL Servers of NdbCluster %q : %s",
+			getNamespacedName(nc), err)
+		return finishProcessing()
+	}
+	defer db.Close()
+
+	userName, host := nmu.Spec.UserName, nmu.GetHost()
+	grants, err := mysqlclient.GetUserGrants(ctx, db, userName, host)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	specChanged := nmu.Generation != nmu.Status.ProcessedGeneration
+	passwordChanged := passwordSecret.ResourceVersion != nmu.Status.PasswordSecretVersion
+	drifted := !specChanged && !reflect.DeepEqual(grants, nmu.Status.AppliedGrants)
+	if !specChanged && !passwordChanged && !drifted {
+		// User is in sync with the spec
+		nmu.Status.Message = ""
+		return continueProcessing()
+	}
+
+	account := fmt.Sprintf("'%s'@'%s'", userName, host)
+	if drifted {
+		klog.Infof("Grants of MySQL user %s, declared by NdbMySQLUser %q, were modified outside the operator",
+			account, getNamespacedName(nmu))
+		uc.recorder.Eventf(nmu, nil, corev1.EventTypeWarning,
+			ReasonMySQLUserDrifted, ActionReconcile, MessageMySQLUserDrifted, account)
+		now := metav1.Now()
+		nmu.Status.LastDriftTime = &now
+	}
+
+	if err = mysqlclient.ReconcileUser(ctx, db, nmu, string(password), passwordChanged); err != nil {
+		nmu.Status.Message = fmt.Sprintf("Failed to sync MySQL user %s : %s", account, err)
+		uc.recorder.Eventf(nmu, nil, corev1.EventTypeWarning,
+			ReasonMySQLUserSyncFailed, ActionReconcile, nmu.Status.Message)
+		return errorWhileProcessing(err)
+	}
+
+	// Record the grants of the user to detect any future drift
+	if grants, err = mysqlclient.GetUserGrants(ctx, db, userName, host); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	now := metav1.Now()
+	nmu.Status.ProcessedGeneration = nmu.Generation
+	nmu.Status.PasswordSecretVersion = passwordSecret.ResourceVersion
+	nmu.Status.AppliedGrants = grants
+	nmu.Status.LastAppliedTime = &now
+	nmu.Status.Message = ""
+
+	klog.Infof("MySQL user %s was synced with NdbMySQLUser %q", account, getNamespacedName(nmu))
+	uc.recorder.Eventf(nmu, nil, corev1.EventTypeNormal,
+		ReasonMySQLUserSynced, ActionReconcile, MessageMySQLUserSynced, account)
+	return continueProcessing()
+}
+
+// dropUser drops the MySQL user declared by the given NdbMySQLUser
+// and then removes the finalizer to let the resource be deleted.
+func (uc *NdbMySQLUserController) dropUser(ctx context.Context, nmu *v1.NdbMySQLUser) syncResult {
+	if !hasFinalizer(nmu, constants.MySQLUserFinalizer) {
+		// Nothing to do
+		return finishProcessing()
+	}
+
+	nc, err := uc.ndbsLister.NdbClusters(nmu.Namespace).Get(nmu.Spec.ClusterName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errorWhileProcessing(err)
+	}
+
+	if nc != nil && nc.DeletionTimestamp == nil && !nmu.HasReservedUserName() {
+		db, err := connectToMySQLServer(ctx, uc.kubernetesClient, uc.statefulSetLister, nc)
+		if err != nil && !errors.Is(err, errNoMySQLServers) {
+			// Retry until the user is dropped
+			return errorWhileProcessing(err)
+		}
+
+		if db != nil {
+			err = mysqlclient.DropUserIfExists(ctx, db, nmu.Spec.UserName, nmu.GetHost())
+			db.Close()
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+	// else the NdbCluster, and hence the user, does not exist anymore
+	// or the user is a reserved one that must never be dropped
+
+	nmu.Finalizers = removeFinalizer(nmu.Finalizers, constants.MySQLUserFinalizer)
+	_, err = uc.ndbClient.MysqlV1().NdbMySQLUsers(nmu.Namespace).Update(ctx, nmu, metav1.UpdateOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to remove finalizer from NdbMySQLUser %q : %s", getNamespacedName(nmu), err)
+		return errorWhileProcessing(err)
+	}
+
+	return finishProcessing()
+}
+
+// updateUserStatus updates the status of the given NdbMySQLUser resource
+func (uc *NdbMySQLUserController) updateUserStatus(ctx context.Context, nmu *v1.NdbMySQLUser) error {
+	status := nmu.Status.DeepCopy()
+	nmuInterface := uc.ndbClient.MysqlV1().NdbMySQLUsers(nmu.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		status.DeepCopyInto(&nmu.Status)
+		updatedNmu, updateErr := nmuInterface.UpdateStatus(ctx, nmu, metav1.UpdateOptions{})
+		if updateErr == nil {
+			updatedNmu.DeepCopyInto(nmu)
+			return nil
+		}
+
+		// Get the latest version of the NdbMySQLUser to retry the update
+		latestNmu, getErr := nmuInterface.Get(ctx, nmu.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get NdbMySQLUser resource during status update %q: %v",
+				getNamespacedName(nmu), getErr)
+			return getErr
+		}
+		latestNmu.DeepCopyInto(nmu)
+
+		return updateErr
+	})
+
+	if err != nil {
+		klog.Errorf("Failed to update the status of NdbMySQLUser resource %q : %v",
+			getNamespacedName(nmu), err)
+	}
+
+	return err
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	informers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// newTestMySQLServerObjects returns the MySQL Server StatefulSet, with
+// one ready replica, and the NDB Operator password Secret of the given
+// NdbCluster, required by the controllers to connect to the MySQL Server.
+func newTestMySQLServerObjects(nc *v1.NdbCluster) []runtime.Object {
+	mysqldSfset := newTestStatefulSet(1)
+	mysqldSfset.ObjectMeta = metav1.ObjectMeta{
+		Name:      nc.GetWorkloadName(constants.NdbNodeTypeMySQLD),
+		Namespace: nc.Namespace,
+	}
+
+	operatorPasswordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMySQLNDBOperatorPasswordSecretName(nc),
+			Namespace: nc.Namespace,
+		},
+		Data: map[string][]byte{corev1.BasicAuthPasswordKey: []byte("password")},
+	}
+
+	return []runtime.Object{mysqldSfset, operatorPasswordSecret}
+}
+
+// hasRecordedEvent returns true if an Event with the given
+// reason was recorded by the given FakeRecorder
+func hasRecordedEvent(recorder events.EventRecorder, reason string) bool {
+	for {
+		select {
+		case event := <-recorder.(*events.FakeRecorder).Events:
+			if strings.Contains(event, " "+reason+" ") {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+// executedStatements returns the statements, other than
+// the queries, received by the given fake MySQL Server
+func executedStatements(mysqld *testutils.FakeSQLServer) []string {
+	var statements []string
+	for _, query := range mysqld.Queries() {
+		if !strings.HasPrefix(query, "SELECT ") && !strings.HasPrefix(query, "SHOW ") {
+			statements = append(statements, query)
+		}
+	}
+	return statements
+}
+
+// fakeMySQLUser is a MySQL user in a fake MySQL Server. The
+// grants reported by SHOW GRANTS are set by the tests.
+type fakeMySQLUser struct {
+	lock   sync.Mutex
+	exists bool
+	grants []string
+}
+
+func (fmu *fakeMySQLUser) handler(query string, _ []driver.Value) ([]string, [][]driver.Value, error) {
+	fmu.lock.Lock()
+	defer fmu.lock.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT COUNT(*) FROM mysql.user"):
+		count := 0
+		if fmu.exists {
+			count = 1
+		}
+		return []string{"COUNT(*)"}, [][]driver.Value{{int64(count)}}, nil
+
+	case strings.HasPrefix(query, "SHOW GRANTS FOR"):
+		var rows [][]driver.Value
+		for _, grant := range fmu.grants {
+			rows = append(rows, []driver.Value{grant})
+		}
+		return []string{"Grants"}, rows, nil
+
+	case strings.HasPrefix(query, "CREATE USER"):
+		fmu.exists = true
+	}
+
+	return nil, nil, nil
+}
+
+// newTestNdbMySQLUser returns an NdbMySQLUser, along with its password
+// Secret, that declares the MySQL user 'app' in the given NdbCluster
+func newTestNdbMySQLUser(nc *v1.NdbCluster) (*v1.NdbMySQLUser, *corev1.Secret) {
+	nmu := &v1.NdbMySQLUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app-user",
+			Namespace:  nc.Namespace,
+			Generation: 1,
+		},
+		Spec: v1.NdbMySQLUserSpec{
+			ClusterName:        nc.Name,
+			UserName:           "app",
+			PasswordSecretName: "app-password",
+			Grants: []v1.NdbMySQLUserGrant{
+				{Privileges: []string{"SELECT", "INSERT"}, Database: "shop"},
+			},
+		},
+	}
+
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nmu.Spec.PasswordSecretName,
+			Namespace:       nc.Namespace,
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{corev1.BasicAuthPasswordKey: []byte("app-password")},
+	}
+
+	return nmu, passwordSecret
+}
+
+// syncTestNdbMySQLUser runs the syncHandler of a new NdbMySQLUserController,
+// whose informer caches are synced with the given objects, for the given
+// NdbMySQLUser and returns the controller and the updated NdbMySQLUser.
+func syncTestNdbMySQLUser(t *testing.T, nmu *v1.NdbMySQLUser,
+	k8sObjects []runtime.Object, ndbObjects ...runtime.Object) (*NdbMySQLUserController, *v1.NdbMySQLUser) {
+	t.Helper()
+
+	k8sClient := k8sfake.NewSimpleClientset(k8sObjects...)
+	ndbClient := fake.NewSimpleClientset(append(ndbObjects, nmu)...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	ndbIf := informers.NewSharedInformerFactory(ndbClient, 0)
+	uc := NewNdbMySQLUserController(k8sClient, ndbClient, k8sIf, ndbIf)
+	uc.recorder = events.NewFakeRecorder(10)
+	t.Cleanup(uc.workqueue.ShutDown)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	k8sIf.Start(stopCh)
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, uc.informerSyncedMethods...); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	if _, sr := uc.syncHandler(context.TODO(), getNamespacedName(nmu)); sr.getError() != nil {
+		t.Fatalf("Unexpected error during sync : %s", sr.getError())
+	}
+
+	nmu, err := ndbClient.MysqlV1().NdbMySQLUsers(nmu.Namespace).Get(context.TODO(), nmu.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbMySQLUser : %s", err)
+	}
+	return uc, nmu
+}
+
+func TestNdbMySQLUserCreation(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nmu, passwordSecret := newTestNdbMySQLUser(nc)
+	user := &fakeMySQLUser{}
+	mysqld := newFakeMySQLServer(t, "", user.handler)
+
+	uc, nmu := syncTestNdbMySQLUser(t, nmu,
+		append(newTestMySQLServerObjects(nc), passwordSecret), nc)
+
+	expected := []string{
+		"CREATE USER 'app'@'%' IDENTIFIED BY 'app-password'",
+		"ALTER USER 'app'@'%' WITH MAX_QUERIES_PER_HOUR 0 MAX_UPDATES_PER_HOUR 0 " +
+			"MAX_CONNECTIONS_PER_HOUR 0 MAX_USER_CONNECTIONS 0",
+		"GRANT NDB_STORED_USER ON *.* TO 'app'@'%'",
+		"GRANT INSERT, SELECT ON `shop`.* TO 'app'@'%'",
+	}
+	if statements := executedStatements(mysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+
+	if !hasFinalizer(nmu, constants.MySQLUserFinalizer) {
+		t.Errorf("Expected the %q finalizer to be added", constants.MySQLUserFinalizer)
+	}
+	if nmu.Status.ProcessedGeneration != nmu.Generation ||
+		nmu.Status.PasswordSecretVersion != passwordSecret.ResourceVersion ||
+		nmu.Status.LastAppliedTime == nil || nmu.Status.Message != "" {
+		t.Errorf("Expected the status to record the synced user but got %+v", nmu.Status)
+	}
+	if !hasRecordedEvent(uc.recorder, ReasonMySQLUserSynced) {
+		t.Errorf("Expected a %s event", ReasonMySQLUserSynced)
+	}
+}
+
+func TestNdbMySQLUserDrift(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nmu, passwordSecret := newTestNdbMySQLUser(nc)
+	appliedGrants := []string{
+		"GRANT USAGE ON *.* TO `app`@`%`",
+		"GRANT NDB_STORED_USER ON *.* TO `app`@`%`",
+		"GRANT SELECT, INSERT ON `shop`.* TO `app`@`%`",
+	}
+	nmu.Finalizers = []string{constants.MySQLUserFinalizer}
+	nmu.Status = v1.NdbMySQLUserStatus{
+		ProcessedGeneration:   nmu.Generation,
+		PasswordSecretVersion: passwordSecret.ResourceVersion,
+		AppliedGrants:         appliedGrants,
+	}
+
+	// A privilege was granted outside the operator
+	user := &fakeMySQLUser{
+		exists: true,
+		grants: append(appliedGrants[:2:2], "GRANT SELECT, INSERT, DELETE ON `shop`.* TO `app`@`%`"),
+	}
+	mysqld := newFakeMySQLServer(t, "", user.handler)
+
+	uc, nmu := syncTestNdbMySQLUser(t, nmu,
+		append(newTestMySQLServerObjects(nc), passwordSecret), nc)
+
+	// Only the extra privilege should be revoked and the password left untouched
+	expected := []string{
+		"ALTER USER 'app'@'%' WITH MAX_QUERIES_PER_HOUR 0 MAX_UPDATES_PER_HOUR 0 " +
+			"MAX_CONNECTIONS_PER_HOUR 0 MAX_USER_CONNECTIONS 0",
+		"REVOKE DELETE ON `shop`.* FROM 'app'@'%'",
+	}
+	if statements := executedStatements(mysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+
+	if nmu.Status.LastDriftTime == nil {
+		t.Error("Expected the drift to be recorded in the status")
+	}
+	if !hasRecordedEvent(uc.recorder, ReasonMySQLUserDrifted) {
+		t.Errorf("Expected a %s event", ReasonMySQLUserDrifted)
+	}
+}
+
+func TestNdbMySQLUserWithReservedName(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nmu, passwordSecret := newTestNdbMySQLUser(nc)
+	nmu.Spec.UserName = "root"
+	user := &fakeMySQLUser{exists: true}
+	mysqld := newFakeMySQLServer(t, "", user.handler)
+	k8sObjects := append(newTestMySQLServerObjects(nc), passwordSecret)
+
+	uc, nmu := syncTestNdbMySQLUser(t, nmu, k8sObjects, nc)
+
+	if queries := mysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected the reserved user not to be modified but the MySQL Server received %q", queries)
+	}
+	if hasFinalizer(nmu, constants.MySQLUserFinalizer) {
+		t.Errorf("Expected the %q finalizer not to be added to a reserved user", constants.MySQLUserFinalizer)
+	}
+	if !strings.Contains(nmu.Status.Message, "reserved") {
+		t.Errorf("Expected the status to report the reserved user but got %q", nmu.Status.Message)
+	}
+	if !hasRecordedEvent(uc.recorder, ReasonMySQLUserRejected) {
+		t.Errorf("Expected a %s event", ReasonMySQLUserRejected)
+	}
+
+	// The deletion of an NdbMySQLUser of a reserved user,
+	// created before the webhook, should not drop the user.
+	now := metav1.Now()
+	nmu.DeletionTimestamp = &now
+	nmu.Finalizers = []string{constants.MySQLUserFinalizer}
+	_, nmu = syncTestNdbMySQLUser(t, nmu, k8sObjects, nc)
+
+	if queries := executedStatements(mysqld); len(queries) != 0 {
+		t.Errorf("Expected the reserved user not to be dropped but the MySQL Server received %q", queries)
+	}
+	if hasFinalizer(nmu, constants.MySQLUserFinalizer) {
+		t.Errorf("Expected the %q finalizer to be removed", constants.MySQLUserFinalizer)
+	}
+}
+
+func TestNdbMySQLUserOfPausedNdbCluster(t *testing.T) {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nc.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+	nmu, passwordSecret := newTestNdbMySQLUser(nc)
+	user := &fakeMySQLUser{}
+	mysqld := newFakeMySQLServer(t, "", user.handler)
+	k8sObjects := append(newTestMySQLServerObjects(nc), passwordSecret)
+
+	// The user is not created while the reconciliation is paused
+	uc, syncedNmu := syncTestNdbMySQLUser(t, nmu, k8sObjects, nc)
+	if queries := mysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is paused but got %q", queries)
+	}
+	if syncedNmu.Status.ProcessedGeneration != 0 || !strings.Contains(syncedNmu.Status.Message, "has been paused") {
+		t.Errorf("Expected the status to report the paused NdbCluster but got %+v", syncedNmu.Status)
+	}
+	if !hasRecordedEvent(uc.recorder, ReasonNdbClusterPaused) {
+		t.Errorf("Expected a %s event", ReasonNdbClusterPaused)
+	}
+
+	// Nor is it dropped
+	deletedNmu := syncedNmu.DeepCopy()
+	now := metav1.Now()
+	deletedNmu.DeletionTimestamp = &now
+	_, deletedNmu = syncTestNdbMySQLUser(t, deletedNmu, k8sObjects, nc)
+	if queries := mysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is paused but got %q", queries)
+	}
+	if !hasFinalizer(deletedNmu, constants.MySQLUserFinalizer) {
+		t.Errorf("Expected the %q finalizer to be retained until the user is dropped",
+			constants.MySQLUserFinalizer)
+	}
+
+	// The user is created once the reconciliation is resumed
+	nc.Annotations = nil
+	_, syncedNmu = syncTestNdbMySQLUser(t, syncedNmu, k8sObjects, nc)
+	if statements := executedStatements(mysqld); len(statements) == 0 ||
+		!strings.HasPrefix(statements[0], "CREATE USER 'app'@'%'") {
+		t.Errorf("Expected the user to be created after the resume but got %q", statements)
+	}
+	if syncedNmu.Status.ProcessedGeneration != syncedNmu.Generation || syncedNmu.Status.Message != "" {
+		t.Errorf("Expected the status to record the created user but got %+v", syncedNmu.Status)
+	}
+}
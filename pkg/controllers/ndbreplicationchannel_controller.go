@@ -0,0 +1,682 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+
+	"github.com/mysql/ndb-operator/config/debug"
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	ndbclientset "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	ndbinformers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	ndblisters "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/mysqlclient"
+	"github.com/mysql/ndb-operator/pkg/resources"
+)
+
+// replicationChannelCheckInterval is the interval at which
+// the health and the lag of the replication channels are checked
+const replicationChannelCheckInterval = 30 * time.Second
+
+// NdbReplicationChannelController is the controller implementation for the
+// NdbReplicationChannel resources. It configures and starts the replication
+// channels in the binlog MySQL Servers of the replica NdbClusters, via the
+// NDB Operator user, and periodically reports their health and lag. A
+// channel that has been stopped, e.g. by a restart of the replica MySQL
+// Server, is restarted from the last epoch applied to the replica.
+type NdbReplicationChannelController struct {
+	kubernetesClient kubernetes.Interface
+	ndbClient        ndbclientset.Interface
+
+	// NdbCluster and NdbReplicationChannel Listers
+	ndbsLister                   ndblisters.NdbClusterLister
+	ndbReplicationChannelsLister ndblisters.NdbReplicationChannelLister
+
+	// K8s Listers
+	statefulSetLister appslisters.StatefulSetLister
+	secretLister      corelisters.SecretLister
+
+	// Slice of InformerSynced methods for all the informers used by the controller
+	informerSyncedMethods []cache.InformerSynced
+
+	// A rate limited workqueue for queueing the NdbReplicationChannel
+	// resource keys on receiving an event or when a health check is due.
+	workqueue workqueue.RateLimitingInterface
+	// An event recorder for recording Event resources to the Kubernetes API.
+	recorder events.EventRecorder
+
+	// The reconciliations being run by the workers, tracked to detect stuck workers
+	activeSyncs activeSyncs
+}
+
+// NewNdbReplicationChannelController returns a new NdbReplicationChannel controller
+func NewNdbReplicationChannelController(
+	kubernetesClient kubernetes.Interface,
+	ndbClient ndbclientset.Interface,
+	k8sSharedIndexInformer kubeinformers.SharedInformerFactory,
+	ndbSharedIndexInformer ndbinformers.SharedInformerFactory) *NdbReplicationChannelController {
+
+	// Register for all the required informers
+	ndbClusterInformer := ndbSharedIndexInformer.Mysql().V1().NdbClusters()
+	ndbReplicationChannelInformer := ndbSharedIndexInformer.Mysql().V1().NdbReplicationChannels()
+	statefulSetInformer := k8sSharedIndexInformer.Apps().V1().StatefulSets()
+	secretInformer := k8sSharedIndexInformer.Core().V1().Secrets()
+
+	controller := &NdbReplicationChannelController{
+		kubernetesClient:             kubernetesClient,
+		ndbClient:                    ndbClient,
+		ndbsLister:                   ndbClusterInformer.Lister(),
+		ndbReplicationChannelsLister: ndbReplicationChannelInformer.Lister(),
+		statefulSetLister:            statefulSetInformer.Lister(),
+		secretLister:                 secretInformer.Lister(),
+		informerSyncedMethods: []cache.InformerSynced{
+			ndbClusterInformer.Informer().HasSynced,
+			ndbReplicationChannelInformer.Informer().HasSynced,
+			statefulSetInformer.Informer().HasSynced,
+			secretInformer.Informer().HasSynced,
+		},
+		workqueue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "NdbReplicationChannels"),
+		recorder: newEventRecorder(kubernetesClient),
+	}
+
+	// Set up event handler for NdbReplicationChannel resource changes
+	ndbReplicationChannelInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			key := getNamespacedName(obj.(*v1.NdbReplicationChannel))
+			klog.Infof("New NdbReplicationChannel resource added : %q, queueing it for reconciliation", key)
+			controller.workqueue.Add(key)
+		},
+
+		UpdateFunc: func(old, new interface{}) {
+			oldNrc := old.(*v1.NdbReplicationChannel)
+			newNrc := new.(*v1.NdbReplicationChannel)
+			if oldNrc.Generation == newNrc.Generation && newNrc.DeletionTimestamp == nil {
+				// Periodic resync or a status update - the
+				// health checks are done by the requeues
+				return
+			}
+			controller.workqueue.Add(getNamespacedName(newNrc))
+		},
+	})
+
+	// Set up event handler for the Secrets to restart the channels
+	// when the password of the replication user changes.
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret := old.(*corev1.Secret)
+			newSecret := new.(*corev1.Secret)
+			if oldSecret.ResourceVersion == newSecret.ResourceVersion {
+				return
+			}
+
+			nrcList, err := controller.ndbReplicationChannelsLister.NdbReplicationChannels(
+				newSecret.Namespace).List(labels.Everything())
+			if err != nil {
+				klog.Errorf("Failed to list the NdbReplicationChannels in namespace %q : %s", newSecret.Namespace, err)
+				return
+			}
+			for _, nrc := range nrcList {
+				if nrc.Spec.Source.UserSecretName == newSecret.Name {
+					controller.workqueue.Add(getNamespacedName(nrc))
+				}
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run starts the workers that process the NdbReplicationChannel resources.
+// It will block until ctx is cancelled, at which point it will shut down
+// the workqueue and wait for the workers to finish processing their
+// current work items.
+func (rc *NdbReplicationChannelController) Run(ctx context.Context, threadiness int) error {
+	defer utilruntime.HandleCrash()
+	defer rc.workqueue.ShutDown()
+
+	klog.Info("Starting NdbReplicationChannel controller")
+
+	// Wait for the caches to be synced before starting workers
+	if ok := cache.WaitForNamedCacheSync(
+		controllerName, ctx.Done(), rc.informerSyncedMethods...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	// Launch worker go routines to process NdbReplicationChannel resources
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for rc.processNextWorkItem(ctx) {
+			}
+		}()
+	}
+
+	klog.Info("Started NdbReplicationChannel workers")
+	<-ctx.Done()
+	klog.Info("Shutting down NdbReplicationChannel workers")
+
+	return nil
+}
+
+// processNextWorkItem reads a single work item off the
+// workqueue and processes it, by calling the syncHandler.
+func (rc *NdbReplicationChannelController) processNextWorkItem(ctx context.Context) bool {
+	item, shutdown := rc.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer rc.workqueue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		rc.workqueue.Forget(item)
+		klog.Error(debug.InternalError(fmt.Errorf("expected string in workqueue but got %#v", item)))
+		return true
+	}
+
+	rc.activeSyncs.start(key)
+	requeueAfter, sr := rc.syncHandler(rc.activeSyncs.withHeartbeats(ctx, key), key)
+	rc.activeSyncs.finish(key)
+	if err := sr.getError(); err != nil {
+		klog.Infof("Reconciliation of NdbReplicationChannel resource %q failed : %s", key, err)
+		klog.Info("Re-queuing resource to retry reconciliation after error")
+		rc.workqueue.AddRateLimited(key)
+		return true
+	}
+
+	rc.workqueue.Forget(item)
+	if requeueAfter > 0 {
+		rc.workqueue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+// syncHandler starts the replication channel as per the NdbReplicationChannel
+// spec, or removes it if the NdbReplicationChannel is being deleted, and
+// records its health. It returns the duration after which the channel has
+// to be processed again.
+func (rc *NdbReplicationChannelController) syncHandler(ctx context.Context, key string) (time.Duration, syncResult) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return 0, finishProcessing()
+	}
+
+	nrcOrg, err := rc.ndbReplicationChannelsLister.NdbReplicationChannels(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.Infof("NdbReplicationChannel resource %q does not exist anymore", key)
+			return 0, finishProcessing()
+		}
+		klog.Errorf("Failed to retrieve NdbReplicationChannel resource %q", key)
+		return 0, errorWhileProcessing(err)
+	}
+
+	// Work on a copy to avoid mutating the cache
+	nrc := nrcOrg.DeepCopy()
+
+	if nrc.DeletionTimestamp != nil {
+		if nc, err := rc.ndbsLister.NdbClusters(namespace).Get(nrc.Spec.ClusterName); err == nil && nc.IsPaused() {
+			// The channel is removed once the reconciliation
+			// of the NdbCluster is resumed.
+			return replicationChannelCheckInterval, finishProcessing()
+		}
+		return 0, rc.removeChannel(ctx, nrc)
+	}
+
+	if !hasFinalizer(nrc, constants.ReplicationChannelFinalizer) {
+		nrc.Finalizers = append(nrc.Finalizers, constants.ReplicationChannelFinalizer)
+		updatedNrc, err := rc.ndbClient.MysqlV1().NdbReplicationChannels(namespace).Update(
+			ctx, nrc, metav1.UpdateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to add finalizer to NdbReplicationChannel %q : %s", key, err)
+			return 0, errorWhileProcessing(err)
+		}
+		nrc = updatedNrc
+	}
+
+	sr := rc.reconcileChannel(ctx, nrc)
+
+	if !reflect.DeepEqual(nrcOrg.Status, nrc.Status) {
+		if err = rc.updateChannelStatus(ctx, nrc); err != nil {
+			return 0, errorWhileProcessing(err)
+		}
+	}
+
+	if err = sr.getError(); err != nil {
+		return 0, sr
+	}
+
+	// Check the health of the channel periodically. This also retries
+	// the setup of a channel whose NdbCluster is not available yet.
+	return replicationChannelCheckInterval, finishProcessing()
+}
+
+// getBinlogMySQLServerStatefulSet returns the StatefulSet of the binlog
+// MySQL Servers of the given NdbCluster. errNoMySQLServers is returned
+// if the StatefulSet doesn't exist or has been scaled down to 0.
+func (rc *NdbReplicationChannelController) getBinlogMySQLServerStatefulSet(
+	nc *v1.NdbCluster) (*appsv1.StatefulSet, error) {
+	sfset, err := rc.statefulSetLister.StatefulSets(nc.Namespace).Get(nc.GetBinlogMySQLServerWorkloadName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errNoMySQLServers
+		}
+		return nil, err
+	}
+
+	if sfset.Spec.Replicas == nil || *sfset.Spec.Replicas == 0 {
+		return nil, errNoMySQLServers
+	}
+
+	return sfset, nil
+}
+
+// connectToReplicaMySQLServer opens a connection, as the NDB Operator user,
+// to the first binlog MySQL Server of the given NdbCluster, which runs the
+// replication channel.
+func (rc *NdbReplicationChannelController) connectToReplicaMySQLServer(
+	ctx context.Context, nc *v1.NdbCluster, sfset *appsv1.StatefulSet) (*sql.DB, error) {
+	if sfset.Status.ReadyReplicas == 0 {
+		return nil, fmt.Errorf("binlog MySQL Servers of NdbCluster %q are not ready yet", getNamespacedName(nc))
+	}
+
+	operatorPassword, err := NewMySQLUserPasswordSecretInterface(rc.kubernetesClient).ExtractPassword(
+		ctx, nc.Namespace, resources.GetMySQLNDBOperatorPasswordSecretName(nc))
+	if err != nil {
+		return nil, err
+	}
+
+	return mysqlclient.ConnectToStatefulSet(sfset, mysqlclient.DbMySQL, operatorPassword)
+}
+
+// reconcileChannel starts the replication channel if it has not been
+// configured yet, if it has been stopped, or if the spec or the password
+// of the replication user has changed, and then records its health. The
+// status of the given NdbReplicationChannel is updated in-place and has
+// to be persisted by the caller.
+func (rc *NdbReplicationChannelController) reconcileChannel(
+	ctx context.Context, nrc *v1.NdbReplicationChannel) syncResult {
+	nc, err := rc.ndbsLister.NdbClusters(nrc.Namespace).Get(nrc.Spec.ClusterName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			nrc.Status.Message = fmt.Sprintf("NdbCluster %q does not exist",
+				getNamespacedName2(nrc.Namespace, nrc.Spec.ClusterName))
+			return finishProcessing()
+		}
+		return errorWhileProcessing(err)
+	}
+
+	if nc.Spec.Replication == nil {
+		nrc.Status.Message = fmt.Sprintf(
+			"NdbCluster %q does not have spec.replication set", getNamespacedName(nc))
+		return finishProcessing()
+	}
+
+	if nc.IsPaused() {
+		// The MySQL Cluster must not be touched until the reconciliation
+		// of the NdbCluster is resumed. The channel keeps running.
+		if message := getNdbClusterPausedMessage(nc); nrc.Status.Message != message {
+			nrc.Status.Message = message
+			rc.recorder.Eventf(nrc, nil, corev1.EventTypeNormal, ReasonNdbClusterPaused, ActionNone, message)
+		}
+		return finishProcessing()
+	}
+
+	if nc.Spec.Suspended {
+		// The binlog MySQL Servers are not running. The channel is
+		// resumed, after the last applied epoch, once they are back.
+		nrc.Status.Message = fmt.Sprintf("NdbCluster %q is suspended", getNamespacedName(nc))
+		nrc.Status.ReceiverState, nrc.Status.ApplierState = "", ""
+		nrc.Status.Healthy, nrc.Status.LagSeconds = false, nil
+		return finishProcessing()
+	}
+
+	userSecret, err := rc.secretLister.Secrets(nrc.Namespace).Get(nrc.Spec.Source.UserSecretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			nrc.Status.Message = fmt.Sprintf("Secret %q does not exist",
+				getNamespacedName2(nrc.Namespace, nrc.Spec.Source.UserSecretName))
+			return finishProcessing()
+		}
+		return errorWhileProcessing(err)
+	}
+
+	password, exists := userSecret.Data[corev1.BasicAuthPasswordKey]
+	if !exists || len(password) == 0 {
+		nrc.Status.Message = fmt.Sprintf("Secret %q has no %q key",
+			getNamespacedName(userSecret), corev1.BasicAuthPasswordKey)
+		return finishProcessing()
+	}
+
+	sfset, err := rc.getBinlogMySQLServerStatefulSet(nc)
+	if err == nil {
+		var db *sql.DB
+		if db, err = rc.connectToReplicaMySQLServer(ctx, nc, sfset); err == nil {
+			defer db.Close()
+			nrc.Status.ReplicaMySQLServer = sfset.Name + "-0"
+			return rc.reconcileChannelInReplica(ctx, nrc, db, userSecret)
+		}
+	}
+
+	nrc.Status.Message = fmt.Sprintf("Failed to connect to the binlog MySQL Servers of NdbCluster %q : %s",
+		getNamespacedName(nc), err)
+	return finishProcessing()
+}
+
+// reconcileChannelInReplica (re)starts the replication channel in the
+// replica MySQL Server, if required, and records its health.
+func (rc *NdbReplicationChannelController) reconcileChannelInReplica(
+	ctx context.Context, nrc *v1.NdbReplicationChannel, db *sql.DB, userSecret *corev1.Secret) syncResult {
+
+	replicaStatus, err := mysqlclient.GetReplicaStatus(ctx, db)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	sourceHost := nrc.GetSourceHost()
+	startRequired := replicaStatus == nil || replicaStatus.IsStopped() ||
+		nrc.Generation != nrc.Status.ProcessedGeneration ||
+		userSecret.ResourceVersion != nrc.Status.UserSecretVersion
+
+	if !startRequired {
+		// Switch to another binlog MySQL Server of the
+		// source if the current one has failed.
+		if failoverHost := rc.getFailoverHost(
+			ctx, nrc, replicaStatus, sourceHost, userSecret); failoverHost != "" {
+			now := metav1.Now()
+			nrc.Status.LastFailoverTime = &now
+			sourceHost = failoverHost
+			startRequired = true
+		}
+	}
+
+	if startRequired {
+		// The channel has not been configured yet, has been stopped by a
+		// restart, has to be reconfigured with the new spec, or has to be
+		// switched to another binlog MySQL Server of the source.
+		if sr := rc.startChannel(ctx, nrc, db, userSecret, sourceHost); sr.stopSync() {
+			return sr
+		}
+
+		if replicaStatus, err = mysqlclient.GetReplicaStatus(ctx, db); err != nil {
+			return errorWhileProcessing(err)
+		}
+	}
+
+	lastAppliedEpoch, err := mysqlclient.GetLastAppliedEpoch(ctx, db)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	rc.updateChannelHealth(nrc, replicaStatus, lastAppliedEpoch)
+	nrc.Status.Message = ""
+	return continueProcessing()
+}
+
+// getFailoverHost returns the binlog MySQL Server of the source to which
+// the channel has to be switched, or an empty string if the channel can
+// continue replicating from the current one. The channel is switched when
+// the current binlog MySQL Server is not reachable or when its binary log
+// has a gap, i.e. the applier has stopped due to a lost events incident.
+func (rc *NdbReplicationChannelController) getFailoverHost(ctx context.Context, nrc *v1.NdbReplicationChannel,
+	replicaStatus *mysqlclient.ReplicaStatus, sourceHost string, userSecret *corev1.Secret) string {
+	sourceHosts := nrc.GetSourceHosts()
+	if len(sourceHosts) == 1 {
+		// No other binlog MySQL Server to switch to
+		return ""
+	}
+
+	password := string(userSecret.Data[corev1.BasicAuthPasswordKey])
+	var reason string
+	if replicaStatus.HasLostEvents() {
+		reason = replicaStatus.LastApplierError
+	} else if replicaStatus.ReceiverState != "Yes" && replicaStatus.LastReceiverError != "" {
+		// The receiver is unable to read from the source.
+		// Verify that the source is indeed not reachable.
+		if err := pingReplicationSource(sourceHost, nrc.GetSourcePort(), password); err == nil {
+			return ""
+		}
+		reason = replicaStatus.LastReceiverError
+	} else {
+		// Channel is running fine
+		return ""
+	}
+
+	// Try the other binlog MySQL Servers in order, starting from the one after the current
+	var start int
+	for i, host := range sourceHosts {
+		if host == sourceHost {
+			start = i
+		}
+	}
+	for i := 1; i < len(sourceHosts); i++ {
+		host := sourceHosts[(start+i)%len(sourceHosts)]
+		if err := pingReplicationSource(host, nrc.GetSourcePort(), password); err != nil {
+			klog.Infof("Binlog MySQL Server %s is not reachable : %s", nrc.GetSourceAddress(host), err)
+			continue
+		}
+
+		klog.Infof("NdbReplicationChannel %q is switching from %s to %s : %s", getNamespacedName(nrc),
+			nrc.GetSourceAddress(sourceHost), nrc.GetSourceAddress(host), reason)
+		rc.recorder.Eventf(nrc, nil, corev1.EventTypeWarning, ReasonReplicationChannelFailover, ActionReplicate,
+			MessageReplicationChannelFailover, nrc.GetSourceAddress(sourceHost), nrc.GetSourceAddress(host), reason)
+		return host
+	}
+
+	klog.Infof("NdbReplicationChannel %q has no reachable binlog MySQL Server to switch to",
+		getNamespacedName(nrc))
+	return ""
+}
+
+// pingReplicationSource verifies that the given binlog MySQL
+// Server of the source is reachable as the replication user
+func pingReplicationSource(host string, port int32, password string) error {
+	sourceDb, err := mysqlclient.ConnectAsReplicationUser(host, port, password)
+	if err != nil {
+		return err
+	}
+	return sourceDb.Close()
+}
+
+// startChannel starts the replication channel from the position, in the
+// binary log of the given binlog MySQL Server of the source, that follows
+// the last epoch applied to the replica. As the epochs are the same in the
+// binary logs of all the binlog MySQL Servers of the source, this is also
+// used to switch the channel between them. The replication starts from
+// the beginning of the binary log of the source if no epoch has been
+// applied to the replica yet.
+func (rc *NdbReplicationChannelController) startChannel(ctx context.Context,
+	nrc *v1.NdbReplicationChannel, db *sql.DB, userSecret *corev1.Secret, sourceHost string) syncResult {
+
+	sourceAddress := nrc.GetSourceAddress(sourceHost)
+	password := string(userSecret.Data[corev1.BasicAuthPasswordKey])
+
+	epoch, err := mysqlclient.GetLastAppliedEpoch(ctx, db)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	var logFile string
+	var logPos int64
+	if epoch != 0 {
+		// Find the position of the epoch in the binary log of the source
+		sourceDb, err := mysqlclient.ConnectAsReplicationUser(sourceHost, nrc.GetSourcePort(), password)
+		if err == nil {
+			logFile, logPos, err = mysqlclient.GetBinlogPositionAfterEpoch(ctx, sourceDb, epoch)
+			sourceDb.Close()
+		}
+
+		if err != nil {
+			nrc.Status.Message = fmt.Sprintf(
+				"Failed to find the position of epoch %d in the binary log of %s : %s", epoch, sourceAddress, err)
+			rc.recorder.Eventf(nrc, nil, corev1.EventTypeWarning,
+				ReasonReplicationChannelSetupFailed, ActionReplicate, nrc.Status.Message)
+			// Retry after some time
+			return finishProcessing()
+		}
+	}
+
+	if err = mysqlclient.StartReplication(
+		ctx, db, sourceHost, nrc.GetSourcePort(), password, logFile, logPos); err != nil {
+		nrc.Status.Message = fmt.Sprintf("Failed to start replicating from %s : %s", sourceAddress, err)
+		rc.recorder.Eventf(nrc, nil, corev1.EventTypeWarning,
+			ReasonReplicationChannelSetupFailed, ActionReplicate, nrc.Status.Message)
+		return errorWhileProcessing(err)
+	}
+
+	nrc.Status.ProcessedGeneration = nrc.Generation
+	nrc.Status.UserSecretVersion = userSecret.ResourceVersion
+	nrc.Status.SourceHost = sourceHost
+	nrc.Status.SourceLogFile = logFile
+	nrc.Status.SourceLogPosition = logPos
+
+	klog.Infof("NdbReplicationChannel %q started replicating from %s after epoch %d",
+		getNamespacedName(nrc), sourceAddress, epoch)
+	rc.recorder.Eventf(nrc, nil, corev1.EventTypeNormal,
+		ReasonReplicationChannelStarted, ActionReplicate, MessageReplicationChannelStarted, sourceAddress, epoch)
+	return continueProcessing()
+}
+
+// updateChannelHealth records the health and the lag of the replication
+// channel in the status and raises an Event when the health changes.
+func (rc *NdbReplicationChannelController) updateChannelHealth(
+	nrc *v1.NdbReplicationChannel, replicaStatus *mysqlclient.ReplicaStatus, lastAppliedEpoch int64) {
+	status := &nrc.Status
+	wasHealthy, previousError := status.Healthy, status.LastError
+
+	now := metav1.Now()
+	status.LastCheckTime = &now
+	status.LastAppliedEpoch = lastAppliedEpoch
+	if replicaStatus == nil {
+		// Replication was reset outside the operator
+		status.ReceiverState, status.ApplierState = "", ""
+		status.LagSeconds, status.LastError = nil, ""
+		status.Healthy = false
+		return
+	}
+
+	status.ReceiverState = replicaStatus.ReceiverState
+	status.ApplierState = replicaStatus.ApplierState
+	status.LagSeconds = replicaStatus.SecondsBehindSource
+	status.LastError = replicaStatus.LastError()
+	status.Healthy = replicaStatus.IsRunning() && status.LastError == ""
+
+	sourceAddress := nrc.GetSourceAddress(nrc.GetSourceHost())
+	if wasHealthy && !status.Healthy {
+		reason := status.LastError
+		if reason == "" {
+			reason = fmt.Sprintf("receiver thread running : %s, applier thread running : %s",
+				status.ReceiverState, status.ApplierState)
+		}
+		klog.Infof("NdbReplicationChannel %q is unhealthy : %s", getNamespacedName(nrc), reason)
+		rc.recorder.Eventf(nrc, nil, corev1.EventTypeWarning,
+			ReasonReplicationChannelUnhealthy, ActionReplicate, MessageReplicationChannelUnhealthy, sourceAddress, reason)
+	} else if !wasHealthy && status.Healthy && previousError != "" {
+		klog.Infof("NdbReplicationChannel %q is healthy again", getNamespacedName(nrc))
+		rc.recorder.Eventf(nrc, nil, corev1.EventTypeNormal,
+			ReasonReplicationChannelHealthy, ActionReplicate, MessageReplicationChannelHealthy, sourceAddress)
+	}
+}
+
+// removeChannel stops and removes the replication channel declared by the
+// given NdbReplicationChannel and then removes the finalizer to let the
+// resource be deleted.
+func (rc *NdbReplicationChannelController) removeChannel(ctx context.Context, nrc *v1.NdbReplicationChannel) syncResult {
+	if !hasFinalizer(nrc, constants.ReplicationChannelFinalizer) {
+		// Nothing to do
+		return finishProcessing()
+	}
+
+	nc, err := rc.ndbsLister.NdbClusters(nrc.Namespace).Get(nrc.Spec.ClusterName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errorWhileProcessing(err)
+	}
+
+	if nc != nil && nc.DeletionTimestamp == nil && nc.Spec.Replication != nil {
+		sfset, err := rc.getBinlogMySQLServerStatefulSet(nc)
+		if err != nil && !errors.Is(err, errNoMySQLServers) {
+			return errorWhileProcessing(err)
+		}
+
+		if sfset != nil {
+			db, err := rc.connectToReplicaMySQLServer(ctx, nc, sfset)
+			if err != nil {
+				// Retry until the channel is removed
+				return errorWhileProcessing(err)
+			}
+
+			err = mysqlclient.RemoveReplication(ctx, db)
+			db.Close()
+			if err != nil {
+				return errorWhileProcessing(err)
+			}
+		}
+	}
+	// else the NdbCluster, and hence the channel, does not exist anymore
+
+	nrc.Finalizers = removeFinalizer(nrc.Finalizers, constants.ReplicationChannelFinalizer)
+	_, err = rc.ndbClient.MysqlV1().NdbReplicationChannels(nrc.Namespace).Update(ctx, nrc, metav1.UpdateOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to remove finalizer from NdbReplicationChannel %q : %s", getNamespacedName(nrc), err)
+		return errorWhileProcessing(err)
+	}
+
+	return finishProcessing()
+}
+
+// updateChannelStatus updates the status of the given NdbReplicationChannel resource
+func (rc *NdbReplicationChannelController) updateChannelStatus(ctx context.Context, nrc *v1.NdbReplicationChannel) error {
+	status := nrc.Status.DeepCopy()
+	nrcInterface := rc.ndbClient.MysqlV1().NdbReplicationChannels(nrc.Namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		status.DeepCopyInto(&nrc.Status)
+		updatedNrc, updateErr := nrcInterface.UpdateStatus(ctx, nrc, metav1.UpdateOptions{})
+		if updateErr == nil {
+			updatedNrc.DeepCopyInto(nrc)
+			return nil
+		}
+
+		// Get the latest version of the NdbReplicationChannel to retry the update
+		latestNrc, getErr := nrcInterface.Get(ctx, nrc.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.Errorf("Failed to get NdbReplicationChannel resource during status update %q: %v",
+				getNamespacedName(nrc), getErr)
+			return getErr
+		}
+		latestNrc.DeepCopyInto(nrc)
+
+		return updateErr
+	})
+
+	if err != nil {
+		klog.Errorf("Failed to update the status of NdbReplicationChannel resource %q : %v",
+			getNamespacedName(nrc), err)
+	}
+
+	return err
+}
@@ -0,0 +1,578 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/fake"
+	informers "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+// fakeReplica is the replication channel of a fake replica MySQL Server.
+// The channel is configured and started via the statements executed by
+// the controller and its state is updated by the tests.
+type fakeReplica struct {
+	lock              sync.Mutex
+	configured        bool
+	sourceHost        string
+	receiverState     string
+	applierState      string
+	lagSeconds        *int64
+	lastReceiverError string
+	lastApplierError  string
+	lastApplierErrno  int64
+	lastAppliedEpoch  int64
+}
+
+func (fr *fakeReplica) handler(query string, _ []driver.Value) ([]string, [][]driver.Value, error) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	switch {
+	case query == "SHOW REPLICA STATUS":
+		columns := []string{"Source_Host", "Source_Port", "Replica_IO_Running", "Replica_SQL_Running",
+			"Last_IO_Error", "Last_SQL_Errno", "Last_SQL_Error", "Seconds_Behind_Source"}
+		if !fr.configured {
+			return columns, nil, nil
+		}
+		var lag driver.Value
+		if fr.lagSeconds != nil {
+			lag = *fr.lagSeconds
+		}
+		return columns, [][]driver.Value{{fr.sourceHost, int64(3306), fr.receiverState, fr.applierState,
+			fr.lastReceiverError, fr.lastApplierErrno, fr.lastApplierError, lag}}, nil
+
+	case strings.HasPrefix(query, "SELECT COALESCE(MAX(epoch), 0)"):
+		return []string{"epoch"}, [][]driver.Value{{fr.lastAppliedEpoch}}, nil
+
+	case strings.HasPrefix(query, "CHANGE REPLICATION SOURCE TO"):
+		fr.configured = true
+		fr.sourceHost = strings.Split(query, "'")[1]
+
+	case query == "START REPLICA":
+		fr.receiverState, fr.applierState = "Yes", "Yes"
+		fr.lastReceiverError, fr.lastApplierError, fr.lastApplierErrno = "", "", 0
+		lag := int64(0)
+		fr.lagSeconds = &lag
+
+	case query == "STOP REPLICA":
+		fr.receiverState, fr.applierState = "No", "No"
+		fr.lagSeconds = nil
+	}
+
+	return nil, nil, nil
+}
+
+// newFakeSourceHandler returns a fake binlog MySQL Server handler
+// that reports the given position as the one following the epoch.
+func newFakeSourceHandler(epoch int64, logFile string, logPos int64) testutils.FakeSQLHandler {
+	return func(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+		if !strings.Contains(query, ".ndb_binlog_index WHERE epoch = ?") {
+			return nil, nil, nil
+		}
+
+		columns := []string{"next_file", "next_position"}
+		if len(args) != 1 || args[0] != epoch {
+			return columns, nil, nil
+		}
+		return columns, [][]driver.Value{{logFile, logPos}}, nil
+	}
+}
+
+// newTestNdbReplicationChannel returns an NdbReplicationChannel, along
+// with the Secret of the replication user, that replicates into the
+// given NdbCluster from the given binlog MySQL Servers of the source.
+func newTestNdbReplicationChannel(
+	nc *v1.NdbCluster, sourceHost string, failoverHosts ...string) (*v1.NdbReplicationChannel, *corev1.Secret) {
+	nrc := &v1.NdbReplicationChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "from-source",
+			Namespace:  nc.Namespace,
+			Generation: 1,
+		},
+		Spec: v1.NdbReplicationChannelSpec{
+			ClusterName: nc.Name,
+			Source: v1.NdbReplicationSource{
+				Host:           sourceHost,
+				FailoverHosts:  failoverHosts,
+				UserSecretName: "source-replication-user",
+			},
+		},
+	}
+
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            nrc.Spec.Source.UserSecretName,
+			Namespace:       nc.Namespace,
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{corev1.BasicAuthPasswordKey: []byte("replication-password")},
+	}
+
+	return nrc, userSecret
+}
+
+// newTestReplicaNdb returns an NdbCluster, with replication enabled,
+// into which the test NdbReplicationChannels replicate
+func newTestReplicaNdb() *v1.NdbCluster {
+	nc := testutils.NewTestNdb(metav1.NamespaceDefault, "example-ndb", 2)
+	nc.Spec.Replication = &v1.NdbClusterReplicationSpec{ServerIdOffset: 1000}
+	return nc
+}
+
+// markChannelStarted records in the status of the given NdbReplicationChannel
+// that the channel has been started from the given binlog MySQL Server
+func markChannelStarted(nrc *v1.NdbReplicationChannel, userSecret *corev1.Secret, sourceHost string) {
+	nrc.Status.ProcessedGeneration = nrc.Generation
+	nrc.Status.UserSecretVersion = userSecret.ResourceVersion
+	nrc.Status.SourceHost = sourceHost
+	nrc.Status.Healthy = true
+}
+
+// syncTestNdbReplicationChannel runs the syncHandler of a new
+// NdbReplicationChannelController, whose informer caches are synced with
+// the given objects, for the given NdbReplicationChannel and returns the
+// controller, the requeue duration and the updated NdbReplicationChannel.
+func syncTestNdbReplicationChannel(t *testing.T, nrc *v1.NdbReplicationChannel, k8sObjects []runtime.Object,
+	ndbObjects ...runtime.Object) (*NdbReplicationChannelController, time.Duration, *v1.NdbReplicationChannel) {
+	t.Helper()
+
+	k8sClient := k8sfake.NewSimpleClientset(k8sObjects...)
+	ndbClient := fake.NewSimpleClientset(append(ndbObjects, nrc)...)
+	k8sIf := kubeinformers.NewSharedInformerFactory(k8sClient, 0)
+	ndbIf := informers.NewSharedInformerFactory(ndbClient, 0)
+	rc := NewNdbReplicationChannelController(k8sClient, ndbClient, k8sIf, ndbIf)
+	rc.recorder = events.NewFakeRecorder(10)
+	t.Cleanup(rc.workqueue.ShutDown)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	k8sIf.Start(stopCh)
+	ndbIf.Start(stopCh)
+	if ok := cache.WaitForNamedCacheSync(controllerName, stopCh, rc.informerSyncedMethods...); !ok {
+		t.Fatal("failed to wait for caches to sync")
+	}
+
+	requeueAfter, sr := rc.syncHandler(context.TODO(), getNamespacedName(nrc))
+	if sr.getError() != nil {
+		t.Fatalf("Unexpected error during sync : %s", sr.getError())
+	}
+
+	nrc, err := ndbClient.MysqlV1().NdbReplicationChannels(nrc.Namespace).Get(
+		context.TODO(), nrc.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to retrieve NdbReplicationChannel : %s", err)
+	}
+	return rc, requeueAfter, nrc
+}
+
+func TestNdbReplicationChannelInitialStart(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nrc, userSecret := newTestNdbReplicationChannel(nc, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// No epoch has been applied to the replica - the
+	// channel starts from the beginning of the binary log
+	replica := &fakeReplica{}
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	sourceMysqld := newFakeMySQLServer(t, "source-0.example.com", newFakeSourceHandler(0, "", 0))
+
+	rc, requeueAfter, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	expected := []string{
+		"STOP REPLICA",
+		"CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source-0.example.com', SOURCE_PORT = 3306, " +
+			"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'replication-password', SOURCE_SSL = 1",
+		"START REPLICA",
+	}
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if queries := sourceMysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected the source not to be queried without an applied epoch but got %q", queries)
+	}
+	if requeueAfter != replicationChannelCheckInterval {
+		t.Errorf("Expected the channel to be checked after %s but got %s",
+			replicationChannelCheckInterval, requeueAfter)
+	}
+	status := syncedNrc.Status
+	if status.ProcessedGeneration != nrc.Generation || status.UserSecretVersion != userSecret.ResourceVersion ||
+		status.SourceHost != "source-0.example.com" || status.SourceLogFile != "" || status.SourceLogPosition != 0 ||
+		status.ReplicaMySQLServer != nc.GetBinlogMySQLServerWorkloadName()+"-0" || status.Message != "" {
+		t.Errorf("Expected the status to record the started channel but got %+v", status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelStarted) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelStarted)
+	}
+
+	// The replica has been restored from a backup of the source
+	// and the channel has to start after the restored epoch.
+	replica = &fakeReplica{lastAppliedEpoch: 4294967298}
+	replicaMysqld = newFakeMySQLServer(t, "", replica.handler)
+	sourceMysqld = newFakeMySQLServer(t, "source-0.example.com",
+		newFakeSourceHandler(4294967298, "binlog.000002", 1204))
+
+	_, _, syncedNrc = syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	expected[1] += ", SOURCE_LOG_FILE = 'binlog.000002', SOURCE_LOG_POS = 1204"
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if queries := sourceMysqld.Queries(); len(queries) != 1 || !strings.Contains(queries[0], "ndb_binlog_index") {
+		t.Errorf("Expected the position of the epoch to be read from the source but got %q", queries)
+	}
+	status = syncedNrc.Status
+	if status.SourceLogFile != "binlog.000002" || status.SourceLogPosition != 1204 ||
+		status.LastAppliedEpoch != 4294967298 {
+		t.Errorf("Expected the status to record the position after the applied epoch but got %+v", status)
+	}
+}
+
+func TestNdbReplicationChannelRestart(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nrc, userSecret := newTestNdbReplicationChannel(nc, "source-0.example.com")
+	markChannelStarted(nrc, userSecret, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// The running channel is left untouched
+	lag := int64(0)
+	replica := &fakeReplica{
+		configured:       true,
+		sourceHost:       "source-0.example.com",
+		receiverState:    "Yes",
+		applierState:     "Yes",
+		lagSeconds:       &lag,
+		lastAppliedEpoch: 8589934593,
+	}
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	sourceMysqld := newFakeMySQLServer(t, "source-0.example.com",
+		newFakeSourceHandler(8589934593, "binlog.000003", 868))
+	rc, _, _ := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	if statements := executedStatements(replicaMysqld); len(statements) != 0 {
+		t.Errorf("Expected the running channel not to be restarted but got %q", statements)
+	}
+	if queries := sourceMysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected the source not to be queried for the running channel but got %q", queries)
+	}
+	if hasRecordedEvent(rc.recorder, ReasonReplicationChannelStarted) {
+		t.Error("Expected the running channel not to be restarted")
+	}
+
+	// The replica MySQL Server has restarted and the channel
+	// is stopped - it is resumed after the last applied epoch
+	replica.lock.Lock()
+	replica.receiverState, replica.applierState, replica.lagSeconds = "No", "No", nil
+	replica.lock.Unlock()
+	replicaMysqld = newFakeMySQLServer(t, "", replica.handler)
+	sourceMysqld = newFakeMySQLServer(t, "source-0.example.com",
+		newFakeSourceHandler(8589934593, "binlog.000003", 868))
+
+	rc, _, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	expected := []string{
+		"STOP REPLICA",
+		"CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source-0.example.com', SOURCE_PORT = 3306, " +
+			"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'replication-password', SOURCE_SSL = 1, " +
+			"SOURCE_LOG_FILE = 'binlog.000003', SOURCE_LOG_POS = 868",
+		"START REPLICA",
+	}
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if len(sourceMysqld.Queries()) == 0 {
+		t.Error("Expected the position of the last applied epoch to be read from the source")
+	}
+	if status := syncedNrc.Status; status.SourceLogFile != "binlog.000003" || status.SourceLogPosition != 868 ||
+		!status.Healthy || status.ReceiverState != "Yes" || status.ApplierState != "Yes" {
+		t.Errorf("Expected the status to record the resumed channel but got %+v", status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelStarted) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelStarted)
+	}
+
+	// The password of the replication user has changed
+	userSecret.ResourceVersion = "2"
+	userSecret.Data[corev1.BasicAuthPasswordKey] = []byte("new-password")
+	replicaMysqld = newFakeMySQLServer(t, "", replica.handler)
+	newFakeMySQLServer(t, "source-0.example.com", newFakeSourceHandler(8589934593, "binlog.000003", 868))
+
+	_, _, syncedNrc = syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	statements := executedStatements(replicaMysqld)
+	if len(statements) != 3 || !strings.Contains(statements[1], "SOURCE_PASSWORD = 'new-password'") {
+		t.Errorf("Expected the channel to be restarted with the new password but got %q", statements)
+	}
+	if syncedNrc.Status.UserSecretVersion != "2" {
+		t.Errorf("Expected the status to record the new Secret version but got %q",
+			syncedNrc.Status.UserSecretVersion)
+	}
+}
+
+func TestNdbReplicationChannelStatus(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nrc, userSecret := newTestNdbReplicationChannel(nc, "source-0.example.com")
+	markChannelStarted(nrc, userSecret, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// The channel is running and lagging behind the source
+	lag := int64(7)
+	replica := &fakeReplica{
+		configured:       true,
+		sourceHost:       "source-0.example.com",
+		receiverState:    "Yes",
+		applierState:     "Yes",
+		lagSeconds:       &lag,
+		lastAppliedEpoch: 12884901889,
+	}
+	newFakeMySQLServer(t, "", replica.handler)
+	_, _, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	status := syncedNrc.Status
+	if !status.Healthy || status.LagSeconds == nil || *status.LagSeconds != 7 ||
+		status.LastAppliedEpoch != 12884901889 || status.LastError != "" || status.LastCheckTime == nil {
+		t.Errorf("Expected the status to record the healthy channel but got %+v", status)
+	}
+
+	// The applier has stopped due to an error
+	replica.lock.Lock()
+	replica.applierState, replica.lagSeconds = "No", nil
+	replica.lastApplierErrno, replica.lastApplierError = 1032, "Could not execute Update_rows event"
+	replica.lock.Unlock()
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	rc, _, syncedNrc := syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	status = syncedNrc.Status
+	if status.Healthy || status.LagSeconds != nil || status.ApplierState != "No" ||
+		status.LastError != "Could not execute Update_rows event" {
+		t.Errorf("Expected the status to record the failed applier but got %+v", status)
+	}
+	if statements := executedStatements(replicaMysqld); len(statements) != 0 {
+		t.Errorf("Expected the failed channel not to be restarted but got %q", statements)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelUnhealthy) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelUnhealthy)
+	}
+
+	// The error has been fixed and the applier restarted by the user
+	replica.lock.Lock()
+	replica.applierState, replica.lagSeconds = "Yes", &lag
+	replica.lastApplierErrno, replica.lastApplierError = 0, ""
+	replica.lock.Unlock()
+	newFakeMySQLServer(t, "", replica.handler)
+	rc, _, syncedNrc = syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	if status = syncedNrc.Status; !status.Healthy || status.LastError != "" {
+		t.Errorf("Expected the status to record the recovered channel but got %+v", status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelHealthy) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelHealthy)
+	}
+}
+
+func TestNdbReplicationChannelFailover(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nrc, userSecret := newTestNdbReplicationChannel(nc,
+		"source-0.example.com", "source-1.example.com", "source-2.example.com")
+	markChannelStarted(nrc, userSecret, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// The binlog MySQL Server being replicated from is not reachable
+	replica := &fakeReplica{
+		configured:        true,
+		sourceHost:        "source-0.example.com",
+		receiverState:     "Connecting",
+		applierState:      "Yes",
+		lastReceiverError: "error reconnecting to source 'ndb-replication-user@source-0.example.com:3306'",
+		lastAppliedEpoch:  17179869187,
+	}
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	newFakeMySQLServer(t, "source-0.example.com",
+		newFakeSourceHandler(17179869187, "binlog.000004", 310)).SetDown(true)
+	// The next binlog MySQL Server has the epoch at a different position
+	newFakeMySQLServer(t, "source-1.example.com", newFakeSourceHandler(17179869187, "binlog.000007", 5410))
+	newFakeMySQLServer(t, "source-2.example.com", newFakeSourceHandler(17179869187, "binlog.000001", 120))
+
+	rc, _, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	expected := []string{
+		"STOP REPLICA",
+		"CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source-1.example.com', SOURCE_PORT = 3306, " +
+			"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'replication-password', SOURCE_SSL = 1, " +
+			"SOURCE_LOG_FILE = 'binlog.000007', SOURCE_LOG_POS = 5410",
+		"START REPLICA",
+	}
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	status := syncedNrc.Status
+	if status.SourceHost != "source-1.example.com" || status.LastFailoverTime == nil ||
+		status.SourceLogFile != "binlog.000007" || status.SourceLogPosition != 5410 || !status.Healthy {
+		t.Errorf("Expected the status to record the switch to source-1 but got %+v", status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelFailover) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelFailover)
+	}
+
+	// The binary log of the new source has a gap - the channel moves
+	// on to the next binlog MySQL Server and resumes from the epoch
+	// applied before the gap.
+	replica.lock.Lock()
+	replica.applierState, replica.lagSeconds = "No", nil
+	replica.lastApplierErrno = 1590
+	replica.lastApplierError = "The incident LOST_EVENTS occurred on the source"
+	replica.lastAppliedEpoch = 21474836481
+	replica.lock.Unlock()
+	replicaMysqld = newFakeMySQLServer(t, "", replica.handler)
+	newFakeMySQLServer(t, "source-2.example.com", newFakeSourceHandler(21474836481, "binlog.000002", 96))
+
+	rc, _, syncedNrc = syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	expected[1] = "CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source-2.example.com', SOURCE_PORT = 3306, " +
+		"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'replication-password', SOURCE_SSL = 1, " +
+		"SOURCE_LOG_FILE = 'binlog.000002', SOURCE_LOG_POS = 96"
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if status = syncedNrc.Status; status.SourceHost != "source-2.example.com" ||
+		status.SourceLogFile != "binlog.000002" || status.SourceLogPosition != 96 {
+		t.Errorf("Expected the status to record the switch to source-2 but got %+v", status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonReplicationChannelFailover) {
+		t.Errorf("Expected a %s event", ReasonReplicationChannelFailover)
+	}
+
+	// None of the other binlog MySQL Servers is reachable - the
+	// channel keeps retrying the current one
+	replica.lock.Lock()
+	replica.receiverState, replica.lastReceiverError = "Connecting", "error reconnecting to source"
+	replica.lock.Unlock()
+	replicaMysqld = newFakeMySQLServer(t, "", replica.handler)
+	for _, host := range nrc.GetSourceHosts() {
+		newFakeMySQLServer(t, host, newFakeSourceHandler(0, "", 0)).SetDown(true)
+	}
+
+	rc, _, syncedNrc = syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	if statements := executedStatements(replicaMysqld); len(statements) != 0 {
+		t.Errorf("Expected the channel not to be switched but got %q", statements)
+	}
+	if syncedNrc.Status.SourceHost != "source-2.example.com" || syncedNrc.Status.Healthy {
+		t.Errorf("Expected the status to report the unhealthy channel at source-2 but got %+v", syncedNrc.Status)
+	}
+	if hasRecordedEvent(rc.recorder, ReasonReplicationChannelFailover) {
+		t.Error("Expected the channel not to be switched to an unreachable source")
+	}
+}
+
+func TestNdbReplicationChannelOfSuspendedNdbCluster(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nc.Spec.Suspended = true
+	nrc, userSecret := newTestNdbReplicationChannel(nc, "source-0.example.com")
+	markChannelStarted(nrc, userSecret, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// The channel is left alone while the NdbCluster is suspended
+	replica := &fakeReplica{
+		configured:       true,
+		sourceHost:       "source-0.example.com",
+		receiverState:    "No",
+		applierState:     "No",
+		lastAppliedEpoch: 25769803777,
+	}
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	newFakeMySQLServer(t, "source-0.example.com", newFakeSourceHandler(25769803777, "binlog.000005", 4721))
+
+	_, requeueAfter, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	if queries := replicaMysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is suspended but got %q", queries)
+	}
+	if requeueAfter != replicationChannelCheckInterval {
+		t.Errorf("Expected the channel to be checked after %s but got %s",
+			replicationChannelCheckInterval, requeueAfter)
+	}
+	if status := syncedNrc.Status; status.Healthy || !strings.Contains(status.Message, "is suspended") {
+		t.Errorf("Expected the status to report the suspended NdbCluster but got %+v", status)
+	}
+
+	// The NdbCluster has been resumed and the channel, stopped
+	// by the restart, is resumed after the last applied epoch.
+	nc.Spec.Suspended = false
+	_, _, syncedNrc = syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	expected := []string{
+		"STOP REPLICA",
+		"CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source-0.example.com', SOURCE_PORT = 3306, " +
+			"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'replication-password', SOURCE_SSL = 1, " +
+			"SOURCE_LOG_FILE = 'binlog.000005', SOURCE_LOG_POS = 4721",
+		"START REPLICA",
+	}
+	if statements := executedStatements(replicaMysqld); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+	if status := syncedNrc.Status; !status.Healthy || status.Message != "" {
+		t.Errorf("Expected the status to record the resumed channel but got %+v", status)
+	}
+}
+
+func TestNdbReplicationChannelOfPausedNdbCluster(t *testing.T) {
+	nc := newTestReplicaNdb()
+	nc.Annotations = map[string]string{v1.PausedAnnotation: "true"}
+	nrc, userSecret := newTestNdbReplicationChannel(nc, "source-0.example.com")
+	markChannelStarted(nrc, userSecret, "source-0.example.com")
+	k8sObjects := append(newTestMySQLServerObjects(nc), userSecret)
+
+	// The stopped channel is not restarted while the reconciliation is paused
+	replica := &fakeReplica{
+		configured:       true,
+		sourceHost:       "source-0.example.com",
+		receiverState:    "No",
+		applierState:     "No",
+		lastAppliedEpoch: 30064771073,
+	}
+	replicaMysqld := newFakeMySQLServer(t, "", replica.handler)
+	newFakeMySQLServer(t, "source-0.example.com", newFakeSourceHandler(30064771073, "binlog.000006", 157))
+
+	rc, _, syncedNrc := syncTestNdbReplicationChannel(t, nrc, k8sObjects, nc)
+	if queries := replicaMysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is paused but got %q", queries)
+	}
+	if !strings.Contains(syncedNrc.Status.Message, "has been paused") {
+		t.Errorf("Expected the status to report the paused NdbCluster but got %+v", syncedNrc.Status)
+	}
+	if !hasRecordedEvent(rc.recorder, ReasonNdbClusterPaused) {
+		t.Errorf("Expected a %s event", ReasonNdbClusterPaused)
+	}
+
+	// Nor is the channel removed
+	deletedNrc := syncedNrc.DeepCopy()
+	now := metav1.Now()
+	deletedNrc.DeletionTimestamp = &now
+	_, _, deletedNrc = syncTestNdbReplicationChannel(t, deletedNrc, k8sObjects, nc)
+	if queries := replicaMysqld.Queries(); len(queries) != 0 {
+		t.Errorf("Expected no queries while the NdbCluster is paused but got %q", queries)
+	}
+	if !hasFinalizer(deletedNrc, constants.ReplicationChannelFinalizer) {
+		t.Errorf("Expected the %q finalizer to be retained until the channel is removed",
+			constants.ReplicationChannelFinalizer)
+	}
+
+	// The channel is restarted once the reconciliation is resumed
+	nc.Annotations = nil
+	_, _, syncedNrc = syncTestNdbReplicationChannel(t, syncedNrc, k8sObjects, nc)
+	if statements := executedStatements(replicaMysqld); len(statements) != 3 ||
+		!strings.Contains(statements[1], "SOURCE_LOG_FILE = 'binlog.000006', SOURCE_LOG_POS = 157") {
+		t.Errorf("Expected the channel to be restarted after the resume but got %q", statements)
+	}
+	if status := syncedNrc.Status; !status.Healthy || status.Message != "" {
+		t.Errorf("Expected the status to record the restarted channel but got %+v", status)
+	}
+}
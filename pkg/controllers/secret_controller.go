@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,6 +8,7 @@ import (
 	"context"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
 	corev1 "k8s.io/api/core/v1"
@@ -74,6 +75,7 @@ type MySQLUserPasswordSecretControlInterface interface {
 	DefaultSecretControlInterface
 	EnsureMySQLRootPassword(ctx context.Context, ndb *v1.NdbCluster) (*corev1.Secret, error)
 	EnsureNDBOperatorPassword(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error)
+	EnsureReplicationUserPassword(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error)
 }
 
 // mysqlUserPasswordSecrets implements MySQLUserPasswordSecretControlInterface and
@@ -155,3 +157,203 @@ func (mups *mysqlUserPasswordSecrets) EnsureNDBOperatorPassword(
 	klog.Errorf("successfully created secret %s", secretName)
 	return secret, err
 }
+
+// EnsureReplicationUserPassword checks if the replication user secret
+// exists and creates a new one if it doesn't exist already
+func (mups *mysqlUserPasswordSecrets) EnsureReplicationUserPassword(
+	ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error) {
+	secretName, customSecret := resources.GetReplicationUserPasswordSecretName(nc)
+
+	secret, err := mups.secretInterface(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err == nil {
+		// Secret exists
+		return secret, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		// Error retrieving the secret
+		klog.Errorf("Failed to retrieve secret %s : %v", secretName, err)
+		return nil, err
+	}
+
+	if customSecret {
+		// Secret specified in the spec doesn't exist
+		klog.Errorf("Replication user password Secret specified in the NdbCluster spec doesn't exist : %v", err)
+		return nil, err
+	}
+
+	// Secret not found and not a custom secret - create a new one
+	secret = resources.NewReplicationUserPasswordSecret(nc)
+	secret, err = mups.secretInterface(nc.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to create secret %s : %v", secretName, err)
+	}
+
+	return secret, err
+}
+
+type TLSCASecretControlInterface interface {
+	DefaultSecretControlInterface
+	EnsureCASecret(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error)
+}
+
+// tlsCASecrets implements TLSCASecretControlInterface and can
+// handle the Secret holding the cluster CA used by the MySQL Cluster.
+type tlsCASecrets struct {
+	secretDefaults
+}
+
+// NewTLSCASecretInterface creates and returns a new TLSCASecretControlInterface
+func NewTLSCASecretInterface(client kubernetes.Interface) TLSCASecretControlInterface {
+	return &tlsCASecrets{
+		secretDefaults{
+			client: client,
+		},
+	}
+}
+
+// EnsureCASecret checks if the Secret with the cluster CA exists and
+// is valid. A new Secret with a new CA is created if it doesn't exist.
+func (tcs *tlsCASecrets) EnsureCASecret(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error) {
+	secretName, customSecret := ndbtls.GetCASecretName(nc)
+
+	secret, err := tcs.secretInterface(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err == nil {
+		// Secret exists
+		if err = ndbtls.ValidateCASecret(secret); err != nil {
+			klog.Errorf("Secret %q does not have a valid CA : %s", secretName, err)
+			return nil, err
+		}
+		return secret, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		// Error retrieving the secret
+		klog.Errorf("Failed to retrieve secret %s : %v", secretName, err)
+		return nil, err
+	}
+
+	if customSecret {
+		// Secret specified in the spec doesn't exist
+		klog.Errorf("CA Secret specified in the NdbCluster spec doesn't exist : %v", err)
+		return nil, err
+	}
+
+	// Secret not found and not a custom secret - create a new one
+	if secret, err = ndbtls.NewCASecret(nc); err != nil {
+		klog.Errorf("Failed to generate the cluster CA : %s", err)
+		return nil, err
+	}
+	secret, err = tcs.secretInterface(nc.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to create secret %s : %v", secretName, err)
+	}
+
+	return secret, err
+}
+
+type MySQLServerTLSSecretControlInterface interface {
+	DefaultSecretControlInterface
+	EnsureMySQLServerTLSSecret(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error)
+}
+
+// mysqldTLSSecrets implements MySQLServerTLSSecretControlInterface and can handle
+// the Secrets holding the MySQL Server certificates and the CA that signs them.
+type mysqldTLSSecrets struct {
+	secretDefaults
+}
+
+// NewMySQLServerTLSSecretInterface creates and returns a new MySQLServerTLSSecretControlInterface
+func NewMySQLServerTLSSecretInterface(client kubernetes.Interface) MySQLServerTLSSecretControlInterface {
+	return &mysqldTLSSecrets{
+		secretDefaults{
+			client: client,
+		},
+	}
+}
+
+// ensureMySQLServerCASecret checks if the Secret with the MySQL
+// Server CA exists and creates a new one if it doesn't exist already
+func (mts *mysqldTLSSecrets) ensureMySQLServerCASecret(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error) {
+	secretName := ndbtls.GetMySQLServerCASecretName(nc)
+
+	secret, err := mts.secretInterface(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err == nil {
+		// Secret exists
+		if err = ndbtls.ValidateCASecret(secret); err != nil {
+			klog.Errorf("Secret %q does not have a valid CA : %s", secretName, err)
+			return nil, err
+		}
+		return secret, nil
+	}
+
+	if !errors.IsNotFound(err) {
+		// Error retrieving the secret
+		klog.Errorf("Failed to retrieve secret %s : %v", secretName, err)
+		return nil, err
+	}
+
+	// Secret not found - create a new one
+	if secret, err = ndbtls.NewMySQLServerCASecret(nc); err != nil {
+		klog.Errorf("Failed to generate the MySQL Server CA : %s", err)
+		return nil, err
+	}
+	secret, err = mts.secretInterface(nc.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to create secret %s : %v", secretName, err)
+	}
+
+	return secret, err
+}
+
+// EnsureMySQLServerTLSSecret checks if the Secret with the MySQL Server
+// certificate exists and creates a new one if it doesn't exist already.
+// The certificate is renewed if it is about to expire or if it doesn't
+// cover all the MySQL Servers of the NdbCluster.
+func (mts *mysqldTLSSecrets) EnsureMySQLServerTLSSecret(ctx context.Context, nc *v1.NdbCluster) (*corev1.Secret, error) {
+	caSecret, err := mts.ensureMySQLServerCASecret(ctx, nc)
+	if err != nil {
+		return nil, err
+	}
+
+	secretName := ndbtls.GetMySQLServerTLSSecretName(nc)
+	secret, err := mts.secretInterface(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		// Error retrieving the secret
+		klog.Errorf("Failed to retrieve secret %s : %v", secretName, err)
+		return nil, err
+	}
+
+	secretExists := err == nil
+	if secretExists {
+		needsRenewal, reason := ndbtls.MySQLServerCertNeedsRenewal(nc, caSecret, secret)
+		if !needsRenewal {
+			// Secret exists and has a valid certificate
+			return secret, nil
+		}
+		klog.Infof("Renewing the MySQL Server certificate in Secret %q as %s", secretName, reason)
+	}
+
+	newSecret, err := ndbtls.NewMySQLServerTLSSecret(nc, caSecret)
+	if err != nil {
+		klog.Errorf("Failed to generate the MySQL Server certificate : %s", err)
+		return nil, err
+	}
+
+	if !secretExists {
+		secret, err = mts.secretInterface(nc.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
+		if err != nil {
+			klog.Errorf("Failed to create secret %s : %v", secretName, err)
+		}
+		return secret, err
+	}
+
+	// Update the existing secret with the new certificate
+	updatedSecret := secret.DeepCopy()
+	updatedSecret.Data = newSecret.Data
+	secret, err = mts.secretInterface(nc.Namespace).Update(ctx, updatedSecret, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to update secret %s : %v", secretName, err)
+	}
+	return secret, err
+}
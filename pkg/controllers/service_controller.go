@@ -6,7 +6,9 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
 
@@ -25,6 +27,8 @@ type ServiceControlInterface interface {
 		ctx context.Context, sc *SyncContext, ndbSfset statefulset.NdbStatefulSetInterface) (*corev1.Service, error)
 	patchService(
 		ctx context.Context, sc *SyncContext, ndbSfset statefulset.NdbStatefulSetInterface) error
+	patchServiceSelector(
+		ctx context.Context, sc *SyncContext, ndbSfset statefulset.NdbStatefulSetInterface) error
 	deleteService(
 		ctx context.Context, namespace, name string) error
 }
@@ -53,7 +57,8 @@ func (svcCtrl *serviceControl) ensureService(
 	ndbSfset statefulset.NdbStatefulSetInterface) (*corev1.Service, error) {
 
 	nc := sc.ndb
-	serviceName := nc.GetServiceName(ndbSfset.GetTypeName())
+	// The governing Service has the same name as the StatefulSet
+	serviceName := ndbSfset.GetName(nc)
 
 	svc, err := svcCtrl.serviceLister.Services(nc.Namespace).Get(serviceName)
 
@@ -105,7 +110,17 @@ func (svcCtrl *serviceControl) patchService(
 	nc := sc.ndb
 	updatedSvc := ndbSfset.NewGoverningService(nc)
 
-	// Only changing the Service type is supported
+	// The labels removed from the selector are removed before the pods are
+	// updated, so that the Service keeps selecting the pods while they are
+	// restarted. Any new labels are added by patchServiceSelector once all
+	// the pods have them.
+	if selectorRemovesLabels(currentSvc.Spec.Selector, updatedSvc.Spec.Selector) {
+		if err = svcCtrl.updateSelector(ctx, currentSvc, updatedSvc.Spec.Selector); err != nil {
+			return err
+		}
+	}
+
+	// Only changing the Service type and the selector is supported
 	if currentSvc.Spec.Type == updatedSvc.Spec.Type {
 		// No change to service
 		return nil
@@ -128,6 +143,69 @@ func (svcCtrl *serviceControl) patchService(
 	return nil
 }
 
+// selectorRemovesLabels returns true if the updated selector only
+// removes labels from the current selector and does not add any.
+func selectorRemovesLabels(currentSelector, updatedSelector map[string]string) bool {
+	if len(updatedSelector) >= len(currentSelector) {
+		return false
+	}
+	for key, value := range updatedSelector {
+		if currentSelector[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// updateSelector patches the selector of the given service
+func (svcCtrl *serviceControl) updateSelector(
+	ctx context.Context, svc *corev1.Service, selector map[string]string) error {
+	// A JSON Merge patch replaces the selector only
+	// partly - set the removed labels to null.
+	selectorPatch := make(map[string]interface{})
+	for key := range svc.Spec.Selector {
+		selectorPatch[key] = nil
+	}
+	for key, value := range selector {
+		selectorPatch[key] = value
+	}
+	jsonMergePatch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"selector": selectorPatch},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = svcCtrl.getServiceInterface(svc.Namespace).Patch(
+		ctx, svc.GetName(), types.MergePatchType, jsonMergePatch, metav1.PatchOptions{})
+	if err != nil {
+		klog.Errorf("Failed to patch the selector of the service %q : %s", getNamespacedName(svc), err)
+		return err
+	}
+
+	klog.Infof("Selector of the service %q has been patched successfully", getNamespacedName(svc))
+	return nil
+}
+
+// patchServiceSelector patches the selector of the given StatefulSet's
+// service if it has changed. It should be called only after all the
+// pods of the StatefulSet have been updated with the new labels.
+func (svcCtrl *serviceControl) patchServiceSelector(
+	ctx context.Context, sc *SyncContext, ndbSfset statefulset.NdbStatefulSetInterface) error {
+	currentSvc, err := svcCtrl.ensureService(ctx, sc, ndbSfset)
+	if err != nil {
+		return err
+	}
+
+	updatedSelector := ndbSfset.NewGoverningService(sc.ndb).Spec.Selector
+	if reflect.DeepEqual(currentSvc.Spec.Selector, updatedSelector) {
+		// No change to the selector
+		return nil
+	}
+
+	return svcCtrl.updateSelector(ctx, currentSvc, updatedSelector)
+}
+
 // deleteService deletes the given service
 func (svcCtrl *serviceControl) deleteService(
 	ctx context.Context, namespace, name string) error {
@@ -0,0 +1,28 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"testing"
+)
+
+func TestSelectorRemovesLabels(t *testing.T) {
+	current := map[string]string{"cluster": "example", "role": "application"}
+	for _, tc := range []struct {
+		updated  map[string]string
+		expected bool
+		desc     string
+	}{
+		{map[string]string{"cluster": "example"}, true, "label removed"},
+		{map[string]string{"cluster": "example", "role": "application"}, false, "no change"},
+		{map[string]string{"cluster": "example", "role": "binlog"}, false, "label updated"},
+		{map[string]string{"cluster": "example", "role": "application", "group": "a"}, false, "label added"},
+		{map[string]string{"group": "a"}, false, "label removed and another added"},
+	} {
+		if removes := selectorRemovesLabels(current, tc.updated); removes != tc.expected {
+			t.Errorf("%s : expected selectorRemovesLabels to return %v but got %v", tc.desc, tc.expected, removes)
+		}
+	}
+}
@@ -137,7 +137,7 @@ func (ndbSfset *ndbNodeStatefulSetImpl) deleteStatefulSet(ctx context.Context, s
 	klog.Errorf("Deleted the StatefulSet %q", getNamespacedName(sfset))
 
 	// Delete the governing Service
-	return sc.serviceController.deleteService(ctx, sfset.Namespace, sc.ndb.GetServiceName(ndbSfset.GetTypeName()))
+	return sc.serviceController.deleteService(ctx, sfset.Namespace, sfset.Spec.ServiceName)
 }
 
 // EnsureStatefulSet creates a StatefulSet for the MySQL Cluster nodes if one doesn't exist already.
@@ -226,9 +226,10 @@ func (ndbSfset *ndbNodeStatefulSetImpl) ReconcileStatefulSet(
 		// be patched to remove the DataNodeInitialRestart field to trigger the second iteration.
 		// During this second iteration, the change will be noted by the statefulset, and it will
 		// be patched again to remove the --initial flag from the data node pod's command argument.
+		// The data node StatefulSet is also patched if the TDE password has been changed.
 		if !(ndbSfset.GetTypeName() == constants.NdbNodeTypeNdbmtd &&
-			isInitialFlagSet(sfset) &&
-			!sc.configSummary.DataNodeInitialRestart) {
+			((isInitialFlagSet(sfset) && !sc.configSummary.DataNodeInitialRestart) ||
+				sc.tdePasswordUpdatePending)) {
 			// StatefulSet upto date
 			return continueProcessing()
 		}
@@ -248,9 +249,12 @@ func (ndbSfset *ndbNodeStatefulSetImpl) ReconcileStatefulSet(
 	}
 
 	if ndbSfset.GetTypeName() == constants.NdbNodeTypeNdbmtd &&
-		*(sfset.Spec.Replicas) < *(updatedStatefulSet.Spec.Replicas) {
+		*(sfset.Spec.Replicas) < *(updatedStatefulSet.Spec.Replicas) &&
+		!nc.IsRedundancyLevelMigrationInProgress() {
 		// New data nodes are being added to MySQL Cluster
-		// config but do not start the new nodes yet.
+		// config but do not start the new nodes yet. During a
+		// redundancyLevel migration, all the data nodes are
+		// started together via an initial system restart.
 		*(updatedStatefulSet.Spec.Replicas) = *(sfset.Spec.Replicas)
 		// Set the AddNodeOnlineInProgress Annotation
 		updatedStatefulSet.Annotations[AddNodeOnlineInProgress] = "true"
@@ -7,12 +7,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	listersbatchv1 "k8s.io/client-go/listers/batch/v1"
 	listerscorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
@@ -34,6 +36,8 @@ type SyncContext struct {
 	mgmdNodeSfset *appsv1.StatefulSet
 	dataNodeSfSet *appsv1.StatefulSet
 	mysqldSfset   *appsv1.StatefulSet
+	// StatefulSets of the MySQL Server groups, mapped by the group name
+	mysqldGroupSfsets map[string]*appsv1.StatefulSet
 
 	ndb *v1.NdbCluster
 
@@ -49,13 +53,50 @@ type SyncContext struct {
 	kubernetesClient kubernetes.Interface
 	ndbClient        ndbclientset.Interface
 	ndbsLister       ndblisters.NdbClusterLister
+	ndbBackupsLister ndblisters.NdbClusterBackupLister
 	podLister        listerscorev1.PodLister
 	pvcLister        listerscorev1.PersistentVolumeClaimLister
 	serviceLister    listerscorev1.ServiceLister
+	jobLister        listersbatchv1.JobLister
 
 	// bool flag to control the NdbCluster status processedGeneration value
 	syncSuccess bool
 
+	// restore progress of the backup specified in spec.restoreFrom,
+	// generated during this sync if the restore is in progress
+	restoreStatus *v1.NdbClusterRestoreStatus
+
+	// disk space usage of the tablespaces, collected
+	// during this sync after reconciling the disk data objects
+	tablespaceStatus []v1.NdbTablespaceStatus
+
+	// progress of the migration to the redundancyLevel
+	// specified in the spec, updated during this sync
+	redundancyLevelMigration *v1.NdbRedundancyLevelMigrationStatus
+
+	// progress of the re-encryption of the data node file
+	// systems with a new TDE password, updated during this sync
+	tdePasswordRotation *v1.NdbTDEPasswordRotationStatus
+	// tdePasswordUpdatePending is set to true if the data node
+	// StatefulSet has to be patched with the new TDE password
+	tdePasswordUpdatePending bool
+
+	// conflict counters of the binlog MySQL Server, collected during
+	// this sync after reconciling the conflict detection functions
+	conflictDetectionStatus *v1.NdbConflictDetectionStatus
+	// conflictFunctionsRemoved is set to true if all the conflict
+	// functions were removed from the mysql.ndb_replication table
+	conflictFunctionsRemoved bool
+
+	// status of the MySQL Cluster nodes as reported by the Management
+	// Server, retrieved at most once per reconciliation, either by the
+	// sync or by the first status update. It is nil if the status
+	// could not be retrieved.
+	clusterStatus mgmapi.ClusterStatus
+	// clusterStatusRetrieved is set once the retrieval
+	// of clusterStatus has been attempted
+	clusterStatusRetrieved bool
+
 	// recorder is an event recorder for recording Event resources to the Kubernetes API.
 	recorder events.EventRecorder
 }
@@ -111,7 +152,25 @@ func (sc *SyncContext) validateMySQLServerStatefulSet() (*appsv1.StatefulSet, er
 	return sc.mysqldController.GetStatefulSet(sc)
 }
 
+// retrieveWorkloads retrieves the existing StatefulSets of the NdbCluster
+// without creating or updating any of them. This is used to generate the
+// status of the NdbCluster when its reconciliation has been paused.
+func (sc *SyncContext) retrieveWorkloads() (err error) {
+	if sc.mgmdNodeSfset, err = sc.mgmdController.GetStatefulSet(sc); err != nil {
+		return err
+	}
+	if sc.dataNodeSfSet, err = sc.ndbmtdController.GetStatefulSet(sc); err != nil {
+		return err
+	}
+	if sc.mysqldSfset, err = sc.validateMySQLServerStatefulSet(); err != nil {
+		return err
+	}
+	sc.mysqldGroupSfsets, err = sc.mysqldController.GetGroupStatefulSets(sc)
+	return err
+}
+
 // ensurePodDisruptionBudgets creates PodDisruptionBudgets for data nodes
+// and, if there are more than one management node, the management nodes
 func (sc *SyncContext) ensurePodDisruptionBudget(ctx context.Context) (existed bool, err error) {
 	// ensure ndbmtd PDB
 	if sc.pdbController == nil {
@@ -119,8 +178,22 @@ func (sc *SyncContext) ensurePodDisruptionBudget(ctx context.Context) (existed b
 		// return true to suppress operator's "created" log
 		return true, nil
 	}
-	return sc.pdbController.EnsurePodDisruptionBudget(
-		ctx, sc, sc.ndbmtdController.GetTypeName())
+	if existed, err = sc.pdbController.EnsurePodDisruptionBudget(
+		ctx, sc, sc.ndbmtdController.GetTypeName()); err != nil {
+		return false, err
+	}
+
+	// ensure mgmd PDB
+	if sc.ndb.GetManagementNodeCount() > 1 {
+		mgmdPDBExisted, err := sc.pdbController.EnsurePodDisruptionBudget(
+			ctx, sc, sc.mgmdController.GetTypeName())
+		if err != nil {
+			return false, err
+		}
+		existed = existed && mgmdPDBExisted
+	}
+
+	return existed, nil
 }
 
 // reconcileManagementNodeStatefulSet patches the Management Node
@@ -185,26 +258,47 @@ func (sc *SyncContext) ensurePodVersion(
 // is resumed only after the restarted data nodes become ready. At any
 // given time, only one data node per group will be affected by this
 // maneuver, ensuring MySQL Cluster's availability.
+//
+// If the NdbCluster uses the FullRestart update strategy, all the data
+// nodes are restarted together instead, via restartAllDataNodes.
 func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult {
 	ndbmtdSfset := sc.dataNodeSfSet
 	if statefulsetUpdateComplete(ndbmtdSfset) {
 		// All data nodes have the desired pod version.
+		if sc.isFullRestartInProgress() {
+			// The data nodes are up again after a full restart.
+			// Delete the FullRestartInProgress annotation.
+			klog.Info("Full restart of the data nodes is complete")
+			updatedSfset := ndbmtdSfset.DeepCopy()
+			delete(updatedSfset.GetAnnotations(), FullRestartInProgress)
+			if sr := sc.ndbmtdController.patchStatefulSet(ctx, ndbmtdSfset, updatedSfset); sr.stopSync() {
+				return sr
+			}
+		}
+
 		// Continue with rest of the sync process.
 		klog.Info("All Data node pods are up-to-date and ready")
 		return continueProcessing()
 	}
 
+	if sc.ndb.UsesFullRestartUpdateStrategy() && !sc.isTDEPasswordRotationInProgress() {
+		// The data nodes are always restarted one per node group during
+		// a TDE password rotation as they are restarted with the --initial
+		// flag, and restarting all of them together would wipe out the data.
+		return sc.restartAllDataNodes(ctx)
+	}
+
 	desiredPodRevisionHash := ndbmtdSfset.Status.UpdateRevision
 	klog.Infof("Ensuring Data Node pods have the desired podSpec version, %s", desiredPodRevisionHash)
 
 	// Get the node and nodegroup details via clusterStatus
-	mgmClient, err := mgmapi.NewMgmClient(sc.ndb.GetConnectstring())
+	mgmClient, err := newMgmClient(ctx, sc.kubernetesClient, sc.ndb)
 	if err != nil {
 		return errorWhileProcessing(err)
 	}
 	defer mgmClient.Disconnect()
 
-	clusterStatus, err := mgmClient.GetStatus()
+	clusterStatus, err := sc.getClusterStatus(mgmClient)
 	if err != nil {
 		klog.Errorf("Error getting cluster status from management server: %s", err)
 		return errorWhileProcessing(err)
@@ -236,9 +330,9 @@ func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult
 		var nodesBeingUpdated []int
 		for _, nodeId := range candidateNodeIds {
 			// Generate the pod name using nodeId.
-			// Data node with nodeId 'i' runs in a pod with ordinal index 'i-1-numberOfMgmdNodes'
+			// Data node with nodeId 'i' runs in a pod with ordinal index 'i-dataNodeStartNodeId'
 			ndbmtdPodName := fmt.Sprintf(
-				"%s-%d", ndbmtdSfset.Name, nodeId-1-int(sc.configSummary.NumOfManagementNodes))
+				"%s-%d", ndbmtdSfset.Name, nodeId-int(sc.configSummary.DataNodeStartNodeId))
 
 			// Check the pod version and delete it if its outdated
 			podDeleted, err := sc.ensurePodVersion(
@@ -270,6 +364,97 @@ func (sc *SyncContext) ensureDataNodePodVersion(ctx context.Context) syncResult
 	return continueProcessing()
 }
 
+// isFullRestartInProgress returns true if all the data nodes
+// are being restarted together to apply a spec update.
+func (sc *SyncContext) isFullRestartInProgress() bool {
+	if sc.dataNodeSfSet == nil {
+		return false
+	}
+	_, exists := sc.dataNodeSfSet.GetAnnotations()[FullRestartInProgress]
+	return exists
+}
+
+// restartAllDataNodes stops all the data nodes via the Management Server
+// and then deletes all the data node pods that have an outdated PodSpec
+// version, allowing the K8s StatefulSet controller to restart them with
+// the latest pod definition and config. The restarted data nodes start
+// together via a system restart. The MySQL Cluster is unavailable until
+// then, and this is marked by the FullRestartInProgress annotation.
+func (sc *SyncContext) restartAllDataNodes(ctx context.Context) syncResult {
+	ndbmtdSfset := sc.dataNodeSfSet
+	if !sc.isFullRestartInProgress() {
+		// Mark the start of the full restart
+		updatedSfset := ndbmtdSfset.DeepCopy()
+		if updatedSfset.Annotations == nil {
+			updatedSfset.Annotations = make(map[string]string)
+		}
+		updatedSfset.Annotations[FullRestartInProgress] = "true"
+		if sr := sc.ndbmtdController.patchStatefulSet(ctx, ndbmtdSfset, updatedSfset); sr.stopSync() {
+			return sr
+		}
+		sc.dataNodeSfSet = updatedSfset
+	}
+
+	// Stop all the data nodes that are still running
+	stoppedNodeIds, err := sc.stopRunningDataNodes(ctx)
+	if err != nil {
+		return errorWhileProcessing(err)
+	}
+	if len(stoppedNodeIds) != 0 {
+		klog.Infof("Stopped all the data nodes %v to apply the update via a full restart", stoppedNodeIds)
+	}
+
+	// Delete all the outdated data node pods together
+	desiredPodRevisionHash := ndbmtdSfset.Status.UpdateRevision
+	for i := int32(0); i < *(ndbmtdSfset.Spec.Replicas); i++ {
+		ndbmtdPodName := fmt.Sprintf("%s-%d", ndbmtdSfset.Name, i)
+		nodeId := i + sc.configSummary.DataNodeStartNodeId
+		if _, err = sc.ensurePodVersion(
+			ctx, ndbmtdSfset.Namespace, ndbmtdPodName, desiredPodRevisionHash,
+			fmt.Sprintf("Data Node(nodeId=%d)", nodeId)); err != nil {
+			return errorWhileProcessing(err)
+		}
+	}
+
+	// Stop processing. Reconciliation will continue once
+	// the data nodes have restarted and are ready again.
+	klog.Info("All the data nodes are being restarted with the desired pod version")
+	return finishProcessing()
+}
+
+// stopRunningDataNodes stops, via the Management Server, all the data
+// nodes that are still connected to it, and returns their node ids.
+func (sc *SyncContext) stopRunningDataNodes(ctx context.Context) ([]int, error) {
+	mgmClient, err := newMgmClient(ctx, sc.kubernetesClient, sc.ndb)
+	if err != nil {
+		return nil, err
+	}
+	defer mgmClient.Disconnect()
+
+	clusterStatus, err := mgmClient.GetStatus()
+	if err != nil {
+		klog.Errorf("Error getting cluster status from management server: %s", err)
+		return nil, err
+	}
+
+	var runningDataNodeIds []int
+	for nodeId, nodeStatus := range clusterStatus {
+		if nodeStatus.IsDataNode() && nodeStatus.IsConnected {
+			runningDataNodeIds = append(runningDataNodeIds, nodeId)
+		}
+	}
+
+	if len(runningDataNodeIds) != 0 {
+		sort.Ints(runningDataNodeIds)
+		if err = mgmClient.StopNodes(runningDataNodeIds); err != nil {
+			klog.Errorf("Failed to stop the data nodes %v : %s", runningDataNodeIds, err)
+			return nil, err
+		}
+	}
+
+	return runningDataNodeIds, nil
+}
+
 // ensureAllResources creates all K8s resources required for running the
 // MySQL Cluster if they do no exist already. Resource creation needs to
 // be idempotent just like any other step in the syncHandler. The config
@@ -311,6 +496,25 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 		return errorWhileProcessing(err)
 	}
 
+	// Ensure that the cluster CA exists before creating the
+	// statefulSets, as the pods need it to sign their certificates.
+	if sc.ndb.Spec.TLS != nil {
+		if _, err := NewTLSCASecretInterface(sc.kubernetesClient).EnsureCASecret(ctx, sc.ndb); err != nil {
+			klog.Errorf("Failed to ensure the cluster CA secret : %s", err)
+			return errorWhileProcessing(err)
+		}
+	}
+
+	// Ensure that the MySQL Server certificate exists and is up-to-date.
+	// Any renewed certificate will be rolled out to the MySQL Servers
+	// when their StatefulSets are reconciled.
+	if sc.ndb.GetMySQLServerTLSSpec() != nil {
+		if _, err := NewMySQLServerTLSSecretInterface(sc.kubernetesClient).EnsureMySQLServerTLSSecret(ctx, sc.ndb); err != nil {
+			klog.Errorf("Failed to ensure the MySQL Server certificate secret : %s", err)
+			return errorWhileProcessing(err)
+		}
+	}
+
 	initialSystemRestart := sc.ndb.Status.ProcessedGeneration == 0
 
 	nc := sc.ndb
@@ -410,6 +614,18 @@ func (sc *SyncContext) ensureAllResources(ctx context.Context) syncResult {
 		return finishProcessing()
 	}
 
+	// Similarly, verify that the StatefulSets of the MySQL Server groups are owned by the NdbCluster resource.
+	if sc.mysqldGroupSfsets, err = sc.mysqldController.GetGroupStatefulSets(sc); err != nil {
+		return errorWhileProcessing(err)
+	}
+
+	for _, groupSfset := range sc.mysqldGroupSfsets {
+		if !statefulsetUpdateComplete(groupSfset) && !nc.HasSyncError() {
+			// MySQL Server group StatefulSet is not complete yet.
+			return finishProcessing()
+		}
+	}
+
 	// The StatefulSets already existed before this sync loop.
 	// There is a rare chance that some other resources were created during
 	// this sync loop as they were dropped by some other application other
@@ -459,6 +675,17 @@ func (sc *SyncContext) isStatefulsetUpdated(statefulset *appsv1.StatefulSet, exp
 	}
 }
 
+// mysqldGroupStatefulSetsUpdated checks if all the MySQL Server group
+// StatefulSets are complete, as determined by isStatefulsetUpdated.
+func (sc *SyncContext) mysqldGroupStatefulSetsUpdated(expectedConfigGeneration int64) bool {
+	for _, groupSfset := range sc.mysqldGroupSfsets {
+		if !sc.isStatefulsetUpdated(groupSfset, expectedConfigGeneration, Complete) {
+			return false
+		}
+	}
+	return true
+}
+
 // ensureWorkloadsReadiness checks if all the workloads created for the
 // NdbCluster resource are ready. The sync is stopped if they are not ready.
 func (sc *SyncContext) ensureWorkloadsReadiness() syncResult {
@@ -471,7 +698,8 @@ func (sc *SyncContext) ensureWorkloadsReadiness() syncResult {
 	// particular workload needs to be patched. So, no need to wait for the stale versions
 	if sc.isStatefulsetUpdated(sc.mgmdNodeSfset, NdbGeneration, Complete) &&
 		sc.isStatefulsetUpdated(sc.dataNodeSfSet, NdbGeneration, Ready) &&
-		sc.isStatefulsetUpdated(sc.mysqldSfset, NdbGeneration, Complete) {
+		sc.isStatefulsetUpdated(sc.mysqldSfset, NdbGeneration, Complete) &&
+		sc.mysqldGroupStatefulSetsUpdated(NdbGeneration) {
 		klog.Infof("All workloads owned by the NdbCluster resource %q are ready", getNamespacedName(sc.ndb))
 		return continueProcessing()
 	}
@@ -501,6 +729,12 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Suspend or resume the MySQL Cluster as requested by spec.suspended.
+	// Nothing else is reconciled while the NdbCluster is suspended.
+	if sr := sc.reconcileSuspension(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// All resources and workloads exist.
 	// Continue further only if all the workloads are ready.
 	if sr := sc.ensureWorkloadsReadiness(); sr.stopSync() {
@@ -547,6 +781,11 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 	}
 	klog.Info("All Management node pods are up-to-date and ready")
 
+	// Re-encrypt the data node file systems if the TDE password has changed
+	if sr := sc.reconcileTDEPasswordRotation(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// Reconcile Data Nodes by updating their statefulSet definition
 	if sr := sc.reconcileDataNodeStatefulSet(ctx); sr.stopSync() {
 		return sr
@@ -557,6 +796,19 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Restore the backup specified in spec.restoreFrom, if any,
+	// before the MySQL Servers are started.
+	if sr := sc.reconcileRestore(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Migrate the MySQL Cluster to the redundancyLevel specified in
+	// the spec, if it has changed. This patches the config map with
+	// the new redundancyLevel once the data has been backed up.
+	if sr := sc.reconcileRedundancyLevelMigration(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// Second pass of MySQL Server reconciliation
 	// Reconcile the rest of spec/config change in MySQL Server StatefulSet
 	if sr := sc.mysqldController.ReconcileStatefulSet(ctx, sc); sr.stopSync() {
@@ -573,6 +825,26 @@ func (sc *SyncContext) sync(ctx context.Context) syncResult {
 		return sr
 	}
 
+	// Rotate the passwords of the Root and the NDB Operator users, if required
+	if sr := sc.mysqldController.reconcilePasswords(ctx, sc); sr.stopSync() {
+		return sr
+	}
+
+	// Create the replication user, if the NdbCluster takes part in the replication
+	if sr := sc.mysqldController.reconcileReplicationUser(ctx, sc); sr.stopSync() {
+		return sr
+	}
+
+	// Store the conflict detection functions declared in the spec
+	if sr := sc.reconcileConflictDetection(ctx); sr.stopSync() {
+		return sr
+	}
+
+	// Create the disk data objects declared in the spec
+	if sr := sc.reconcileDiskData(ctx); sr.stopSync() {
+		return sr
+	}
+
 	// At this point, the MySQL Cluster is in sync with the configuration in the config map.
 	// The configuration in the config map has to be checked to see if it is still the
 	// desired config specified in the Ndb object.
@@ -599,6 +871,10 @@ func (sc *SyncContext) updateNdbClusterStatus(ctx context.Context) (statusUpdate
 
 	// Use the DeepCopied NdbCluster resource to make the update
 	nc := sc.ndb
+	// Retrieve the state of the MySQL Cluster nodes, if the sync has not
+	// already done so, to compute the Available, Degraded and Progressing
+	// conditions
+	sc.retrieveClusterStatus(ctx)
 	// Generate status with recent state of various resources
 	status := sc.calculateNdbClusterStatus()
 
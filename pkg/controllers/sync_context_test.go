@@ -0,0 +1,83 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	listerscorev1 "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+func TestFullRestartOfDataNodes(t *testing.T) {
+	sc, k8sClient := newTestDataNodeSyncContext(t, 4, nil)
+	sc.ndb.Spec.UpdateStrategy = v1.NdbClusterUpdateStrategyFullRestart
+	// The data node StatefulSet has been updated to
+	// rev-2 but all the pods are still running rev-1
+	sc.dataNodeSfSet.Status.UpdateRevision = "rev-2"
+	sc.dataNodeSfSet.Status.UpdatedReplicas = 0
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for i := 0; i < 4; i++ {
+		pod := newTestDataNodePod(sc.dataNodeSfSet, i, "rev-1", true)
+		if err := podIndexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+		if err := k8sClient.Tracker().Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc.podLister = listerscorev1.NewPodLister(podIndexer)
+	fms := newFakeMgmServer(t, newTestClusterStatus())
+
+	// Verify that all the data nodes are stopped before any of the pods is
+	// deleted, so that they all start together via a system restart.
+	var deletedPods []string
+	k8sClient.PrependReactor("delete", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			fms.lock.Lock()
+			defer fms.lock.Unlock()
+			for nodeId := 3; nodeId <= 6; nodeId++ {
+				if fms.clusterStatus[nodeId].IsConnected {
+					t.Errorf("Pod %q deleted while the data node %d is still running",
+						action.(k8stesting.DeleteAction).GetName(), nodeId)
+				}
+			}
+			deletedPods = append(deletedPods, action.(k8stesting.DeleteAction).GetName())
+			return false, nil, nil
+		})
+
+	sr := sc.ensureDataNodePodVersion(context.TODO())
+	if !sr.stopSync() || sr.getError() != nil {
+		t.Fatalf("Expected the sync to stop without an error but got %v", sr.getError())
+	}
+	if !fms.hasCall("StopNodes [3 4 5 6]") {
+		t.Errorf("Expected all the data nodes to be stopped together but the Management Server received %v",
+			fms.calls)
+	}
+	if len(deletedPods) != 4 {
+		t.Errorf("Expected all the 4 data node pods to be deleted but got %v", deletedPods)
+	}
+	sfset := getTestDataNodeStatefulSet(t, sc, k8sClient)
+	if _, exists := sfset.Annotations[FullRestartInProgress]; !exists {
+		t.Fatalf("Expected the %q annotation to be set during the full restart", FullRestartInProgress)
+	}
+
+	// The data nodes have restarted with the new pod version
+	sc.dataNodeSfSet = sfset
+	sc.dataNodeSfSet.Status = newTestStatefulSet(4).Status
+	sc.dataNodeSfSet.Status.CurrentReplicas = 4
+	if sr = sc.ensureDataNodePodVersion(context.TODO()); sr.stopSync() {
+		t.Fatalf("Expected the sync to continue after the full restart but got %v", sr.getError())
+	}
+	if _, exists := getTestDataNodeStatefulSet(t, sc, k8sClient).Annotations[FullRestartInProgress]; exists {
+		t.Errorf("Expected the %q annotation to be removed after the full restart", FullRestartInProgress)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Package cron parses the standard 5 field cron schedules
+// and calculates the activation times of those schedules.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule.
+// Each field is a bitset of the values allowed in it.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// dayOfMonthStar and dayOfWeekStar are set if the respective
+	// fields were specified as '*'. If both the day fields are
+	// restricted, a day matches if either one of the fields match.
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// fieldBounds describes the allowed values of a schedule field
+type fieldBounds struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 are accepted for Sunday
+	dayOfWeekBounds = fieldBounds{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors are the predefined schedules that can be used in place of the 5 fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses the given cron schedule of form
+// '<minute> <hour> <day of month> <month> <day of week>'.
+// Each field can be a '*', a value, a range (a-b) or a list
+// of them separated by commas, optionally followed by a step
+// (/n). One of the descriptors like @daily, @hourly etc. can
+// also be used instead of the fields.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		fieldsSpec, exists := descriptors[strings.ToLower(spec)]
+		if !exists {
+			return nil, fmt.Errorf("unrecognised descriptor %q", spec)
+		}
+		spec = fieldsSpec
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q but found %d", spec, len(fields))
+	}
+
+	var err error
+	s := &Schedule{
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}
+	for _, f := range []struct {
+		field  string
+		bounds fieldBounds
+		bits   *uint64
+	}{
+		{fields[0], minuteBounds, &s.minute},
+		{fields[1], hourBounds, &s.hour},
+		{fields[2], dayOfMonthBounds, &s.dayOfMonth},
+		{fields[3], monthBounds, &s.month},
+		{fields[4], dayOfWeekBounds, &s.dayOfWeek},
+	} {
+		if *f.bits, err = parseField(f.field, f.bounds); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7 is an alias for Sunday
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+
+	return s, nil
+}
+
+// parseField parses a comma separated list of ranges
+// and returns a bitset of all the values in them.
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, rangeSpec := range strings.Split(field, ",") {
+		rangeBits, err := parseRange(rangeSpec, bounds)
+		if err != nil {
+			return 0, err
+		}
+		bits |= rangeBits
+	}
+	return bits, nil
+}
+
+// parseRange parses a range of form '*', 'a', 'a-b'
+// optionally followed by a step '/n' and returns
+// a bitset of all the values in the range.
+func parseRange(rangeSpec string, bounds fieldBounds) (uint64, error) {
+	start, end, step := bounds.min, bounds.max, uint(1)
+
+	rangeAndStep := strings.SplitN(rangeSpec, "/", 2)
+	if len(rangeAndStep) == 2 {
+		var err error
+		if step, err = parseValue(rangeAndStep[1], fieldBounds{name: bounds.name + " step", min: 1, max: bounds.max}); err != nil {
+			return 0, err
+		}
+	}
+
+	if rangeAndStep[0] != "*" {
+		startAndEnd := strings.SplitN(rangeAndStep[0], "-", 2)
+		var err error
+		if start, err = parseValue(startAndEnd[0], bounds); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case len(startAndEnd) == 2:
+			if end, err = parseValue(startAndEnd[1], bounds); err != nil {
+				return 0, err
+			}
+		case len(rangeAndStep) == 1:
+			// A single value
+			end = start
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid %s range %q", bounds.name, rangeSpec)
+		}
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+// parseValue parses a number or a name and validates it against the bounds
+func parseValue(value string, bounds fieldBounds) (uint, error) {
+	if v, exists := bounds.names[strings.ToLower(value)]; exists {
+		return v, nil
+	}
+
+	v, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", bounds.name, value)
+	}
+
+	if uint(v) < bounds.min || uint(v) > bounds.max {
+		return 0, fmt.Errorf("%s value %d out of range [%d-%d]", bounds.name, v, bounds.min, bounds.max)
+	}
+
+	return uint(v), nil
+}
+
+// Next returns the first activation time of the schedule
+// that is later than the given time. A zero time is returned
+// if the schedule cannot be satisfied, for example 30th February.
+func (s *Schedule) Next(t time.Time) time.Time {
+	// Start from the next whole minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Give up if nothing matches within the next 5 years
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			// Move to the start of the next month
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			// Move to the start of the next day
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			// Move to the start of the next hour. The minutes are reset
+			// via time.Date as truncating to the hour is done in absolute
+			// time and is off for locations with sub-hour UTC offsets.
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches returns true if the day of the given time matches the schedule
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.dayOfMonthStar || s.dayOfWeekStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	parseTime := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("failed to parse time %q : %s", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		schedule string
+		from     string
+		expected string
+	}{
+		{"* * * * *", "2024-01-01T10:00:30Z", "2024-01-01T10:01:00Z"},
+		{"*/15 * * * *", "2024-01-01T10:00:00Z", "2024-01-01T10:15:00Z"},
+		{"30 2 * * *", "2024-01-01T10:00:00Z", "2024-01-02T02:30:00Z"},
+		{"@hourly", "2024-01-01T10:59:59Z", "2024-01-01T11:00:00Z"},
+		{"@daily", "2024-12-31T23:00:00Z", "2025-01-01T00:00:00Z"},
+		{"@weekly", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"0 0 * * 7", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"0 12 * * mon-fri", "2024-01-05T13:00:00Z", "2024-01-08T12:00:00Z"},
+		{"0 0 1,15 * *", "2024-01-02T00:00:00Z", "2024-01-15T00:00:00Z"},
+		{"0 0 29 feb *", "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		// Either the day of month or the day of week has to match
+		{"0 0 13 * 5", "2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z"},
+		{"0 0 5-20/5 * *", "2024-01-06T00:00:00Z", "2024-01-10T00:00:00Z"},
+		// Sub-hour schedules in locations with sub-hour UTC offsets
+		{"15 12 * * *", "2024-01-01T10:50:00+05:30", "2024-01-01T12:15:00+05:30"},
+		{"*/20 * * * *", "2024-01-01T10:50:30+05:45", "2024-01-01T11:00:00+05:45"},
+		{"45 * * * *", "2024-01-01T10:50:00+09:30", "2024-01-01T11:45:00+09:30"},
+		// Impossible schedule
+		{"0 0 30 2 *", "2024-01-01T00:00:00Z", "0001-01-01T00:00:00Z"},
+	}
+
+	for _, tc := range tests {
+		schedule, err := Parse(tc.schedule)
+		if err != nil {
+			t.Errorf("Failed to parse schedule %q : %s", tc.schedule, err)
+			continue
+		}
+
+		next := schedule.Next(parseTime(tc.from))
+		if !next.Equal(parseTime(tc.expected)) {
+			t.Errorf("Schedule %q from %s : expected %s but got %s",
+				tc.schedule, tc.from, tc.expected, next.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every5m",
+	} {
+		if _, err := Parse(schedule); err == nil {
+			t.Errorf("Expected schedule %q to fail parsing", schedule)
+		}
+	}
+}
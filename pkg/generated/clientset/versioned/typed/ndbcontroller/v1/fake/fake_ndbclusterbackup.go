@@ -0,0 +1,130 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNdbClusterBackups implements NdbClusterBackupInterface
+type FakeNdbClusterBackups struct {
+	Fake *FakeMysqlV1
+	ns   string
+}
+
+var ndbclusterbackupsResource = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v1", Resource: "ndbclusterbackups"}
+
+var ndbclusterbackupsKind = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v1", Kind: "NdbClusterBackup"}
+
+// Get takes name of the ndbClusterBackup, and returns the corresponding ndbClusterBackup object, and an error if there is any.
+func (c *FakeNdbClusterBackups) Get(ctx context.Context, name string, options v1.GetOptions) (result *ndbcontrollerv1.NdbClusterBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(ndbclusterbackupsResource, c.ns, name), &ndbcontrollerv1.NdbClusterBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackup), err
+}
+
+// List takes label and field selectors, and returns the list of NdbClusterBackups that match those selectors.
+func (c *FakeNdbClusterBackups) List(ctx context.Context, opts v1.ListOptions) (result *ndbcontrollerv1.NdbClusterBackupList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(ndbclusterbackupsResource, ndbclusterbackupsKind, c.ns, opts), &ndbcontrollerv1.NdbClusterBackupList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &ndbcontrollerv1.NdbClusterBackupList{ListMeta: obj.(*ndbcontrollerv1.NdbClusterBackupList).ListMeta}
+	for _, item := range obj.(*ndbcontrollerv1.NdbClusterBackupList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested ndbClusterBackups.
+func (c *FakeNdbClusterBackups) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(ndbclusterbackupsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a ndbClusterBackup and creates it.  Returns the server's representation of the ndbClusterBackup, and an error, if there is any.
+func (c *FakeNdbClusterBackups) Create(ctx context.Context, ndbClusterBackup *ndbcontrollerv1.NdbClusterBackup, opts v1.CreateOptions) (result *ndbcontrollerv1.NdbClusterBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(ndbclusterbackupsResource, c.ns, ndbClusterBackup), &ndbcontrollerv1.NdbClusterBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackup), err
+}
+
+// Update takes the representation of a ndbClusterBackup and updates it. Returns the server's representation of the ndbClusterBackup, and an error, if there is any.
+func (c *FakeNdbClusterBackups) Update(ctx context.Context, ndbClusterBackup *ndbcontrollerv1.NdbClusterBackup, opts v1.UpdateOptions) (result *ndbcontrollerv1.NdbClusterBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(ndbclusterbackupsResource, c.ns, ndbClusterBackup), &ndbcontrollerv1.NdbClusterBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackup), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNdbClusterBackups) UpdateStatus(ctx context.Context, ndbClusterBackup *ndbcontrollerv1.NdbClusterBackup, opts v1.UpdateOptions) (*ndbcontrollerv1.NdbClusterBackup, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ndbclusterbackupsResource, "status", c.ns, ndbClusterBackup), &ndbcontrollerv1.NdbClusterBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackup), err
+}
+
+// Delete takes name of the ndbClusterBackup and deletes it. Returns an error if one occurs.
+func (c *FakeNdbClusterBackups) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(ndbclusterbackupsResource, c.ns, name), &ndbcontrollerv1.NdbClusterBackup{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNdbClusterBackups) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(ndbclusterbackupsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &ndbcontrollerv1.NdbClusterBackupList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched ndbClusterBackup.
+func (c *FakeNdbClusterBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *ndbcontrollerv1.NdbClusterBackup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(ndbclusterbackupsResource, c.ns, name, pt, data, subresources...), &ndbcontrollerv1.NdbClusterBackup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackup), err
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNdbClusterBackupSchedules implements NdbClusterBackupScheduleInterface
+type FakeNdbClusterBackupSchedules struct {
+	Fake *FakeMysqlV1
+	ns   string
+}
+
+var ndbclusterbackupschedulesResource = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v1", Resource: "ndbclusterbackupschedules"}
+
+var ndbclusterbackupschedulesKind = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v1", Kind: "NdbClusterBackupSchedule"}
+
+// Get takes name of the ndbClusterBackupSchedule, and returns the corresponding ndbClusterBackupSchedule object, and an error if there is any.
+func (c *FakeNdbClusterBackupSchedules) Get(ctx context.Context, name string, options v1.GetOptions) (result *ndbcontrollerv1.NdbClusterBackupSchedule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(ndbclusterbackupschedulesResource, c.ns, name), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackupSchedule), err
+}
+
+// List takes label and field selectors, and returns the list of NdbClusterBackupSchedules that match those selectors.
+func (c *FakeNdbClusterBackupSchedules) List(ctx context.Context, opts v1.ListOptions) (result *ndbcontrollerv1.NdbClusterBackupScheduleList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(ndbclusterbackupschedulesResource, ndbclusterbackupschedulesKind, c.ns, opts), &ndbcontrollerv1.NdbClusterBackupScheduleList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &ndbcontrollerv1.NdbClusterBackupScheduleList{ListMeta: obj.(*ndbcontrollerv1.NdbClusterBackupScheduleList).ListMeta}
+	for _, item := range obj.(*ndbcontrollerv1.NdbClusterBackupScheduleList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested ndbClusterBackupSchedules.
+func (c *FakeNdbClusterBackupSchedules) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(ndbclusterbackupschedulesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a ndbClusterBackupSchedule and creates it.  Returns the server's representation of the ndbClusterBackupSchedule, and an error, if there is any.
+func (c *FakeNdbClusterBackupSchedules) Create(ctx context.Context, ndbClusterBackupSchedule *ndbcontrollerv1.NdbClusterBackupSchedule, opts v1.CreateOptions) (result *ndbcontrollerv1.NdbClusterBackupSchedule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(ndbclusterbackupschedulesResource, c.ns, ndbClusterBackupSchedule), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackupSchedule), err
+}
+
+// Update takes the representation of a ndbClusterBackupSchedule and updates it. Returns the server's representation of the ndbClusterBackupSchedule, and an error, if there is any.
+func (c *FakeNdbClusterBackupSchedules) Update(ctx context.Context, ndbClusterBackupSchedule *ndbcontrollerv1.NdbClusterBackupSchedule, opts v1.UpdateOptions) (result *ndbcontrollerv1.NdbClusterBackupSchedule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(ndbclusterbackupschedulesResource, c.ns, ndbClusterBackupSchedule), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackupSchedule), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNdbClusterBackupSchedules) UpdateStatus(ctx context.Context, ndbClusterBackupSchedule *ndbcontrollerv1.NdbClusterBackupSchedule, opts v1.UpdateOptions) (*ndbcontrollerv1.NdbClusterBackupSchedule, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ndbclusterbackupschedulesResource, "status", c.ns, ndbClusterBackupSchedule), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackupSchedule), err
+}
+
+// Delete takes name of the ndbClusterBackupSchedule and deletes it. Returns an error if one occurs.
+func (c *FakeNdbClusterBackupSchedules) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(ndbclusterbackupschedulesResource, c.ns, name), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNdbClusterBackupSchedules) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(ndbclusterbackupschedulesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &ndbcontrollerv1.NdbClusterBackupScheduleList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched ndbClusterBackupSchedule.
+func (c *FakeNdbClusterBackupSchedules) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *ndbcontrollerv1.NdbClusterBackupSchedule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(ndbclusterbackupschedulesResource, c.ns, name, pt, data, subresources...), &ndbcontrollerv1.NdbClusterBackupSchedule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbClusterBackupSchedule), err
+}
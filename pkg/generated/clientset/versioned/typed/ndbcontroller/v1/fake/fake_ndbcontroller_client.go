@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -20,6 +20,26 @@ func (c *FakeMysqlV1) NdbClusters(namespace string) v1.NdbClusterInterface {
 	return &FakeNdbClusters{c, namespace}
 }
 
+func (c *FakeMysqlV1) NdbClusterBackups(namespace string) v1.NdbClusterBackupInterface {
+	return &FakeNdbClusterBackups{c, namespace}
+}
+
+func (c *FakeMysqlV1) NdbClusterBackupSchedules(namespace string) v1.NdbClusterBackupScheduleInterface {
+	return &FakeNdbClusterBackupSchedules{c, namespace}
+}
+
+func (c *FakeMysqlV1) NdbMySQLDatabases(namespace string) v1.NdbMySQLDatabaseInterface {
+	return &FakeNdbMySQLDatabases{c, namespace}
+}
+
+func (c *FakeMysqlV1) NdbMySQLUsers(namespace string) v1.NdbMySQLUserInterface {
+	return &FakeNdbMySQLUsers{c, namespace}
+}
+
+func (c *FakeMysqlV1) NdbReplicationChannels(namespace string) v1.NdbReplicationChannelInterface {
+	return &FakeNdbReplicationChannels{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeMysqlV1) RESTClient() rest.Interface {
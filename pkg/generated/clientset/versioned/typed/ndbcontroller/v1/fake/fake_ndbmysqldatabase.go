@@ -0,0 +1,130 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNdbMySQLDatabases implements NdbMySQLDatabaseInterface
+type FakeNdbMySQLDatabases struct {
+	Fake *FakeMysqlV1
+	ns   string
+}
+
+var ndbmysqldatabasesResource = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v1", Resource: "ndbmysqldatabases"}
+
+var ndbmysqldatabasesKind = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v1", Kind: "NdbMySQLDatabase"}
+
+// Get takes name of the ndbMySQLDatabase, and returns the corresponding ndbMySQLDatabase object, and an error if there is any.
+func (c *FakeNdbMySQLDatabases) Get(ctx context.Context, name string, options v1.GetOptions) (result *ndbcontrollerv1.NdbMySQLDatabase, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(ndbmysqldatabasesResource, c.ns, name), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLDatabase), err
+}
+
+// List takes label and field selectors, and returns the list of NdbMySQLDatabases that match those selectors.
+func (c *FakeNdbMySQLDatabases) List(ctx context.Context, opts v1.ListOptions) (result *ndbcontrollerv1.NdbMySQLDatabaseList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(ndbmysqldatabasesResource, ndbmysqldatabasesKind, c.ns, opts), &ndbcontrollerv1.NdbMySQLDatabaseList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &ndbcontrollerv1.NdbMySQLDatabaseList{ListMeta: obj.(*ndbcontrollerv1.NdbMySQLDatabaseList).ListMeta}
+	for _, item := range obj.(*ndbcontrollerv1.NdbMySQLDatabaseList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested ndbMySQLDatabases.
+func (c *FakeNdbMySQLDatabases) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(ndbmysqldatabasesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a ndbMySQLDatabase and creates it.  Returns the server's representation of the ndbMySQLDatabase, and an error, if there is any.
+func (c *FakeNdbMySQLDatabases) Create(ctx context.Context, ndbMySQLDatabase *ndbcontrollerv1.NdbMySQLDatabase, opts v1.CreateOptions) (result *ndbcontrollerv1.NdbMySQLDatabase, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(ndbmysqldatabasesResource, c.ns, ndbMySQLDatabase), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLDatabase), err
+}
+
+// Update takes the representation of a ndbMySQLDatabase and updates it. Returns the server's representation of the ndbMySQLDatabase, and an error, if there is any.
+func (c *FakeNdbMySQLDatabases) Update(ctx context.Context, ndbMySQLDatabase *ndbcontrollerv1.NdbMySQLDatabase, opts v1.UpdateOptions) (result *ndbcontrollerv1.NdbMySQLDatabase, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(ndbmysqldatabasesResource, c.ns, ndbMySQLDatabase), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLDatabase), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNdbMySQLDatabases) UpdateStatus(ctx context.Context, ndbMySQLDatabase *ndbcontrollerv1.NdbMySQLDatabase, opts v1.UpdateOptions) (*ndbcontrollerv1.NdbMySQLDatabase, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ndbmysqldatabasesResource, "status", c.ns, ndbMySQLDatabase), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLDatabase), err
+}
+
+// Delete takes name of the ndbMySQLDatabase and deletes it. Returns an error if one occurs.
+func (c *FakeNdbMySQLDatabases) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(ndbmysqldatabasesResource, c.ns, name), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNdbMySQLDatabases) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(ndbmysqldatabasesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &ndbcontrollerv1.NdbMySQLDatabaseList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched ndbMySQLDatabase.
+func (c *FakeNdbMySQLDatabases) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *ndbcontrollerv1.NdbMySQLDatabase, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(ndbmysqldatabasesResource, c.ns, name, pt, data, subresources...), &ndbcontrollerv1.NdbMySQLDatabase{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLDatabase), err
+}
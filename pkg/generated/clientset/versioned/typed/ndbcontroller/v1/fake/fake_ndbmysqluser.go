@@ -0,0 +1,130 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNdbMySQLUsers implements NdbMySQLUserInterface
+type FakeNdbMySQLUsers struct {
+	Fake *FakeMysqlV1
+	ns   string
+}
+
+var ndbmysqlusersResource = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v1", Resource: "ndbmysqlusers"}
+
+var ndbmysqlusersKind = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v1", Kind: "NdbMySQLUser"}
+
+// Get takes name of the ndbMySQLUser, and returns the corresponding ndbMySQLUser object, and an error if there is any.
+func (c *FakeNdbMySQLUsers) Get(ctx context.Context, name string, options v1.GetOptions) (result *ndbcontrollerv1.NdbMySQLUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(ndbmysqlusersResource, c.ns, name), &ndbcontrollerv1.NdbMySQLUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLUser), err
+}
+
+// List takes label and field selectors, and returns the list of NdbMySQLUsers that match those selectors.
+func (c *FakeNdbMySQLUsers) List(ctx context.Context, opts v1.ListOptions) (result *ndbcontrollerv1.NdbMySQLUserList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(ndbmysqlusersResource, ndbmysqlusersKind, c.ns, opts), &ndbcontrollerv1.NdbMySQLUserList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &ndbcontrollerv1.NdbMySQLUserList{ListMeta: obj.(*ndbcontrollerv1.NdbMySQLUserList).ListMeta}
+	for _, item := range obj.(*ndbcontrollerv1.NdbMySQLUserList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested ndbMySQLUsers.
+func (c *FakeNdbMySQLUsers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(ndbmysqlusersResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a ndbMySQLUser and creates it.  Returns the server's representation of the ndbMySQLUser, and an error, if there is any.
+func (c *FakeNdbMySQLUsers) Create(ctx context.Context, ndbMySQLUser *ndbcontrollerv1.NdbMySQLUser, opts v1.CreateOptions) (result *ndbcontrollerv1.NdbMySQLUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(ndbmysqlusersResource, c.ns, ndbMySQLUser), &ndbcontrollerv1.NdbMySQLUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLUser), err
+}
+
+// Update takes the representation of a ndbMySQLUser and updates it. Returns the server's representation of the ndbMySQLUser, and an error, if there is any.
+func (c *FakeNdbMySQLUsers) Update(ctx context.Context, ndbMySQLUser *ndbcontrollerv1.NdbMySQLUser, opts v1.UpdateOptions) (result *ndbcontrollerv1.NdbMySQLUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(ndbmysqlusersResource, c.ns, ndbMySQLUser), &ndbcontrollerv1.NdbMySQLUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLUser), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNdbMySQLUsers) UpdateStatus(ctx context.Context, ndbMySQLUser *ndbcontrollerv1.NdbMySQLUser, opts v1.UpdateOptions) (*ndbcontrollerv1.NdbMySQLUser, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ndbmysqlusersResource, "status", c.ns, ndbMySQLUser), &ndbcontrollerv1.NdbMySQLUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLUser), err
+}
+
+// Delete takes name of the ndbMySQLUser and deletes it. Returns an error if one occurs.
+func (c *FakeNdbMySQLUsers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(ndbmysqlusersResource, c.ns, name), &ndbcontrollerv1.NdbMySQLUser{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNdbMySQLUsers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(ndbmysqlusersResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &ndbcontrollerv1.NdbMySQLUserList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched ndbMySQLUser.
+func (c *FakeNdbMySQLUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *ndbcontrollerv1.NdbMySQLUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(ndbmysqlusersResource, c.ns, name, pt, data, subresources...), &ndbcontrollerv1.NdbMySQLUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbMySQLUser), err
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNdbReplicationChannels implements NdbReplicationChannelInterface
+type FakeNdbReplicationChannels struct {
+	Fake *FakeMysqlV1
+	ns   string
+}
+
+var ndbreplicationchannelsResource = schema.GroupVersionResource{Group: "mysql.oracle.com", Version: "v1", Resource: "ndbreplicationchannels"}
+
+var ndbreplicationchannelsKind = schema.GroupVersionKind{Group: "mysql.oracle.com", Version: "v1", Kind: "NdbReplicationChannel"}
+
+// Get takes name of the ndbReplicationChannel, and returns the corresponding ndbReplicationChannel object, and an error if there is any.
+func (c *FakeNdbReplicationChannels) Get(ctx context.Context, name string, options v1.GetOptions) (result *ndbcontrollerv1.NdbReplicationChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(ndbreplicationchannelsResource, c.ns, name), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbReplicationChannel), err
+}
+
+// List takes label and field selectors, and returns the list of NdbReplicationChannels that match those selectors.
+func (c *FakeNdbReplicationChannels) List(ctx context.Context, opts v1.ListOptions) (result *ndbcontrollerv1.NdbReplicationChannelList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(ndbreplicationchannelsResource, ndbreplicationchannelsKind, c.ns, opts), &ndbcontrollerv1.NdbReplicationChannelList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &ndbcontrollerv1.NdbReplicationChannelList{ListMeta: obj.(*ndbcontrollerv1.NdbReplicationChannelList).ListMeta}
+	for _, item := range obj.(*ndbcontrollerv1.NdbReplicationChannelList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested ndbReplicationChannels.
+func (c *FakeNdbReplicationChannels) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(ndbreplicationchannelsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a ndbReplicationChannel and creates it.  Returns the server's representation of the ndbReplicationChannel, and an error, if there is any.
+func (c *FakeNdbReplicationChannels) Create(ctx context.Context, ndbReplicationChannel *ndbcontrollerv1.NdbReplicationChannel, opts v1.CreateOptions) (result *ndbcontrollerv1.NdbReplicationChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(ndbreplicationchannelsResource, c.ns, ndbReplicationChannel), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbReplicationChannel), err
+}
+
+// Update takes the representation of a ndbReplicationChannel and updates it. Returns the server's representation of the ndbReplicationChannel, and an error, if there is any.
+func (c *FakeNdbReplicationChannels) Update(ctx context.Context, ndbReplicationChannel *ndbcontrollerv1.NdbReplicationChannel, opts v1.UpdateOptions) (result *ndbcontrollerv1.NdbReplicationChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(ndbreplicationchannelsResource, c.ns, ndbReplicationChannel), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbReplicationChannel), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNdbReplicationChannels) UpdateStatus(ctx context.Context, ndbReplicationChannel *ndbcontrollerv1.NdbReplicationChannel, opts v1.UpdateOptions) (*ndbcontrollerv1.NdbReplicationChannel, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ndbreplicationchannelsResource, "status", c.ns, ndbReplicationChannel), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbReplicationChannel), err
+}
+
+// Delete takes name of the ndbReplicationChannel and deletes it. Returns an error if one occurs.
+func (c *FakeNdbReplicationChannels) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(ndbreplicationchannelsResource, c.ns, name), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNdbReplicationChannels) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(ndbreplicationchannelsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &ndbcontrollerv1.NdbReplicationChannelList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched ndbReplicationChannel.
+func (c *FakeNdbReplicationChannels) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *ndbcontrollerv1.NdbReplicationChannel, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(ndbreplicationchannelsResource, c.ns, name, pt, data, subresources...), &ndbcontrollerv1.NdbReplicationChannel{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*ndbcontrollerv1.NdbReplicationChannel), err
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	scheme "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NdbClusterBackupsGetter has a method to return a NdbClusterBackupInterface.
+// A group's client should implement this interface.
+type NdbClusterBackupsGetter interface {
+	NdbClusterBackups(namespace string) NdbClusterBackupInterface
+}
+
+// NdbClusterBackupInterface has methods to work with NdbClusterBackup resources.
+type NdbClusterBackupInterface interface {
+	Create(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.CreateOptions) (*v1.NdbClusterBackup, error)
+	Update(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.UpdateOptions) (*v1.NdbClusterBackup, error)
+	UpdateStatus(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.UpdateOptions) (*v1.NdbClusterBackup, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NdbClusterBackup, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NdbClusterBackupList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbClusterBackup, err error)
+	NdbClusterBackupExpansion
+}
+
+// ndbClusterBackups implements NdbClusterBackupInterface
+type ndbClusterBackups struct {
+	client rest.Interface
+	ns     string
+}
+
+// newNdbClusterBackups returns a NdbClusterBackups
+func newNdbClusterBackups(c *MysqlV1Client, namespace string) *ndbClusterBackups {
+	return &ndbClusterBackups{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the ndbClusterBackup, and returns the corresponding ndbClusterBackup object, and an error if there is any.
+func (c *ndbClusterBackups) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NdbClusterBackup, err error) {
+	result = &v1.NdbClusterBackup{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NdbClusterBackups that match those selectors.
+func (c *ndbClusterBackups) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NdbClusterBackupList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.NdbClusterBackupList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested ndbClusterBackups.
+func (c *ndbClusterBackups) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a ndbClusterBackup and creates it.  Returns the server's representation of the ndbClusterBackup, and an error, if there is any.
+func (c *ndbClusterBackups) Create(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.CreateOptions) (result *v1.NdbClusterBackup, err error) {
+	result = &v1.NdbClusterBackup{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a ndbClusterBackup and updates it. Returns the server's representation of the ndbClusterBackup, and an error, if there is any.
+func (c *ndbClusterBackups) Update(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.UpdateOptions) (result *v1.NdbClusterBackup, err error) {
+	result = &v1.NdbClusterBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		Name(ndbClusterBackup.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *ndbClusterBackups) UpdateStatus(ctx context.Context, ndbClusterBackup *v1.NdbClusterBackup, opts metav1.UpdateOptions) (result *v1.NdbClusterBackup, err error) {
+	result = &v1.NdbClusterBackup{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		Name(ndbClusterBackup.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackup).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the ndbClusterBackup and deletes it. Returns an error if one occurs.
+func (c *ndbClusterBackups) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *ndbClusterBackups) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched ndbClusterBackup.
+func (c *ndbClusterBackups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbClusterBackup, err error) {
+	result = &v1.NdbClusterBackup{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("ndbclusterbackups").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	scheme "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NdbClusterBackupSchedulesGetter has a method to return a NdbClusterBackupScheduleInterface.
+// A group's client should implement this interface.
+type NdbClusterBackupSchedulesGetter interface {
+	NdbClusterBackupSchedules(namespace string) NdbClusterBackupScheduleInterface
+}
+
+// NdbClusterBackupScheduleInterface has methods to work with NdbClusterBackupSchedule resources.
+type NdbClusterBackupScheduleInterface interface {
+	Create(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.CreateOptions) (*v1.NdbClusterBackupSchedule, error)
+	Update(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.UpdateOptions) (*v1.NdbClusterBackupSchedule, error)
+	UpdateStatus(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.UpdateOptions) (*v1.NdbClusterBackupSchedule, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NdbClusterBackupSchedule, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NdbClusterBackupScheduleList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbClusterBackupSchedule, err error)
+	NdbClusterBackupScheduleExpansion
+}
+
+// ndbClusterBackupSchedules implements NdbClusterBackupScheduleInterface
+type ndbClusterBackupSchedules struct {
+	client rest.Interface
+	ns     string
+}
+
+// newNdbClusterBackupSchedules returns a NdbClusterBackupSchedules
+func newNdbClusterBackupSchedules(c *MysqlV1Client, namespace string) *ndbClusterBackupSchedules {
+	return &ndbClusterBackupSchedules{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the ndbClusterBackupSchedule, and returns the corresponding ndbClusterBackupSchedule object, and an error if there is any.
+func (c *ndbClusterBackupSchedules) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NdbClusterBackupSchedule, err error) {
+	result = &v1.NdbClusterBackupSchedule{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NdbClusterBackupSchedules that match those selectors.
+func (c *ndbClusterBackupSchedules) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NdbClusterBackupScheduleList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.NdbClusterBackupScheduleList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested ndbClusterBackupSchedules.
+func (c *ndbClusterBackupSchedules) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a ndbClusterBackupSchedule and creates it.  Returns the server's representation of the ndbClusterBackupSchedule, and an error, if there is any.
+func (c *ndbClusterBackupSchedules) Create(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.CreateOptions) (result *v1.NdbClusterBackupSchedule, err error) {
+	result = &v1.NdbClusterBackupSchedule{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackupSchedule).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a ndbClusterBackupSchedule and updates it. Returns the server's representation of the ndbClusterBackupSchedule, and an error, if there is any.
+func (c *ndbClusterBackupSchedules) Update(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.UpdateOptions) (result *v1.NdbClusterBackupSchedule, err error) {
+	result = &v1.NdbClusterBackupSchedule{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		Name(ndbClusterBackupSchedule.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackupSchedule).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *ndbClusterBackupSchedules) UpdateStatus(ctx context.Context, ndbClusterBackupSchedule *v1.NdbClusterBackupSchedule, opts metav1.UpdateOptions) (result *v1.NdbClusterBackupSchedule, err error) {
+	result = &v1.NdbClusterBackupSchedule{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		Name(ndbClusterBackupSchedule.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbClusterBackupSchedule).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the ndbClusterBackupSchedule and deletes it. Returns an error if one occurs.
+func (c *ndbClusterBackupSchedules) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *ndbClusterBackupSchedules) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched ndbClusterBackupSchedule.
+func (c *ndbClusterBackupSchedules) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbClusterBackupSchedule, err error) {
+	result = &v1.NdbClusterBackupSchedule{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("ndbclusterbackupschedules").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
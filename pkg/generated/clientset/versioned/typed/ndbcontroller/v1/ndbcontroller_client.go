@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -15,6 +15,11 @@ import (
 type MysqlV1Interface interface {
 	RESTClient() rest.Interface
 	NdbClustersGetter
+	NdbClusterBackupsGetter
+	NdbClusterBackupSchedulesGetter
+	NdbMySQLDatabasesGetter
+	NdbMySQLUsersGetter
+	NdbReplicationChannelsGetter
 }
 
 // MysqlV1Client is used to interact with features provided by the mysql.oracle.com group.
@@ -26,6 +31,26 @@ func (c *MysqlV1Client) NdbClusters(namespace string) NdbClusterInterface {
 	return newNdbClusters(c, namespace)
 }
 
+func (c *MysqlV1Client) NdbClusterBackups(namespace string) NdbClusterBackupInterface {
+	return newNdbClusterBackups(c, namespace)
+}
+
+func (c *MysqlV1Client) NdbClusterBackupSchedules(namespace string) NdbClusterBackupScheduleInterface {
+	return newNdbClusterBackupSchedules(c, namespace)
+}
+
+func (c *MysqlV1Client) NdbMySQLDatabases(namespace string) NdbMySQLDatabaseInterface {
+	return newNdbMySQLDatabases(c, namespace)
+}
+
+func (c *MysqlV1Client) NdbMySQLUsers(namespace string) NdbMySQLUserInterface {
+	return newNdbMySQLUsers(c, namespace)
+}
+
+func (c *MysqlV1Client) NdbReplicationChannels(namespace string) NdbReplicationChannelInterface {
+	return newNdbReplicationChannels(c, namespace)
+}
+
 // NewForConfig creates a new MysqlV1Client for the given config.
 func NewForConfig(c *rest.Config) (*MysqlV1Client, error) {
 	config := *c
@@ -0,0 +1,183 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	scheme "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NdbMySQLDatabasesGetter has a method to return a NdbMySQLDatabaseInterface.
+// A group's client should implement this interface.
+type NdbMySQLDatabasesGetter interface {
+	NdbMySQLDatabases(namespace string) NdbMySQLDatabaseInterface
+}
+
+// NdbMySQLDatabaseInterface has methods to work with NdbMySQLDatabase resources.
+type NdbMySQLDatabaseInterface interface {
+	Create(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.CreateOptions) (*v1.NdbMySQLDatabase, error)
+	Update(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.UpdateOptions) (*v1.NdbMySQLDatabase, error)
+	UpdateStatus(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.UpdateOptions) (*v1.NdbMySQLDatabase, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NdbMySQLDatabase, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NdbMySQLDatabaseList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbMySQLDatabase, err error)
+	NdbMySQLDatabaseExpansion
+}
+
+// ndbMySQLDatabases implements NdbMySQLDatabaseInterface
+type ndbMySQLDatabases struct {
+	client rest.Interface
+	ns     string
+}
+
+// newNdbMySQLDatabases returns a NdbMySQLDatabases
+func newNdbMySQLDatabases(c *MysqlV1Client, namespace string) *ndbMySQLDatabases {
+	return &ndbMySQLDatabases{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the ndbMySQLDatabase, and returns the corresponding ndbMySQLDatabase object, and an error if there is any.
+func (c *ndbMySQLDatabases) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NdbMySQLDatabase, err error) {
+	result = &v1.NdbMySQLDatabase{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NdbMySQLDatabases that match those selectors.
+func (c *ndbMySQLDatabases) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NdbMySQLDatabaseList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.NdbMySQLDatabaseList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested ndbMySQLDatabases.
+func (c *ndbMySQLDatabases) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a ndbMySQLDatabase and creates it.  Returns the server's representation of the ndbMySQLDatabase, and an error, if there is any.
+func (c *ndbMySQLDatabases) Create(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.CreateOptions) (result *v1.NdbMySQLDatabase, err error) {
+	result = &v1.NdbMySQLDatabase{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLDatabase).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a ndbMySQLDatabase and updates it. Returns the server's representation of the ndbMySQLDatabase, and an error, if there is any.
+func (c *ndbMySQLDatabases) Update(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.UpdateOptions) (result *v1.NdbMySQLDatabase, err error) {
+	result = &v1.NdbMySQLDatabase{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		Name(ndbMySQLDatabase.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLDatabase).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *ndbMySQLDatabases) UpdateStatus(ctx context.Context, ndbMySQLDatabase *v1.NdbMySQLDatabase, opts metav1.UpdateOptions) (result *v1.NdbMySQLDatabase, err error) {
+	result = &v1.NdbMySQLDatabase{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		Name(ndbMySQLDatabase.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLDatabase).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the ndbMySQLDatabase and deletes it. Returns an error if one occurs.
+func (c *ndbMySQLDatabases) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *ndbMySQLDatabases) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched ndbMySQLDatabase.
+func (c *ndbMySQLDatabases) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbMySQLDatabase, err error) {
+	result = &v1.NdbMySQLDatabase{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("ndbmysqldatabases").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	scheme "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NdbMySQLUsersGetter has a method to return a NdbMySQLUserInterface.
+// A group's client should implement this interface.
+type NdbMySQLUsersGetter interface {
+	NdbMySQLUsers(namespace string) NdbMySQLUserInterface
+}
+
+// NdbMySQLUserInterface has methods to work with NdbMySQLUser resources.
+type NdbMySQLUserInterface interface {
+	Create(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.CreateOptions) (*v1.NdbMySQLUser, error)
+	Update(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.UpdateOptions) (*v1.NdbMySQLUser, error)
+	UpdateStatus(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.UpdateOptions) (*v1.NdbMySQLUser, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NdbMySQLUser, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NdbMySQLUserList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbMySQLUser, err error)
+	NdbMySQLUserExpansion
+}
+
+// ndbMySQLUsers implements NdbMySQLUserInterface
+type ndbMySQLUsers struct {
+	client rest.Interface
+	ns     string
+}
+
+// newNdbMySQLUsers returns a NdbMySQLUsers
+func newNdbMySQLUsers(c *MysqlV1Client, namespace string) *ndbMySQLUsers {
+	return &ndbMySQLUsers{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the ndbMySQLUser, and returns the corresponding ndbMySQLUser object, and an error if there is any.
+func (c *ndbMySQLUsers) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NdbMySQLUser, err error) {
+	result = &v1.NdbMySQLUser{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NdbMySQLUsers that match those selectors.
+func (c *ndbMySQLUsers) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NdbMySQLUserList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.NdbMySQLUserList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested ndbMySQLUsers.
+func (c *ndbMySQLUsers) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a ndbMySQLUser and creates it.  Returns the server's representation of the ndbMySQLUser, and an error, if there is any.
+func (c *ndbMySQLUsers) Create(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.CreateOptions) (result *v1.NdbMySQLUser, err error) {
+	result = &v1.NdbMySQLUser{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a ndbMySQLUser and updates it. Returns the server's representation of the ndbMySQLUser, and an error, if there is any.
+func (c *ndbMySQLUsers) Update(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.UpdateOptions) (result *v1.NdbMySQLUser, err error) {
+	result = &v1.NdbMySQLUser{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		Name(ndbMySQLUser.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *ndbMySQLUsers) UpdateStatus(ctx context.Context, ndbMySQLUser *v1.NdbMySQLUser, opts metav1.UpdateOptions) (result *v1.NdbMySQLUser, err error) {
+	result = &v1.NdbMySQLUser{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		Name(ndbMySQLUser.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbMySQLUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the ndbMySQLUser and deletes it. Returns an error if one occurs.
+func (c *ndbMySQLUsers) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *ndbMySQLUsers) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched ndbMySQLUser.
+func (c *ndbMySQLUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbMySQLUser, err error) {
+	result = &v1.NdbMySQLUser{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("ndbmysqlusers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	scheme "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// NdbReplicationChannelsGetter has a method to return a NdbReplicationChannelInterface.
+// A group's client should implement this interface.
+type NdbReplicationChannelsGetter interface {
+	NdbReplicationChannels(namespace string) NdbReplicationChannelInterface
+}
+
+// NdbReplicationChannelInterface has methods to work with NdbReplicationChannel resources.
+type NdbReplicationChannelInterface interface {
+	Create(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.CreateOptions) (*v1.NdbReplicationChannel, error)
+	Update(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.UpdateOptions) (*v1.NdbReplicationChannel, error)
+	UpdateStatus(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.UpdateOptions) (*v1.NdbReplicationChannel, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.NdbReplicationChannel, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.NdbReplicationChannelList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbReplicationChannel, err error)
+	NdbReplicationChannelExpansion
+}
+
+// ndbReplicationChannels implements NdbReplicationChannelInterface
+type ndbReplicationChannels struct {
+	client rest.Interface
+	ns     string
+}
+
+// newNdbReplicationChannels returns a NdbReplicationChannels
+func newNdbReplicationChannels(c *MysqlV1Client, namespace string) *ndbReplicationChannels {
+	return &ndbReplicationChannels{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the ndbReplicationChannel, and returns the corresponding ndbReplicationChannel object, and an error if there is any.
+func (c *ndbReplicationChannels) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.NdbReplicationChannel, err error) {
+	result = &v1.NdbReplicationChannel{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of NdbReplicationChannels that match those selectors.
+func (c *ndbReplicationChannels) List(ctx context.Context, opts metav1.ListOptions) (result *v1.NdbReplicationChannelList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.NdbReplicationChannelList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested ndbReplicationChannels.
+func (c *ndbReplicationChannels) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a ndbReplicationChannel and creates it.  Returns the server's representation of the ndbReplicationChannel, and an error, if there is any.
+func (c *ndbReplicationChannels) Create(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.CreateOptions) (result *v1.NdbReplicationChannel, err error) {
+	result = &v1.NdbReplicationChannel{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbReplicationChannel).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a ndbReplicationChannel and updates it. Returns the server's representation of the ndbReplicationChannel, and an error, if there is any.
+func (c *ndbReplicationChannels) Update(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.UpdateOptions) (result *v1.NdbReplicationChannel, err error) {
+	result = &v1.NdbReplicationChannel{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		Name(ndbReplicationChannel.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbReplicationChannel).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *ndbReplicationChannels) UpdateStatus(ctx context.Context, ndbReplicationChannel *v1.NdbReplicationChannel, opts metav1.UpdateOptions) (result *v1.NdbReplicationChannel, err error) {
+	result = &v1.NdbReplicationChannel{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		Name(ndbReplicationChannel.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(ndbReplicationChannel).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the ndbReplicationChannel and deletes it. Returns an error if one occurs.
+func (c *ndbReplicationChannels) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *ndbReplicationChannels) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched ndbReplicationChannel.
+func (c *ndbReplicationChannels) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.NdbReplicationChannel, err error) {
+	result = &v1.NdbReplicationChannel{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("ndbreplicationchannels").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -43,6 +43,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=mysql.oracle.com, Version=v1
 	case v1.SchemeGroupVersion.WithResource("ndbclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbClusters().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbclusterbackups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbClusterBackups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbclusterbackupschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbClusterBackupSchedules().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbmysqldatabases"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbMySQLDatabases().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbmysqlusers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbMySQLUsers().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("ndbreplicationchannels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Mysql().V1().NdbReplicationChannels().Informer()}, nil
 
 	}
 
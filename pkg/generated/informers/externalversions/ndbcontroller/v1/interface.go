@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -14,6 +14,16 @@ import (
 type Interface interface {
 	// NdbClusters returns a NdbClusterInformer.
 	NdbClusters() NdbClusterInformer
+	// NdbClusterBackups returns a NdbClusterBackupInformer.
+	NdbClusterBackups() NdbClusterBackupInformer
+	// NdbClusterBackupSchedules returns a NdbClusterBackupScheduleInformer.
+	NdbClusterBackupSchedules() NdbClusterBackupScheduleInformer
+	// NdbMySQLDatabases returns a NdbMySQLDatabaseInformer.
+	NdbMySQLDatabases() NdbMySQLDatabaseInformer
+	// NdbMySQLUsers returns a NdbMySQLUserInformer.
+	NdbMySQLUsers() NdbMySQLUserInformer
+	// NdbReplicationChannels returns a NdbReplicationChannelInformer.
+	NdbReplicationChannels() NdbReplicationChannelInformer
 }
 
 type version struct {
@@ -31,3 +41,28 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) NdbClusters() NdbClusterInformer {
 	return &ndbClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// NdbClusterBackups returns a NdbClusterBackupInformer.
+func (v *version) NdbClusterBackups() NdbClusterBackupInformer {
+	return &ndbClusterBackupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NdbClusterBackupSchedules returns a NdbClusterBackupScheduleInformer.
+func (v *version) NdbClusterBackupSchedules() NdbClusterBackupScheduleInformer {
+	return &ndbClusterBackupScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NdbMySQLDatabases returns a NdbMySQLDatabaseInformer.
+func (v *version) NdbMySQLDatabases() NdbMySQLDatabaseInformer {
+	return &ndbMySQLDatabaseInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NdbMySQLUsers returns a NdbMySQLUserInformer.
+func (v *version) NdbMySQLUsers() NdbMySQLUserInformer {
+	return &ndbMySQLUserInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// NdbReplicationChannels returns a NdbReplicationChannelInformer.
+func (v *version) NdbReplicationChannels() NdbReplicationChannelInformer {
+	return &ndbReplicationChannelInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
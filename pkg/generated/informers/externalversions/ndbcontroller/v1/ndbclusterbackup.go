@@ -0,0 +1,78 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	versioned "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions/internalinterfaces"
+	v1 "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// NdbClusterBackupInformer provides access to a shared informer and lister for
+// NdbClusterBackups.
+type NdbClusterBackupInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.NdbClusterBackupLister
+}
+
+type ndbClusterBackupInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewNdbClusterBackupInformer constructs a new informer for NdbClusterBackup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewNdbClusterBackupInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredNdbClusterBackupInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredNdbClusterBackupInformer constructs a new informer for NdbClusterBackup type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredNdbClusterBackupInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MysqlV1().NdbClusterBackups(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MysqlV1().NdbClusterBackups(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&ndbcontrollerv1.NdbClusterBackup{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *ndbClusterBackupInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredNdbClusterBackupInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *ndbClusterBackupInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&ndbcontrollerv1.NdbClusterBackup{}, f.defaultInformer)
+}
+
+func (f *ndbClusterBackupInformer) Lister() v1.NdbClusterBackupLister {
+	return v1.NewNdbClusterBackupLister(f.Informer().GetIndexer())
+}
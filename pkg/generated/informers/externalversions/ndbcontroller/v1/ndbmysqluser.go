@@ -0,0 +1,78 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	ndbcontrollerv1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	versioned "github.com/mysql/ndb-operator/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/mysql/ndb-operator/pkg/generated/informers/externalversions/internalinterfaces"
+	v1 "github.com/mysql/ndb-operator/pkg/generated/listers/ndbcontroller/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// NdbMySQLUserInformer provides access to a shared informer and lister for
+// NdbMySQLUsers.
+type NdbMySQLUserInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.NdbMySQLUserLister
+}
+
+type ndbMySQLUserInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewNdbMySQLUserInformer constructs a new informer for NdbMySQLUser type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewNdbMySQLUserInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredNdbMySQLUserInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredNdbMySQLUserInformer constructs a new informer for NdbMySQLUser type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredNdbMySQLUserInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MysqlV1().NdbMySQLUsers(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.MysqlV1().NdbMySQLUsers(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&ndbcontrollerv1.NdbMySQLUser{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *ndbMySQLUserInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredNdbMySQLUserInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *ndbMySQLUserInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&ndbcontrollerv1.NdbMySQLUser{}, f.defaultInformer)
+}
+
+func (f *ndbMySQLUserInformer) Lister() v1.NdbMySQLUserLister {
+	return v1.NewNdbMySQLUserLister(f.Informer().GetIndexer())
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -13,3 +13,43 @@ type NdbClusterListerExpansion interface{}
 // NdbClusterNamespaceListerExpansion allows custom methods to be added to
 // NdbClusterNamespaceLister.
 type NdbClusterNamespaceListerExpansion interface{}
+
+// NdbClusterBackupListerExpansion allows custom methods to be added to
+// NdbClusterBackupLister.
+type NdbClusterBackupListerExpansion interface{}
+
+// NdbClusterBackupNamespaceListerExpansion allows custom methods to be added to
+// NdbClusterBackupNamespaceLister.
+type NdbClusterBackupNamespaceListerExpansion interface{}
+
+// NdbClusterBackupScheduleListerExpansion allows custom methods to be added to
+// NdbClusterBackupScheduleLister.
+type NdbClusterBackupScheduleListerExpansion interface{}
+
+// NdbClusterBackupScheduleNamespaceListerExpansion allows custom methods to be added to
+// NdbClusterBackupScheduleNamespaceLister.
+type NdbClusterBackupScheduleNamespaceListerExpansion interface{}
+
+// NdbMySQLDatabaseListerExpansion allows custom methods to be added to
+// NdbMySQLDatabaseLister.
+type NdbMySQLDatabaseListerExpansion interface{}
+
+// NdbMySQLDatabaseNamespaceListerExpansion allows custom methods to be added to
+// NdbMySQLDatabaseNamespaceLister.
+type NdbMySQLDatabaseNamespaceListerExpansion interface{}
+
+// NdbMySQLUserListerExpansion allows custom methods to be added to
+// NdbMySQLUserLister.
+type NdbMySQLUserListerExpansion interface{}
+
+// NdbMySQLUserNamespaceListerExpansion allows custom methods to be added to
+// NdbMySQLUserNamespaceLister.
+type NdbMySQLUserNamespaceListerExpansion interface{}
+
+// NdbReplicationChannelListerExpansion allows custom methods to be added to
+// NdbReplicationChannelLister.
+type NdbReplicationChannelListerExpansion interface{}
+
+// NdbReplicationChannelNamespaceListerExpansion allows custom methods to be added to
+// NdbReplicationChannelNamespaceLister.
+type NdbReplicationChannelNamespaceListerExpansion interface{}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NdbClusterBackupLister helps list NdbClusterBackups.
+// All objects returned here must be treated as read-only.
+type NdbClusterBackupLister interface {
+	// List lists all NdbClusterBackups in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbClusterBackup, err error)
+	// NdbClusterBackups returns an object that can list and get NdbClusterBackups.
+	NdbClusterBackups(namespace string) NdbClusterBackupNamespaceLister
+	NdbClusterBackupListerExpansion
+}
+
+// ndbClusterBackupLister implements the NdbClusterBackupLister interface.
+type ndbClusterBackupLister struct {
+	indexer cache.Indexer
+}
+
+// NewNdbClusterBackupLister returns a new NdbClusterBackupLister.
+func NewNdbClusterBackupLister(indexer cache.Indexer) NdbClusterBackupLister {
+	return &ndbClusterBackupLister{indexer: indexer}
+}
+
+// List lists all NdbClusterBackups in the indexer.
+func (s *ndbClusterBackupLister) List(selector labels.Selector) (ret []*v1.NdbClusterBackup, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbClusterBackup))
+	})
+	return ret, err
+}
+
+// NdbClusterBackups returns an object that can list and get NdbClusterBackups.
+func (s *ndbClusterBackupLister) NdbClusterBackups(namespace string) NdbClusterBackupNamespaceLister {
+	return ndbClusterBackupNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NdbClusterBackupNamespaceLister helps list and get NdbClusterBackups.
+// All objects returned here must be treated as read-only.
+type NdbClusterBackupNamespaceLister interface {
+	// List lists all NdbClusterBackups in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbClusterBackup, err error)
+	// Get retrieves the NdbClusterBackup from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.NdbClusterBackup, error)
+	NdbClusterBackupNamespaceListerExpansion
+}
+
+// ndbClusterBackupNamespaceLister implements the NdbClusterBackupNamespaceLister
+// interface.
+type ndbClusterBackupNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NdbClusterBackups in the indexer for a given namespace.
+func (s ndbClusterBackupNamespaceLister) List(selector labels.Selector) (ret []*v1.NdbClusterBackup, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbClusterBackup))
+	})
+	return ret, err
+}
+
+// Get retrieves the NdbClusterBackup from the indexer for a given namespace and name.
+func (s ndbClusterBackupNamespaceLister) Get(name string) (*v1.NdbClusterBackup, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbclusterbackup"), name)
+	}
+	return obj.(*v1.NdbClusterBackup), nil
+}
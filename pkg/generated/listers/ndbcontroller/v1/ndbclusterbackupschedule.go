@@ -0,0 +1,87 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NdbClusterBackupScheduleLister helps list NdbClusterBackupSchedules.
+// All objects returned here must be treated as read-only.
+type NdbClusterBackupScheduleLister interface {
+	// List lists all NdbClusterBackupSchedules in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbClusterBackupSchedule, err error)
+	// NdbClusterBackupSchedules returns an object that can list and get NdbClusterBackupSchedules.
+	NdbClusterBackupSchedules(namespace string) NdbClusterBackupScheduleNamespaceLister
+	NdbClusterBackupScheduleListerExpansion
+}
+
+// ndbClusterBackupScheduleLister implements the NdbClusterBackupScheduleLister interface.
+type ndbClusterBackupScheduleLister struct {
+	indexer cache.Indexer
+}
+
+// NewNdbClusterBackupScheduleLister returns a new NdbClusterBackupScheduleLister.
+func NewNdbClusterBackupScheduleLister(indexer cache.Indexer) NdbClusterBackupScheduleLister {
+	return &ndbClusterBackupScheduleLister{indexer: indexer}
+}
+
+// List lists all NdbClusterBackupSchedules in the indexer.
+func (s *ndbClusterBackupScheduleLister) List(selector labels.Selector) (ret []*v1.NdbClusterBackupSchedule, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbClusterBackupSchedule))
+	})
+	return ret, err
+}
+
+// NdbClusterBackupSchedules returns an object that can list and get NdbClusterBackupSchedules.
+func (s *ndbClusterBackupScheduleLister) NdbClusterBackupSchedules(namespace string) NdbClusterBackupScheduleNamespaceLister {
+	return ndbClusterBackupScheduleNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NdbClusterBackupScheduleNamespaceLister helps list and get NdbClusterBackupSchedules.
+// All objects returned here must be treated as read-only.
+type NdbClusterBackupScheduleNamespaceLister interface {
+	// List lists all NdbClusterBackupSchedules in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbClusterBackupSchedule, err error)
+	// Get retrieves the NdbClusterBackupSchedule from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.NdbClusterBackupSchedule, error)
+	NdbClusterBackupScheduleNamespaceListerExpansion
+}
+
+// ndbClusterBackupScheduleNamespaceLister implements the NdbClusterBackupScheduleNamespaceLister
+// interface.
+type ndbClusterBackupScheduleNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NdbClusterBackupSchedules in the indexer for a given namespace.
+func (s ndbClusterBackupScheduleNamespaceLister) List(selector labels.Selector) (ret []*v1.NdbClusterBackupSchedule, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbClusterBackupSchedule))
+	})
+	return ret, err
+}
+
+// Get retrieves the NdbClusterBackupSchedule from the indexer for a given namespace and name.
+func (s ndbClusterBackupScheduleNamespaceLister) Get(name string) (*v1.NdbClusterBackupSchedule, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbclusterbackupschedule"), name)
+	}
+	return obj.(*v1.NdbClusterBackupSchedule), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NdbMySQLDatabaseLister helps list NdbMySQLDatabases.
+// All objects returned here must be treated as read-only.
+type NdbMySQLDatabaseLister interface {
+	// List lists all NdbMySQLDatabases in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbMySQLDatabase, err error)
+	// NdbMySQLDatabases returns an object that can list and get NdbMySQLDatabases.
+	NdbMySQLDatabases(namespace string) NdbMySQLDatabaseNamespaceLister
+	NdbMySQLDatabaseListerExpansion
+}
+
+// ndbMySQLDatabaseLister implements the NdbMySQLDatabaseLister interface.
+type ndbMySQLDatabaseLister struct {
+	indexer cache.Indexer
+}
+
+// NewNdbMySQLDatabaseLister returns a new NdbMySQLDatabaseLister.
+func NewNdbMySQLDatabaseLister(indexer cache.Indexer) NdbMySQLDatabaseLister {
+	return &ndbMySQLDatabaseLister{indexer: indexer}
+}
+
+// List lists all NdbMySQLDatabases in the indexer.
+func (s *ndbMySQLDatabaseLister) List(selector labels.Selector) (ret []*v1.NdbMySQLDatabase, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbMySQLDatabase))
+	})
+	return ret, err
+}
+
+// NdbMySQLDatabases returns an object that can list and get NdbMySQLDatabases.
+func (s *ndbMySQLDatabaseLister) NdbMySQLDatabases(namespace string) NdbMySQLDatabaseNamespaceLister {
+	return ndbMySQLDatabaseNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NdbMySQLDatabaseNamespaceLister helps list and get NdbMySQLDatabases.
+// All objects returned here must be treated as read-only.
+type NdbMySQLDatabaseNamespaceLister interface {
+	// List lists all NdbMySQLDatabases in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbMySQLDatabase, err error)
+	// Get retrieves the NdbMySQLDatabase from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.NdbMySQLDatabase, error)
+	NdbMySQLDatabaseNamespaceListerExpansion
+}
+
+// ndbMySQLDatabaseNamespaceLister implements the NdbMySQLDatabaseNamespaceLister
+// interface.
+type ndbMySQLDatabaseNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NdbMySQLDatabases in the indexer for a given namespace.
+func (s ndbMySQLDatabaseNamespaceLister) List(selector labels.Selector) (ret []*v1.NdbMySQLDatabase, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbMySQLDatabase))
+	})
+	return ret, err
+}
+
+// Get retrieves the NdbMySQLDatabase from the indexer for a given namespace and name.
+func (s ndbMySQLDatabaseNamespaceLister) Get(name string) (*v1.NdbMySQLDatabase, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbmysqldatabase"), name)
+	}
+	return obj.(*v1.NdbMySQLDatabase), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NdbMySQLUserLister helps list NdbMySQLUsers.
+// All objects returned here must be treated as read-only.
+type NdbMySQLUserLister interface {
+	// List lists all NdbMySQLUsers in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbMySQLUser, err error)
+	// NdbMySQLUsers returns an object that can list and get NdbMySQLUsers.
+	NdbMySQLUsers(namespace string) NdbMySQLUserNamespaceLister
+	NdbMySQLUserListerExpansion
+}
+
+// ndbMySQLUserLister implements the NdbMySQLUserLister interface.
+type ndbMySQLUserLister struct {
+	indexer cache.Indexer
+}
+
+// NewNdbMySQLUserLister returns a new NdbMySQLUserLister.
+func NewNdbMySQLUserLister(indexer cache.Indexer) NdbMySQLUserLister {
+	return &ndbMySQLUserLister{indexer: indexer}
+}
+
+// List lists all NdbMySQLUsers in the indexer.
+func (s *ndbMySQLUserLister) List(selector labels.Selector) (ret []*v1.NdbMySQLUser, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbMySQLUser))
+	})
+	return ret, err
+}
+
+// NdbMySQLUsers returns an object that can list and get NdbMySQLUsers.
+func (s *ndbMySQLUserLister) NdbMySQLUsers(namespace string) NdbMySQLUserNamespaceLister {
+	return ndbMySQLUserNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NdbMySQLUserNamespaceLister helps list and get NdbMySQLUsers.
+// All objects returned here must be treated as read-only.
+type NdbMySQLUserNamespaceLister interface {
+	// List lists all NdbMySQLUsers in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbMySQLUser, err error)
+	// Get retrieves the NdbMySQLUser from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.NdbMySQLUser, error)
+	NdbMySQLUserNamespaceListerExpansion
+}
+
+// ndbMySQLUserNamespaceLister implements the NdbMySQLUserNamespaceLister
+// interface.
+type ndbMySQLUserNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NdbMySQLUsers in the indexer for a given namespace.
+func (s ndbMySQLUserNamespaceLister) List(selector labels.Selector) (ret []*v1.NdbMySQLUser, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbMySQLUser))
+	})
+	return ret, err
+}
+
+// Get retrieves the NdbMySQLUser from the indexer for a given namespace and name.
+func (s ndbMySQLUserNamespaceLister) Get(name string) (*v1.NdbMySQLUser, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbmysqluser"), name)
+	}
+	return obj.(*v1.NdbMySQLUser), nil
+}
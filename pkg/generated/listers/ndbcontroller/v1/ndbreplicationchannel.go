@@ -0,0 +1,87 @@
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NdbReplicationChannelLister helps list NdbReplicationChannels.
+// All objects returned here must be treated as read-only.
+type NdbReplicationChannelLister interface {
+	// List lists all NdbReplicationChannels in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbReplicationChannel, err error)
+	// NdbReplicationChannels returns an object that can list and get NdbReplicationChannels.
+	NdbReplicationChannels(namespace string) NdbReplicationChannelNamespaceLister
+	NdbReplicationChannelListerExpansion
+}
+
+// ndbReplicationChannelLister implements the NdbReplicationChannelLister interface.
+type ndbReplicationChannelLister struct {
+	indexer cache.Indexer
+}
+
+// NewNdbReplicationChannelLister returns a new NdbReplicationChannelLister.
+func NewNdbReplicationChannelLister(indexer cache.Indexer) NdbReplicationChannelLister {
+	return &ndbReplicationChannelLister{indexer: indexer}
+}
+
+// List lists all NdbReplicationChannels in the indexer.
+func (s *ndbReplicationChannelLister) List(selector labels.Selector) (ret []*v1.NdbReplicationChannel, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbReplicationChannel))
+	})
+	return ret, err
+}
+
+// NdbReplicationChannels returns an object that can list and get NdbReplicationChannels.
+func (s *ndbReplicationChannelLister) NdbReplicationChannels(namespace string) NdbReplicationChannelNamespaceLister {
+	return ndbReplicationChannelNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NdbReplicationChannelNamespaceLister helps list and get NdbReplicationChannels.
+// All objects returned here must be treated as read-only.
+type NdbReplicationChannelNamespaceLister interface {
+	// List lists all NdbReplicationChannels in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.NdbReplicationChannel, err error)
+	// Get retrieves the NdbReplicationChannel from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.NdbReplicationChannel, error)
+	NdbReplicationChannelNamespaceListerExpansion
+}
+
+// ndbReplicationChannelNamespaceLister implements the NdbReplicationChannelNamespaceLister
+// interface.
+type ndbReplicationChannelNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NdbReplicationChannels in the indexer for a given namespace.
+func (s ndbReplicationChannelNamespaceLister) List(selector labels.Selector) (ret []*v1.NdbReplicationChannel, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.NdbReplicationChannel))
+	})
+	return ret, err
+}
+
+// Get retrieves the NdbReplicationChannel from the indexer for a given namespace and name.
+func (s ndbReplicationChannelNamespaceLister) Get(name string) (*v1.NdbReplicationChannel, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("ndbreplicationchannel"), name)
+	}
+	return obj.(*v1.NdbReplicationChannel), nil
+}
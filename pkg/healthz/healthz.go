@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Package healthz serves the liveness and readiness endpoints of the NDB Operator
+package healthz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	klog "k8s.io/klog/v2"
+)
+
+// Check is a named health check that returns an error if it fails
+type Check struct {
+	Name  string
+	Check func() error
+}
+
+// newHandler returns an http.Handler that runs the given checks and
+// replies with a 200 if all of them pass and a 500 if any of them fail.
+// The reply lists the result of every check.
+func newHandler(endpoint string, checks []Check) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var reply strings.Builder
+		failed := false
+		for _, check := range checks {
+			if err := check.Check(); err != nil {
+				klog.Warningf("%s check %q failed : %s", endpoint, check.Name, err)
+				fmt.Fprintf(&reply, "[-]%s failed: %s\n", check.Name, err)
+				failed = true
+			} else {
+				fmt.Fprintf(&reply, "[+]%s ok\n", check.Name)
+			}
+		}
+
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if failed {
+			writer.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(&reply, "%s check failed\n", endpoint)
+		} else {
+			klog.V(2).Infof("Replying OK to %s probe", endpoint)
+			fmt.Fprintf(&reply, "%s check passed\n", endpoint)
+		}
+		_, _ = writer.Write([]byte(reply.String()))
+	})
+}
+
+// Serve runs an HTTP server, listening on the given address, that
+// runs the liveness checks at the /healthz endpoint and the readiness
+// checks at the /readyz endpoint. It blocks until the context is done
+// and then shuts down the server.
+func Serve(ctx context.Context, addr string, livenessChecks, readinessChecks []Check) {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", newHandler("healthz", livenessChecks))
+	mux.Handle("/readyz", newHandler("readyz", readinessChecks))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down the health probe server : %s", err)
+		}
+	}()
+
+	klog.Infof("Serving health probes at %s/healthz and %s/readyz", addr, addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		klog.Fatalf("Error running the health probe server : %s", err)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	passingCheck := Check{Name: "passing", Check: func() error { return nil }}
+	failingCheck := Check{Name: "failing", Check: func() error { return errors.New("caches not synced") }}
+
+	for _, tc := range []struct {
+		desc           string
+		checks         []Check
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"no checks", nil, http.StatusOK, "readyz check passed"},
+		{"all checks pass", []Check{passingCheck}, http.StatusOK, "[+]passing ok"},
+		{"a check fails", []Check{passingCheck, failingCheck},
+			http.StatusInternalServerError, "[-]failing failed: caches not synced"},
+	} {
+		recorder := httptest.NewRecorder()
+		newHandler("readyz", tc.checks).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if recorder.Code != tc.expectedStatus {
+			t.Errorf("%s : expected status %d but got %d", tc.desc, tc.expectedStatus, recorder.Code)
+		}
+		if body := recorder.Body.String(); !strings.Contains(body, tc.expectedBody) {
+			t.Errorf("%s : expected body to contain %q but got %q", tc.desc, tc.expectedBody, body)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecInPod executes the given command in the given container of the pod
+// and returns the stdout of the command. The stderr of the command is
+// included in the returned error if the command fails.
+func ExecInPod(ctx context.Context, client kubernetes.Interface, config *restclient.Config,
+	namespace, podName, containerName string, cmd []string) (string, error) {
+
+	// Create a rest request to exec the command in the pod
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").Name(podName).Namespace(namespace).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   cmd,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return "", fmt.Errorf("command %v failed in pod %s/%s : %w (stderr : %q)",
+			cmd, namespace, podName, err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
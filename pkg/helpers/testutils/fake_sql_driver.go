@@ -0,0 +1,188 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package testutils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// FakeSQLDriverName is the name of the fake MySQL driver registered
+// with database/sql. The connections opened via this driver are
+// served by the FakeSQLServer registered for the host in the DSN.
+const FakeSQLDriverName = "fake-mysql"
+
+// FakeSQLHandler returns the columns and the rows of the result of the given
+// query, or an error. The rows are ignored for the statements executed via Exec.
+type FakeSQLHandler func(query string, args []driver.Value) (columns []string, rows [][]driver.Value, err error)
+
+// FakeSQLServer is a fake MySQL Server that answers the queries via its handler
+type FakeSQLServer struct {
+	lock    sync.Mutex
+	handler FakeSQLHandler
+	queries []string
+	down    bool
+}
+
+// Queries returns all the queries received by the server
+func (fss *FakeSQLServer) Queries() []string {
+	fss.lock.Lock()
+	defer fss.lock.Unlock()
+	return append([]string(nil), fss.queries...)
+}
+
+// SetDown makes the server refuse all connections and queries if down is true
+func (fss *FakeSQLServer) SetDown(down bool) {
+	fss.lock.Lock()
+	defer fss.lock.Unlock()
+	fss.down = down
+}
+
+// errServerDown is returned by a FakeSQLServer that is down
+var errServerDown = errors.New("fake MySQL Server is down")
+
+// handle runs the given query via the handler
+func (fss *FakeSQLServer) handle(query string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+	fss.lock.Lock()
+	if fss.down {
+		fss.lock.Unlock()
+		return nil, nil, errServerDown
+	}
+	fss.queries = append(fss.queries, query)
+	handler := fss.handler
+	fss.lock.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i := range args {
+		values[i] = args[i].Value
+	}
+	return handler(query, values)
+}
+
+// fakeSQLDriver routes the connections to the FakeSQLServers based on the host
+type fakeSQLDriver struct {
+	lock    sync.Mutex
+	servers map[string]*FakeSQLServer
+}
+
+var (
+	fakeDriver         = &fakeSQLDriver{servers: make(map[string]*FakeSQLServer)}
+	registerFakeDriver sync.Once
+	// dsnHost extracts the host from a DSN of form user:password@tcp(host:port)/db
+	dsnHost = regexp.MustCompile(`@tcp\(([^)]*?)(:\d+)?\)`)
+)
+
+// NewFakeSQLServer starts a FakeSQLServer that serves the connections opened,
+// via the FakeSQLDriverName driver, to the given host. A server started with
+// an empty host serves the connections to all the hosts without a server.
+func NewFakeSQLServer(host string, handler FakeSQLHandler) *FakeSQLServer {
+	registerFakeDriver.Do(func() {
+		sql.Register(FakeSQLDriverName, fakeDriver)
+	})
+
+	fss := &FakeSQLServer{handler: handler}
+	fakeDriver.lock.Lock()
+	defer fakeDriver.lock.Unlock()
+	fakeDriver.servers[host] = fss
+	return fss
+}
+
+// StopFakeSQLServers removes all the FakeSQLServers
+func StopFakeSQLServers() {
+	fakeDriver.lock.Lock()
+	defer fakeDriver.lock.Unlock()
+	fakeDriver.servers = make(map[string]*FakeSQLServer)
+}
+
+// Open opens a connection to the FakeSQLServer of the host in the DSN
+func (d *fakeSQLDriver) Open(dsn string) (driver.Conn, error) {
+	host := ""
+	if match := dsnHost.FindStringSubmatch(dsn); match != nil {
+		host = match[1]
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	server, exists := d.servers[host]
+	if !exists {
+		if server, exists = d.servers[""]; !exists {
+			return nil, errors.New("no fake MySQL Server running at " + host)
+		}
+	}
+
+	return &fakeSQLConn{server: server}, nil
+}
+
+// fakeSQLConn is a connection to a FakeSQLServer
+type fakeSQLConn struct {
+	server *FakeSQLServer
+}
+
+func (c *fakeSQLConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported by the fake MySQL Server")
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported by the fake MySQL Server")
+}
+
+func (c *fakeSQLConn) Ping(context.Context) error {
+	c.server.lock.Lock()
+	defer c.server.lock.Unlock()
+	if c.server.down {
+		return errServerDown
+	}
+	return nil
+}
+
+func (c *fakeSQLConn) QueryContext(
+	_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	columns, rows, err := c.server.handle(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeSQLRows{columns: columns, rows: rows}, nil
+}
+
+func (c *fakeSQLConn) ExecContext(
+	_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, _, err := c.server.handle(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// fakeSQLRows is the result of a query returned by a FakeSQLServer
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// leaderInfo reports the identity of the current
+	// leader, as observed by this operator replica
+	leaderInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "leader_info",
+		Help:      "The identity of the NDB Operator replica currently holding the leader election lease.",
+	}, []string{"identity"})
+
+	// isLeader reports if this operator replica is the leader
+	isLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "is_leader",
+		Help:      "1 if this NDB Operator replica is the leader and runs the controllers, 0 otherwise.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(leaderInfo, isLeader)
+}
+
+// SetLeader records the identity of the new leader
+func SetLeader(identity string) {
+	leaderInfo.Reset()
+	leaderInfo.WithLabelValues(identity).Set(1)
+}
+
+// SetIsLeader records if this operator replica is the leader
+func SetIsLeader(leader bool) {
+	if leader {
+		isLeader.Set(1)
+	} else {
+		isLeader.Set(0)
+	}
+}
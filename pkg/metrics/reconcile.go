@@ -0,0 +1,61 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// reconcileTotal counts the reconciliations of every NdbCluster
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "reconcile_total",
+		Help:      "Total number of reconciliations of the NdbCluster.",
+	}, []string{"namespace", "name"})
+
+	// reconcileErrorsTotal counts the failed reconciliations of every NdbCluster
+	reconcileErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Total number of reconciliations of the NdbCluster that failed with an error.",
+	}, []string{"namespace", "name"})
+
+	// reconcileDuration tracks the time taken by the reconciliations of every NdbCluster
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Time taken by the reconciliations of the NdbCluster.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	Registry.MustRegister(reconcileTotal, reconcileErrorsTotal, reconcileDuration)
+}
+
+// ObserveReconcile records a reconciliation of the given
+// NdbCluster, that took the given duration and failed if
+// err is not nil.
+func ObserveReconcile(namespace, name string, duration time.Duration, err error) {
+	reconcileTotal.WithLabelValues(namespace, name).Inc()
+	reconcileDuration.WithLabelValues(namespace, name).Observe(duration.Seconds())
+	if err != nil {
+		reconcileErrorsTotal.WithLabelValues(namespace, name).Inc()
+	} else {
+		// Initialise the error counter so that it is exported even before the first error
+		reconcileErrorsTotal.WithLabelValues(namespace, name)
+	}
+}
+
+// DeleteReconcileMetrics removes the reconciliation
+// metrics of an NdbCluster that has been deleted
+func DeleteReconcileMetrics(namespace, name string) {
+	reconcileTotal.DeleteLabelValues(namespace, name)
+	reconcileErrorsTotal.DeleteLabelValues(namespace, name)
+	reconcileDuration.DeleteLabelValues(namespace, name)
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Package metrics defines the Prometheus metrics exported by the NDB Operator
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Namespace is the prefix of all the metrics exported by the NDB Operator
+const Namespace = "ndb_operator"
+
+// Registry is the registry with all the metrics exported by the NDB Operator
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	// Export the Go runtime and the process metrics of the operator
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	klog "k8s.io/klog/v2"
+)
+
+// Serve exposes the metrics in the Registry at the /metrics endpoint of
+// an HTTP server listening on the given address. It blocks until the
+// context is done and then shuts down the server.
+func Serve(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		ErrorLog: klogErrorLogger{},
+	}))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down the metrics server : %s", err)
+		}
+	}()
+
+	klog.Infof("Serving metrics at %s/metrics", addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		klog.Fatalf("Error running the metrics server : %s", err)
+	}
+}
+
+// klogErrorLogger logs the errors, encountered
+// while gathering and serving the metrics, via klog
+type klogErrorLogger struct{}
+
+func (klogErrorLogger) Println(v ...interface{}) {
+	klog.Error(v...)
+}
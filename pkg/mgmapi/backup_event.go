@@ -0,0 +1,188 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mgmapi
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mysql/ndb-operator/config/debug"
+)
+
+const (
+	// logEventCategoryBackup is the backup event category
+	// (CFG_LOGLEVEL_BACKUP - CFG_MIN_LOGLEVEL in mgmapi_config_parameters.h)
+	logEventCategoryBackup = 11
+	// logEventMaxLevel is the maximum log level of an event.
+	// Subscribing with this level delivers all the events in a category.
+	logEventMaxLevel = 15
+
+	// pingMessage is periodically sent by the Management Server
+	// to all event listeners to detect broken connections.
+	pingMessage = "<PING>"
+)
+
+// BackupEventType is the type of the backup event
+// sent by the Management Server to the event listeners.
+type BackupEventType int
+
+// Backup event types as defined by Ndb_logevent_type in ndb_logevent.h
+const (
+	BackupStarted       BackupEventType = 54
+	BackupFailedToStart BackupEventType = 55
+	BackupCompleted     BackupEventType = 56
+	BackupAborted       BackupEventType = 57
+)
+
+// BackupEvent has the details of a backup event.
+type BackupEvent struct {
+	Type BackupEventType
+	// SourceNodeId is the id of the data node that reported the event
+	SourceNodeId int
+	// BackupId is not available in a BackupFailedToStart event
+	BackupId int
+	// The following details are available only in a BackupCompleted event
+	StartGCP      uint32
+	StopGCP       uint32
+	NumOfRecords  uint64
+	NumOfBytes    uint64
+	NumOfLogBytes uint64
+	// Error is set only in BackupFailedToStart and BackupAborted events
+	Error int
+}
+
+// readEventReplyLines reads a reply or an event from the event stream.
+// The first line, which is the header, is stored against an empty
+// key and the rest of the lines are split into key value pairs using
+// the given separator. Empty lines and ping messages preceding the
+// header are skipped.
+func readEventReplyLines(reader *bufio.Reader, separator string) (map[string]string, error) {
+	details := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if _, headerRead := details[""]; !headerRead {
+			if line == "" || line == pingMessage {
+				// Not yet started reading the reply
+				continue
+			}
+			details[""] = line
+			continue
+		}
+
+		if line == "" {
+			// Empty line marks the end of the reply
+			return details, nil
+		}
+
+		tokens := strings.SplitN(line, separator, 2)
+		if len(tokens) != 2 {
+			return nil, debug.InternalError("unexpected format in event stream : " + line)
+		}
+		details[tokens[0]] = strings.TrimSpace(tokens[1])
+	}
+}
+
+// newBackupEvent creates a BackupEvent from the given event details.
+// It returns nil if the event is not one of the BackupEventTypes.
+func newBackupEvent(event map[string]string) (*BackupEvent, error) {
+	eventType, err := strconv.Atoi(event["type"])
+	if err != nil {
+		return nil, debug.InternalError("type in event has unexpected format : " + err.Error())
+	}
+
+	backupEvent := &BackupEvent{
+		Type: BackupEventType(eventType),
+	}
+	switch backupEvent.Type {
+	case BackupStarted, BackupFailedToStart, BackupCompleted, BackupAborted:
+	default:
+		// Not a BackupEvent
+		return nil, nil
+	}
+
+	// Parse the required details. All values are unsigned 32-bit
+	// integers and the 64-bit values are split into two.
+	values := make(map[string]uint64)
+	for _, key := range []string{
+		"source_nodeid", "backup_id", "start_gci", "stop_gci",
+		"n_records", "n_records_hi", "n_bytes", "n_bytes_hi",
+		"n_log_bytes", "n_log_bytes_hi", "error",
+	} {
+		value, exists := event[key]
+		if !exists {
+			// Not all details are sent in every event
+			continue
+		}
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, debug.InternalError(
+				"value of " + key + " in backup event has unexpected format : " + err.Error())
+		}
+		values[key] = v
+	}
+
+	backupEvent.SourceNodeId = int(values["source_nodeid"])
+	backupEvent.BackupId = int(values["backup_id"])
+	backupEvent.StartGCP = uint32(values["start_gci"])
+	backupEvent.StopGCP = uint32(values["stop_gci"])
+	backupEvent.NumOfRecords = values["n_records"] | values["n_records_hi"]<<32
+	backupEvent.NumOfBytes = values["n_bytes"] | values["n_bytes_hi"]<<32
+	backupEvent.NumOfLogBytes = values["n_log_bytes"] | values["n_log_bytes_hi"]<<32
+	backupEvent.Error = int(values["error"])
+
+	return backupEvent, nil
+}
+
+// FindBackupOutcomeInClusterLog searches the given Management Server cluster
+// log for the completion or the abort of the backup with the given id and
+// returns the outcome as a BackupEvent. It returns nil if the cluster log
+// doesn't have the outcome of the backup, i.e. the backup is still running.
+// If the backup id has been reused, the outcome logged last is returned.
+func FindBackupOutcomeInClusterLog(clusterLog string, backupId int) *BackupEvent {
+	// Format of the log messages as defined in EventLogger.cpp
+	outcomeRegex := regexp.MustCompile(fmt.Sprintf(
+		`Node (\d+): Backup %d started from (?:node \d+ completed\. `+
+			`StartGCP: (\d+) StopGCP: (\d+) #Records: (\d+) #LogRecords: \d+ `+
+			`Data: (\d+) bytes Log: (\d+) bytes|\d+ has been aborted\. Error: (\d+))`, backupId))
+
+	matches := outcomeRegex.FindAllStringSubmatch(clusterLog, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// The regex matches only digits and so the
+	// parse errors, if any, can be safely ignored.
+	parseUint := func(value string) uint64 {
+		v, _ := strconv.ParseUint(value, 10, 64)
+		return v
+	}
+
+	match := matches[len(matches)-1]
+	backupEvent := &BackupEvent{
+		SourceNodeId: int(parseUint(match[1])),
+		BackupId:     backupId,
+	}
+	if match[7] != "" {
+		backupEvent.Type = BackupAborted
+		backupEvent.Error = int(parseUint(match[7]))
+		return backupEvent
+	}
+
+	backupEvent.Type = BackupCompleted
+	backupEvent.StartGCP = uint32(parseUint(match[2]))
+	backupEvent.StopGCP = uint32(parseUint(match[3]))
+	backupEvent.NumOfRecords = parseUint(match[4])
+	backupEvent.NumOfBytes = parseUint(match[5])
+	backupEvent.NumOfLogBytes = parseUint(match[6])
+	return backupEvent
+}
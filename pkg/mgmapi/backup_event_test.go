@@ -0,0 +1,56 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mgmapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindBackupOutcomeInClusterLog(t *testing.T) {
+	clusterLog := `2024-03-01 10:00:00 [MgmtSrvr] INFO     -- Node 3: Backup 1 started from node 1
+2024-03-01 10:00:05 [MgmtSrvr] INFO     -- Node 3: Backup 1 started from node 1 completed. StartGCP: 1200 StopGCP: 1203 #Records: 2059 #LogRecords: 0 Data: 52044 bytes Log: 0 bytes
+2024-03-01 11:00:00 [MgmtSrvr] INFO     -- Node 3: Backup 11 started from node 1
+2024-03-01 11:00:01 [MgmtSrvr] ALERT    -- Node 4: Backup 11 started from 1 has been aborted. Error: 1323
+2024-03-01 12:00:00 [MgmtSrvr] INFO     -- Node 3: Backup 12 started from node 1
+`
+
+	tests := []struct {
+		name     string
+		backupId int
+		expected *BackupEvent
+	}{
+		{
+			name:     "completed backup",
+			backupId: 1,
+			expected: &BackupEvent{
+				Type: BackupCompleted, SourceNodeId: 3, BackupId: 1,
+				StartGCP: 1200, StopGCP: 1203, NumOfRecords: 2059, NumOfBytes: 52044,
+			},
+		},
+		{
+			name:     "aborted backup",
+			backupId: 11,
+			expected: &BackupEvent{Type: BackupAborted, SourceNodeId: 4, BackupId: 11, Error: 1323},
+		},
+		{
+			name:     "running backup",
+			backupId: 12,
+		},
+		{
+			name:     "unknown backup",
+			backupId: 13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := FindBackupOutcomeInClusterLog(clusterLog, tt.backupId)
+			if !reflect.DeepEqual(event, tt.expected) {
+				t.Errorf("Expected %+v but got %+v", tt.expected, event)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -214,3 +214,14 @@ func (cs ClusterStatus) GetConnectedDataNodesWithNodeGroup(ng int) []int {
 	sort.Ints(nodesInNodeGroup)
 	return nodesInNodeGroup
 }
+
+// GetDataNodeStartNodeId returns the lowest nodeId assigned to a data node
+func (cs ClusterStatus) GetDataNodeStartNodeId() int {
+	startNodeId := 0
+	for nodeId, node := range cs {
+		if node.IsDataNode() && (startNodeId == 0 || nodeId < startNodeId) {
+			startNodeId = nodeId
+		}
+	}
+	return startNodeId
+}
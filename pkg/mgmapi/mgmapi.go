@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -7,6 +7,7 @@ package mgmapi
 import (
 	"bufio"
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -26,6 +27,11 @@ type MgmClient interface {
 	StopNodes(nodeIds []int) error
 	TryReserveNodeId(nodeId int, nodeType NodeTypeEnum) (int, error)
 	CreateNodeGroup(nodeIds []int) (int, error)
+	DropNodeGroup(ng int) error
+	StartBackup(backupId int) (int, error)
+	AbortBackup(backupId int) error
+	ListenBackupEvents() error
+	ReadBackupEvent(timeout time.Duration) (*BackupEvent, error)
 
 	GetConfigVersion(nodeID ...int) (uint32, error)
 	GetDataMemory(dataNodeId int) (uint64, error)
@@ -50,12 +56,29 @@ type MgmClient interface {
 // MySQL Cluster nodes via wire protocol.
 type mgmClientImpl struct {
 	connection net.Conn
+	// eventReader reads the events sent by the Management
+	// Server once the connection has been turned into an
+	// event stream by ListenBackupEvents.
+	eventReader *bufio.Reader
+	// tlsConfig, if set, is used to upgrade the
+	// connection to TLS right after it is opened.
+	tlsConfig *tls.Config
 }
 
 // NewMgmClient returns a new mgmClientImpl connected to MySQL Cluster
 func NewMgmClient(connectstring string, desiredNodeId ...int) (*mgmClientImpl, error) {
+	return NewMgmClientWithTLS(connectstring, nil, desiredNodeId...)
+}
+
+// NewMgmClientWithTLS returns a new mgmClientImpl connected to MySQL
+// Cluster. If tlsConfig is not nil, the connection is upgraded to TLS
+// using the given config before sending any other command.
+func NewMgmClientWithTLS(
+	connectstring string, tlsConfig *tls.Config, desiredNodeId ...int) (*mgmClientImpl, error) {
 
-	client := &mgmClientImpl{}
+	client := &mgmClientImpl{
+		tlsConfig: tlsConfig,
+	}
 	var err error
 	switch len(desiredNodeId) {
 	case 0:
@@ -103,12 +126,44 @@ func (mci *mgmClientImpl) connect(connectstring string) error {
 			continue
 		}
 		klog.V(4).Infof("Management server connected to node at %q", host)
+
+		if mci.tlsConfig != nil {
+			if err = mci.startTLS(); err != nil {
+				klog.Errorf("Failed to start TLS with Management Node at %q : %s", host, err)
+				mci.Disconnect()
+				mci.connection = nil
+				// Try the next host in the connectstring
+				continue
+			}
+			klog.V(4).Infof("Connection to Management Node at %q upgraded to TLS", host)
+		}
 		break
 	}
 
 	return err
 }
 
+// startTLS upgrades the connection to the Management Server to TLS
+func (mci *mgmClientImpl) startTLS() error {
+	// Ask the Management Server to start the TLS handshake
+	_, err := mci.executeCommand("start tls", nil, false, []string{"start tls reply", "result"})
+	if err != nil {
+		return err
+	}
+
+	// The connection is now ready for the TLS handshake
+	tlsConnection := tls.Client(mci.connection, mci.tlsConfig)
+	if err = tlsConnection.SetDeadline(time.Now().Add(defaultReadWriteTimeout)); err != nil {
+		return err
+	}
+	if err = tlsConnection.Handshake(); err != nil {
+		return err
+	}
+
+	mci.connection = tlsConnection
+	return nil
+}
+
 // connectToNodeId creates a tcp connection to the mgmd with the given id
 // Note : always use NewMgmClient to create a client rather
 // than directly using mgmClientImpl and connectToNodeId
@@ -181,15 +236,12 @@ const (
 	delayedReplyTimeout = 300 * time.Second
 )
 
-// executeCommand sends the command to the Management Server,
-// reads back the reply, parses it and returns the reply and
-// other details to the caller.
-func (mci *mgmClientImpl) executeCommand(
-	command string, args map[string]interface{},
-	slowCommand bool, expectedReplyDetails []string) (map[string]string, error) {
+// sendCommand builds the command with the given args and
+// sends it to the connected Management Server.
+func (mci *mgmClientImpl) sendCommand(command string, args map[string]interface{}) error {
 
 	if mci.connection == nil {
-		return nil, debug.InternalError("MgmClient is not connected to Management server")
+		return debug.InternalError("MgmClient is not connected to Management server")
 	}
 
 	// Build the command and args
@@ -205,12 +257,27 @@ func (mci *mgmClientImpl) executeCommand(
 	err := mci.connection.SetWriteDeadline(time.Now().Add(defaultReadWriteTimeout))
 	if err != nil {
 		klog.Error("SetWriteDeadline failed : ", err)
-		return nil, err
+		return err
 	}
 
 	// Send the command along with the args to the connected mgmd server
 	if _, err = mci.connection.Write(cmdWithArgs.Bytes()); err != nil {
 		klog.Error("failed to send command to connected management server :", err)
+		return err
+	}
+
+	return nil
+}
+
+// executeCommand sends the command to the Management Server,
+// reads back the reply, parses it and returns the reply and
+// other details to the caller.
+func (mci *mgmClientImpl) executeCommand(
+	command string, args map[string]interface{},
+	slowCommand bool, expectedReplyDetails []string) (map[string]string, error) {
+
+	// Send the command along with the args to the connected mgmd server
+	if err := mci.sendCommand(command, args); err != nil {
 		return nil, err
 	}
 
@@ -219,7 +286,7 @@ func (mci *mgmClientImpl) executeCommand(
 	if slowCommand {
 		replyReadTimeout = delayedReplyTimeout
 	}
-	err = mci.connection.SetReadDeadline(time.Now().Add(replyReadTimeout))
+	err := mci.connection.SetReadDeadline(time.Now().Add(replyReadTimeout))
 	if err != nil {
 		klog.Error("SetReadDeadline failed : ", err)
 		return nil, err
@@ -611,6 +678,195 @@ func (mci *mgmClientImpl) CreateNodeGroup(nodeIds []int) (int, error) {
 	return ng, nil
 }
 
+// DropNodeGroup drops the nodegroup with the given id. The
+// nodegroup should be empty, i.e. should not have any data
+// in it, for the Management Server to be able to drop it.
+func (mci *mgmClientImpl) DropNodeGroup(ng int) error {
+
+	// command :
+	// drop nodegroup
+	// ng: <nodegroup ID>
+
+	// reply :
+	// drop nodegroup reply
+	// result: Ok
+
+	args := map[string]interface{}{
+		"ng": ng,
+	}
+
+	// send the command and read the reply
+	_, err := mci.executeCommand(
+		"drop nodegroup", args, true,
+		[]string{"drop nodegroup reply", "result"})
+	return err
+}
+
+// StartBackup starts a backup of the MySQL Cluster with the given backupId
+// and waits until the backup has been started by the data nodes. If the
+// backupId is 0, the next available id is picked by the data nodes. On
+// success, it returns the id of the started backup.
+func (mci *mgmClientImpl) StartBackup(backupId int) (int, error) {
+
+	// command :
+	// start backup
+	// completed: <0 - do not wait, 1 - wait until started, 2 - wait until completed>
+	// backupid: <backupId> (optional)
+
+	// reply :
+	// start backup reply
+	// result: Ok
+	// id: <backupId>
+
+	// build args
+	args := map[string]interface{}{
+		// wait only until the backup is started. Completion of
+		// the backup has to be tracked via the backup events.
+		"completed": 1,
+	}
+	if backupId != 0 {
+		args["backupid"] = backupId
+	}
+
+	// send the command and read the reply
+	reply, err := mci.executeCommand(
+		"start backup", args, true,
+		[]string{"start backup reply", "result", "id"})
+	if err != nil {
+		return 0, err
+	}
+
+	startedBackupId, err := strconv.Atoi(reply["id"])
+	if err != nil {
+		return 0, debug.InternalError("id in start backup reply has unexpected format : " + err.Error())
+	}
+
+	return startedBackupId, nil
+}
+
+// AbortBackup aborts the running backup with the given backupId
+func (mci *mgmClientImpl) AbortBackup(backupId int) error {
+
+	// command :
+	// abort backup
+	// id: <backupId>
+
+	// reply :
+	// abort backup reply
+	// result: Ok
+
+	args := map[string]interface{}{
+		"id": backupId,
+	}
+
+	// send the command and read the reply
+	_, err := mci.executeCommand(
+		"abort backup", args, false,
+		[]string{"abort backup reply", "result"})
+	return err
+}
+
+// ListenBackupEvents subscribes to the backup events generated by the
+// data nodes. Once subscribed, the Management Server turns the connection
+// into an event stream and, no other command can be executed via this client.
+// The events can be read using the ReadBackupEvent method.
+func (mci *mgmClientImpl) ListenBackupEvents() error {
+
+	// command :
+	// listen event
+	// filter: <category>=<level> [<category>=<level> ...]
+	// parsable: 1
+
+	// reply :
+	// listen event
+	// result: 0
+
+	args := map[string]interface{}{
+		"filter":   fmt.Sprintf("%d=%d", logEventCategoryBackup, logEventMaxLevel),
+		"parsable": 1,
+	}
+
+	if err := mci.sendCommand("listen event", args); err != nil {
+		return err
+	}
+
+	// The event stream follows the reply. So, use a reader that
+	// will be retained by the client to read the reply and the events.
+	err := mci.connection.SetReadDeadline(time.Now().Add(defaultReadWriteTimeout))
+	if err != nil {
+		klog.Error("SetReadDeadline failed : ", err)
+		return err
+	}
+	mci.eventReader = bufio.NewReader(mci.connection)
+
+	reply, err := readEventReplyLines(mci.eventReader, ": ")
+	if err != nil {
+		klog.Error("failed to read reply from Management Server :", err)
+		return err
+	}
+
+	if reply[""] != "listen event" {
+		return debug.InternalError("unexpected header in listen event reply")
+	}
+
+	// Unlike other commands, listen event returns a numeric result
+	if result := reply["result"]; result != "0" {
+		if msg, exists := reply["msg"]; exists {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("listen event failed with result %q", result)
+	}
+
+	return nil
+}
+
+// ReadBackupEvent reads the next backup event sent by the Management
+// Server. It waits at most for the given timeout for an event to arrive.
+// ListenBackupEvents should be called before using this method.
+func (mci *mgmClientImpl) ReadBackupEvent(timeout time.Duration) (*BackupEvent, error) {
+
+	// event :
+	// log event reply
+	// type=<event type>
+	// time=<timestamp>
+	// source_nodeid=<nodeId>
+	// <key>=<value>
+	// ...
+
+	if mci.eventReader == nil {
+		return nil, debug.InternalError("ReadBackupEvent called before ListenBackupEvents")
+	}
+
+	err := mci.connection.SetReadDeadline(time.Now().Add(timeout))
+	if err != nil {
+		klog.Error("SetReadDeadline failed : ", err)
+		return nil, err
+	}
+
+	for {
+		event, err := readEventReplyLines(mci.eventReader, "=")
+		if err != nil {
+			return nil, err
+		}
+
+		if event[""] != "log event reply" {
+			// Unexpected data in the event stream
+			return nil, debug.InternalError("unexpected header in event : " + event[""])
+		}
+
+		backupEvent, err := newBackupEvent(event)
+		if err != nil {
+			return nil, err
+		}
+
+		if backupEvent != nil {
+			return backupEvent, nil
+		}
+
+		// Not an event that the caller is interested in. Read the next one.
+	}
+}
+
 // getConfig extracts the value of the config variable 'configKey'
 // from the MySQL Cluster node with node id 'nodeId'. The config
 // is either retrieved from the config stored in connected
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -607,3 +607,76 @@ func TestMgmClientImpl_TryReserveNodeId(t *testing.T) {
 		t.Errorf("TryReserveNodeId returned an unexpected error : %s", err.Error())
 	}
 }
+
+func TestMgmClientImpl_StartBackup(t *testing.T) {
+	mgmServer, mci := newFakeMgmServerAndClient(t)
+	defer mci.Disconnect()
+	defer mgmServer.disconnect()
+	mgmServer.run([]byte("start backup reply\nresult: Ok\nid: 7"))
+
+	backupId, err := mci.StartBackup(0)
+	if err != nil {
+		t.Fatalf("StartBackup failed : %s", err)
+	}
+
+	if backupId != 7 {
+		t.Errorf("StartBackup returned unexpected backup id %d", backupId)
+	}
+}
+
+func TestMgmClientImpl_DropNodeGroup(t *testing.T) {
+	mgmServer, mci := newFakeMgmServerAndClient(t)
+	defer mci.Disconnect()
+	defer mgmServer.disconnect()
+	mgmServer.run([]byte("drop nodegroup reply\nresult: Ok"))
+
+	if err := mci.DropNodeGroup(1); err != nil {
+		t.Fatalf("DropNodeGroup failed : %s", err)
+	}
+
+	// Management Server should not drop a nodegroup that has data in it
+	mgmServer.run([]byte("drop nodegroup reply\nresult: Nodegroup is not empty"))
+	if err := mci.DropNodeGroup(1); err == nil || err.Error() != "Nodegroup is not empty" {
+		t.Errorf("Expected DropNodeGroup to fail but got %v", err)
+	}
+}
+
+func TestMgmClientImpl_ReadBackupEvent(t *testing.T) {
+	mgmServer, mci := newFakeMgmServerAndClient(t)
+	defer mci.Disconnect()
+	defer mgmServer.disconnect()
+	mgmServer.run(
+		[]byte("listen event\nresult: 0\n\n"),
+		[]byte("<PING>\n"),
+		// BackupStatus event - should be skipped by the client
+		[]byte("log event reply\ntype=62\ntime=0\nsource_nodeid=3\nbackup_id=7\n\n"),
+		[]byte("log event reply\ntype=56\ntime=0\nsource_nodeid=3\nstarting_node=147\n"+
+			"backup_id=7\nstart_gci=1028\nstop_gci=1031\nn_bytes=1024\nn_records=12\n"+
+			"n_log_bytes=64\nn_log_records=1\nn_bytes_hi=1\nn_records_hi=0\n"),
+	)
+
+	if err := mci.ListenBackupEvents(); err != nil {
+		t.Fatalf("ListenBackupEvents failed : %s", err)
+	}
+
+	event, err := mci.ReadBackupEvent(defaultReadWriteTimeout)
+	if err != nil {
+		t.Fatalf("ReadBackupEvent failed : %s", err)
+	}
+
+	expectedEvent := &BackupEvent{
+		Type:          BackupCompleted,
+		SourceNodeId:  3,
+		BackupId:      7,
+		StartGCP:      1028,
+		StopGCP:       1031,
+		NumOfRecords:  12,
+		NumOfBytes:    1<<32 | 1024,
+		NumOfLogBytes: 64,
+	}
+	if !reflect.DeepEqual(event, expectedEvent) {
+		t.Error("ReadBackupEvent returned unexpected event")
+		t.Errorf("  Expected : %#v", expectedEvent)
+		t.Errorf("  Actual   : %#v", event)
+	}
+}
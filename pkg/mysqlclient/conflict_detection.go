@@ -0,0 +1,252 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+const (
+	// ndbReplicationTable is the table read by the binlog MySQL Servers
+	// to find the conflict detection function to be used for a table
+	ndbReplicationTable = "`" + DbMySQL + "`.`ndb_replication`"
+
+	// createNdbReplicationTable creates the mysql.ndb_replication table
+	// with the definition documented in the MySQL Cluster manual
+	createNdbReplicationTable = "CREATE TABLE IF NOT EXISTS " + ndbReplicationTable + " (" +
+		"db VARBINARY(63), table_name VARBINARY(63), server_id INT UNSIGNED, " +
+		"binlog_type INT UNSIGNED, conflict_fn VARBINARY(128), " +
+		"PRIMARY KEY USING HASH (db, table_name, server_id)" +
+		") ENGINE=NDB PARTITION BY KEY(db, table_name)"
+
+	// binlogTypeFullUseUpdate logs the full rows and logs the updates as
+	// updates, which is required by all the conflict detection functions
+	binlogTypeFullUseUpdate = 7
+
+	// exceptionsTableSuffix is appended to the name of a
+	// table to get the name of its exceptions table
+	exceptionsTableSuffix = "$EX"
+)
+
+// getConflictFunction returns the conflict function, as stored in
+// the conflict_fn column of the mysql.ndb_replication table
+func getConflictFunction(cd *v1.NdbConflictDetectionSpec) string {
+	if cd.Function.IsEpochFunction() {
+		return string(cd.Function) + "()"
+	}
+	return fmt.Sprintf("%s(%s)", cd.Function, cd.Column)
+}
+
+// getExceptionsTableStatement returns the CREATE TABLE statement
+// of the exceptions table of the given table, using the extended
+// format, with the primary key columns of the table.
+func getExceptionsTableStatement(database, table string, primaryKeyColumns [][2]string) string {
+	columns := []string{
+		"`NDB$server_id` INT UNSIGNED",
+		"`NDB$source_server_id` INT UNSIGNED",
+		"`NDB$source_epoch` BIGINT UNSIGNED",
+		"`NDB$count` INT UNSIGNED",
+		"`NDB$OP_TYPE` ENUM('WRITE_ROW','UPDATE_ROW','DELETE_ROW','REFRESH_ROW','READ_ROW') NOT NULL",
+		"`NDB$CFT_CAUSE` ENUM('ROW_DOES_NOT_EXIST','ROW_ALREADY_EXISTS','DATA_IN_CONFLICT','TRANS_IN_CONFLICT') NOT NULL",
+	}
+	for _, column := range primaryKeyColumns {
+		columns = append(columns, QuoteIdentifier(column[0])+" "+column[1])
+	}
+	columns = append(columns,
+		"PRIMARY KEY(`NDB$server_id`, `NDB$source_server_id`, `NDB$source_epoch`, `NDB$count`)")
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (%s) ENGINE=NDB",
+		QuoteIdentifier(database), QuoteIdentifier(table+exceptionsTableSuffix), strings.Join(columns, ", "))
+}
+
+// getPrimaryKeyColumns returns the names and the types of the primary key
+// columns of the given table. It returns nil if the table doesn't exist.
+func getPrimaryKeyColumns(ctx context.Context, db *sql.DB, database, table string) ([][2]string, error) {
+	query := "SELECT c.COLUMN_NAME, c.COLUMN_TYPE FROM " + DbInformationSchema + ".KEY_COLUMN_USAGE k " +
+		"JOIN " + DbInformationSchema + ".COLUMNS c ON c.TABLE_SCHEMA = k.TABLE_SCHEMA " +
+		"AND c.TABLE_NAME = k.TABLE_NAME AND c.COLUMN_NAME = k.COLUMN_NAME " +
+		"WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ? AND k.CONSTRAINT_NAME = 'PRIMARY' " +
+		"ORDER BY k.ORDINAL_POSITION"
+	rows, err := db.QueryContext(ctx, query, database, table)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns [][2]string
+	for rows.Next() {
+		var name, columnType string
+		if err = rows.Scan(&name, &columnType); err != nil {
+			klog.Errorf("Failed to scan the primary key columns : %s", err)
+			return nil, err
+		}
+		columns = append(columns, [2]string{name, columnType})
+	}
+
+	return columns, rows.Err()
+}
+
+// reconcileExceptionsTable creates the exceptions table of the given
+// table if it doesn't exist yet. The exceptions table is created only
+// after the table exists as it has the primary key columns of the table.
+func reconcileExceptionsTable(ctx context.Context, db *sql.DB, database, table string) error {
+	primaryKeyColumns, err := getPrimaryKeyColumns(ctx, db, database, table)
+	if err != nil {
+		return err
+	}
+
+	if len(primaryKeyColumns) == 0 {
+		klog.Warningf("Table `%s`.`%s` does not exist yet or has no primary key. "+
+			"Its exceptions table will be created later.", database, table)
+		return nil
+	}
+
+	query := getExceptionsTableStatement(database, table, primaryKeyColumns)
+	if _, err = db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+	return nil
+}
+
+// getConflictFunctions returns the conflict functions stored in the
+// mysql.ndb_replication table for all the MySQL Servers (server_id 0),
+// mapped by the database and the table name.
+func getConflictFunctions(ctx context.Context, db *sql.DB) (map[[2]string]string, error) {
+	query := "SELECT db, table_name, conflict_fn FROM " + ndbReplicationTable +
+		" WHERE server_id = 0 AND conflict_fn IS NOT NULL"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflictFunctions := make(map[[2]string]string)
+	for rows.Next() {
+		var database, table, conflictFunction string
+		if err = rows.Scan(&database, &table, &conflictFunction); err != nil {
+			klog.Errorf("Failed to scan the conflict functions : %s", err)
+			return nil, err
+		}
+		conflictFunctions[[2]string{database, table}] = conflictFunction
+	}
+
+	return conflictFunctions, rows.Err()
+}
+
+// ReconcileConflictDetection stores the given conflict detection functions
+// in the mysql.ndb_replication table and creates the exceptions tables.
+// Any conflict function, stored for all the MySQL Servers, that is not in
+// the given list is removed. The exceptions tables are not dropped.
+func ReconcileConflictDetection(
+	ctx context.Context, db *sql.DB, conflictDetection []v1.NdbConflictDetectionSpec) error {
+	if _, err := db.ExecContext(ctx, createNdbReplicationTable); err != nil {
+		klog.Errorf("Error executing %s: %s", createNdbReplicationTable, err)
+		return err
+	}
+
+	existingFunctions, err := getConflictFunctions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for i := range conflictDetection {
+		cd := &conflictDetection[i]
+		key := [2]string{cd.Database, cd.Table}
+
+		// The exceptions table has to exist before the binlog
+		// MySQL Server reads the conflict function of the table
+		if cd.ExceptionsTable {
+			if err = reconcileExceptionsTable(ctx, db, cd.Database, cd.Table); err != nil {
+				return err
+			}
+		}
+
+		conflictFunction := getConflictFunction(cd)
+		if existingFunctions[key] != conflictFunction {
+			klog.Infof("Setting conflict function %s for table `%s`.`%s`", conflictFunction, cd.Database, cd.Table)
+			query := "REPLACE INTO " + ndbReplicationTable + " VALUES (?, ?, 0, ?, ?)"
+			if _, err = db.ExecContext(ctx, query,
+				cd.Database, cd.Table, binlogTypeFullUseUpdate, conflictFunction); err != nil {
+				klog.Errorf("Error executing %s: %s", query, err)
+				return err
+			}
+		}
+		delete(existingFunctions, key)
+	}
+
+	// Remove the conflict functions not declared anymore
+	for key := range existingFunctions {
+		klog.Infof("Removing the conflict function of table `%s`.`%s`", key[0], key[1])
+		query := "DELETE FROM " + ndbReplicationTable + " WHERE db = ? AND table_name = ? AND server_id = 0"
+		if _, err = db.ExecContext(ctx, query, key[0], key[1]); err != nil {
+			klog.Errorf("Error executing %s: %s", query, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseConflictCounters generates the conflict detection
+// status from the given Ndb_conflict_* status variables
+func parseConflictCounters(statusVariables map[string]string) *v1.NdbConflictDetectionStatus {
+	counter := func(name string) int64 {
+		value, _ := strconv.ParseInt(statusVariables[name], 10, 64)
+		return value
+	}
+
+	status := &v1.NdbConflictDetectionStatus{
+		RejectedTransactions:  counter("Ndb_conflict_trans_reject_count"),
+		RejectedRows:          counter("Ndb_conflict_trans_row_reject_count"),
+		DeleteDeleteConflicts: counter("Ndb_conflict_epoch_delete_delete_count"),
+		LastConflictEpoch:     counter("Ndb_conflict_last_conflict_epoch"),
+	}
+
+	// Every conflict function has a counter named Ndb_conflict_fn_<function>
+	for name := range statusVariables {
+		if strings.HasPrefix(name, "Ndb_conflict_fn_") {
+			status.Conflicts += counter(name)
+		}
+	}
+
+	return status
+}
+
+// GetConflictCounters returns the conflict counters of the MySQL Server
+func GetConflictCounters(ctx context.Context, db *sql.DB) (*v1.NdbConflictDetectionStatus, error) {
+	query := "SHOW GLOBAL STATUS LIKE 'Ndb\\_conflict\\_%'"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	statusVariables := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err = rows.Scan(&name, &value); err != nil {
+			klog.Errorf("Failed to read the output of %s: %s", query, err)
+			return nil, err
+		}
+		statusVariables[name] = value
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return parseConflictCounters(statusVariables), nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+func TestGetConflictFunction(t *testing.T) {
+	for _, tc := range []struct {
+		cd       v1.NdbConflictDetectionSpec
+		expected string
+	}{
+		{v1.NdbConflictDetectionSpec{Function: v1.NdbConflictFunctionEpoch2}, "NDB$EPOCH2()"},
+		{v1.NdbConflictDetectionSpec{Function: v1.NdbConflictFunctionEpochTrans}, "NDB$EPOCH_TRANS()"},
+		{v1.NdbConflictDetectionSpec{Function: v1.NdbConflictFunctionMax, Column: "version"}, "NDB$MAX(version)"},
+		{v1.NdbConflictDetectionSpec{Function: v1.NdbConflictFunctionOld, Column: "ts"}, "NDB$OLD(ts)"},
+	} {
+		if conflictFunction := getConflictFunction(&tc.cd); conflictFunction != tc.expected {
+			t.Errorf("Expected conflict function %q but got %q", tc.expected, conflictFunction)
+		}
+	}
+}
+
+func TestGetExceptionsTableStatement(t *testing.T) {
+	expected := "CREATE TABLE IF NOT EXISTS `shop`.`orders$EX` (" +
+		"`NDB$server_id` INT UNSIGNED, `NDB$source_server_id` INT UNSIGNED, " +
+		"`NDB$source_epoch` BIGINT UNSIGNED, `NDB$count` INT UNSIGNED, " +
+		"`NDB$OP_TYPE` ENUM('WRITE_ROW','UPDATE_ROW','DELETE_ROW','REFRESH_ROW','READ_ROW') NOT NULL, " +
+		"`NDB$CFT_CAUSE` ENUM('ROW_DOES_NOT_EXIST','ROW_ALREADY_EXISTS','DATA_IN_CONFLICT','TRANS_IN_CONFLICT') NOT NULL, " +
+		"`id` int unsigned, `region` varchar(16), " +
+		"PRIMARY KEY(`NDB$server_id`, `NDB$source_server_id`, `NDB$source_epoch`, `NDB$count`)) ENGINE=NDB"
+	statement := getExceptionsTableStatement("shop", "orders",
+		[][2]string{{"id", "int unsigned"}, {"region", "varchar(16)"}})
+	if statement != expected {
+		t.Errorf("Expected statement %q but got %q", expected, statement)
+	}
+}
+
+func TestParseConflictCounters(t *testing.T) {
+	status := parseConflictCounters(map[string]string{
+		"Ndb_conflict_fn_max":                    "3",
+		"Ndb_conflict_fn_epoch2":                 "5",
+		"Ndb_conflict_trans_reject_count":        "2",
+		"Ndb_conflict_trans_row_reject_count":    "7",
+		"Ndb_conflict_epoch_delete_delete_count": "1",
+		"Ndb_conflict_last_conflict_epoch":       "4294967301",
+		"Ndb_conflict_refresh_op_count":          "9",
+	})
+
+	expected := &v1.NdbConflictDetectionStatus{
+		Conflicts:             8,
+		RejectedTransactions:  2,
+		RejectedRows:          7,
+		DeleteDeleteConflicts: 1,
+		LastConflictEpoch:     4294967301,
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("Expected conflict counters %+v but got %+v", expected, status)
+	}
+}
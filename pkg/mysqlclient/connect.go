@@ -1,4 +1,4 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -17,9 +17,12 @@ import (
 const (
 	mysqldPort      = 3306
 	ndbOperatorUser = "ndb-operator-user"
-	sqlDriverName   = "mysql"
 )
 
+// SQLDriverName is the name of the database/sql driver used to connect
+// to the MySQL Servers. The tests replace it with a fake driver.
+var SQLDriverName = "mysql"
+
 // System Database names
 const (
 	DbNdbInfo           = "ndbinfo"
@@ -29,10 +32,24 @@ const (
 
 // Connect to the MySQL Server at given mysqldHost
 func Connect(mysqldHost string, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
-	// Generate the complete address to connect to
-	dataSource := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?timeout=10s",
-		ndbOperatorUser, ndbOperatorPassword, mysqldHost, mysqldPort, dbName)
-	db, err := sql.Open(sqlDriverName, dataSource)
+	return connect(ndbOperatorUser, ndbOperatorPassword, mysqldHost, mysqldPort, dbName)
+}
+
+// ConnectAsReplicationUser connects, as the replication user, to the
+// binlog MySQL Server of a source MySQL Cluster at the given host and port
+func ConnectAsReplicationUser(host string, port int32, password string) (*sql.DB, error) {
+	return connect(ReplicationUser, password, host, int(port), DbMySQL)
+}
+
+// connect opens a connection, as the given user, to the MySQL Server at the given host and port
+func connect(user, password, mysqldHost string, port int, dbName string) (*sql.DB, error) {
+	// Generate the complete address to connect to.
+	// TLS is used if the MySQL Server supports it, so that
+	// the connection works even when the user is required
+	// to connect only via encrypted connections.
+	dataSource := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?timeout=10s&tls=preferred",
+		user, password, mysqldHost, port, dbName)
+	db, err := sql.Open(SQLDriverName, dataSource)
 	if err != nil {
 		klog.Infof("Error opening connection to MySQL server at %q : %s", mysqldHost, err)
 		return nil, err
@@ -58,11 +75,17 @@ func Connect(mysqldHost string, dbName string, ndbOperatorPassword string) (*sql
 
 // ConnectToStatefulSet opens a connection to the first MySQL Server pod managed by the given MySQL Server StatefulSet
 func ConnectToStatefulSet(mysqldSfset *appsv1.StatefulSet, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
+	return ConnectToStatefulSetPod(mysqldSfset, 0, dbName, ndbOperatorPassword)
+}
 
-	// Generate the MySQL Server host using the hostname of StatefulSet's pod-0
-	mysqldHost := fmt.Sprintf("%s-0.%s.%s",
-		mysqldSfset.Name, mysqldSfset.Spec.ServiceName, mysqldSfset.Namespace)
+// ConnectToStatefulSetPod opens a connection to the MySQL Server pod
+// with the given ordinal managed by the given MySQL Server StatefulSet
+func ConnectToStatefulSetPod(mysqldSfset *appsv1.StatefulSet,
+	podOrdinal int32, dbName string, ndbOperatorPassword string) (*sql.DB, error) {
 
-	return Connect(mysqldHost, dbName, ndbOperatorPassword)
+	// Generate the MySQL Server host using the hostname of the StatefulSet's pod
+	mysqldHost := fmt.Sprintf("%s-%d.%s.%s",
+		mysqldSfset.Name, podOrdinal, mysqldSfset.Spec.ServiceName, mysqldSfset.Namespace)
 
+	return Connect(mysqldHost, dbName, ndbOperatorPassword)
 }
@@ -0,0 +1,72 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+// getDatabaseCharset returns the default character set and collation
+// of the given database. Empty strings are returned if it doesn't exist.
+func getDatabaseCharset(ctx context.Context, db *sql.DB, databaseName string) (charset, collation string, err error) {
+	query := "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM " +
+		DbInformationSchema + ".SCHEMATA WHERE SCHEMA_NAME = ?"
+	err = db.QueryRowContext(ctx, query, databaseName).Scan(&charset, &collation)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Database doesn't exist
+		return "", "", nil
+	}
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+	}
+	return charset, collation, err
+}
+
+// ReconcileDatabase creates the database declared by the given NdbMySQLDatabase
+// if it doesn't exist yet, or alters its default character set and collation
+// if they differ from the spec. It returns true if the database had to be
+// created or altered and false if it was already up-to-date.
+func ReconcileDatabase(ctx context.Context, db *sql.DB, nmd *v1.NdbMySQLDatabase) (bool, error) {
+	spec := &nmd.Spec
+	charset, collation, err := getDatabaseCharset(ctx, db, spec.DatabaseName)
+	if err != nil {
+		return false, err
+	}
+
+	var query string
+	if charset == "" {
+		// Database doesn't exist
+		query = "CREATE DATABASE IF NOT EXISTS " + QuoteIdentifier(spec.DatabaseName)
+	} else if (spec.CharacterSet != "" && !strings.EqualFold(spec.CharacterSet, charset)) ||
+		(spec.Collation != "" && !strings.EqualFold(spec.Collation, collation)) {
+		// Database exists but has a different character set or collation
+		query = "ALTER DATABASE " + QuoteIdentifier(spec.DatabaseName)
+	} else {
+		// Database is up-to-date
+		return false, nil
+	}
+
+	if spec.CharacterSet != "" {
+		query += " CHARACTER SET " + spec.CharacterSet
+	}
+	if spec.Collation != "" {
+		query += " COLLATE " + spec.Collation
+	}
+
+	klog.Infof("Running '%s'", query)
+	if _, err = db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return false, err
+	}
+
+	return true, nil
+}
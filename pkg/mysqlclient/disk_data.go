@@ -0,0 +1,139 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+// Types of the disk data files in ndbinfo.files
+const (
+	fileTypeUndoFile = "Undo file"
+	fileTypeDataFile = "Data file"
+)
+
+// getDiskDataFiles returns the names of the disk data files of the given type
+// that belong to the logfile group or the tablespace with the given name.
+func getDiskDataFiles(ctx context.Context, db *sql.DB, fileType, objectName string) (map[string]bool, error) {
+	query := "SELECT name FROM " + DbNdbInfo + ".files WHERE type = ? AND parent_name = ?"
+	rows, err := db.QueryContext(ctx, query, fileType, objectName)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]bool)
+	for rows.Next() {
+		var fileName string
+		if err = rows.Scan(&fileName); err != nil {
+			klog.Errorf("Failed to scan the disk data files : %s", err)
+			return nil, err
+		}
+		files[fileName] = true
+	}
+
+	return files, rows.Err()
+}
+
+// execDiskDataQuery executes the given query that creates or alters a disk data object
+func execDiskDataQuery(ctx context.Context, db *sql.DB, query string) error {
+	klog.Infof("Running '%s'", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+	return nil
+}
+
+// ReconcileLogfileGroup creates the given logfile group if it does
+// not exist yet and then adds any missing undo files to it.
+func ReconcileLogfileGroup(ctx context.Context, db *sql.DB, lfg *v1.NdbLogfileGroupSpec) error {
+	existingFiles, err := getDiskDataFiles(ctx, db, fileTypeUndoFile, lfg.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, undoFile := range lfg.UndoFiles {
+		if existingFiles[undoFile.Name] {
+			// Undo file already exists
+			continue
+		}
+
+		var query string
+		if len(existingFiles) == 0 {
+			// The logfile group doesn't exist yet. Create it with the first undo file.
+			query = fmt.Sprintf("CREATE LOGFILE GROUP `%s` ADD UNDOFILE '%s' INITIAL_SIZE %d",
+				lfg.Name, undoFile.Name, undoFile.InitialSize.Value())
+			if lfg.UndoBufferSize != nil {
+				query += fmt.Sprintf(" UNDO_BUFFER_SIZE %d", lfg.UndoBufferSize.Value())
+			}
+		} else {
+			query = fmt.Sprintf("ALTER LOGFILE GROUP `%s` ADD UNDOFILE '%s' INITIAL_SIZE %d",
+				lfg.Name, undoFile.Name, undoFile.InitialSize.Value())
+		}
+
+		if err = execDiskDataQuery(ctx, db, query+" ENGINE NDBCLUSTER"); err != nil {
+			return err
+		}
+		existingFiles[undoFile.Name] = true
+	}
+
+	return nil
+}
+
+// ReconcileTablespace creates the given tablespace if it does
+// not exist yet and then adds any missing data files to it.
+func ReconcileTablespace(ctx context.Context, db *sql.DB, ts *v1.NdbTablespaceSpec) error {
+	existingFiles, err := getDiskDataFiles(ctx, db, fileTypeDataFile, ts.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, dataFile := range ts.DataFiles {
+		if existingFiles[dataFile.Name] {
+			// Data file already exists
+			continue
+		}
+
+		var query string
+		if len(existingFiles) == 0 {
+			// The tablespace doesn't exist yet. Create it with the first data file.
+			query = fmt.Sprintf("CREATE TABLESPACE `%s` ADD DATAFILE '%s' USE LOGFILE GROUP `%s` INITIAL_SIZE %d",
+				ts.Name, dataFile.Name, ts.LogfileGroup, dataFile.InitialSize.Value())
+			if ts.ExtentSize != nil {
+				query += fmt.Sprintf(" EXTENT_SIZE %d", ts.ExtentSize.Value())
+			}
+		} else {
+			query = fmt.Sprintf("ALTER TABLESPACE `%s` ADD DATAFILE '%s' INITIAL_SIZE %d",
+				ts.Name, dataFile.Name, dataFile.InitialSize.Value())
+		}
+
+		if err = execDiskDataQuery(ctx, db, query+" ENGINE NDBCLUSTER"); err != nil {
+			return err
+		}
+		existingFiles[dataFile.Name] = true
+	}
+
+	return nil
+}
+
+// GetTablespaceExtents returns the number of free and total extents in the
+// data files of the tablespace with the given name, as reported by ndbinfo.
+func GetTablespaceExtents(ctx context.Context, db *sql.DB, tablespace string) (freeExtents, totalExtents int64, err error) {
+	query := "SELECT COALESCE(SUM(free_extents), 0), COALESCE(SUM(total_extents), 0) " +
+		"FROM " + DbNdbInfo + ".files WHERE type = ? AND parent_name = ?"
+	if err = db.QueryRowContext(ctx, query, fileTypeDataFile, tablespace).Scan(&freeExtents, &totalExtents); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return 0, 0, err
+	}
+	return freeExtents, totalExtents, nil
+}
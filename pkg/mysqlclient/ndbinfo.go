@@ -0,0 +1,162 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+
+	klog "k8s.io/klog/v2"
+)
+
+// NdbInfoMemoryUsage is a row of the ndbinfo.memoryusage table
+type NdbInfoMemoryUsage struct {
+	NodeId     int
+	MemoryType string
+	Used       int64
+	Total      int64
+}
+
+// NdbInfoLogUsage is a row of the ndbinfo.logspaces or the ndbinfo.logbuffers table
+type NdbInfoLogUsage struct {
+	NodeId  int
+	LogType string
+	LogId   int
+	LogPart int
+	Used    int64
+	Total   int64
+}
+
+// NdbInfoDiskPageBuffer holds the ndbinfo.diskpagebuffer
+// counters of a data node, summed over all its LDM instances
+type NdbInfoDiskPageBuffer struct {
+	NodeId                   int
+	PagesWritten             int64
+	PagesWrittenLCP          int64
+	PagesRead                int64
+	LogWaits                 int64
+	PageRequestsDirectReturn int64
+	PageRequestsWaitQueue    int64
+	PageRequestsWaitIO       int64
+}
+
+// NdbInfoThreadStat is a row of the ndbinfo.threadstat table
+type NdbInfoThreadStat struct {
+	NodeId     int
+	ThreadNo   int
+	ThreadName string
+	// Loops executed by the thread
+	Loops int64
+	// Signals executed by the thread
+	Signals int64
+	// Waits performed by the thread for new signals
+	Waits int64
+	// User and system CPU time used by the thread, in microseconds
+	UserTime   int64
+	SystemTime int64
+}
+
+// NdbInfoResourceUsage is the resource usage of
+// the data nodes as reported by the ndbinfo tables
+type NdbInfoResourceUsage struct {
+	MemoryUsage    []NdbInfoMemoryUsage
+	LogSpaces      []NdbInfoLogUsage
+	LogBuffers     []NdbInfoLogUsage
+	DiskPageBuffer []NdbInfoDiskPageBuffer
+	ThreadStat     []NdbInfoThreadStat
+}
+
+// queryNdbInfo executes the given query and calls scanRow for every row returned
+func queryNdbInfo(ctx context.Context, db *sql.DB, query string, scanRow func(rows *sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scanRow(rows); err != nil {
+			klog.Errorf("Failed to read the output of %s: %s", query, err)
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// getLogUsage returns the log usage from the given ndbinfo table
+func getLogUsage(ctx context.Context, db *sql.DB, table string) ([]NdbInfoLogUsage, error) {
+	var logUsage []NdbInfoLogUsage
+	query := "SELECT node_id, log_type, log_id, log_part, used, total FROM " + DbNdbInfo + "." + table
+	err := queryNdbInfo(ctx, db, query, func(rows *sql.Rows) error {
+		var lu NdbInfoLogUsage
+		if err := rows.Scan(&lu.NodeId, &lu.LogType, &lu.LogId, &lu.LogPart, &lu.Used, &lu.Total); err != nil {
+			return err
+		}
+		logUsage = append(logUsage, lu)
+		return nil
+	})
+	return logUsage, err
+}
+
+// GetNdbInfoResourceUsage returns the resource usage of the data nodes from the
+// ndbinfo memoryusage, logspaces, logbuffers, diskpagebuffer and threadstat tables.
+func GetNdbInfoResourceUsage(ctx context.Context, db *sql.DB) (*NdbInfoResourceUsage, error) {
+	var usage NdbInfoResourceUsage
+
+	query := "SELECT node_id, memory_type, used, total FROM " + DbNdbInfo + ".memoryusage"
+	if err := queryNdbInfo(ctx, db, query, func(rows *sql.Rows) error {
+		var mu NdbInfoMemoryUsage
+		if err := rows.Scan(&mu.NodeId, &mu.MemoryType, &mu.Used, &mu.Total); err != nil {
+			return err
+		}
+		usage.MemoryUsage = append(usage.MemoryUsage, mu)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if usage.LogSpaces, err = getLogUsage(ctx, db, "logspaces"); err != nil {
+		return nil, err
+	}
+
+	if usage.LogBuffers, err = getLogUsage(ctx, db, "logbuffers"); err != nil {
+		return nil, err
+	}
+
+	query = "SELECT node_id, SUM(pages_written), SUM(pages_written_lcp), SUM(pages_read), SUM(log_waits), " +
+		"SUM(page_requests_direct_return), SUM(page_requests_wait_queue), SUM(page_requests_wait_io) " +
+		"FROM " + DbNdbInfo + ".diskpagebuffer GROUP BY node_id"
+	if err = queryNdbInfo(ctx, db, query, func(rows *sql.Rows) error {
+		var dpb NdbInfoDiskPageBuffer
+		if err := rows.Scan(&dpb.NodeId, &dpb.PagesWritten, &dpb.PagesWrittenLCP, &dpb.PagesRead,
+			&dpb.LogWaits, &dpb.PageRequestsDirectReturn, &dpb.PageRequestsWaitQueue,
+			&dpb.PageRequestsWaitIO); err != nil {
+			return err
+		}
+		usage.DiskPageBuffer = append(usage.DiskPageBuffer, dpb)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	query = "SELECT node_id, thr_no, thr_nm, c_loop, c_exec, c_wait, os_ru_utime, os_ru_stime " +
+		"FROM " + DbNdbInfo + ".threadstat"
+	if err = queryNdbInfo(ctx, db, query, func(rows *sql.Rows) error {
+		var ts NdbInfoThreadStat
+		if err := rows.Scan(&ts.NodeId, &ts.ThreadNo, &ts.ThreadName,
+			&ts.Loops, &ts.Signals, &ts.Waits, &ts.UserTime, &ts.SystemTime); err != nil {
+			return err
+		}
+		usage.ThreadStat = append(usage.ThreadStat, ts)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
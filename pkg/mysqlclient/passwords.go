@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	klog "k8s.io/klog/v2"
+)
+
+// CurrentUserAccount is the account of the user of a connection
+const CurrentUserAccount = "CURRENT_USER()"
+
+// RootUserAccount returns the account name of the root user with the given host
+func RootUserAccount(rootHost string) string {
+	return userAccount("root", rootHost)
+}
+
+// RotatePassword sets newPassword as the password of the given account
+// and retains its current password as a secondary password, so that
+// the clients can connect using either of them until the old password
+// is discarded by DiscardOldPassword.
+func RotatePassword(ctx context.Context, db *sql.DB, account, newPassword string) error {
+	klog.Infof("Rotating the password of the MySQL user %s", account)
+	// The query is not logged as it has the password
+	query := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s RETAIN CURRENT PASSWORD", account, quoteString(newPassword))
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Failed to rotate the password of the MySQL user %s : %s", account, err)
+		return err
+	}
+	return nil
+}
+
+// DiscardOldPassword discards the secondary password of the given account
+func DiscardOldPassword(ctx context.Context, db *sql.DB, account string) error {
+	query := fmt.Sprintf("ALTER USER %s DISCARD OLD PASSWORD", account)
+	klog.Infof("Running '%s'", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+	return nil
+}
+
+// GetLocalNDBOperatorAccounts returns the accounts of the NDB Operator
+// user that are local to the MySQL Server, i.e. all the accounts except
+// the one used by the NDB Operator, which is shared by all the MySQL
+// Servers. These accounts are used by the data node pods.
+func GetLocalNDBOperatorAccounts(ctx context.Context, db *sql.DB) ([]string, error) {
+	query := "SELECT host FROM mysql.user WHERE user = ? AND CONCAT(user, '@', host) != CURRENT_USER()"
+	rows, err := db.QueryContext(ctx, query, ndbOperatorUser)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []string
+	for rows.Next() {
+		var host string
+		if err = rows.Scan(&host); err != nil {
+			klog.Errorf("Failed to scan the hosts of the NDB Operator user : %s", err)
+			return nil, err
+		}
+		accounts = append(accounts, userAccount(ndbOperatorUser, host))
+	}
+
+	return accounts, rows.Err()
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	klog "k8s.io/klog/v2"
+)
+
+// ReplicationUser is the user, created in the binlog MySQL Servers of the
+// source MySQL Clusters, via which the replicas read the binary logs
+const ReplicationUser = "ndb-replication-user"
+
+// errReplicaIncident is the error (ER_REPLICA_INCIDENT) reported by the
+// applier when it reads an incident event, like the LOST_EVENTS incident
+// written by a binlog MySQL Server that has a gap in its binary log.
+const errReplicaIncident = 1590
+
+// getReplicationUserStatements returns the statements that create the
+// replication user, or update its password, and grant it the privileges
+// required to read the binary log and the mysql.ndb_binlog_index table.
+func getReplicationUserStatements(password string) []string {
+	account := userAccount(ReplicationUser, "%")
+	return []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s IDENTIFIED BY %s", account, quoteString(password)),
+		fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", account, quoteString(password)),
+		fmt.Sprintf("GRANT REPLICATION SLAVE ON *.* TO %s", account),
+		fmt.Sprintf("GRANT SELECT ON %s.`ndb_binlog_index` TO %s", QuoteIdentifier(DbMySQL), account),
+		fmt.Sprintf("GRANT %s ON *.* TO %s", privilegeNdbStoredUser, account),
+	}
+}
+
+// ReconcileReplicationUser creates the replication user if it doesn't
+// exist yet, or updates its password. The user is shared by all the
+// MySQL Servers of the MySQL Cluster.
+func ReconcileReplicationUser(ctx context.Context, db *sql.DB, password string) error {
+	klog.Infof("Updating the replication user '%s'", ReplicationUser)
+	for _, statement := range getReplicationUserStatements(password) {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			// The statements have the password and hence are not logged
+			klog.Errorf("Failed to update the replication user : %s", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLastAppliedEpoch returns the last epoch, replicated from any other
+// MySQL Cluster, that has been applied to the MySQL Cluster. It returns
+// 0 if no epoch has been applied yet.
+func GetLastAppliedEpoch(ctx context.Context, db *sql.DB) (int64, error) {
+	var epoch int64
+	query := "SELECT COALESCE(MAX(epoch), 0) FROM " + DbMySQL + ".ndb_apply_status WHERE server_id <> @@server_id"
+	if err := db.QueryRowContext(ctx, query).Scan(&epoch); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return 0, err
+	}
+	return epoch, nil
+}
+
+// GetBinlogPositionAfterEpoch returns the position, in the binary log of the
+// source MySQL Server, that follows the given epoch of the source MySQL Cluster.
+func GetBinlogPositionAfterEpoch(ctx context.Context, db *sql.DB, epoch int64) (logFile string, logPos int64, err error) {
+	// Continue from the end of the epoch if it is in the binary log
+	query := "SELECT SUBSTRING_INDEX(next_file, '/', -1), next_position FROM " + DbMySQL +
+		".ndb_binlog_index WHERE epoch = ? ORDER BY epoch ASC LIMIT 1"
+	err = db.QueryRowContext(ctx, query, epoch).Scan(&logFile, &logPos)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The epoch did not change any data in this MySQL Server's
+		// binary log - continue from the beginning of the next one.
+		query = "SELECT SUBSTRING_INDEX(File, '/', -1), position FROM " + DbMySQL +
+			".ndb_binlog_index WHERE epoch > ? ORDER BY epoch ASC LIMIT 1"
+		err = db.QueryRowContext(ctx, query, epoch).Scan(&logFile, &logPos)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", 0, fmt.Errorf("epoch %d, or any epoch after it, is not in the binary log of the source", epoch)
+		}
+	}
+
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return "", 0, err
+	}
+	return logFile, logPos, nil
+}
+
+// ReplicaStatus is the state of the replication channel
+// of a replica MySQL Server, as reported by SHOW REPLICA STATUS
+type ReplicaStatus struct {
+	SourceHost    string
+	SourcePort    int32
+	ReceiverState string
+	ApplierState  string
+	// SecondsBehindSource is nil if the channel is not running
+	SecondsBehindSource *int64
+	LastReceiverError   string
+	LastApplierError    string
+	LastApplierErrno    int
+}
+
+// IsRunning returns true if both the receiver
+// and the applier threads are running
+func (rs *ReplicaStatus) IsRunning() bool {
+	return rs.ReceiverState == "Yes" && rs.ApplierState == "Yes"
+}
+
+// IsStopped returns true if both the receiver and the applier threads
+// have been stopped, and not due to an error, e.g. by a restart.
+func (rs *ReplicaStatus) IsStopped() bool {
+	return rs.ReceiverState == "No" && rs.ApplierState == "No" && rs.LastError() == ""
+}
+
+// HasLostEvents returns true if the applier has stopped due to an
+// incident in the binary log of the source, i.e. the binary log has
+// a gap and is not usable anymore to replicate from.
+func (rs *ReplicaStatus) HasLostEvents() bool {
+	return rs.LastApplierErrno == errReplicaIncident
+}
+
+// LastError returns the last error reported by the applier or the receiver thread
+func (rs *ReplicaStatus) LastError() string {
+	if rs.LastApplierError != "" {
+		return rs.LastApplierError
+	}
+	return rs.LastReceiverError
+}
+
+// parseReplicaStatus parses a row returned by SHOW REPLICA STATUS
+func parseReplicaStatus(columns []string, values []sql.NullString) *ReplicaStatus {
+	row := make(map[string]sql.NullString)
+	for i, column := range columns {
+		row[column] = values[i]
+	}
+
+	status := &ReplicaStatus{
+		SourceHost:        row["Source_Host"].String,
+		ReceiverState:     row["Replica_IO_Running"].String,
+		ApplierState:      row["Replica_SQL_Running"].String,
+		LastReceiverError: row["Last_IO_Error"].String,
+		LastApplierError:  row["Last_SQL_Error"].String,
+	}
+
+	if errno, err := strconv.Atoi(row["Last_SQL_Errno"].String); err == nil {
+		status.LastApplierErrno = errno
+	}
+
+	if port, err := strconv.ParseInt(row["Source_Port"].String, 10, 32); err == nil {
+		status.SourcePort = int32(port)
+	}
+
+	if lag := row["Seconds_Behind_Source"]; lag.Valid {
+		if seconds, err := strconv.ParseInt(lag.String, 10, 64); err == nil {
+			status.SecondsBehindSource = &seconds
+		}
+	}
+
+	return status
+}
+
+// GetReplicaStatus returns the state of the replication channel of the
+// MySQL Server or nil if the replication has not been configured yet.
+func GetReplicaStatus(ctx context.Context, db *sql.DB) (*ReplicaStatus, error) {
+	query := "SHOW REPLICA STATUS"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		// Replication has not been configured
+		return nil, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err = rows.Scan(scanArgs...); err != nil {
+		klog.Errorf("Failed to read the output of %s: %s", query, err)
+		return nil, err
+	}
+
+	return parseReplicaStatus(columns, values), nil
+}
+
+// getChangeReplicationSourceStatement returns the CHANGE REPLICATION SOURCE
+// statement that points the replica to the given position in the binary log
+// of the source. The replication starts from the beginning of the binary log
+// if no log file is given.
+func getChangeReplicationSourceStatement(host string, port int32, password, logFile string, logPos int64) string {
+	statement := fmt.Sprintf("CHANGE REPLICATION SOURCE TO SOURCE_HOST = %s, SOURCE_PORT = %d, "+
+		"SOURCE_USER = %s, SOURCE_PASSWORD = %s, SOURCE_SSL = 1",
+		quoteString(host), port, quoteString(ReplicationUser), quoteString(password))
+	if logFile != "" {
+		statement += fmt.Sprintf(", SOURCE_LOG_FILE = %s, SOURCE_LOG_POS = %d", quoteString(logFile), logPos)
+	}
+	return statement
+}
+
+// StartReplication (re)starts the replication channel of the replica
+// MySQL Server from the given position in the binary log of the source.
+func StartReplication(ctx context.Context, db *sql.DB,
+	host string, port int32, password, logFile string, logPos int64) error {
+	klog.Infof("Starting replication from %s:%d at position %s:%d", host, port, logFile, logPos)
+	statements := []string{
+		"STOP REPLICA",
+		getChangeReplicationSourceStatement(host, port, password, logFile, logPos),
+		"START REPLICA",
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			// The statements might have the password and hence are not logged
+			klog.Errorf("Failed to start the replication : %s", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveReplication stops the replication channel of the replica
+// MySQL Server and removes its configuration and credentials
+func RemoveReplication(ctx context.Context, db *sql.DB) error {
+	klog.Info("Removing the replication channel")
+	for _, query := range []string{"STOP REPLICA", "RESET REPLICA ALL"} {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			klog.Errorf("Error executing %s: %s", query, err)
+			return err
+		}
+	}
+	return nil
+}
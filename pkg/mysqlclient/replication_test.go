@@ -0,0 +1,81 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestGetChangeReplicationSourceStatement(t *testing.T) {
+	expected := "CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'source.example.com', SOURCE_PORT = 3306, " +
+		"SOURCE_USER = 'ndb-replication-user', SOURCE_PASSWORD = 'pass\\'word', SOURCE_SSL = 1"
+	if statement := getChangeReplicationSourceStatement(
+		"source.example.com", 3306, "pass'word", "", 0); statement != expected {
+		t.Errorf("Expected statement %q but got %q", expected, statement)
+	}
+
+	expected += ", SOURCE_LOG_FILE = 'binlog.000002', SOURCE_LOG_POS = 1204"
+	if statement := getChangeReplicationSourceStatement(
+		"source.example.com", 3306, "pass'word", "binlog.000002", 1204); statement != expected {
+		t.Errorf("Expected statement %q but got %q", expected, statement)
+	}
+}
+
+func TestParseReplicaStatus(t *testing.T) {
+	columns := []string{"Replica_IO_State", "Source_Host", "Source_Port", "Replica_IO_Running",
+		"Replica_SQL_Running", "Last_SQL_Errno", "Last_SQL_Error", "Seconds_Behind_Source", "Last_IO_Error"}
+
+	// A running channel
+	status := parseReplicaStatus(columns, []sql.NullString{
+		{String: "Waiting for source to send event", Valid: true},
+		{String: "source.example.com", Valid: true},
+		{String: "3306", Valid: true},
+		{String: "Yes", Valid: true},
+		{String: "Yes", Valid: true},
+		{String: "0", Valid: true},
+		{String: "", Valid: true},
+		{String: "12", Valid: true},
+		{String: "", Valid: true},
+	})
+	if !status.IsRunning() || status.IsStopped() ||
+		status.SourceHost != "source.example.com" || status.SourcePort != 3306 ||
+		status.SecondsBehindSource == nil || *status.SecondsBehindSource != 12 {
+		t.Errorf("Unexpected replica status : %+v", status)
+	}
+
+	// A channel whose applier stopped due to an error
+	status = parseReplicaStatus(columns, []sql.NullString{
+		{String: "", Valid: true},
+		{String: "source.example.com", Valid: true},
+		{String: "3306", Valid: true},
+		{String: "Yes", Valid: true},
+		{String: "No", Valid: true},
+		{String: "1032", Valid: true},
+		{String: "Error executing row event", Valid: true},
+		{},
+		{String: "", Valid: true},
+	})
+	if status.IsRunning() || status.IsStopped() || status.HasLostEvents() ||
+		status.SecondsBehindSource != nil || status.LastError() != "Error executing row event" {
+		t.Errorf("Unexpected replica status : %+v", status)
+	}
+
+	// A channel whose applier stopped due to a gap in the binary log of the source
+	status = parseReplicaStatus(columns, []sql.NullString{
+		{String: "", Valid: true},
+		{String: "source.example.com", Valid: true},
+		{String: "3306", Valid: true},
+		{String: "Yes", Valid: true},
+		{String: "No", Valid: true},
+		{String: "1590", Valid: true},
+		{String: "The incident LOST_EVENTS occurred on the source", Valid: true},
+		{},
+		{String: "", Valid: true},
+	})
+	if !status.HasLostEvents() {
+		t.Errorf("Replica status should have reported lost events : %+v", status)
+	}
+}
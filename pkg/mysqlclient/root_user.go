@@ -1,4 +1,4 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -114,3 +114,40 @@ func UpdateRootUser(mysqldSfset *appsv1.StatefulSet, oldRootHost, newRootHost st
 	}
 	return nil
 }
+
+// UpdateRequireSecureTransport updates the root user with the given host and the
+// NDB Operator user to allow them to connect only via encrypted connections
+// if requireSecureTransport is true and via any connection otherwise.
+func UpdateRequireSecureTransport(mysqldSfset *appsv1.StatefulSet,
+	rootHost string, requireSecureTransport bool, ndbOperatorPassword string) error {
+	db, err := ConnectToStatefulSet(mysqldSfset, DbMySQL, ndbOperatorPassword)
+	if err != nil {
+		return err
+	}
+
+	tlsOption := "none"
+	if requireSecureTransport {
+		tlsOption = "ssl"
+	}
+
+	klog.Infof("Updating the root and the NDB Operator users to require %s", tlsOption)
+	queries := []string{
+		// Update the NDB Operator user via CURRENT_USER()
+		// as its host is known only to the MySQL Server.
+		fmt.Sprintf("alter user current_user() require %s", tlsOption),
+	}
+	if rootHost != "localhost" {
+		// The local root user is used only via the unix socket,
+		// through which encrypted connections are not possible.
+		queries = append(queries, fmt.Sprintf("alter user 'root'@'%s' require %s", rootHost, tlsOption))
+	}
+
+	for _, query := range queries {
+		if _, err = db.Exec(query); err != nil {
+			klog.Infof("Error executing %s: %s", query, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	klog "k8s.io/klog/v2"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+const (
+	// privilegeNdbStoredUser is the privilege that makes a user
+	// and its privileges shared by all the MySQL Servers
+	privilegeNdbStoredUser = "NDB_STORED_USER"
+	// privilegeUsage is the privilege of a user with no privileges
+	privilegeUsage = "USAGE"
+	// privilegeAll is the privilege that includes all the
+	// privileges, except the GRANT OPTION, on an object
+	privilegeAll = "ALL PRIVILEGES"
+	// privilegeGrantOption is the privilege to grant
+	// the privileges held on an object to other users
+	privilegeGrantOption = "GRANT OPTION"
+)
+
+// validPrivilege matches the static and dynamic privilege names
+var validPrivilege = regexp.MustCompile(`^[A-Za-z_]+( [A-Za-z_]+)*$`)
+
+// quoteString returns the given string as a quoted SQL string literal
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// QuoteIdentifier returns the given name as a quoted SQL identifier
+func QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// userAccount returns the account name of the given user and host
+func userAccount(userName, host string) string {
+	return quoteString(userName) + "@" + quoteString(host)
+}
+
+// grantObject returns the object, on which the privileges of the given grant apply
+func grantObject(grant *v1.NdbMySQLUserGrant) string {
+	object := "*"
+	if grant.Database != "*" {
+		object = QuoteIdentifier(grant.Database)
+	}
+
+	if grant.Table == "" || grant.Table == "*" {
+		return object + ".*"
+	}
+	return object + "." + QuoteIdentifier(grant.Table)
+}
+
+// privilegeSet is a set of privileges, including the GRANT OPTION
+type privilegeSet map[string]bool
+
+// difference returns, in sorted order, the privileges
+// that are in the privilegeSet but not in the other one
+func (ps privilegeSet) difference(other privilegeSet) []string {
+	var privileges []string
+	for privilege := range ps {
+		if !other[privilege] {
+			privileges = append(privileges, privilege)
+		}
+	}
+	sort.Strings(privileges)
+	return privileges
+}
+
+// objectPrivileges maps the objects, like `db`.* or *.*,
+// to the set of privileges granted on them
+type objectPrivileges map[string]privilegeSet
+
+// add adds the given privilege to the set of privileges of the object
+func (op objectPrivileges) add(object, privilege string) {
+	if op[object] == nil {
+		op[object] = make(privilegeSet)
+	}
+	op[object][privilege] = true
+}
+
+// sortedObjects returns the objects in sorted order
+func (op objectPrivileges) sortedObjects() []string {
+	objects := make([]string, 0, len(op))
+	for object := range op {
+		objects = append(objects, object)
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+// normalizePrivilege returns the given privilege in the
+// form in which it is reported by SHOW GRANTS
+func normalizePrivilege(privilege string) string {
+	privilege = strings.ToUpper(strings.Join(strings.Fields(privilege), " "))
+	if privilege == "ALL" {
+		return privilegeAll
+	}
+	return privilege
+}
+
+// getDeclaredPrivileges returns the privileges declared in the NdbMySQLUser spec
+func getDeclaredPrivileges(nmu *v1.NdbMySQLUser) (objectPrivileges, error) {
+	declared := make(objectPrivileges)
+	for i := range nmu.Spec.Grants {
+		grant := &nmu.Spec.Grants[i]
+		object := grantObject(grant)
+		for _, privilege := range grant.Privileges {
+			if !validPrivilege.MatchString(privilege) {
+				return nil, fmt.Errorf("invalid privilege %q", privilege)
+			}
+			declared.add(object, normalizePrivilege(privilege))
+		}
+		if grant.WithGrantOption {
+			declared.add(object, privilegeGrantOption)
+		}
+	}
+
+	if nmu.IsNdbStoredUser() {
+		declared.add("*.*", privilegeNdbStoredUser)
+	}
+
+	return declared, nil
+}
+
+// parseGrants returns the privileges and the roles
+// granted to a user, as reported by SHOW GRANTS
+func parseGrants(grants []string) (granted objectPrivileges, roles []string) {
+	granted = make(objectPrivileges)
+	for _, grant := range grants {
+		if !strings.HasPrefix(grant, "GRANT ") {
+			continue
+		}
+
+		// The grants are of form "GRANT <privileges> ON <object> TO <account>"
+		// or "GRANT <roles> TO <account>", optionally with a grant option.
+		grant = strings.TrimPrefix(grant, "GRANT ")
+		withGrantOption := strings.HasSuffix(grant, " WITH GRANT OPTION")
+		grant = strings.TrimSuffix(grant, " WITH GRANT OPTION")
+		grant = strings.TrimSuffix(grant, " WITH ADMIN OPTION")
+
+		toIndex := strings.LastIndex(grant, " TO ")
+		if toIndex == -1 {
+			continue
+		}
+		privileges, object, isPrivilegeGrant := strings.Cut(grant[:toIndex], " ON ")
+
+		for _, privilege := range strings.Split(privileges, ",") {
+			privilege = strings.TrimSpace(privilege)
+			if !isPrivilegeGrant {
+				// Role grant
+				roles = append(roles, privilege)
+			} else if privilege != privilegeUsage {
+				granted.add(object, privilege)
+			}
+		}
+		if isPrivilegeGrant && withGrantOption {
+			granted.add(object, privilegeGrantOption)
+		}
+	}
+
+	return granted, roles
+}
+
+// getPrivilegeUpdateStatements returns the REVOKE and GRANT statements that
+// change the granted privileges and roles of the given account to the declared
+// privileges. Only the privileges that differ are revoked or granted, so that
+// the user never loses, even momentarily, a privilege declared in the spec.
+func getPrivilegeUpdateStatements(account string,
+	granted, declared objectPrivileges, roles []string) []string {
+	var statements []string
+
+	// Revoke the privileges before granting any, as revoking ALL
+	// PRIVILEGES also revokes the privileges granted before it.
+	for _, object := range granted.sortedObjects() {
+		grantedOnObject := granted[object]
+		if declared[object][privilegeAll] {
+			// ALL PRIVILEGES includes all the other privileges except the
+			// GRANT OPTION, which SHOW GRANTS might list one by one.
+			grantedOnObject = privilegeSet{privilegeGrantOption: grantedOnObject[privilegeGrantOption]}
+		}
+
+		if revoked := grantedOnObject.difference(declared[object]); len(revoked) != 0 {
+			statements = append(statements, fmt.Sprintf("REVOKE %s ON %s FROM %s",
+				strings.Join(revoked, ", "), object, account))
+		}
+	}
+
+	// Roles are never declared in the spec
+	if len(roles) != 0 {
+		statements = append(statements, fmt.Sprintf("REVOKE %s FROM %s", strings.Join(roles, ", "), account))
+	}
+
+	for _, object := range declared.sortedObjects() {
+		missing := declared[object].difference(granted[object])
+		if len(missing) == 0 {
+			continue
+		}
+
+		var privileges []string
+		withGrantOption := false
+		for _, privilege := range missing {
+			if privilege == privilegeGrantOption {
+				withGrantOption = true
+			} else {
+				privileges = append(privileges, privilege)
+			}
+		}
+		if len(privileges) == 0 {
+			// Only the GRANT OPTION is missing
+			privileges = []string{privilegeUsage}
+		}
+
+		statement := fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), object, account)
+		if withGrantOption {
+			statement += " WITH GRANT OPTION"
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
+
+// GetUserGrants returns the grants of the given user as reported by SHOW GRANTS.
+// A nil slice is returned if the user does not exist.
+func GetUserGrants(ctx context.Context, db *sql.DB, userName, host string) ([]string, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host = ?"
+	if err := db.QueryRowContext(ctx, query, userName, host).Scan(&count); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	if count == 0 {
+		// User does not exist
+		return nil, nil
+	}
+
+	query = "SHOW GRANTS FOR " + userAccount(userName, host)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []string{}
+	for rows.Next() {
+		var grant string
+		if err = rows.Scan(&grant); err != nil {
+			klog.Errorf("Failed to scan the grants of user %s : %s", userAccount(userName, host), err)
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+
+	return grants, rows.Err()
+}
+
+// ReconcileUser creates the MySQL user declared by the given NdbMySQLUser,
+// if it does not exist yet, and then updates its password, if updatePassword
+// is true, and resource limits. The privileges of the user are then updated
+// to match the spec, by revoking only the privileges not declared in the spec
+// and by granting only the declared privileges that the user doesn't have.
+func ReconcileUser(ctx context.Context, db *sql.DB,
+	nmu *v1.NdbMySQLUser, password string, updatePassword bool) error {
+	userName, host := nmu.Spec.UserName, nmu.GetHost()
+	account := userAccount(userName, host)
+
+	declared, err := getDeclaredPrivileges(nmu)
+	if err != nil {
+		return err
+	}
+
+	existingGrants, err := GetUserGrants(ctx, db, userName, host)
+	if err != nil {
+		return err
+	}
+
+	// The queries with the password are not logged
+	if existingGrants == nil {
+		// User doesn't exist yet
+		klog.Infof("Creating the MySQL user %s", account)
+		query := fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", account, quoteString(password))
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			klog.Errorf("Failed to create the MySQL user %s : %s", account, err)
+			return err
+		}
+	} else if updatePassword {
+		klog.Infof("Updating the password of the MySQL user %s", account)
+		query := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", account, quoteString(password))
+		if _, err = db.ExecContext(ctx, query); err != nil {
+			klog.Errorf("Failed to update the password of the MySQL user %s : %s", account, err)
+			return err
+		}
+	}
+
+	// Update the resource limits. Unspecified limits are reset to 0, i.e. no limit.
+	limits := nmu.Spec.ResourceLimits
+	if limits == nil {
+		limits = &v1.NdbMySQLUserResourceLimits{}
+	}
+	query := fmt.Sprintf("ALTER USER %s WITH MAX_QUERIES_PER_HOUR %d MAX_UPDATES_PER_HOUR %d "+
+		"MAX_CONNECTIONS_PER_HOUR %d MAX_USER_CONNECTIONS %d", account, limits.MaxQueriesPerHour,
+		limits.MaxUpdatesPerHour, limits.MaxConnectionsPerHour, limits.MaxUserConnections)
+	klog.Infof("Running '%s'", query)
+	if _, err = db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+
+	// Revoke the privileges not declared in the spec and grant the missing ones
+	granted, roles := parseGrants(existingGrants)
+	for _, statement := range getPrivilegeUpdateStatements(account, granted, declared, roles) {
+		klog.Infof("Running '%s'", statement)
+		if _, err = db.ExecContext(ctx, statement); err != nil {
+			klog.Errorf("Error executing %s: %s", statement, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DropUserIfExists drops the given MySQL user if it exists
+func DropUserIfExists(ctx context.Context, db *sql.DB, userName, host string) error {
+	query := "DROP USER IF EXISTS " + userAccount(userName, host)
+	klog.Infof("Running '%s'", query)
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		klog.Errorf("Error executing %s: %s", query, err)
+		return err
+	}
+	return nil
+}
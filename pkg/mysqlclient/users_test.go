@@ -0,0 +1,143 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package mysqlclient
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+)
+
+func newTestNdbMySQLUser() *v1.NdbMySQLUser {
+	return &v1.NdbMySQLUser{
+		Spec: v1.NdbMySQLUserSpec{
+			UserName: "app",
+			Grants: []v1.NdbMySQLUserGrant{
+				{Privileges: []string{"SELECT", "insert"}, Database: "shop"},
+				{Privileges: []string{"ALL"}, Database: "shop", Table: "orders", WithGrantOption: true},
+				{Privileges: []string{"PROCESS"}, Database: "*"},
+			},
+		},
+	}
+}
+
+func TestGetDeclaredPrivileges(t *testing.T) {
+	nmu := newTestNdbMySQLUser()
+	declared, err := getDeclaredPrivileges(nmu)
+	if err != nil {
+		t.Fatalf("getDeclaredPrivileges failed : %s", err)
+	}
+	expected := objectPrivileges{
+		"`shop`.*":        {"SELECT": true, "INSERT": true},
+		"`shop`.`orders`": {"ALL PRIVILEGES": true, "GRANT OPTION": true},
+		"*.*":             {"PROCESS": true, "NDB_STORED_USER": true},
+	}
+	if !reflect.DeepEqual(declared, expected) {
+		t.Errorf("Expected declared privileges %v but got %v", expected, declared)
+	}
+
+	// Privileges are not quoted and hence should be validated
+	nmu.Spec.Grants[0].Privileges = []string{"SELECT ON *.* TO 'root'@'%'; DROP USER"}
+	if _, err = getDeclaredPrivileges(nmu); err == nil {
+		t.Error("getDeclaredPrivileges should have failed for an invalid privilege")
+	}
+}
+
+func TestGetPrivilegeUpdateStatements(t *testing.T) {
+	declared, err := getDeclaredPrivileges(newTestNdbMySQLUser())
+	if err != nil {
+		t.Fatalf("getDeclaredPrivileges failed : %s", err)
+	}
+	account := userAccount("app", "%")
+
+	testcases := []struct {
+		desc       string
+		grants     []string
+		statements []string
+	}{
+		{
+			desc: "new user",
+			statements: []string{
+				"GRANT NDB_STORED_USER, PROCESS ON *.* TO 'app'@'%'",
+				"GRANT INSERT, SELECT ON `shop`.* TO 'app'@'%'",
+				"GRANT ALL PRIVILEGES ON `shop`.`orders` TO 'app'@'%' WITH GRANT OPTION",
+			},
+		},
+		{
+			desc: "user in sync with the spec",
+			grants: []string{
+				"GRANT USAGE ON *.* TO `app`@`%`",
+				"GRANT PROCESS ON *.* TO `app`@`%`",
+				"GRANT NDB_STORED_USER ON *.* TO `app`@`%`",
+				"GRANT SELECT, INSERT ON `shop`.* TO `app`@`%`",
+				"GRANT ALL PRIVILEGES ON `shop`.`orders` TO `app`@`%` WITH GRANT OPTION",
+			},
+		},
+		{
+			desc: "user with privileges granted outside the operator",
+			grants: []string{
+				"GRANT PROCESS, RELOAD ON *.* TO `app`@`%`",
+				"GRANT NDB_STORED_USER ON *.* TO `app`@`%`",
+				"GRANT SELECT, INSERT, DELETE ON `shop`.* TO `app`@`%` WITH GRANT OPTION",
+				"GRANT ALL PRIVILEGES ON `shop`.`orders` TO `app`@`%` WITH GRANT OPTION",
+				"GRANT SELECT ON `hr`.* TO `app`@`%`",
+				"GRANT `reader`@`%`,`writer`@`%` TO `app`@`%`",
+			},
+			statements: []string{
+				"REVOKE RELOAD ON *.* FROM 'app'@'%'",
+				"REVOKE SELECT ON `hr`.* FROM 'app'@'%'",
+				"REVOKE DELETE, GRANT OPTION ON `shop`.* FROM 'app'@'%'",
+				"REVOKE `reader`@`%`, `writer`@`%` FROM 'app'@'%'",
+			},
+		},
+		{
+			desc: "user with privileges revoked outside the operator",
+			grants: []string{
+				"GRANT NDB_STORED_USER ON *.* TO `app`@`%`",
+				"GRANT SELECT ON `shop`.* TO `app`@`%`",
+				"GRANT SELECT, INSERT ON `shop`.`orders` TO `app`@`%`",
+			},
+			statements: []string{
+				"GRANT PROCESS ON *.* TO 'app'@'%'",
+				"GRANT INSERT ON `shop`.* TO 'app'@'%'",
+				"GRANT ALL PRIVILEGES ON `shop`.`orders` TO 'app'@'%' WITH GRANT OPTION",
+			},
+		},
+		{
+			desc: "user with only the grant option revoked",
+			grants: []string{
+				"GRANT PROCESS ON *.* TO `app`@`%`",
+				"GRANT NDB_STORED_USER ON *.* TO `app`@`%`",
+				"GRANT SELECT, INSERT ON `shop`.* TO `app`@`%`",
+				"GRANT ALL PRIVILEGES ON `shop`.`orders` TO `app`@`%`",
+			},
+			statements: []string{
+				"GRANT USAGE ON `shop`.`orders` TO 'app'@'%' WITH GRANT OPTION",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		granted, roles := parseGrants(tc.grants)
+		statements := getPrivilegeUpdateStatements(account, granted, declared, roles)
+		if !reflect.DeepEqual(statements, tc.statements) {
+			t.Errorf("Testcase %q : expected statements %q but got %q", tc.desc, tc.statements, statements)
+		}
+	}
+
+	// NDB_STORED_USER is revoked when it is not declared anymore
+	nmu := newTestNdbMySQLUser()
+	ndbStoredUser := false
+	nmu.Spec.NdbStoredUser = &ndbStoredUser
+	if declared, err = getDeclaredPrivileges(nmu); err != nil {
+		t.Fatalf("getDeclaredPrivileges failed : %s", err)
+	}
+	granted, roles := parseGrants(testcases[1].grants)
+	expected := []string{"REVOKE NDB_STORED_USER ON *.* FROM 'app'@'%'"}
+	if statements := getPrivilegeUpdateStatements(account, granted, declared, roles); !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Expected statements %q but got %q", expected, statements)
+	}
+}
@@ -1,15 +1,110 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
-import v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+import (
+	"fmt"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 // GetNumOfSectionsRequiredForMySQLServers returns the
 // number of sections required by the MySQL Servers.
 func GetNumOfSectionsRequiredForMySQLServers(nc *v1.NdbCluster) int32 {
-	return nc.GetMySQLServerMaxNodeCount() * nc.GetMySQLServerConnectionPoolSize()
+	numOfSections := nc.GetMySQLServerMaxNodeCount() * nc.GetMySQLServerConnectionPoolSize()
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		numOfSections += mysqldGroup.MaxNodeCount * mysqldGroup.ConnectionPoolSize
+	}
+	return numOfSections
+}
+
+// GetDataNodeStartNodeId returns the nodeId of the first data node. The
+// nodeIds of the data nodes continue after the nodeIds of the first two
+// management nodes and are retained once the MySQL Cluster has been
+// started, so that management nodes can be added later without having
+// to change the nodeIds of the existing data nodes.
+func GetDataNodeStartNodeId(nc *v1.NdbCluster, cs *ConfigSummary) int {
+	if cs != nil && cs.DataNodeStartNodeId != 0 {
+		return int(cs.DataNodeStartNodeId)
+	}
+
+	if nc.GetManagementNodeCount() == 1 {
+		return 2
+	}
+	return 3
+}
+
+// GetManagementNodeId returns the nodeId of the management node running in
+// the pod with the given StatefulSet ordinal index. The management nodes
+// that do not fit before the data nodes are assigned nodeIds in the
+// descending order from the highest nodeId allowed in a MySQL Cluster.
+func GetManagementNodeId(podOrdinalIndex, dataNodeStartNodeId int) int {
+	if podOrdinalIndex < dataNodeStartNodeId-1 {
+		return podOrdinalIndex + 1
+	}
+
+	return constants.MaxNodeId - (podOrdinalIndex - (dataNodeStartNodeId - 1))
+}
+
+// GetMySQLServerGroupStartNodeId returns the nodeId of the first [mysqld]
+// section declared for the MySQL Server group with the given name. The
+// sections of the groups follow the ones of spec.mysqlNode in the config.
+func GetMySQLServerGroupStartNodeId(nc *v1.NdbCluster, groupName string) int {
+	startNodeId := constants.NdbNodeTypeAPIStartNodeId +
+		int(nc.GetMySQLServerMaxNodeCount()*nc.GetMySQLServerConnectionPoolSize())
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		if mysqldGroup.Name == groupName {
+			break
+		}
+		startNodeId += int(mysqldGroup.MaxNodeCount * mysqldGroup.ConnectionPoolSize)
+	}
+	return startNodeId
+}
+
+// GetMySQLServerGroupConfigKey returns the config map key
+// that stores the my.cnf of the given MySQL Server group
+func GetMySQLServerGroupConfigKey(groupName string) string {
+	return groupName + "-" + constants.MySQLConfigKey
+}
+
+// mysqldSection has the details of a [mysqld] section in the config
+type mysqldSection struct {
+	NodeId      int
+	PodName     string
+	ServiceName string
+}
+
+// getMySQLServerSections returns the [mysqld] sections to be declared for
+// the MySQL Servers specified via spec.mysqlNode and spec.mysqlNodeGroups.
+// If connection pool is enabled, successive sections are assigned to a
+// single MySQL Server.
+func getMySQLServerSections(nc *v1.NdbCluster) (sections []mysqldSection) {
+	nodeId := constants.NdbNodeTypeAPIStartNodeId
+	appendSections := func(workloadName string, maxNodeCount, connectionPoolSize int32) {
+		for podIdx := int32(0); podIdx < maxNodeCount; podIdx++ {
+			for j := int32(0); j < connectionPoolSize; j++ {
+				sections = append(sections, mysqldSection{
+					NodeId:      nodeId,
+					PodName:     fmt.Sprintf("%s-%d", workloadName, podIdx),
+					ServiceName: workloadName,
+				})
+				nodeId++
+			}
+		}
+	}
+
+	appendSections(nc.GetWorkloadName(constants.NdbNodeTypeMySQLD),
+		nc.GetMySQLServerMaxNodeCount(), nc.GetMySQLServerConnectionPoolSize())
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		appendSections(nc.GetMySQLServerGroupWorkloadName(mysqldGroup.Name),
+			mysqldGroup.MaxNodeCount, mysqldGroup.ConnectionPoolSize)
+	}
+
+	return sections
 }
 
 // GetTDESecretName returns TDE secret name to be used
@@ -17,3 +112,24 @@ func GetNumOfSectionsRequiredForMySQLServers(nc *v1.NdbCluster) int32 {
 func GetTDESecretName(nc *v1.NdbCluster) string {
 	return nc.Spec.TDESecretName
 }
+
+// GetDataNodeBackupPath returns the directory, inside a data node pod,
+// where the data node stores the files of the backup with the given id.
+func GetDataNodeBackupPath(nc *v1.NdbCluster, backupId int32) string {
+	return fmt.Sprintf("%s/BACKUP/BACKUP-%d", nc.GetDataNodeBackupDataDir(), backupId)
+}
+
+// getNodeGroupConfig returns the config specified in spec.dataNode.nodeGroups
+// for the node group of the data node with the given statefulset pod ordinal.
+func getNodeGroupConfig(nc *v1.NdbCluster, podIdx int) map[string]*intstr.IntOrString {
+	if ngSpec := nc.GetDataNodeGroupSpec(int32(podIdx) / nc.Spec.RedundancyLevel); ngSpec != nil {
+		return ngSpec.Config
+	}
+	return nil
+}
+
+// GetManagementNodeClusterLogFiles returns a shell glob matching the cluster
+// log files, including the rotated ones, written by a Management Server.
+func GetManagementNodeClusterLogFiles() string {
+	return constants.DataDir + "/data/ndb_*_cluster.log*"
+}
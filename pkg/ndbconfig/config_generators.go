@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -9,6 +9,7 @@ import (
 	"net"
 	"text/template"
 
+	"k8s.io/apimachinery/pkg/util/intstr"
 	klog "k8s.io/klog/v2"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -18,6 +19,9 @@ import (
 // Operator sets NoOfReplicas and ServerPort in the default ndbd section
 const numOfOperatorSetConfigs = 2
 
+// Operator sets NodeId, Hostname and DataDir in every ndbd section
+const numOfOperatorSetNdbdSectionConfigs = 3
+
 // MySQL Cluster config template
 var mgmtConfigTmpl = `{{- /* Template to generate management config ini */ -}}
 # Auto generated config.ini - DO NOT EDIT
@@ -39,6 +43,13 @@ ServerPort=1186
 {{- if .Spec.TDESecretName }}
 EncryptedFileSystem=1
 {{ end }}
+{{- if .Spec.TLS }}
+RequireCertificate=true
+RequireTls=true
+{{ end }}
+{{- if .HasDiskDataPVC }}
+FileSystemPathDD={{GetDiskDataDir}}
+{{ end }}
 {{- range $configKey, $configValue := .Spec.DataNode.Config }}
 {{$configKey}}={{$configValue}}
 {{- end}}
@@ -52,6 +63,10 @@ AllowUnresolvedHostnames=1
 NodeId={{$nodeId}}
 Hostname={{$.Name}}-{{NdbNodeTypeMgmd}}-{{$idx}}.{{$.GetServiceName NdbNodeTypeMgmd}}.{{$hostnameSuffix}}
 DataDir={{GetDataDir}}
+{{- if $.Spec.TLS}}
+RequireCertificate=true
+RequireTls=true
+{{- end}}
 
 {{end -}}
 {{range $idx, $nodeId := GetNodeIds NdbNodeTypeNdbmtd -}}
@@ -61,6 +76,9 @@ Hostname={{$.Name}}-{{NdbNodeTypeNdbmtd}}-{{$idx}}.{{$.GetServiceName NdbNodeTyp
 DataDir={{GetDataDir}}
 {{if IsNewDataNode $nodeId -}}
 NodeGroup=65536
+{{end -}}
+{{range $configKey, $configValue := GetNodeGroupConfig $idx -}}
+{{$configKey}}={{$configValue}}
 {{end}}
 {{end -}}
 # Dedicated API section to be used by NDB Operator
@@ -69,10 +87,10 @@ NodeId={{NdbOperatorDedicatedAPINodeId}}
 Dedicated=1
 
 # MySQLD sections to be used exclusively by MySQL Servers
-{{range $nodeId, $podIdx := GetMySQLServerNodeIds -}}
+{{range $section := GetMySQLServerSections -}}
 [mysqld]
-NodeId={{$nodeId}}
-Hostname={{$.Name}}-{{NdbNodeTypeMySQLD}}-{{$podIdx}}.{{$.GetServiceName NdbNodeTypeMySQLD}}.{{$hostnameSuffix}}
+NodeId={{$section.NodeId}}
+Hostname={{$section.PodName}}.{{$section.ServiceName}}.{{$hostnameSuffix}}
 
 {{end -}}
 # API sections to be used by generic NDBAPI applications
@@ -91,8 +109,9 @@ NodeId={{$nodeId}}
 func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (string, error) {
 
 	var (
-		// Variables that keep track of the first free mgmd/data node id and api nodeId
-		ndbdMgmdStartNodeId = 1
+		// Variables that keep track of the first free data node id and api nodeId
+		dataNodeStartNodeId = GetDataNodeStartNodeId(ndb, oldConfigSummary)
+		ndbdStartNodeId     = dataNodeStartNodeId
 		apiStartNodeId      = constants.NdbNodeTypeAPIStartNodeId
 
 		// newDataNodeStartId tracks the starting nodeId of the new
@@ -100,9 +119,12 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 		newDataNodeStartId = 0
 	)
 
-	if oldConfigSummary != nil && oldConfigSummary.NumOfDataNodes < ndb.Spec.DataNode.NodeCount {
-		// Data Nodes are being added to the configuration.
-		newDataNodeStartId = int(ndb.GetManagementNodeCount() + oldConfigSummary.NumOfDataNodes + 1)
+	if oldConfigSummary != nil && oldConfigSummary.NumOfDataNodes < ndb.Spec.DataNode.NodeCount &&
+		oldConfigSummary.RedundancyLevel == ndb.Spec.RedundancyLevel {
+		// Data Nodes are being added to the configuration. When the redundancy
+		// level changes, all the data nodes are started together via an initial
+		// system restart and the new nodes need not be added to new nodegroups.
+		newDataNodeStartId = dataNodeStartNodeId + int(oldConfigSummary.NumOfDataNodes)
 	}
 
 	tmpl := template.New("config.ini")
@@ -113,10 +135,14 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 			var numberOfNodes int32
 			switch nodeType {
 			case constants.NdbNodeTypeMgmd:
-				startNodeId = &ndbdMgmdStartNodeId
-				numberOfNodes = ndb.GetManagementNodeCount()
+				// The management nodes are not assigned sequential nodeIds
+				nodeIds := make([]int, ndb.GetManagementNodeCount())
+				for i := range nodeIds {
+					nodeIds[i] = GetManagementNodeId(i, dataNodeStartNodeId)
+				}
+				return nodeIds
 			case constants.NdbNodeTypeNdbmtd:
-				startNodeId = &ndbdMgmdStartNodeId
+				startNodeId = &ndbdStartNodeId
 				numberOfNodes = ndb.Spec.DataNode.NodeCount
 			case constants.NdbNodeTypeMySQLD:
 				startNodeId = &apiStartNodeId
@@ -135,23 +161,20 @@ func GetConfigString(ndb *v1.NdbCluster, oldConfigSummary *ConfigSummary) (strin
 			}
 			return nodeIds
 		},
-		"GetMySQLServerNodeIds": func() map[int]int {
-			startNodeId := &apiStartNodeId
-			numberOfNodeIds := GetNumOfSectionsRequiredForMySQLServers(ndb)
-			nodeIdToPodIdx := make(map[int]int)
-			ndbConnectionPoolSize := ndb.GetMySQLServerConnectionPoolSize()
-			podIdx := 0
-			for i := 0; int32(i) < numberOfNodeIds; i = i + int(ndbConnectionPoolSize) {
-				for j := 0; j < int(ndbConnectionPoolSize); j++ {
-					nodeIdToPodIdx[*startNodeId] = podIdx
-					*startNodeId++
-				}
-				podIdx++
-			}
-
-			return nodeIdToPodIdx
+		// GetMySQLServerSections returns the [mysqld] sections of
+		// the MySQL Servers of spec.mysqlNode and spec.mysqlNodeGroups
+		"GetMySQLServerSections": func() []mysqldSection {
+			sections := getMySQLServerSections(ndb)
+			apiStartNodeId += len(sections)
+			return sections
+		},
+		"GetDataDir":     func() string { return constants.DataDir + "/data" },
+		"GetDiskDataDir": func() string { return constants.DiskDataDir },
+		// GetNodeGroupConfig returns the config specified in spec.dataNode.nodeGroups
+		// for the node group of the data node with the given statefulset pod ordinal
+		"GetNodeGroupConfig": func(podIdx int) map[string]*intstr.IntOrString {
+			return getNodeGroupConfig(ndb, podIdx)
 		},
-		"GetDataDir": func() string { return constants.DataDir + "/data" },
 		"IsNewDataNode": func(nodeId int) bool {
 			return newDataNodeStartId != 0 && nodeId >= newDataNodeStartId
 		},
@@ -206,24 +229,48 @@ var myCnfTemplate = `{{- /* Template to generate my.cnf config ini */ -}}
 {{.GetMySQLCnf}}
 `
 
+// myCnfSource is implemented by the specs that declare a my.cnf
+type myCnfSource interface {
+	GetMySQLCnf() string
+}
+
 // GetMySQLConfigString returns the MySQL Server config(my.cnf)
 // to be used by the MySQL Server StatefulSet.
 func GetMySQLConfigString(nc *v1.NdbCluster, oldConfigSummary *ConfigSummary) (string, error) {
+	var oldConfigVersion int32
+	if oldConfigSummary != nil {
+		oldConfigVersion = oldConfigSummary.MySQLServerConfigVersion
+	}
+	return getMySQLConfigString(nc, oldConfigVersion)
+}
+
+// GetMySQLServerGroupConfigString returns the MySQL Server config(my.cnf)
+// to be used by the StatefulSet of the given MySQL Server group.
+func GetMySQLServerGroupConfigString(
+	mysqldGroup *v1.NdbMysqldGroupSpec, oldConfigSummary *ConfigSummary) (string, error) {
+	var oldConfigVersion int32
+	if oldConfigSummary != nil {
+		if groupSummary, exists := oldConfigSummary.MySQLServerGroups[mysqldGroup.Name]; exists {
+			oldConfigVersion = groupSummary.MySQLServerConfigVersion
+		}
+	}
+	return getMySQLConfigString(mysqldGroup, oldConfigVersion)
+}
 
-	if nc.GetMySQLCnf() == "" {
+// getMySQLConfigString generates the my.cnf from the given
+// source with a version succeeding the oldConfigVersion.
+func getMySQLConfigString(cnfSource myCnfSource, oldConfigVersion int32) (string, error) {
+
+	if cnfSource.GetMySQLCnf() == "" {
 		return "", nil
 	}
 
 	tmpl := template.New("my.cnf")
 	tmpl.Funcs(template.FuncMap{
 		"GetConfigVersion": func() int32 {
-			if oldConfigSummary == nil {
-				// First version of the my.cnf config based on newly added NdbCluster spec.
-				return 1
-			} else {
-				// Bump up the config version for every change.
-				return oldConfigSummary.MySQLServerConfigVersion + 1
-			}
+			// Bump up the config version for every change. The first
+			// version of the my.cnf config will have the version 1.
+			return oldConfigVersion + 1
 		},
 	})
 
@@ -233,7 +280,7 @@ func GetMySQLConfigString(nc *v1.NdbCluster, oldConfigSummary *ConfigSummary) (s
 	}
 
 	var myCnf bytes.Buffer
-	if err := tmpl.Execute(&myCnf, nc); err != nil {
+	if err := tmpl.Execute(&myCnf, cnfSource); err != nil {
 		return "", err
 	}
 
@@ -1,12 +1,14 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mysql/ndb-operator/config/debug"
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -24,10 +26,12 @@ type ConfigSummary struct {
 	MySQLClusterConfigVersion int32
 	// MySQLServerConfigVersion is the version of the my.cnf stored in the config map
 	MySQLServerConfigVersion int32
-	// NumOfManagementNodes is number of Management Nodes (1 or 2).
+	// NumOfManagementNodes is number of Management Nodes.
 	NumOfManagementNodes int32
 	// NumOfDataNodes is the number of Data Nodes.
 	NumOfDataNodes int32
+	// DataNodeStartNodeId is the nodeId of the first Data Node.
+	DataNodeStartNodeId int32
 	// NumOfMySQLServers is the number of MySQL Servers
 	// expected to connect to the MySQL Cluster data nodes.
 	NumOfMySQLServers int32
@@ -39,6 +43,8 @@ type ConfigSummary struct {
 	RedundancyLevel int32
 	// defaultNdbdSection has the values extracted from the default ndbd section of the management config.
 	defaultNdbdSection configparser.Section
+	// ndbdSections has the values extracted from the ndbd sections of the management config.
+	ndbdSections []configparser.Section
 	// defaultMgmdSection has the values extracted from the default ndbd section of the management config.
 	defaultMgmdSection configparser.Section
 	// MySQLLoadBalancer indicates if the load balancer service for MySQL servers needs to be enabled
@@ -53,6 +59,22 @@ type ConfigSummary struct {
 	TDEPasswordSecretName string
 	// DataNodeInitialRestart indicates if the data nodes need to perform a initial restart
 	DataNodeInitialRestart bool
+	// MySQLServerGroups has the summary of the MySQL Server
+	// groups declared via spec.mysqlNodeGroups, keyed by name.
+	MySQLServerGroups map[string]*MySQLServerGroupSummary
+	// mysqldSections has the values extracted from the mysqld sections of the management config.
+	mysqldSections []configparser.Section
+}
+
+// MySQLServerGroupSummary contains a summary of a MySQL
+// Server group extracted from the configMap data.
+type MySQLServerGroupSummary struct {
+	// NumOfMySQLServers is the number of MySQL Servers in the group
+	NumOfMySQLServers int32
+	// MySQLServerConfigVersion is the version of the group's my.cnf stored in the config map
+	MySQLServerConfigVersion int32
+	// myCnfConfig has the parsed my.cnf of the group
+	myCnfConfig configparser.ConfigIni
 }
 
 // parseInt32 parses the given string into an Int32
@@ -96,12 +118,20 @@ func NewConfigSummary(configMapData map[string]string) (*ConfigSummary, error) {
 		MySQLLoadBalancer:      parseBool(configMapData[constants.MySQLLoadBalancer]),
 		ManagementLoadBalancer: parseBool(configMapData[constants.ManagementLoadBalancer]),
 		defaultNdbdSection:     config.GetSection("ndbd default"),
+		ndbdSections:           config.GetAllSections("ndbd"),
+		mysqldSections:         config.GetAllSections("mysqld"),
 		defaultMgmdSection:     config.GetSection("mgmd default"),
 		MySQLRootHost:          configMapData[constants.MySQLRootHost],
 		TDEPasswordSecretName:  configMapData[constants.TDEPasswordSecretName],
 		DataNodeInitialRestart: parseBool(configMapData[constants.DataNodeInitialRestart]),
 	}
 
+	// Extract the nodeId of the first data node
+	if len(cs.ndbdSections) != 0 {
+		nodeId, _ := cs.ndbdSections[0].GetValue("NodeId")
+		cs.DataNodeStartNodeId = parseInt32(nodeId)
+	}
+
 	// Update MySQL Config details if it exists
 	mysqlConfigString := configMapData[constants.MySQLConfigKey]
 	if mysqlConfigString != "" {
@@ -114,6 +144,34 @@ func NewConfigSummary(configMapData map[string]string) (*ConfigSummary, error) {
 			cs.myCnfConfig.GetValueFromSection("header", "ConfigVersion"))
 	}
 
+	// Extract the MySQL Server group details if they exist
+	if mysqldGroupsString := configMapData[constants.MySQLServerGroups]; mysqldGroupsString != "" {
+		var numOfMySQLServers map[string]int32
+		if err = json.Unmarshal([]byte(mysqldGroupsString), &numOfMySQLServers); err != nil {
+			// Should never happen as the operator generated the value
+			return nil, debug.InternalError(err)
+		}
+
+		cs.MySQLServerGroups = make(map[string]*MySQLServerGroupSummary)
+		for groupName, nodeCount := range numOfMySQLServers {
+			groupSummary := &MySQLServerGroupSummary{
+				NumOfMySQLServers: nodeCount,
+			}
+
+			if myCnfString := configMapData[GetMySQLServerGroupConfigKey(groupName)]; myCnfString != "" {
+				groupSummary.myCnfConfig, err = configparser.ParseString(myCnfString)
+				if err != nil {
+					// Should never happen as the operator generated the my.cnf
+					return nil, debug.InternalError(err)
+				}
+				groupSummary.MySQLServerConfigVersion = parseInt32(
+					groupSummary.myCnfConfig.GetValueFromSection("header", "ConfigVersion"))
+			}
+
+			cs.MySQLServerGroups[groupName] = groupSummary
+		}
+	}
+
 	return cs, nil
 }
 
@@ -127,6 +185,14 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 	if cs.TDEPasswordSecretName != "" {
 		totalNdbdConfig = totalNdbdConfig + 1
 	}
+	// Add a count for RequireCertificate and RequireTls if TLS is enabled
+	if nc.Spec.TLS != nil {
+		totalNdbdConfig = totalNdbdConfig + 2
+	}
+	// Add a count for FileSystemPathDD if a disk data PVC is used
+	if nc.HasDiskDataPVC() {
+		totalNdbdConfig = totalNdbdConfig + 1
+	}
 	// Check if the default ndbd section has been updated
 	if totalNdbdConfig != len(cs.defaultNdbdSection) {
 		// A config has been added (or) removed from default ndbd section
@@ -142,17 +208,53 @@ func (cs *ConfigSummary) MySQLClusterConfigNeedsUpdate(nc *v1.NdbCluster) (needs
 		}
 	}
 
+	// Check if the management nodes are being added
+	if cs.NumOfManagementNodes != nc.GetManagementNodeCount() {
+		return true
+	}
+
 	// Check if the data nodes are being added
 	if cs.NumOfDataNodes < nc.Spec.DataNode.NodeCount {
 		return true
 	}
 
+	// Check if the node group specific configs have been updated
+	for podIdx, ndbdSection := range cs.ndbdSections {
+		ngConfig := getNodeGroupConfig(nc, podIdx)
+		// Operator sets NodeId, Hostname, DataDir and, during
+		// an online add, NodeGroup in the ndbd sections.
+		totalConfig := len(ngConfig) + numOfOperatorSetNdbdSectionConfigs
+		if _, exists := ndbdSection.GetValue("NodeGroup"); exists {
+			totalConfig++
+		}
+		if totalConfig != len(ndbdSection) {
+			// A config has been added (or) removed from the ndbd section
+			return true
+		}
+
+		for configKey, configValue := range ngConfig {
+			if value, exists := ndbdSection.GetValue(configKey); !exists || value != configValue.String() {
+				// Either the config doesn't exist or the value has been changed
+				return true
+			}
+		}
+	}
+
 	// Check if there is a change in the number of MySQL server
 	// slots or number of free api slots.
 	if cs.NumOfMySQLServerSlots != GetNumOfSectionsRequiredForMySQLServers(nc) {
 		return true
 	}
 
+	// Check if the MySQL Server slots have been reassigned
+	// among the MySQL Servers of spec.mysqlNodeGroups.
+	for i, section := range getMySQLServerSections(nc) {
+		hostname, _ := cs.mysqldSections[i].GetValue("Hostname")
+		if !strings.HasPrefix(hostname, section.PodName+"."+section.ServiceName+".") {
+			return true
+		}
+	}
+
 	// Check if there is a change in the TDE password
 	if cs.TDEPasswordSecretName != GetTDESecretName(nc) {
 		// If the field is unset in new or old config. Then the EncryptedFileSystem config parameter
@@ -200,10 +302,27 @@ func (cs *ConfigSummary) MySQLCnfNeedsUpdate(nc *v1.NdbCluster) (needsUpdate boo
 		return myCnf != "", nil
 	}
 
+	return myCnfNeedsUpdate(cs.myCnfConfig, myCnf)
+}
+
+// MySQLServerGroupCnfNeedsUpdate checks if the my.cnf config of
+// the given MySQL Server group stored in the configMap needs to be updated
+func (cs *ConfigSummary) MySQLServerGroupCnfNeedsUpdate(
+	mysqldGroup *v1.NdbMysqldGroupSpec) (needsUpdate bool, err error) {
+	var oldMyCnfConfig configparser.ConfigIni
+	if cs != nil && cs.MySQLServerGroups[mysqldGroup.Name] != nil {
+		oldMyCnfConfig = cs.MySQLServerGroups[mysqldGroup.Name].myCnfConfig
+	}
+
+	return myCnfNeedsUpdate(oldMyCnfConfig, mysqldGroup.GetMySQLCnf())
+}
+
+// myCnfNeedsUpdate checks if the given myCnf differs from the oldMyCnfConfig
+func myCnfNeedsUpdate(oldMyCnfConfig configparser.ConfigIni, myCnf string) (needsUpdate bool, err error) {
 	if myCnf == "" {
 		// myCnf is empty.
 		// Update required if it previously had a value
-		return cs.myCnfConfig != nil, nil
+		return oldMyCnfConfig != nil, nil
 	}
 
 	myCnfConfig, err := configparser.ParseString(myCnf)
@@ -213,5 +332,5 @@ func (cs *ConfigSummary) MySQLCnfNeedsUpdate(nc *v1.NdbCluster) (needsUpdate boo
 	}
 
 	// Compare the configs and return if the config needs an update
-	return !cs.myCnfConfig.IsEqual(myCnfConfig), nil
+	return !oldMyCnfConfig.IsEqual(myCnfConfig), nil
 }
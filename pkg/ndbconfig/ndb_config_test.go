@@ -1,16 +1,21 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package ndbconfig
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+	"github.com/mysql/ndb-operator/pkg/ndbconfig/configparser"
 )
 
 func errorIfNotEqual(t *testing.T, expected, actual int32, desc string) {
@@ -219,6 +224,7 @@ func Test_GetConfigString_withOldConfigSummary(t *testing.T) {
 	oldConfigSummary := &ConfigSummary{
 		MySQLClusterConfigVersion: 3,
 		NumOfDataNodes:            2,
+		RedundancyLevel:           2,
 	}
 
 	configString, err := GetConfigString(ndb, oldConfigSummary)
@@ -322,3 +328,208 @@ config2=value2
 		t.Errorf("Generated :\n%s\n", configString)
 	}
 }
+
+func Test_GetConfigString_withDiskDataPVC(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.DataNode.DiskData = &v1.NdbDiskDataSpec{
+		PVCSpec: &corev1.PersistentVolumeClaimSpec{},
+	}
+	configString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey: configString,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	// The disk data files should be stored in the dedicated PVC
+	if value, _ := cs.defaultNdbdSection.GetValue("FileSystemPathDD"); value != constants.DiskDataDir {
+		t.Errorf("Expected FileSystemPathDD to be %q but got %q", constants.DiskDataDir, value)
+	}
+
+	// The operator set FileSystemPathDD should not be treated as a config change
+	if cs.MySQLClusterConfigNeedsUpdate(nc) {
+		t.Error("Expected the MySQL Cluster config to be up-to-date")
+	}
+}
+
+func Test_GetConfigString_withTLS(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.TLS = &v1.NdbClusterTLSSpec{}
+	configString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the generated config string : %s", err)
+	}
+
+	// The data nodes and the management nodes should require TLS
+	sections := append(config.GetAllSections("ndb_mgmd"), config.GetSection("ndbd default"))
+	for _, section := range sections {
+		for _, configKey := range []string{"RequireCertificate", "RequireTls"} {
+			if value, _ := section.GetValue(configKey); value != "true" {
+				t.Errorf("Expected %s to be true but got %q in section %v", configKey, value, section)
+			}
+		}
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey: configString,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	// The operator set TLS configs should not be treated as a config change
+	if cs.MySQLClusterConfigNeedsUpdate(nc) {
+		t.Error("Expected the MySQL Cluster config to be up-to-date")
+	}
+}
+
+func Test_GetConfigString_withNodeGroupConfig(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.DataNode.NodeCount = 4
+	dataMemory := intstr.FromString("2G")
+	nc.Spec.DataNode.NodeGroups = []v1.NdbDataNodeGroupSpec{
+		{
+			NodeGroup: 1,
+			Config: map[string]*intstr.IntOrString{
+				"DataMemory": &dataMemory,
+			},
+		},
+	}
+	configString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the generated config : %s", err)
+	}
+
+	// Only the data nodes of node group 1 should have the DataMemory config
+	for podIdx, ndbdSection := range config.GetAllSections("ndbd") {
+		value, exists := ndbdSection.GetValue("DataMemory")
+		if inNodeGroup1 := podIdx >= 2; exists != inNodeGroup1 || (exists && value != "2G") {
+			t.Errorf("Unexpected DataMemory %q in the ndbd section of the data node %d", value, podIdx)
+		}
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey: configString,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+
+	if cs.MySQLClusterConfigNeedsUpdate(nc) {
+		t.Error("Expected the MySQL Cluster config to be up-to-date")
+	}
+
+	// Updating a node group config should be treated as a config change
+	dataMemory = intstr.FromString("4G")
+	if !cs.MySQLClusterConfigNeedsUpdate(nc) {
+		t.Error("Expected the MySQL Cluster config to need an update")
+	}
+}
+
+func Test_GetConfigString_withMySQLServerGroups(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.MysqlNodeGroups = []v1.NdbMysqldGroupSpec{
+		{
+			Name:               "analytics",
+			NodeCount:          1,
+			MaxNodeCount:       2,
+			ConnectionPoolSize: 2,
+		},
+	}
+	configString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the generated config : %s", err)
+	}
+
+	// The sections of the group should follow the 4 sections of spec.mysqlNode
+	mysqldSections := config.GetAllSections("mysqld")
+	if len(mysqldSections) != 8 {
+		t.Fatalf("Expected 8 mysqld sections but got %d", len(mysqldSections))
+	}
+
+	groupStartNodeId := GetMySQLServerGroupStartNodeId(nc, "analytics")
+	if groupStartNodeId != constants.NdbNodeTypeAPIStartNodeId+4 {
+		t.Errorf("Unexpected start node id %d for the group", groupStartNodeId)
+	}
+
+	for i, mysqldSection := range mysqldSections[4:] {
+		nodeId, _ := mysqldSection.GetValue("NodeId")
+		if parseInt32(nodeId) != int32(groupStartNodeId+i) {
+			t.Errorf("Expected NodeId %d but got %s", groupStartNodeId+i, nodeId)
+		}
+
+		// Successive sections belong to a single MySQL Server as the connection pool size is 2
+		hostnamePrefix := fmt.Sprintf("example-ndb-mysqld-analytics-%d.example-ndb-mysqld-analytics.", i/2)
+		if hostname, _ := mysqldSection.GetValue("Hostname"); !strings.HasPrefix(hostname, hostnamePrefix) {
+			t.Errorf("Expected Hostname to start with %q but got %q", hostnamePrefix, hostname)
+		}
+	}
+}
+
+func Test_GetConfigString_withAddedManagementNodes(t *testing.T) {
+	// A MySQL Cluster started with a single management node
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.ManagementNode = &v1.NdbManagementNodeSpec{NodeCount: 1}
+	configString, err := GetConfigString(nc, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	cs, err := NewConfigSummary(map[string]string{
+		constants.ConfigIniKey: configString,
+	})
+	if err != nil {
+		t.Fatalf("NewConfigSummary failed : %s", err)
+	}
+	errorIfNotEqual(t, 1, cs.NumOfManagementNodes, "cs.NumOfManagementNodes")
+	errorIfNotEqual(t, 2, cs.DataNodeStartNodeId, "cs.DataNodeStartNodeId")
+
+	// Add two more management nodes
+	nc.Spec.ManagementNode.NodeCount = 3
+	if !cs.MySQLClusterConfigNeedsUpdate(nc) {
+		t.Error("Expected the MySQL Cluster config to need an update")
+	}
+
+	configString, err = GetConfigString(nc, cs)
+	if err != nil {
+		t.Fatalf("Failed to generate config string from Ndb : %s", err)
+	}
+
+	config, err := configparser.ParseString(configString)
+	if err != nil {
+		t.Fatalf("Failed to parse the generated config : %s", err)
+	}
+
+	// The new management nodes should take the highest nodeIds
+	// and the data nodes should retain their existing nodeIds
+	for i, expectedNodeId := range []string{"1", "255", "254"} {
+		if nodeId, _ := config.GetAllSections("ndb_mgmd")[i].GetValue("NodeId"); nodeId != expectedNodeId {
+			t.Errorf("Expected the management node %d to have NodeId %s but got %s", i, expectedNodeId, nodeId)
+		}
+	}
+	for i, expectedNodeId := range []string{"2", "3"} {
+		if nodeId, _ := config.GetAllSections("ndbd")[i].GetValue("NodeId"); nodeId != expectedNodeId {
+			t.Errorf("Expected the data node %d to have NodeId %s but got %s", i, expectedNodeId, nodeId)
+		}
+	}
+}
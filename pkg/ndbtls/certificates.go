@@ -0,0 +1,135 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbtls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// newSerialNumber returns a random serial number for a new certificate
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// newPrivateKey generates a new ECDSA private key and returns
+// it along with its PKCS #8, PEM encoded, form.
+func newPrivateKey() (*ecdsa.PrivateKey, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), nil
+}
+
+// NewCA generates a new self-signed CA certificate valid for the given
+// duration and returns the PEM encoded certificate and private key.
+func NewCA(commonName string, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	key, keyPEM, err := newPrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), keyPEM, nil
+}
+
+// parseCertificate parses the given PEM encoded certificate
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode the PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parsePrivateKey parses the given PEM encoded PKCS #8, EC or PKCS #1 private key
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM encoded private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("unsupported private key type")
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// NewCertificate generates a new private key and a certificate, based on the
+// given template, signed by the given CA. The serial number, validity period
+// and public key of the template are filled in by the method. The PEM encoded
+// certificate and private key are returned.
+func NewCertificate(caCertPEM, caKeyPEM []byte,
+	template *x509.Certificate, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+
+	caCert, err := parseCertificate(caCertPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, err := parsePrivateKey(caKeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, keyPEM, err := newPrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if template.SerialNumber, err = newSerialNumber(); err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template.NotBefore = now.Add(-time.Hour)
+	template.NotAfter = now.Add(validity)
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), keyPEM, nil
+}
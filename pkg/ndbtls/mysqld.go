@@ -0,0 +1,146 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbtls
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+)
+
+const (
+	// MySQLServerCACertKey is the key of the MySQL Server
+	// certificate Secret that holds the CA certificate
+	MySQLServerCACertKey = "ca.crt"
+
+	mysqldCASecretSuffix  = "mysqld-ca"
+	mysqldTLSSecretSuffix = "mysqld-tls"
+
+	// Validity of the MySQL Server certificates
+	mysqldCertValidity = 365 * 24 * time.Hour
+	// The MySQL Server certificates are renewed
+	// when they are about to expire within this time
+	mysqldCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// GetMySQLServerCASecretName returns the name of the Secret that
+// has the CA used to sign the MySQL Server certificates
+func GetMySQLServerCASecretName(nc *v1.NdbCluster) string {
+	return nc.Name + "-" + mysqldCASecretSuffix
+}
+
+// GetMySQLServerTLSSecretName returns the name of the Secret
+// that has the certificate and the private key of the MySQL Servers
+func GetMySQLServerTLSSecretName(nc *v1.NdbCluster) string {
+	return nc.Name + "-" + mysqldTLSSecretSuffix
+}
+
+// NewMySQLServerCASecret generates a new CA to sign the
+// MySQL Server certificates and returns a new Secret holding it
+func NewMySQLServerCASecret(nc *v1.NdbCluster) (*corev1.Secret, error) {
+	return newCASecret(nc, GetMySQLServerCASecretName(nc),
+		nc.Name+" MySQL Server CA", mysqldCASecretSuffix)
+}
+
+// getMySQLServerDNSNames returns the DNS names to be covered by the
+// MySQL Server certificate. The certificate covers the Services of the
+// MySQL Servers and, via wildcards, the DNS names of all their pods.
+func getMySQLServerDNSNames(nc *v1.NdbCluster) []string {
+	serviceNames := []string{nc.GetWorkloadName(constants.NdbNodeTypeMySQLD)}
+	for _, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		serviceNames = append(serviceNames, nc.GetMySQLServerGroupWorkloadName(mysqldGroup.Name))
+	}
+
+	var dnsNames []string
+	for _, serviceName := range serviceNames {
+		for _, name := range []string{
+			serviceName,
+			serviceName + "." + nc.Namespace,
+			serviceName + "." + nc.Namespace + ".svc",
+			serviceName + "." + nc.Namespace + ".svc.cluster.local",
+		} {
+			// Every pod is addressable as <pod-name>.<name>
+			dnsNames = append(dnsNames, name, "*."+name)
+		}
+	}
+
+	return dnsNames
+}
+
+// NewMySQLServerTLSSecret returns a new Secret holding a new MySQL Server
+// private key and a certificate signed by the CA in the given caSecret.
+func NewMySQLServerTLSSecret(nc *v1.NdbCluster, caSecret *corev1.Secret) (*corev1.Secret, error) {
+	caCertPEM := caSecret.Data[corev1.TLSCertKey]
+	certPEM, keyPEM, err := NewCertificate(caCertPEM, caSecret.Data[corev1.TLSPrivateKeyKey], &x509.Certificate{
+		Subject:     pkix.Name{CommonName: nc.GetWorkloadName(constants.NdbNodeTypeMySQLD)},
+		DNSNames:    getMySQLServerDNSNames(nc),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, mysqldCertValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: nc.GetCompleteLabels(map[string]string{
+				constants.ClusterResourceTypeLabel: mysqldTLSSecretSuffix + "-secret",
+			}),
+			Name:            GetMySQLServerTLSSecretName(nc),
+			Namespace:       nc.GetNamespace(),
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Data: map[string][]byte{
+			MySQLServerCACertKey:    caCertPEM,
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+		Type: corev1.SecretTypeTLS,
+	}, nil
+}
+
+// MySQLServerCertNeedsRenewal checks if the MySQL Server certificate
+// in the given tlsSecret has to be renewed. The certificate is renewed
+// if it is about to expire, if it was not signed by the CA in caSecret
+// or if it does not cover the DNS names of all the MySQL Servers.
+func MySQLServerCertNeedsRenewal(nc *v1.NdbCluster, caSecret, tlsSecret *corev1.Secret) (bool, string) {
+	cert, err := parseCertificate(tlsSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return true, fmt.Sprintf("the certificate is not valid : %s", err)
+	}
+
+	if time.Now().Add(mysqldCertRenewBefore).After(cert.NotAfter) {
+		return true, fmt.Sprintf("the certificate expires at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+
+	if !bytes.Equal(tlsSecret.Data[MySQLServerCACertKey], caSecret.Data[corev1.TLSCertKey]) {
+		return true, "the CA has changed"
+	}
+
+	if !reflect.DeepEqual(cert.DNSNames, getMySQLServerDNSNames(nc)) {
+		return true, "the MySQL Servers have changed"
+	}
+
+	return false, ""
+}
+
+// GetMySQLServerCertVersion returns a string that uniquely
+// identifies the MySQL Server certificate in the given Secret.
+func GetMySQLServerCertVersion(tlsSecret *corev1.Secret) string {
+	cert, err := parseCertificate(tlsSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return ""
+	}
+	return cert.SerialNumber.Text(16)
+}
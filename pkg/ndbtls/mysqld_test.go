@@ -0,0 +1,74 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbtls
+
+import (
+	"crypto/x509"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/helpers/testutils"
+)
+
+func TestMySQLServerCertificate(t *testing.T) {
+	nc := testutils.NewTestNdb("default", "example-ndb", 2)
+	nc.Spec.MysqlNode.TLS = &v1.NdbMysqldTLSSpec{}
+
+	caSecret, err := NewMySQLServerCASecret(nc)
+	if err != nil {
+		t.Fatalf("Failed to create the MySQL Server CA : %s", err)
+	}
+	tlsSecret, err := NewMySQLServerTLSSecret(nc, caSecret)
+	if err != nil {
+		t.Fatalf("Failed to create the MySQL Server certificate : %s", err)
+	}
+
+	// Verify that the certificate is valid for the service and the pods
+	cert, err := parseCertificate(tlsSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		t.Fatalf("Failed to parse the MySQL Server certificate : %s", err)
+	}
+	caPool := x509.NewCertPool()
+	caPool.AppendCertsFromPEM(tlsSecret.Data[MySQLServerCACertKey])
+	for _, hostname := range []string{
+		"example-ndb-mysqld",
+		"example-ndb-mysqld-1.example-ndb-mysqld.default",
+		"example-ndb-mysqld-0.example-ndb-mysqld.default.svc.cluster.local",
+	} {
+		if _, err = cert.Verify(x509.VerifyOptions{
+			DNSName: hostname,
+			Roots:   caPool,
+		}); err != nil {
+			t.Errorf("MySQL Server certificate is not valid for %q : %s", hostname, err)
+		}
+	}
+
+	if needsRenewal, reason := MySQLServerCertNeedsRenewal(nc, caSecret, tlsSecret); needsRenewal {
+		t.Errorf("A new MySQL Server certificate should not need renewal, but it does as %s", reason)
+	}
+
+	// Adding a MySQL Server group should require the certificate to be renewed
+	nc.Spec.MysqlNodeGroups = []v1.NdbMysqldGroupSpec{{Name: "oltp", NodeCount: 1}}
+	if needsRenewal, _ := MySQLServerCertNeedsRenewal(nc, caSecret, tlsSecret); !needsRenewal {
+		t.Error("MySQL Server certificate should be renewed to cover the new MySQL Server group")
+	}
+	if tlsSecret, err = NewMySQLServerTLSSecret(nc, caSecret); err != nil {
+		t.Fatalf("Failed to create the MySQL Server certificate : %s", err)
+	}
+	if needsRenewal, reason := MySQLServerCertNeedsRenewal(nc, caSecret, tlsSecret); needsRenewal {
+		t.Errorf("The renewed MySQL Server certificate should not need renewal, but it does as %s", reason)
+	}
+
+	// A change in the CA should require the certificate to be renewed
+	newCASecret, err := NewMySQLServerCASecret(nc)
+	if err != nil {
+		t.Fatalf("Failed to create the MySQL Server CA : %s", err)
+	}
+	if needsRenewal, _ := MySQLServerCertNeedsRenewal(nc, newCASecret, tlsSecret); !needsRenewal {
+		t.Error("MySQL Server certificate should be renewed when the CA changes")
+	}
+}
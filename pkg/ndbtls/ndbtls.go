@@ -0,0 +1,220 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+// Package ndbtls has the methods required to enable TLS in
+// a MySQL Cluster and to connect to its Management Servers
+// when they accept only TLS connections.
+package ndbtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+const (
+	// CACertFileName is the name of the file from which
+	// the MySQL Cluster nodes read the cluster CA certificate
+	CACertFileName = "NDB-Cluster-cert"
+	// CAKeyFileName is the name of the file from which ndb_sign_keys
+	// reads the private key of the cluster CA to sign the node certificates
+	CAKeyFileName = "NDB-Cluster-private-key"
+
+	caSecretSuffix = "ndb-ca"
+
+	// Validity of the CA generated by the operator
+	caValidity = 10 * 365 * 24 * time.Hour
+	// Validity of the client certificates used by the
+	// operator. A new one is generated for every connection.
+	clientCertValidity = 24 * time.Hour
+)
+
+// GetCASecretName returns the name of the Secret that has the cluster CA
+// and a bool flag to specify if it is a custom secret created by the user
+func GetCASecretName(nc *v1.NdbCluster) (secretName string, customSecret bool) {
+	if nc.Spec.TLS != nil && nc.Spec.TLS.CASecretName != "" {
+		return nc.Spec.TLS.CASecretName, true
+	}
+	return nc.Name + "-" + caSecretSuffix, false
+}
+
+// NewCASecret generates a new cluster CA and returns a new Secret holding it
+func NewCASecret(nc *v1.NdbCluster) (*corev1.Secret, error) {
+	secretName, _ := GetCASecretName(nc)
+	return newCASecret(nc, secretName, nc.Name+" NDB Cluster CA", caSecretSuffix)
+}
+
+// newCASecret generates a new CA and returns a new Secret, with
+// the given name, holding the CA certificate and its private key
+func newCASecret(nc *v1.NdbCluster, secretName, commonName, resourceType string) (*corev1.Secret, error) {
+	certPEM, keyPEM, err := NewCA(commonName, caValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: nc.GetCompleteLabels(map[string]string{
+				constants.ClusterResourceTypeLabel: resourceType + "-secret",
+			}),
+			Name:            secretName,
+			Namespace:       nc.GetNamespace(),
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+		Type: corev1.SecretTypeTLS,
+	}, nil
+}
+
+// GetCAVolumeSource returns the volume source that loads the
+// cluster CA into a pod, using the file names expected by the
+// MySQL Cluster nodes and ndb_sign_keys.
+func GetCAVolumeSource(nc *v1.NdbCluster) corev1.VolumeSource {
+	secretName, _ := GetCASecretName(nc)
+	return corev1.VolumeSource{
+		Secret: &corev1.SecretVolumeSource{
+			SecretName: secretName,
+			Items: []corev1.KeyToPath{
+				{
+					Key:  corev1.TLSCertKey,
+					Path: CACertFileName,
+				},
+				{
+					Key:  corev1.TLSPrivateKeyKey,
+					Path: CAKeyFileName,
+				},
+			},
+		},
+	}
+}
+
+// GetSignKeysCommand returns the command that creates private keys and
+// certificates, signed by the CA in the caDir, for the given ndb_sign_keys
+// node types and copies them along with the CA certificate into tlsDir.
+func GetSignKeysCommand(caDir, tlsDir string, nodeTypes ...string) []string {
+	var cmdAndArgs []string
+	for _, nodeType := range nodeTypes {
+		cmdAndArgs = append(cmdAndArgs,
+			"ndb_sign_keys",
+			"--create-key",
+			"--no-config",
+			"--node-type="+nodeType,
+			"--CA-search-path="+caDir,
+			"--keys-dir="+tlsDir,
+			"--to-dir="+tlsDir,
+			"&&",
+		)
+	}
+
+	return append(cmdAndArgs, "cp", caDir+"/"+CACertFileName, tlsDir)
+}
+
+// ValidateCASecret verifies that the given Secret has a valid cluster CA
+func ValidateCASecret(caSecret *corev1.Secret) error {
+	caCert, err := parseCertificate(caSecret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return err
+	}
+	if !caCert.IsCA {
+		return errors.New("the certificate is not a CA certificate")
+	}
+	_, err = parsePrivateKey(caSecret.Data[corev1.TLSPrivateKeyKey])
+	return err
+}
+
+// NewMgmClientTLSConfig returns a tls.Config that can be used to connect
+// to the Management Servers of a MySQL Cluster that uses the given CA.
+// The config has a new client certificate signed by the CA.
+func NewMgmClientTLSConfig(caCertPEM, caKeyPEM []byte) (*tls.Config, error) {
+	certPEM, keyPEM, err := NewCertificate(caCertPEM, caKeyPEM, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "NDB Operator"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, clientCertValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	caCert, err := parseCertificate(caCertPEM)
+	if err != nil {
+		return nil, err
+	}
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		MinVersion:   tls.VersionTLS12,
+		// The Management Server certificates are bound to the hostnames
+		// of the pods and not to the Service or the load balancer through
+		// which the connection is made. So, skip the default verification
+		// and verify only that the certificate is signed by the cluster CA.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("management server did not send a certificate")
+			}
+			certs := make([]*x509.Certificate, len(rawCerts))
+			for i, rawCert := range rawCerts {
+				cert, err := x509.ParseCertificate(rawCert)
+				if err != nil {
+					return err
+				}
+				certs[i] = cert
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				Roots:         caPool,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			})
+			return err
+		},
+	}, nil
+}
+
+// NewMgmClient connects to the Management Servers of the given NdbCluster.
+// If TLS is enabled in the NdbCluster, caSecret should be the Secret that
+// has the cluster CA and the connection is made over TLS.
+func NewMgmClient(nc *v1.NdbCluster, caSecret *corev1.Secret, desiredNodeId ...int) (mgmapi.MgmClient, error) {
+	var tlsConfig *tls.Config
+	if nc.Spec.TLS != nil {
+		if caSecret == nil {
+			return nil, errors.New("the cluster CA is required to connect to the management server")
+		}
+
+		var err error
+		tlsConfig, err = NewMgmClientTLSConfig(
+			caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mgmClient, err := mgmapi.NewMgmClientWithTLS(nc.GetConnectstring(), tlsConfig, desiredNodeId...)
+	if err != nil {
+		return nil, err
+	}
+	return mgmClient, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package ndbtls
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mysql/ndb-operator/pkg/mgmapi"
+)
+
+// runFakeTLSMgmd emulates a Management Server that accepts only TLS
+// connections from clients having a certificate signed by the given CA.
+// It upgrades the first accepted connection to TLS and replies to a
+// 'get mgmd nodeid' command.
+func runFakeTLSMgmd(t *testing.T, listener net.Listener, caCertPEM, caKeyPEM []byte) {
+	t.Helper()
+
+	serverCertPEM, serverKeyPEM, err := NewCertificate(caCertPEM, caKeyPEM, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "example-ndb-mgmd-0"},
+		DNSNames:    []string{"example-ndb-mgmd-0.example-ndb-mgmd"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create the server certificate : %s", err)
+	}
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load the server certificate : %s", err)
+	}
+	caPool := x509.NewCertPool()
+	caPool.AppendCertsFromPEM(caCertPEM)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Wait for the start tls command
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() && scanner.Text() != "" {
+		}
+		if _, err = fmt.Fprint(conn, "start tls reply\nresult: Ok\n\n"); err != nil {
+			return
+		}
+
+		tlsConn := tls.Server(conn, &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientCAs:    caPool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		})
+		if err = tlsConn.Handshake(); err != nil {
+			return
+		}
+
+		// Reply to the get mgmd nodeid command
+		scanner = bufio.NewScanner(tlsConn)
+		for scanner.Scan() && scanner.Text() != "" {
+		}
+		_, _ = fmt.Fprint(tlsConn, "get mgmd nodeid reply\nnodeid: 1\n\n")
+	}()
+}
+
+func TestNewMgmClientTLSConfig(t *testing.T) {
+	caCertPEM, caKeyPEM, err := NewCA("test CA", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create the CA : %s", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start the fake management server : %s", err)
+	}
+	defer listener.Close()
+	runFakeTLSMgmd(t, listener, caCertPEM, caKeyPEM)
+
+	tlsConfig, err := NewMgmClientTLSConfig(caCertPEM, caKeyPEM)
+	if err != nil {
+		t.Fatalf("NewMgmClientTLSConfig failed : %s", err)
+	}
+
+	// Connect to the desired nodeId to verify that the
+	// commands are sent over the TLS connection.
+	mgmClient, err := mgmapi.NewMgmClientWithTLS(listener.Addr().String(), tlsConfig, 1)
+	if err != nil {
+		t.Fatalf("Failed to connect to the management server over TLS : %s", err)
+	}
+	mgmClient.Disconnect()
+
+	// A client that trusts a different CA should fail to connect
+	otherCACertPEM, otherCAKeyPEM, err := NewCA("other CA", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create the CA : %s", err)
+	}
+	if tlsConfig, err = NewMgmClientTLSConfig(otherCACertPEM, otherCAKeyPEM); err != nil {
+		t.Fatalf("NewMgmClientTLSConfig failed : %s", err)
+	}
+	runFakeTLSMgmd(t, listener, caCertPEM, caKeyPEM)
+	if _, err = mgmapi.NewMgmClientWithTLS(listener.Addr().String(), tlsConfig); err == nil {
+		t.Error("Expected the connection to fail as the management server certificate is not signed by the CA")
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -6,6 +6,7 @@ package resources
 
 import (
 	"embed"
+	"encoding/json"
 	"fmt"
 
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
@@ -49,6 +50,12 @@ func updateManagementConfig(
 		data[constants.DataNodeInitialRestart] = "true"
 	}
 
+	if oldConfigSummary != nil && oldConfigSummary.RedundancyLevel != ndb.Spec.RedundancyLevel {
+		// NoOfReplicas is changed, All data nodes need to perform an initial
+		// system restart with the new config. The data is restored afterwards.
+		data[constants.DataNodeInitialRestart] = "true"
+	}
+
 	// add/update the API slot information
 	data[constants.NumOfMySQLServers] = fmt.Sprintf("%d", ndb.GetMySQLServerNodeCount())
 
@@ -89,6 +96,62 @@ func updateMySQLConfig(
 	return nil
 }
 
+// updateMySQLServerGroupsConfig updates the MySQL Server group
+// details and their my.cnf keys in the configMap if required
+func updateMySQLServerGroupsConfig(
+	nc *v1.NdbCluster, data map[string]string, oldConfigSummary *ndbconfig.ConfigSummary) error {
+
+	numOfMySQLServers := make(map[string]int32)
+	for i := range nc.Spec.MysqlNodeGroups {
+		mysqldGroup := &nc.Spec.MysqlNodeGroups[i]
+		numOfMySQLServers[mysqldGroup.Name] = mysqldGroup.NodeCount
+
+		myCnfKey := ndbconfig.GetMySQLServerGroupConfigKey(mysqldGroup.Name)
+		if needsUpdate, err := oldConfigSummary.MySQLServerGroupCnfNeedsUpdate(mysqldGroup); err != nil {
+			klog.Errorf("Failed to check if the my.cnf of MySQL Server group %q needs to be updated : %s",
+				mysqldGroup.Name, err)
+			return err
+		} else if needsUpdate {
+			myCnf, err := ndbconfig.GetMySQLServerGroupConfigString(mysqldGroup, oldConfigSummary)
+			if err != nil {
+				klog.Errorf("Failed to get the my.cnf config string of MySQL Server group %q : %s",
+					mysqldGroup.Name, err)
+				return err
+			}
+
+			if myCnf == "" {
+				delete(data, myCnfKey)
+			} else {
+				data[myCnfKey] = myCnf
+			}
+		}
+	}
+
+	// Remove the my.cnf keys of the groups that have been removed from the spec
+	if oldConfigSummary != nil {
+		for groupName := range oldConfigSummary.MySQLServerGroups {
+			if _, exists := numOfMySQLServers[groupName]; !exists {
+				delete(data, ndbconfig.GetMySQLServerGroupConfigKey(groupName))
+			}
+		}
+	}
+
+	if len(numOfMySQLServers) == 0 {
+		delete(data, constants.MySQLServerGroups)
+		return nil
+	}
+
+	// add/update the number of MySQL Servers of the groups
+	numOfMySQLServersJSON, err := json.Marshal(numOfMySQLServers)
+	if err != nil {
+		klog.Errorf("Failed to marshal the MySQL Server group details : %s", err)
+		return err
+	}
+	data[constants.MySQLServerGroups] = string(numOfMySQLServersJSON)
+
+	return nil
+}
+
 // updateHelperScripts updates the data map with the helper
 // scripts used for the MySQL Server initialisation & health
 // probes and Data node health probe.
@@ -142,6 +205,12 @@ func GetUpdatedConfigMap(
 		return nil
 	}
 
+	// Update the MySQL Server groups and their custom config
+	if err := updateMySQLServerGroupsConfig(ndb, updatedCm.Data, oldConfigSummary); err != nil {
+		klog.Errorf("Failed to update the config map : %v", err)
+		return nil
+	}
+
 	// Update the generation the config map is based on
 	updatedCm.Data[constants.NdbClusterGeneration] = fmt.Sprintf("%d", ndb.Generation)
 
@@ -182,6 +251,11 @@ func CreateConfigMap(ndb *v1.NdbCluster) *corev1.ConfigMap {
 		return nil
 	}
 
+	// Add the MySQL Server groups and their custom config
+	if updateMySQLServerGroupsConfig(ndb, data, nil) != nil {
+		return nil
+	}
+
 	// Add the helper scripts
 	if updateHelperScripts(data) != nil {
 		return nil
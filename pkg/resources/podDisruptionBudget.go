@@ -1,4 +1,4 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -12,7 +12,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// NewPodDisruptionBudget creates a PodDisruptionBudget allowing maximum 1 data node to be unavailable
+// NewPodDisruptionBudget creates a PodDisruptionBudget allowing maximum 1
+// data node or 1 management node, based on the nodeTypeSelector, to be unavailable
 func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policyv1.PodDisruptionBudget {
 
 	// Labels for the resource
@@ -25,7 +26,6 @@ func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policy
 		constants.ClusterNodeTypeLabel: nodeTypeSelector,
 	})
 
-	minAvailable := intstr.FromInt(int(ndb.Spec.DataNode.NodeCount - 1))
 	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            ndb.GetPodDisruptionBudgetName(nodeTypeSelector),
@@ -37,9 +37,17 @@ func NewPodDisruptionBudget(ndb *v1.NdbCluster, nodeTypeSelector string) *policy
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
-			MinAvailable: &minAvailable,
 		},
 	}
 
+	if nodeTypeSelector == constants.NdbNodeTypeMgmd {
+		// Use maxUnavailable as the management nodes can be added later
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	} else {
+		minAvailable := intstr.FromInt(int(ndb.Spec.DataNode.NodeCount - 1))
+		pdb.Spec.MinAvailable = &minAvailable
+	}
+
 	return pdb
 }
@@ -0,0 +1,236 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package resources
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
+)
+
+// RestoreStep is a step in the restore of the backup specified in spec.restoreFrom
+type RestoreStep string
+
+const (
+	// RestoreStepMetadata restores the schema of the backed up tables
+	RestoreStepMetadata RestoreStep = "meta"
+	// RestoreStepData restores the backup files written by a single data node
+	RestoreStepData RestoreStep = "data"
+	// RestoreStepIndexes rebuilds the indexes disabled during the data restore
+	RestoreStepIndexes RestoreStep = "index"
+)
+
+const (
+	restoreVolumeName  = "backup-volume"
+	restoreVolumeMount = constants.DataDir + "/backup"
+
+	// Volumes with the cluster CA and the certificates used by ndb_restore
+	restoreTLSCAVolumeName = "ndb-tls-ca-vol"
+	restoreTLSVolumeName   = "ndb-tls-vol"
+)
+
+// GetRestoreJobName returns the name of the Job that runs the given
+// restore step. The nodeId is used only by the RestoreStepData step.
+func GetRestoreJobName(nc *v1.NdbCluster, step RestoreStep, nodeId int32) string {
+	if step == RestoreStepData {
+		return fmt.Sprintf("%s-restore-%s-%d", nc.Name, step, nodeId)
+	}
+	return fmt.Sprintf("%s-restore-%s", nc.Name, step)
+}
+
+// GetRestoreCommand returns the ndb_restore command that performs the
+// given restore step using the backup files, in the backupPath directory,
+// written by the data node with the given nodeId.
+func GetRestoreCommand(nc *v1.NdbCluster, step RestoreStep, backupId, nodeId int32, backupPath string) []string {
+	args := []string{
+		"ndb_restore",
+		"--ndb-connectstring=" + nc.GetConnectstring(),
+		fmt.Sprintf("--backupid=%d", backupId),
+		fmt.Sprintf("--nodeid=%d", nodeId),
+		"--backup-path=" + backupPath,
+	}
+
+	if nc.Spec.TLS != nil {
+		// Use the API node certificate available in the pod
+		args = append(args, "--ndb-tls-search-path="+constants.TLSDir)
+	}
+
+	switch step {
+	case RestoreStepMetadata:
+		// Restore the schema with the indexes disabled
+		// to speed up the data restore that follows.
+		args = append(args, "--restore-meta", "--disable-indexes")
+	case RestoreStepData:
+		args = append(args, "--restore-data", "--disable-indexes")
+	case RestoreStepIndexes:
+		args = append(args, "--rebuild-indexes")
+	}
+
+	return args
+}
+
+// NewRestoreJob creates a Job that runs ndb_restore to perform the given
+// restore step using the backup files written by the data node with the
+// given nodeId. The metadata restore and the index rebuild can use the
+// backup files of any one of the data nodes.
+func NewRestoreJob(nc *v1.NdbCluster, step RestoreStep, nodeId int32) *batchv1.Job {
+	restoreFrom := nc.Spec.RestoreFrom
+	backupDir := filepath.Join(restoreFrom.Path, fmt.Sprintf("BACKUP-%d", restoreFrom.BackupId))
+	return newRestoreJob(nc, GetRestoreJobName(nc, step, nodeId), step, restoreFrom.BackupId, nodeId,
+		restoreFrom.PersistentVolumeClaimName, backupDir, nil)
+}
+
+// GetMigrationRestoreJobName returns the name of the Job that runs the given
+// step of the restore of the backup, with the given id, taken while migrating
+// to a new redundancyLevel. The nodeId is used only by the RestoreStepData step.
+func GetMigrationRestoreJobName(nc *v1.NdbCluster, step RestoreStep, backupId, nodeId int32) string {
+	if step == RestoreStepData {
+		return fmt.Sprintf("%s-migration-restore-%d-%s-%d", nc.Name, backupId, step, nodeId)
+	}
+	return fmt.Sprintf("%s-migration-restore-%d-%s", nc.Name, backupId, step)
+}
+
+// NewMigrationRestoreJob creates a Job that runs ndb_restore to perform the
+// given restore step using the backup files, taken while migrating to a new
+// redundancyLevel, at the given location. The files are read from the data
+// node PVC that has them, and as the PVC can be mounted only on a single K8s
+// worker node, the Job is run on the same worker node as the data node pod.
+func NewMigrationRestoreJob(nc *v1.NdbCluster, step RestoreStep,
+	backupId int32, location *v1.NdbClusterBackupLocation) *batchv1.Job {
+	// The data node PVC is mounted at the DataDir of the data node pod
+	backupDir := strings.TrimPrefix(location.Path, constants.DataDir+"/data")
+	affinity := &corev1.Affinity{
+		PodAffinity: &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							appsv1.StatefulSetPodNameLabel: location.PodName,
+						},
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			},
+		},
+	}
+	return newRestoreJob(nc, GetMigrationRestoreJobName(nc, step, backupId, location.NodeId),
+		step, backupId, location.NodeId, location.PersistentVolumeClaimName, backupDir, affinity)
+}
+
+// newRestoreJob creates a Job, with the given name and pod affinity, that
+// runs ndb_restore to perform the given restore step using the backup files
+// in the backupDir directory of the PVC with the given name.
+func newRestoreJob(nc *v1.NdbCluster, name string, step RestoreStep, backupId, nodeId int32,
+	claimName, backupDir string, affinity *corev1.Affinity) *batchv1.Job {
+	args := GetRestoreCommand(nc, step, backupId, nodeId, filepath.Join(restoreVolumeMount, backupDir))
+
+	podSpec := corev1.PodSpec{
+		Affinity: affinity,
+		// Failed steps are not retried as ndb_restore is not idempotent
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: nc.GetServiceAccountName(),
+		Containers: []corev1.Container{
+			{
+				Name:            "ndb-restore-container",
+				Image:           nc.Spec.Image,
+				ImagePullPolicy: nc.Spec.ImagePullPolicy,
+				Command:         args,
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      restoreVolumeName,
+						MountPath: restoreVolumeMount,
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: restoreVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+						ReadOnly:  true,
+					},
+				},
+			},
+		},
+	}
+
+	if nc.Spec.TLS != nil {
+		// Create an API node certificate, signed by the
+		// cluster CA, before running ndb_restore.
+		podSpec.InitContainers = []corev1.Container{
+			{
+				Name:            "ndb-restore-tls-init-container",
+				Image:           nc.Spec.Image,
+				ImagePullPolicy: nc.Spec.ImagePullPolicy,
+				Command: []string{"/bin/bash", "-ecx", strings.Join(
+					ndbtls.GetSignKeysCommand(constants.TLSCADir, constants.TLSDir, "api"), " ")},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      restoreTLSCAVolumeName,
+						MountPath: constants.TLSCADir,
+						ReadOnly:  true,
+					},
+					{
+						Name:      restoreTLSVolumeName,
+						MountPath: constants.TLSDir,
+					},
+				},
+			},
+		}
+		restoreContainer := &podSpec.Containers[0]
+		restoreContainer.VolumeMounts = append(restoreContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      restoreTLSVolumeName,
+			MountPath: constants.TLSDir,
+			ReadOnly:  true,
+		})
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         restoreTLSCAVolumeName,
+			VolumeSource: ndbtls.GetCAVolumeSource(nc),
+		}, corev1.Volume{
+			Name: restoreTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	if nc.Spec.ImagePullSecretName != "" {
+		podSpec.ImagePullSecrets = []corev1.LocalObjectReference{
+			{
+				Name: nc.Spec.ImagePullSecretName,
+			},
+		}
+	}
+
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: nc.Namespace,
+			Labels: nc.GetCompleteLabels(map[string]string{
+				constants.ClusterResourceTypeLabel: "restore-job",
+			}),
+			OwnerReferences: nc.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: podSpec,
+			},
+		},
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
 	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
 	corev1 "k8s.io/api/core/v1"
@@ -20,6 +21,16 @@ const (
 	validPasswordChars  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	mysqldRootPassword  = "mysqld-root-password"
 	ndbOperatorPassword = "ndb-operator-password"
+	replicationPassword = "replication-user-password"
+)
+
+const (
+	// RetainedPasswordKey is the key of the NDB Operator password Secret
+	// that holds the old password while the password is being rotated
+	RetainedPasswordKey = "retained-password"
+	// PasswordRotatedAtAnnotation is the annotation key which stores the
+	// time the password in a Secret was last rotated by the operator
+	PasswordRotatedAtAnnotation = ndbcontroller.GroupName + "/password-rotated-at"
 )
 
 // generateRandomPassword generates a random alpha numeric password of length n
@@ -79,3 +90,45 @@ func NewMySQLNDBOperatorPasswordSecret(nc *v1.NdbCluster) *corev1.Secret {
 	secretName := GetMySQLNDBOperatorPasswordSecretName(nc)
 	return newBasicAuthSecretWithRandomPassword(nc, secretName, ndbOperatorPassword)
 }
+
+// GetReplicationUserPasswordSecretName returns the name of the replication user
+// password secret and a bool flag to specify if it is a custom secret created by the user
+func GetReplicationUserPasswordSecretName(nc *v1.NdbCluster) (secretName string, customSecret bool) {
+	if nc.Spec.Replication != nil && nc.Spec.Replication.UserSecretName != "" {
+		return nc.Spec.Replication.UserSecretName, true
+	}
+	return nc.Name + "-" + replicationPassword, false
+}
+
+// NewReplicationUserPasswordSecret creates and returns a new replication user password secret
+func NewReplicationUserPasswordSecret(nc *v1.NdbCluster) *corev1.Secret {
+	secretName, _ := GetReplicationUserPasswordSecretName(nc)
+	return newBasicAuthSecretWithRandomPassword(nc, secretName, replicationPassword)
+}
+
+// RotateSecretPassword returns a copy of the given basic authentication
+// secret with a new random password. The current password is retained
+// under the RetainedPasswordKey if retainCurrentPassword is true.
+func RotateSecretPassword(secret *corev1.Secret, retainCurrentPassword bool, now time.Time) *corev1.Secret {
+	rotatedSecret := secret.DeepCopy()
+	if retainCurrentPassword {
+		rotatedSecret.Data[RetainedPasswordKey] = secret.Data[corev1.BasicAuthPasswordKey]
+	}
+	rotatedSecret.Data[corev1.BasicAuthPasswordKey] = []byte(generateRandomPassword(16))
+
+	if rotatedSecret.Annotations == nil {
+		rotatedSecret.Annotations = make(map[string]string)
+	}
+	rotatedSecret.Annotations[PasswordRotatedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	return rotatedSecret
+}
+
+// GetPasswordRotationTime returns the time the password in the
+// given Secret was last rotated, or when the Secret was created
+// if the password has never been rotated by the operator.
+func GetPasswordRotationTime(secret *corev1.Secret) time.Time {
+	if rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[PasswordRotatedAtAnnotation]); err == nil {
+		return rotatedAt
+	}
+	return secret.CreationTimestamp.Time
+}
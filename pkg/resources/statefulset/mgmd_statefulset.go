@@ -1,4 +1,4 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -87,8 +87,8 @@ func (mss *mgmdStatefulSet) getPodVolumes(nc *v1.NdbCluster) []corev1.Volume {
 }
 
 // getVolumeMounts returns the volumes to be mounted to the mgmd containers
-func (mss *mgmdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
-	return []corev1.VolumeMount{
+func (mss *mgmdStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{
 		// Append the empty dir volume mount to be used as a data dir
 		{
 			Name:      mss.getDataDirVolumeName(),
@@ -104,6 +104,13 @@ func (mss *mgmdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
 		// Mount the work dir volume
 		mss.getWorkDirVolumeMount(),
 	}
+
+	if nc.Spec.TLS != nil {
+		// Mount the node's private key and certificate
+		volumeMounts = append(volumeMounts, getTLSVolumeMount())
+	}
+
+	return volumeMounts
 }
 
 // getContainers returns the containers to run a Management Node
@@ -119,6 +126,10 @@ func (mss *mgmdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container
 		"--ndb-nodeid=$(cat " + NodeIdFilePath + ")",
 	}
 
+	if nc.Spec.TLS != nil {
+		cmdAndArgs = append(cmdAndArgs, getTLSSearchPathArg())
+	}
+
 	if debug.Enabled {
 		// Increase verbosity in debug mode
 		cmdAndArgs = append(cmdAndArgs, "-v")
@@ -126,7 +137,7 @@ func (mss *mgmdStatefulSet) getContainers(nc *v1.NdbCluster) []corev1.Container
 
 	mgmdContainer := mss.createContainer(nc,
 		mss.getContainerName(false),
-		cmdAndArgs, mss.getVolumeMounts(), mgmdPorts)
+		cmdAndArgs, mss.getVolumeMounts(nc), mgmdPorts)
 
 	// Startup probe for the mgmd container
 	mgmdContainer.StartupProbe = &corev1.Probe{
@@ -198,3 +209,10 @@ func NewMgmdStatefulSet() NdbStatefulSetInterface {
 		},
 	}
 }
+
+// GetMgmdContainerName returns the name of the
+// container that runs the Management Server process.
+func GetMgmdContainerName() string {
+	bss := baseStatefulSet{nodeType: constants.NdbNodeTypeMgmd}
+	return bss.getContainerName(false)
+}
@@ -1,10 +1,11 @@
-// Copyright (c) 2020, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2020, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package statefulset
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/mysql/ndb-operator/config/debug"
@@ -13,6 +14,7 @@ import (
 	"github.com/mysql/ndb-operator/pkg/constants"
 	"github.com/mysql/ndb-operator/pkg/helpers"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
 	"github.com/mysql/ndb-operator/pkg/resources"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,10 +36,19 @@ const (
 	mysqldCnfVolName   = mysqldClientName + "-cnf-vol"
 	mysqldCnfMountPath = mysqldDir + "/cnf"
 
+	// MySQL Server certificate volume and mount path
+	mysqldTLSVolName   = mysqldClientName + "-tls-vol"
+	mysqldTLSMountPath = constants.MySQLServerTLSDir
+
+	// Base name of the binary log files written by the binlog MySQL Servers
+	binlogBaseName = "binlog"
+
 	// LastAppliedMySQLServerConfigVersion is the annotation key that holds the last applied version of MySQL Server config (my.cnf version)
 	LastAppliedMySQLServerConfigVersion = ndbcontroller.GroupName + "/last-applied-my-cnf-config-version"
 	// RootPasswordSecret is the name of the secret that holds the password for the root account
 	RootPasswordSecret = ndbcontroller.GroupName + "/root-password-secret"
+	// LastAppliedMySQLServerCertVersion is the annotation key that holds the version of the MySQL Server certificate used by the pods
+	LastAppliedMySQLServerCertVersion = ndbcontroller.GroupName + "/last-applied-mysqld-cert-version"
 )
 
 var (
@@ -50,10 +61,100 @@ var (
 type mysqldStatefulSet struct {
 	baseStatefulSet
 	configMapLister listerscorev1.ConfigMapLister
+	secretLister    listerscorev1.SecretLister
+	// groupName is the name of the MySQL Server group, declared in
+	// spec.mysqlNodeGroups, controlled by this StatefulSet. It is
+	// empty for the MySQL Servers declared in spec.mysqlNode.
+	groupName string
+}
+
+// getGroupSpec returns the spec of the MySQL Servers controlled by the
+// StatefulSet. The spec of the MySQL Servers declared in spec.mysqlNode
+// is returned as an NdbMysqldGroupSpec for uniform access.
+func (mss *mysqldStatefulSet) getGroupSpec(nc *v1.NdbCluster) *v1.NdbMysqldGroupSpec {
+	if mss.groupName != "" {
+		if mysqldGroup := nc.GetMySQLServerGroupSpec(mss.groupName); mysqldGroup != nil {
+			return mysqldGroup
+		}
+		// The group has been removed from the spec
+		return &v1.NdbMysqldGroupSpec{Name: mss.groupName}
+	}
+
+	mysqldSpec := nc.Spec.MysqlNode
+	if mysqldSpec == nil {
+		return &v1.NdbMysqldGroupSpec{}
+	}
+
+	return &v1.NdbMysqldGroupSpec{
+		NodeCount:          mysqldSpec.NodeCount,
+		MaxNodeCount:       mysqldSpec.MaxNodeCount,
+		ConnectionPoolSize: mysqldSpec.ConnectionPoolSize,
+		MyCnf:              mysqldSpec.MyCnf,
+		EnableLoadBalancer: mysqldSpec.EnableLoadBalancer,
+		NdbPodSpec:         mysqldSpec.NdbPodSpec,
+		InitScripts:        mysqldSpec.InitScripts,
+		PVCSpec:            mysqldSpec.PVCSpec,
+	}
+}
+
+// GetMySQLServerCertVersion returns the version of the operator managed
+// MySQL Server certificate or an empty string if it is not enabled.
+func GetMySQLServerCertVersion(nc *v1.NdbCluster, secretLister listerscorev1.SecretLister) (string, error) {
+	if nc.GetMySQLServerTLSSpec() == nil {
+		return "", nil
+	}
+
+	secretName := ndbtls.GetMySQLServerTLSSecretName(nc)
+	tlsSecret, err := secretLister.Secrets(nc.Namespace).Get(secretName)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the MySQL Server certificate Secret %q : %s", secretName, err)
+		return "", err
+	}
+
+	return ndbtls.GetMySQLServerCertVersion(tlsSecret), nil
+}
+
+// getMySQLConfigKey returns the configmap key that stores the my.cnf of the MySQL Servers
+func (mss *mysqldStatefulSet) getMySQLConfigKey() string {
+	if mss.groupName != "" {
+		return ndbconfig.GetMySQLServerGroupConfigKey(mss.groupName)
+	}
+	return constants.MySQLConfigKey
+}
+
+// GetName returns the name of the MySQL Server StatefulSet
+func (mss *mysqldStatefulSet) GetName(nc *v1.NdbCluster) string {
+	if mss.groupName != "" {
+		return nc.GetMySQLServerGroupWorkloadName(mss.groupName)
+	}
+	return mss.baseStatefulSet.GetName(nc)
 }
 
 func (mss *mysqldStatefulSet) NewGoverningService(nc *v1.NdbCluster) *corev1.Service {
-	return newService(nc, mysqldPorts, mss.nodeType, false, nc.Spec.MysqlNode.EnableLoadBalancer)
+	svc := newService(nc, mysqldPorts, mss.nodeType, false, mss.getGroupSpec(nc).EnableLoadBalancer)
+	if mss.groupName != "" {
+		// The Service of a group exposes only the MySQL Servers of that group
+		svc.Name = mss.GetName(nc)
+		svc.Labels[constants.MySQLServerGroupLabel] = mss.groupName
+		svc.Spec.Selector[constants.MySQLServerGroupLabel] = mss.groupName
+	} else if nc.HasDedicatedBinlogServers() {
+		// Exclude the dedicated binlog servers from the Service
+		svc.Spec.Selector[constants.MySQLServerRoleLabel] = constants.MySQLServerRoleApplication
+	}
+	return svc
+}
+
+// getRole returns the role of the MySQL Servers controlled by the
+// StatefulSet, or an empty string if the NdbCluster doesn't have
+// dedicated binlog servers.
+func (mss *mysqldStatefulSet) getRole(nc *v1.NdbCluster) string {
+	if !nc.HasDedicatedBinlogServers() {
+		return ""
+	}
+	if mss.GetName(nc) == nc.GetBinlogMySQLServerWorkloadName() {
+		return constants.MySQLServerRoleBinlog
+	}
+	return constants.MySQLServerRoleApplication
 }
 
 // getPodVolumes returns the volumes to be used by the pod
@@ -87,7 +188,8 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 	var initScriptPvs corev1.ProjectedVolumeSource
 
 	// Create projections for all the scripts and append it to the initScriptPvs
-	for configMapName, configMapKeys := range ndb.Spec.MysqlNode.InitScripts {
+	mysqldGroup := mss.getGroupSpec(ndb)
+	for configMapName, configMapKeys := range mysqldGroup.InitScripts {
 		cm, err := mss.configMapLister.ConfigMaps(ndb.Namespace).Get(configMapName)
 		if err != nil {
 			klog.Errorf("Failed to get configMap '%s/%s' : %s", ndb.Namespace, configMapName, err)
@@ -134,7 +236,7 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 		},
 	})
 
-	if len(ndb.GetMySQLCnf()) > 0 {
+	if len(mysqldGroup.GetMySQLCnf()) > 0 {
 		// Load the cnf configmap key as a volume
 		podVolumes = append(podVolumes, corev1.Volume{
 			Name: mysqldCnfVolName,
@@ -145,7 +247,7 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 					},
 					Items: []corev1.KeyToPath{
 						{
-							Key:  constants.MySQLConfigKey,
+							Key:  mss.getMySQLConfigKey(),
 							Path: constants.MySQLConfigKey,
 						},
 					},
@@ -157,10 +259,22 @@ func (mss *mysqldStatefulSet) getPodVolumes(ndb *v1.NdbCluster) ([]corev1.Volume
 	// An empty directory volume needs to be provided to the mysql server
 	// pods if the NdbCluster resource doesn't have any PVCs defined to
 	// be used with the mysql servers.
-	if ndb.Spec.MysqlNode.PVCSpec == nil {
+	if mysqldGroup.PVCSpec == nil {
 		podVolumes = append(podVolumes, *mss.getEmptyDirPodVolume(mss.getDataDirVolumeName()))
 	}
 
+	if ndb.GetMySQLServerTLSSpec() != nil {
+		// Load the MySQL Server certificate from the secret
+		podVolumes = append(podVolumes, corev1.Volume{
+			Name: mysqldTLSVolName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ndbtls.GetMySQLServerTLSSecretName(ndb),
+				},
+			},
+		})
+	}
+
 	return podVolumes, nil
 }
 
@@ -183,7 +297,7 @@ func (mss *mysqldStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.Volume
 		mss.getWorkDirVolumeMount(),
 	}
 
-	if len(nc.GetMySQLCnf()) > 0 {
+	if len(mss.getGroupSpec(nc).GetMySQLCnf()) > 0 {
 		// Mount the cnf volume
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      mysqldCnfVolName,
@@ -191,6 +305,20 @@ func (mss *mysqldStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.Volume
 		})
 	}
 
+	if nc.Spec.TLS != nil {
+		// Mount the node's private key and certificate
+		volumeMounts = append(volumeMounts, getTLSVolumeMount())
+	}
+
+	if nc.GetMySQLServerTLSSpec() != nil {
+		// Mount the MySQL Server certificate
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      mysqldTLSVolName,
+			MountPath: mysqldTLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	return volumeMounts
 }
 
@@ -202,7 +330,8 @@ func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 
 	// Add the arguments to the command
 	// first, pass any provided cnf options via defaults-file
-	if len(nc.GetMySQLCnf()) > 0 {
+	mysqldGroup := mss.getGroupSpec(nc)
+	if len(mysqldGroup.GetMySQLCnf()) > 0 {
 		cmdAndArgs = append(cmdAndArgs,
 			"--defaults-file="+mysqldCnfMountPath+"/"+constants.MySQLConfigKey)
 	}
@@ -214,10 +343,53 @@ func (mss *mysqldStatefulSet) getMySQLServerCmd(nc *v1.NdbCluster) []string {
 		"--ndb-connectstring="+nc.GetConnectstring(),
 		"--user=mysql",
 		"--datadir="+dataDirectoryMountPath,
-		"--ndb-cluster-connection-pool="+strconv.Itoa(int(nc.GetMySQLServerConnectionPoolSize())),
+		"--ndb-cluster-connection-pool="+strconv.Itoa(int(mysqldGroup.ConnectionPoolSize)),
 		"--ndb-cluster-connection-pool-nodeids=$(cat "+NodeIdFilePath+")",
 	)
 
+	if nc.Spec.TLS != nil {
+		cmdAndArgs = append(cmdAndArgs, getTLSSearchPathArg())
+	}
+
+	if nc.GetMySQLServerTLSSpec() != nil {
+		// Use the operator managed certificate for the client connections
+		cmdAndArgs = append(cmdAndArgs,
+			"--ssl-ca="+mysqldTLSMountPath+"/"+ndbtls.MySQLServerCACertKey,
+			"--ssl-cert="+mysqldTLSMountPath+"/"+corev1.TLSCertKey,
+			"--ssl-key="+mysqldTLSMountPath+"/"+corev1.TLSPrivateKeyKey,
+		)
+	}
+
+	if replicationSpec := nc.Spec.Replication; replicationSpec != nil {
+		cmdAndArgs = append(cmdAndArgs,
+			// Generate a server-id, unique across the replicating NdbClusters,
+			// from the nodeId of the first connection in the connection pool
+			fmt.Sprintf("--server-id=$((%d+$(cut -d, -f1 %s)))", replicationSpec.ServerIdOffset, NodeIdFilePath),
+			// The replication channels are started by the operator from
+			// the last epoch applied to the MySQL Cluster
+			"--skip-replica-start",
+		)
+
+		if mss.GetName(nc) == nc.GetBinlogMySQLServerWorkloadName() {
+			// Record the changes made to the MySQL Cluster in the binary log
+			cmdAndArgs = append(cmdAndArgs,
+				"--log-bin="+binlogBaseName,
+				"--ndb-log-bin=ON",
+			)
+
+			if conflictRole := replicationSpec.ConflictRole; conflictRole != "" {
+				cmdAndArgs = append(cmdAndArgs,
+					// The role used by the NDB$EPOCH functions to resolve the
+					// conflicts in the changes applied from the other NdbCluster
+					"--ndb-conflict-role="+string(conflictRole),
+					// Log the transaction ids required by the
+					// *_TRANS functions of the other NdbCluster
+					"--ndb-log-transaction-id=ON",
+				)
+			}
+		}
+	}
+
 	if debug.Enabled {
 		cmdAndArgs = append(cmdAndArgs,
 			// Enable maximum verbosity for development debugging
@@ -320,28 +492,60 @@ func (mss *mysqldStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 
 	statefulSet := mss.newStatefulSet(nc, cs)
 	statefulSetSpec := &statefulSet.Spec
+	mysqldGroup := mss.getGroupSpec(nc)
 
 	// Fill in MySQL Server specific details
-	replicas := nc.GetMySQLServerNodeCount()
+	replicas := mysqldGroup.NodeCount
 	statefulSetSpec.Replicas = &replicas
 	// Set pod management policy to start MySQL Servers in parallel
 	statefulSetSpec.PodManagementPolicy = appsv1.ParallelPodManagement
 
-	// Update statefulset annotation
-	statefulSetAnnotations := statefulSet.GetAnnotations()
-	statefulSetAnnotations[RootPasswordSecret], _ = resources.GetMySQLRootPasswordSecretName(nc)
+	if mss.groupName == "" {
+		// Update statefulset annotation
+		statefulSetAnnotations := statefulSet.GetAnnotations()
+		statefulSetAnnotations[RootPasswordSecret], _ = resources.GetMySQLRootPasswordSecretName(nc)
+	} else {
+		// Use the group's name and Service, and label the
+		// StatefulSet and its pods with the group name.
+		statefulSet.Name = mss.GetName(nc)
+		statefulSetSpec.ServiceName = mss.GetName(nc)
+		statefulSet.Labels[constants.MySQLServerGroupLabel] = mss.groupName
+		statefulSetSpec.Selector.MatchLabels[constants.MySQLServerGroupLabel] = mss.groupName
+		statefulSetSpec.Template.Labels[constants.MySQLServerGroupLabel] = mss.groupName
+	}
+
+	if role := mss.getRole(nc); role != "" {
+		// Label the pods with their role. The StatefulSet's selector
+		// is immutable and hence the label is not added to it.
+		statefulSetSpec.Template.Labels[constants.MySQLServerRoleLabel] = role
+	}
 
 	// Add VolumeClaimTemplate if data node PVC Spec exists
-	if nc.Spec.MysqlNode.PVCSpec != nil {
+	if mysqldGroup.PVCSpec != nil {
 		statefulSetSpec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
 			// This PVC will be used as a template and an actual PVC will be created by the
 			// statefulset controller with name "<data-dir-vol-name(i.e mysqld-data-vol)>-<pod-name>"
-			*newPVC(nc, mss.getDataDirVolumeName(), nc.Spec.MysqlNode.PVCSpec),
+			*newPVC(nc, mss.getDataDirVolumeName(), mysqldGroup.PVCSpec),
 		}
 	}
 
 	// Update template pod spec
 	podSpec := &statefulSetSpec.Template.Spec
+	if mss.groupName != "" {
+		// The ndb-pod-initializer deduces the nodeIds of the MySQL Servers
+		// of a group from the nodeId of the first [mysqld] section of the
+		// group and the connection pool size of the group.
+		podInitContainer := &podSpec.InitContainers[0]
+		for i := range podInitContainer.Env {
+			if podInitContainer.Env[i].Name == "NDB_CONNECTION_POOL_SIZE" {
+				podInitContainer.Env[i].Value = strconv.Itoa(int(mysqldGroup.ConnectionPoolSize))
+			}
+		}
+		podInitContainer.Env = append(podInitContainer.Env, corev1.EnvVar{
+			Name:  "NDB_MYSQLD_START_NODE_ID",
+			Value: strconv.Itoa(ndbconfig.GetMySQLServerGroupStartNodeId(nc, mss.groupName)),
+		})
+	}
 	podSpec.InitContainers = append(podSpec.InitContainers, mss.getInitDBContainer(nc))
 	podSpec.Containers = mss.getContainers(nc)
 
@@ -357,22 +561,49 @@ func (mss *mysqldStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 		PodAntiAffinity: mss.getPodAntiAffinity(),
 	}
 	// Copy down any podSpec specified via CRD
-	CopyPodSpecFromNdbPodSpec(podSpec, nc.Spec.MysqlNode.NdbPodSpec)
+	CopyPodSpecFromNdbPodSpec(podSpec, mysqldGroup.NdbPodSpec)
 
 	// Annotate the spec template with my.cnf version to trigger
 	// an update of MySQL Servers when my.cnf changes.
+	myCnfVersion := cs.MySQLServerConfigVersion
+	if mss.groupName != "" {
+		myCnfVersion = 0
+		if groupSummary, exists := cs.MySQLServerGroups[mss.groupName]; exists {
+			myCnfVersion = groupSummary.MySQLServerConfigVersion
+		}
+	}
 	podAnnotations := statefulSetSpec.Template.GetAnnotations()
-	podAnnotations[LastAppliedMySQLServerConfigVersion] = strconv.FormatInt(int64(cs.MySQLServerConfigVersion), 10)
+	podAnnotations[LastAppliedMySQLServerConfigVersion] = strconv.FormatInt(int64(myCnfVersion), 10)
+
+	// Annotate the spec template with the certificate version to
+	// restart the MySQL Servers when the certificate is renewed.
+	certVersion, err := GetMySQLServerCertVersion(nc, mss.secretLister)
+	if err != nil {
+		return nil, err
+	}
+	if certVersion != "" {
+		podAnnotations[LastAppliedMySQLServerCertVersion] = certVersion
+	}
 
 	return statefulSet, nil
 }
 
 // NewMySQLdStatefulSet returns a new mysqldStatefulSet
-func NewMySQLdStatefulSet(configMapLister listerscorev1.ConfigMapLister) NdbStatefulSetInterface {
+func NewMySQLdStatefulSet(
+	configMapLister listerscorev1.ConfigMapLister, secretLister listerscorev1.SecretLister) NdbStatefulSetInterface {
+	return NewMySQLdGroupStatefulSet(configMapLister, secretLister, "")
+}
+
+// NewMySQLdGroupStatefulSet returns a new mysqldStatefulSet that controls
+// the MySQL Servers of the given group declared in spec.mysqlNodeGroups
+func NewMySQLdGroupStatefulSet(configMapLister listerscorev1.ConfigMapLister,
+	secretLister listerscorev1.SecretLister, groupName string) NdbStatefulSetInterface {
 	return &mysqldStatefulSet{
-		baseStatefulSet{
+		baseStatefulSet: baseStatefulSet{
 			nodeType: constants.NdbNodeTypeMySQLD,
 		},
-		configMapLister,
+		configMapLister: configMapLister,
+		secretLister:    secretLister,
+		groupName:       groupName,
 	}
 }
@@ -94,7 +94,7 @@ func (bss *baseStatefulSet) createContainer(
 	}
 
 	klog.Infof("Creating container %q from image %s", containerName, nc.Spec.Image)
-	return corev1.Container{
+	container := corev1.Container{
 		Name: containerName,
 		// Use the image provided in spec
 		Image:           nc.Spec.Image,
@@ -127,6 +127,17 @@ func (bss *baseStatefulSet) createContainer(
 		Command:      []string{"/bin/bash", "-ecx", strings.Join(commandAndArgs, " ")},
 		VolumeMounts: volumeMounts,
 	}
+
+	if nc.Spec.TLS != nil {
+		// Export the directory with the node's certificate
+		// to let the helper scripts connect over TLS.
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "NDB_TLS_SEARCH_PATH",
+			Value: tlsMountPath,
+		})
+	}
+
+	return container
 }
 
 // getWorkDirVolumeMount returns the VolumeMount for the work directory
@@ -198,6 +209,15 @@ func (bss *baseStatefulSet) newStatefulSet(
 	// Add the default init container and add the ndbOperatorImagePullSecretName
 	// to the existing ImagePullSecrets list.
 	podSpec.InitContainers = bss.getDefaultInitContainers(nc)
+	if bss.nodeType != constants.NdbNodeTypeMySQLD {
+		// Export the nodeId of the first data node to the env of the
+		// pod initializer to let it deduce the nodeId of the
+		// management and data nodes from their pod ordinal index.
+		podSpec.InitContainers[0].Env = append(podSpec.InitContainers[0].Env, corev1.EnvVar{
+			Name:  "NDB_DATA_NODE_START_NODE_ID",
+			Value: strconv.Itoa(ndbconfig.GetDataNodeStartNodeId(nc, cs)),
+		})
+	}
 	ndbOperatorImagePullSecretName := os.Getenv("NDB_OPERATOR_IMAGE_PULL_SECRET_NAME")
 	if ndbOperatorImagePullSecretName != "" {
 		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{
@@ -208,6 +228,22 @@ func (bss *baseStatefulSet) newStatefulSet(
 	// Add the empty dir volume
 	podSpec.Volumes = []corev1.Volume{*bss.getEmptyDirPodVolume(workDirVolName)}
 
+	if nc.Spec.TLS != nil {
+		// The pod initializer uses a client certificate
+		// signed by the cluster CA to connect to the
+		// Management Servers that accept only TLS.
+		podInitContainer := &podSpec.InitContainers[0]
+		podInitContainer.VolumeMounts = append(podInitContainer.VolumeMounts, getTLSCAVolumeMount())
+		podInitContainer.Env = append(podInitContainer.Env, corev1.EnvVar{
+			Name:  "NDB_TLS_CA_DIR",
+			Value: tlsCAMountPath,
+		})
+
+		// Create the node's certificate before the node is started
+		podSpec.InitContainers = append(podSpec.InitContainers, bss.getTLSInitContainer(nc))
+		podSpec.Volumes = append(podSpec.Volumes, bss.getTLSPodVolumes(nc)...)
+	}
+
 	podSpec.ServiceAccountName = nc.GetServiceAccountName()
 
 	// Labels to be used for the statefulset pods
@@ -1,17 +1,23 @@
-// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package statefulset
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mysql/ndb-operator/config/debug"
+	"github.com/mysql/ndb-operator/pkg/apis/ndbcontroller"
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	"github.com/mysql/ndb-operator/pkg/constants"
-	"github.com/mysql/ndb-operator/pkg/mgmapi"
 	"github.com/mysql/ndb-operator/pkg/ndbconfig"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +31,18 @@ var (
 	ndbmtdPorts = []int32{1186}
 )
 
+const (
+	// Name of the volume that has the disk data files
+	diskDataVolumeName = constants.NdbNodeTypeNdbmtd + "-disk-data-vol"
+
+	// DataNodeGroupPodSpecs is the annotation key that holds the pod specs specified
+	// via spec.dataNode.nodeGroups, mapped to the ordinals of the pods they apply to.
+	// They are applied to the data node pods by the webhook server on creation.
+	DataNodeGroupPodSpecs = ndbcontroller.GroupName + "/data-node-group-pod-specs"
+	// LastAppliedTDEPasswordVersion is the annotation key that holds the version of the TDE password used by the pods
+	LastAppliedTDEPasswordVersion = ndbcontroller.GroupName + "/last-applied-tde-password-version"
+)
+
 // ndbmtdStatefulSet implements the NdbStatefulSetInterface to control a set of data nodes
 type ndbmtdStatefulSet struct {
 	baseStatefulSet
@@ -73,9 +91,8 @@ func (nss *ndbmtdStatefulSet) getPodVolumes(nc *v1.NdbCluster) []corev1.Volume {
 }
 
 // getVolumeMounts returns the volumes to be mounted to the ndbmtd containers
-func (nss *ndbmtdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
-	// return volume mounts
-	return []corev1.VolumeMount{
+func (nss *ndbmtdStatefulSet) getVolumeMounts(nc *v1.NdbCluster) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{
 		{
 			// Volume mount for data directory
 			Name:      nss.getDataDirVolumeName(),
@@ -86,14 +103,40 @@ func (nss *ndbmtdStatefulSet) getVolumeMounts() []corev1.VolumeMount {
 		// Mount the work dir volume
 		nss.getWorkDirVolumeMount(),
 	}
+
+	if nc.HasDiskDataPVC() {
+		// Volume mount for the disk data files
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      diskDataVolumeName,
+			MountPath: constants.DiskDataDir,
+		})
+	}
+
+	if nc.Spec.TLS != nil {
+		// Mount the node's private key and certificate
+		volumeMounts = append(volumeMounts, getTLSVolumeMount())
+	}
+
+	return volumeMounts
 }
 
 // getResourceRequestRequirements computes minimum memory required by the datanode
 // from the MySQL Cluster config and returns the ResourceList with the calculated memory
 func (nss *ndbmtdStatefulSet) getResourceRequestRequirements(nc *v1.NdbCluster) (corev1.ResourceList, error) {
 
+	// Retrieve the cluster CA required to connect to the Management Server
+	var caSecret *corev1.Secret
+	if nc.Spec.TLS != nil {
+		secretName, _ := ndbtls.GetCASecretName(nc)
+		var err error
+		if caSecret, err = nss.secretLister.Secrets(nc.Namespace).Get(secretName); err != nil {
+			klog.Errorf("Failed to retrieve Secret %q : %s", secretName, err)
+			return nil, err
+		}
+	}
+
 	// Connect to the Management Server
-	mgmClient, err := mgmapi.NewMgmClient(nc.GetConnectstring())
+	mgmClient, err := ndbtls.NewMgmClient(nc, caSecret)
 	if err != nil {
 		klog.Errorf("Failed to connect to Management Server : %s", err)
 		return nil, err
@@ -212,6 +255,13 @@ func (nss *ndbmtdStatefulSet) getResourceRequestRequirements(nc *v1.NdbCluster)
 	}, nil
 }
 
+// GetTDEPasswordVersion returns the version of the password stored in the
+// given TDE Secret. The version changes only when the password changes.
+func GetTDEPasswordVersion(tdeSecret *corev1.Secret) string {
+	hash := sha256.Sum256(tdeSecret.Data[corev1.BasicAuthPasswordKey])
+	return hex.EncodeToString(hash[:8])
+}
+
 // getContainers returns the containers to run a data Node
 func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster, addInitialFlag bool) ([]corev1.Container, error) {
 
@@ -225,6 +275,10 @@ func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster, addInitialFlag bo
 		"--ndb-nodeid=$(cat " + NodeIdFilePath + ")",
 	}
 
+	if nc.Spec.TLS != nil {
+		cmdAndArgs = append(cmdAndArgs, getTLSSearchPathArg())
+	}
+
 	if debug.Enabled {
 		// Increase verbosity in debug mode
 		cmdAndArgs = append(cmdAndArgs, "-v")
@@ -248,7 +302,7 @@ func (nss *ndbmtdStatefulSet) getContainers(nc *v1.NdbCluster, addInitialFlag bo
 
 	ndbmtdContainer := nss.createContainer(
 		nc, nss.getContainerName(false), cmdAndArgs,
-		nss.getVolumeMounts(), ndbmtdPorts)
+		nss.getVolumeMounts(nc), ndbmtdPorts)
 
 	// Setup startup probe for data nodes.
 	// The probe uses a script that checks if a data node has started, by
@@ -322,6 +376,12 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 		}
 	}
 
+	// Add VolumeClaimTemplate for the dedicated disk data PVC
+	if nc.HasDiskDataPVC() {
+		statefulSetSpec.VolumeClaimTemplates = append(statefulSetSpec.VolumeClaimTemplates,
+			*newPVC(nc, diskDataVolumeName, nc.Spec.DataNode.DiskData.PVCSpec))
+	}
+
 	// Update template pod spec
 	podSpec := &statefulSetSpec.Template.Spec
 	podSpec.Containers, err = nss.getContainers(nc, cs.DataNodeInitialRestart)
@@ -330,6 +390,17 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 		return nil, err
 	}
 	podSpec.Volumes = append(podSpec.Volumes, nss.getPodVolumes(nc)...)
+
+	// Annotate the pod template with the version of the TDE password
+	if nc.Spec.TDESecretName != "" {
+		secret, err := nss.secretLister.Secrets(nc.Namespace).Get(nc.Spec.TDESecretName)
+		if err != nil {
+			klog.Errorf("Failed to retrieve Secret %q : %s", nc.Spec.TDESecretName, err)
+			return nil, err
+		}
+		statefulSetSpec.Template.Annotations[LastAppliedTDEPasswordVersion] = GetTDEPasswordVersion(secret)
+	}
+
 	// Set default AntiAffinity rules
 	podSpec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: nss.getPodAntiAffinity(),
@@ -337,9 +408,59 @@ func (nss *ndbmtdStatefulSet) NewStatefulSet(cs *ndbconfig.ConfigSummary, nc *v1
 	// Copy down any podSpec specified via CRD
 	CopyPodSpecFromNdbPodSpec(podSpec, nc.Spec.DataNode.NdbPodSpec)
 
+	// Annotate the pod template with the node group specific pod specs
+	if nodeGroupPodSpecs := getDataNodeGroupPodSpecs(nc); len(nodeGroupPodSpecs) != 0 {
+		annotationValue, err := json.Marshal(nodeGroupPodSpecs)
+		if err != nil {
+			klog.Errorf("Failed to marshal the node group pod specs of the statefulset %s", statefulSet.Name)
+			return nil, err
+		}
+		statefulSetSpec.Template.Annotations[DataNodeGroupPodSpecs] = string(annotationValue)
+	}
+
 	return statefulSet, nil
 }
 
+// getDataNodeGroupPodSpecs returns the pod specs specified via
+// spec.dataNode.nodeGroups mapped to the ordinals of the data node pods.
+func getDataNodeGroupPodSpecs(nc *v1.NdbCluster) map[int32]*v1.NdbDataNodeGroupPodSpec {
+	nodeGroupPodSpecs := make(map[int32]*v1.NdbDataNodeGroupPodSpec)
+	redundancyLevel := nc.Spec.RedundancyLevel
+	for _, ngSpec := range nc.Spec.DataNode.NodeGroups {
+		if ngSpec.NdbPodSpec == nil {
+			continue
+		}
+		for podIdx := ngSpec.NodeGroup * redundancyLevel; podIdx < (ngSpec.NodeGroup+1)*redundancyLevel; podIdx++ {
+			nodeGroupPodSpecs[podIdx] = ngSpec.NdbPodSpec
+		}
+	}
+	return nodeGroupPodSpecs
+}
+
+// GetDataNodeGroupPodSpec returns the node group specific pod spec,
+// stored in the annotations of the given data node pod, if any.
+func GetDataNodeGroupPodSpec(pod *corev1.Pod) (*v1.NdbDataNodeGroupPodSpec, error) {
+	annotationValue, exists := pod.GetAnnotations()[DataNodeGroupPodSpecs]
+	if !exists {
+		// No node group specific pod specs
+		return nil, nil
+	}
+
+	var nodeGroupPodSpecs map[int32]*v1.NdbDataNodeGroupPodSpec
+	if err := json.Unmarshal([]byte(annotationValue), &nodeGroupPodSpecs); err != nil {
+		return nil, err
+	}
+
+	// Extract the ordinal from the pod name
+	podName := pod.GetName()
+	podIdx, err := strconv.ParseInt(podName[strings.LastIndex(podName, "-")+1:], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the ordinal from the pod name %q : %w", podName, err)
+	}
+
+	return nodeGroupPodSpecs[int32(podIdx)], nil
+}
+
 // NewNdbmtdStatefulSet returns a new NdbStatefulSetInterface for data nodes
 func NewNdbmtdStatefulSet(secretLister listerscorev1.SecretLister) NdbStatefulSetInterface {
 	return &ndbmtdStatefulSet{
@@ -349,3 +470,17 @@ func NewNdbmtdStatefulSet(secretLister listerscorev1.SecretLister) NdbStatefulSe
 		secretLister,
 	}
 }
+
+// GetDataNodePVCName returns the name of the PVC created by
+// the data node StatefulSet for the pod with the given name.
+func GetDataNodePVCName(podName string) string {
+	bss := baseStatefulSet{nodeType: constants.NdbNodeTypeNdbmtd}
+	return bss.getDataDirVolumeName() + "-" + podName
+}
+
+// GetDataNodeContainerName returns the name of
+// the container that runs the data node process.
+func GetDataNodeContainerName() string {
+	bss := baseStatefulSet{nodeType: constants.NdbNodeTypeNdbmtd}
+	return bss.getContainerName(false)
+}
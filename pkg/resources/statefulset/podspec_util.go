@@ -97,3 +97,45 @@ func CopyPodSpecFromNdbPodSpec(podSpec *corev1.PodSpec, ndbPodSpec *v1.NdbCluste
 	// Copy all the Tolerations
 	podSpec.Tolerations = append(podSpec.Tolerations, ndbPodSpec.Tolerations...)
 }
+
+// ApplyDataNodeGroupPodSpec applies the given node group specific pod spec
+// on top of the podSpec of a data node pod. The NodeSelector is merged, the
+// Tolerations are appended and any specified Affinity replaces the existing one.
+func ApplyDataNodeGroupPodSpec(podSpec *corev1.PodSpec, ngPodSpec *v1.NdbDataNodeGroupPodSpec) {
+	if ngPodSpec == nil {
+		// Nothing to do
+		return
+	}
+
+	// Merge the NodeSelector
+	if len(ngPodSpec.NodeSelector) != 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		for key, value := range ngPodSpec.NodeSelector {
+			podSpec.NodeSelector[key] = value
+		}
+	}
+
+	// Copy the Affinities that have been set
+	if ngPodSpec.Affinity != nil {
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = new(corev1.Affinity)
+		}
+
+		if ngPodSpec.Affinity.NodeAffinity != nil {
+			podSpec.Affinity.NodeAffinity = ngPodSpec.Affinity.NodeAffinity.DeepCopy()
+		}
+
+		if ngPodSpec.Affinity.PodAffinity != nil {
+			podSpec.Affinity.PodAffinity = ngPodSpec.Affinity.PodAffinity.DeepCopy()
+		}
+
+		if ngPodSpec.Affinity.PodAntiAffinity != nil {
+			podSpec.Affinity.PodAntiAffinity = ngPodSpec.Affinity.PodAntiAffinity.DeepCopy()
+		}
+	}
+
+	// Append the Tolerations
+	podSpec.Tolerations = append(podSpec.Tolerations, ngPodSpec.Tolerations...)
+}
@@ -1,6 +1,6 @@
 #!/bin/bash
 
-# Copyright (c) 2022, Oracle and/or its affiliates.
+# Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 #
 # Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -11,8 +11,14 @@ set -e
 # Extract the nodeId written by the init container
 nodeId=$(cat /var/lib/ndb/run/nodeId.val)
 
+# Connect to the Management Servers over TLS if it is enabled
+tlsOptions=()
+if [[ -n "${NDB_TLS_SEARCH_PATH}" ]]; then
+  tlsOptions=(--ndb-tls-search-path="${NDB_TLS_SEARCH_PATH}" --ndb-mgm-tls=strict)
+fi
+
 # Get local mgmd status using `ndb_mgm -e "<nodeId> status"` command
-nodeStatus=$(ndb_mgm -c "localhost" -e "${nodeId} status" --connect-retries=1)
+nodeStatus=$(ndb_mgm -c "localhost" "${tlsOptions[@]}" -e "${nodeId} status" --connect-retries=1)
 # If nodeStatus has "Node ${nodeId}: connected", the management node can be considered ready
 if ! [[ "${nodeStatus}" =~ .*Node\ "${nodeId}":\ connected.* ]]; then
   echo "Management node health check failed."
@@ -28,21 +34,19 @@ fi
 connectstringExcludingNodeId=${NDB_CONNECTSTRING#*,}
 connectstrings=(${connectstringExcludingNodeId//,/ })
 if ((${#connectstrings[@]} == 1)); then
-  # No need to handle update case as there is only one mgmd
-  # (i.e replica=1) and update is denied for such cases.
+  # No need to handle update case as there is only one
+  # mgmd and there is no other mgmd to compare status with.
   exit 0
 fi
 
-# Deduce the nodeId and connectstring of the "other" mgmd
-otherMgmdNodeId=0
+# Deduce the connectstring of an "other" mgmd
 otherMgmdConnectstring=""
-if ((nodeId == 1)); then
-  otherMgmdNodeId=2
-  otherMgmdConnectstring=${connectstrings[1]}
-else
-  otherMgmdNodeId=1
-  otherMgmdConnectstring=${connectstrings[0]}
-fi
+for connectstring in "${connectstrings[@]}"; do
+  if [[ "${connectstring}" != "${HOSTNAME}."* ]]; then
+    otherMgmdConnectstring=${connectstring}
+    break
+  fi
+done
 
 # Check if the other mgmd is already running.
 if [[ $(getent hosts "${otherMgmdConnectstring%:*}" | awk '{print $1}') == "" ]]; then
@@ -54,11 +58,22 @@ fi
 # Other mgmd is running. Local mgmd is ready when it reports the exact
 # same status about the connected mgmd and data nodes as the other mgmd.
 # Note : SQL/API node status is not compared and that seems to be okay for now.
-clusterStatusFromLocalMgmd=$(ndb_mgm -c localhost:1186 --connect-retries=1 -e show)
-clusterStatusFromOtherMgmd=$(ndb_mgm -c "${otherMgmdConnectstring}" --connect-retries=1 -e show)
-# Compare Management nodes' status first
-mgmdStatusFromLocalMgmd=$(echo "${clusterStatusFromLocalMgmd}" | sed -n '/ndb_mgmd(MGM)/,+2p')
-mgmdStatusFromOtherMgmd=$(echo "${clusterStatusFromOtherMgmd}" | sed -n '/ndb_mgmd(MGM)/,+2p')
+clusterStatusFromLocalMgmd=$(ndb_mgm -c localhost:1186 "${tlsOptions[@]}" --connect-retries=1 -e show)
+clusterStatusFromOtherMgmd=$(ndb_mgm -c "${otherMgmdConnectstring}" "${tlsOptions[@]}" --connect-retries=1 -e show)
+# Extract the number of management nodes from other mgmd
+numOfMgmds=$(echo "${clusterStatusFromOtherMgmd}" | grep -Po '\[ndb_mgmd\(MGM\)\]\t\K[0-9]+(?= node\(s\))')
+if ! [[ "${clusterStatusFromOtherMgmd}" =~ .*id=${nodeId}[^0-9].* ]]; then
+  # The other mgmd doesn't have this mgmd in its configuration yet. This
+  # mgmd is being added to the MySQL Cluster and the other mgmds will
+  # be restarted with the new configuration once this mgmd is ready.
+  exit 0
+fi
+
+# Compare Management nodes' status first. The management nodes
+# being added have the highest nodeIds, so compare only the
+# status of first $numOfMgmds management nodes.
+mgmdStatusFromLocalMgmd=$(echo "${clusterStatusFromLocalMgmd}" | sed -n "/ndb_mgmd(MGM)/,+${numOfMgmds}p" | sed '1d')
+mgmdStatusFromOtherMgmd=$(echo "${clusterStatusFromOtherMgmd}" | sed -n "/ndb_mgmd(MGM)/,+${numOfMgmds}p" | sed '1d')
 if [[ "${mgmdStatusFromLocalMgmd}" != "${mgmdStatusFromOtherMgmd}" ]]; then
   # Local Mgmd not ready
   exit 1
@@ -1,6 +1,6 @@
 #!/bin/bash
 
-# Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+# Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 #
 # Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -16,8 +16,14 @@
 # Extract the nodeId written by the init container
 nodeId=$(cat /var/lib/ndb/run/nodeId.val)
 
+# Connect to the Management Servers over TLS if it is enabled
+tlsOptions=()
+if [[ -n "${NDB_TLS_SEARCH_PATH}" ]]; then
+  tlsOptions=(--ndb-tls-search-path="${NDB_TLS_SEARCH_PATH}" --ndb-mgm-tls=strict)
+fi
+
 # Get node status using `ndb_mgm -e "<nodeId> status"` command
-nodeStatus=$(ndb_mgm -c "${NDB_CONNECTSTRING}" -e "${nodeId} status" --connect-retries=1)
+nodeStatus=$(ndb_mgm -c "${NDB_CONNECTSTRING}" "${tlsOptions[@]}" -e "${nodeId} status" --connect-retries=1)
 # If nodeStatus has "Node ${nodeId}: started", the data node can be considered live and ready
 if ! [[ "${nodeStatus}" =~ .*Node\ "${nodeId}":\ started.* ]]; then
   echo "Datanode health check failed."
@@ -0,0 +1,84 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package statefulset
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	"github.com/mysql/ndb-operator/pkg/constants"
+	"github.com/mysql/ndb-operator/pkg/ndbtls"
+)
+
+const (
+	// Volume name and mount path of the cluster CA
+	tlsCAVolName   = "ndb-tls-ca-vol"
+	tlsCAMountPath = constants.TLSCADir
+
+	// Volume name and mount path of the directory that
+	// has the private keys and the certificates of the node
+	tlsVolName   = "ndb-tls-vol"
+	tlsMountPath = constants.TLSDir
+)
+
+// ndbSignKeysNodeTypes maps the NdbNodeType to the ndb_sign_keys node types
+// for which certificates are created in the pods. The management and data
+// node pods also get an API node certificate to be used by the tools, like
+// ndb_mgm and ndb_restore, that run inside them.
+var ndbSignKeysNodeTypes = map[constants.NdbNodeType][]string{
+	constants.NdbNodeTypeMgmd:   {"mgmd", "api"},
+	constants.NdbNodeTypeNdbmtd: {"db", "api"},
+	constants.NdbNodeTypeMySQLD: {"api"},
+}
+
+// getTLSPodVolumes returns the volumes required by the pods to use TLS
+func (bss *baseStatefulSet) getTLSPodVolumes(nc *v1.NdbCluster) []corev1.Volume {
+	return []corev1.Volume{
+		{
+			Name:         tlsCAVolName,
+			VolumeSource: ndbtls.GetCAVolumeSource(nc),
+		},
+		// Empty Dir volume for the node's private keys and certificates
+		*bss.getEmptyDirPodVolume(tlsVolName),
+	}
+}
+
+// getTLSCAVolumeMount returns the VolumeMount for the cluster CA
+func getTLSCAVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      tlsCAVolName,
+		MountPath: tlsCAMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// getTLSVolumeMount returns the VolumeMount for
+// the node's private keys and certificates
+func getTLSVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      tlsVolName,
+		MountPath: tlsMountPath,
+	}
+}
+
+// getTLSSearchPathArg returns the argument that makes
+// the MySQL Cluster binaries use the node's certificate
+func getTLSSearchPathArg() string {
+	return "--ndb-tls-search-path=" + tlsMountPath
+}
+
+// getTLSInitContainer returns the init container that creates the
+// private keys of the node and the certificates signed by the cluster CA.
+func (bss *baseStatefulSet) getTLSInitContainer(nc *v1.NdbCluster) corev1.Container {
+	cmdAndArgs := ndbtls.GetSignKeysCommand(
+		tlsCAMountPath, tlsMountPath, ndbSignKeysNodeTypes[bss.nodeType]...)
+
+	volumeMounts := []corev1.VolumeMount{
+		getTLSCAVolumeMount(),
+		getTLSVolumeMount(),
+	}
+
+	return bss.createContainer(nc, bss.nodeType+"-tls-init-container", cmdAndArgs, volumeMounts, nil)
+}
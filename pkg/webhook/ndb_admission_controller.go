@@ -1,10 +1,12 @@
-// Copyright (c) 2021, 2022, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
 package webhook
 
 import (
+	"fmt"
+
 	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -55,18 +57,20 @@ func (nv *ndbAdmissionController) validateUpdate(
 	reqUID types.UID, newObj runtime.Object, oldObj runtime.Object) *admissionv1.AdmissionResponse {
 
 	oldNC := oldObj.(*v1.NdbCluster)
+	newNC := newObj.(*v1.NdbCluster)
 	// The Operator can handle only one update at a moment, so disallow
 	// any update when the previous update has not completed yet.
 	// In case of previous update failing due to an error, allow the
-	// new update as it might be attempting to fix the error.
-	if oldNC.Status.ProcessedGeneration != oldNC.Generation && !oldNC.HasSyncError() {
+	// new update as it might be attempting to fix the error. Also allow
+	// an update that aborts an ongoing migration to a new redundancyLevel.
+	if oldNC.Status.ProcessedGeneration != oldNC.Generation && !oldNC.HasSyncError() &&
+		!abortsRedundancyLevelMigration(oldNC, newNC) {
 		// The previous update is still being applied, and the sync has
 		// not encountered any errors so far - disallow new update.
 		return requestDenied(reqUID,
 			errors.NewTooManyRequestsError("previous update to the NdbCluster resource is still being applied"))
 	}
 
-	newNC := newObj.(*v1.NdbCluster)
 	if isValid, errList := oldNC.IsValidSpecUpdate(newNC); !isValid {
 		// new ndb does not define a valid configuration
 		return requestDeniedNdbInvalid(reqUID, newNC, errList)
@@ -75,6 +79,13 @@ func (nv *ndbAdmissionController) validateUpdate(
 	return requestAllowed(reqUID)
 }
 
+// abortsRedundancyLevelMigration returns true if the new NdbCluster
+// reverts spec.redundancyLevel to abort an ongoing migration
+func abortsRedundancyLevelMigration(oldNC, newNC *v1.NdbCluster) bool {
+	return oldNC.CanAbortRedundancyLevelMigration() &&
+		oldNC.Status.RedundancyLevelMigration.FromRedundancyLevel == newNC.Spec.RedundancyLevel
+}
+
 func (nv *ndbAdmissionController) mutate(obj runtime.Object) *jsonPatchOperations {
 	nc := obj.(*v1.NdbCluster)
 
@@ -97,5 +108,12 @@ func (nv *ndbAdmissionController) mutate(obj runtime.Object) *jsonPatchOperation
 		patchOps.replace("/spec/mysqlNode/maxNodeCount", nc.Spec.MysqlNode.NodeCount+2)
 	}
 
+	// Default the MaxNodeCount of the MySQL Server groups to nodeCount + 2
+	for i, mysqldGroup := range nc.Spec.MysqlNodeGroups {
+		if mysqldGroup.MaxNodeCount == 0 {
+			patchOps.add(fmt.Sprintf("/spec/mysqlNodeGroups/%d/maxNodeCount", i), mysqldGroup.NodeCount+2)
+		}
+	}
+
 	return &patchOps
 }
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -54,6 +54,20 @@ func Test_ndbAdmissionController_mutate(t *testing.T) {
 			},
 			// No patch expected
 		},
+		{
+			desc: "mysqld group maxNodeCount unspecified",
+			ncSpec: &v1.NdbClusterSpec{
+				MysqlNode: &v1.NdbMysqldSpec{
+					NodeCount:    1,
+					MaxNodeCount: 4,
+				},
+				MysqlNodeGroups: []v1.NdbMysqldGroupSpec{
+					{Name: "oltp", NodeCount: 2, MaxNodeCount: 3},
+					{Name: "reporting", NodeCount: 1},
+				},
+			},
+			expectedPatch: `[{"op":"add","path":"/spec/mysqlNodeGroups/1/maxNodeCount","value":3}]`,
+		},
 	}
 
 	ndbAc := newNdbAdmissionController()
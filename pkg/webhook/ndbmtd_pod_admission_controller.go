@@ -0,0 +1,88 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+)
+
+// ndbmtdPodAdmissionController implements admissionController for the
+// data node pods. It applies the node group specific pod specs, specified
+// via spec.dataNode.nodeGroups of the NdbCluster, to the data node pods.
+type ndbmtdPodAdmissionController struct{}
+
+func newNdbmtdPodAdmissionController() admissionController {
+	return &ndbmtdPodAdmissionController{}
+}
+
+func (npc *ndbmtdPodAdmissionController) getGVR() *metav1.GroupVersionResource {
+	return &metav1.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "pods",
+	}
+}
+
+func (npc *ndbmtdPodAdmissionController) getGVK() *schema.GroupVersionKind {
+	return &schema.GroupVersionKind{
+		Group:   "",
+		Version: "v1",
+		Kind:    "Pod",
+	}
+}
+
+func (npc *ndbmtdPodAdmissionController) newObject() runtime.Object {
+	return &corev1.Pod{}
+}
+
+func (npc *ndbmtdPodAdmissionController) validateCreate(
+	reqUID types.UID, _ runtime.Object) *admissionv1.AdmissionResponse {
+	return unsupportedValidatorOperation(reqUID, admissionv1.Create)
+}
+
+func (npc *ndbmtdPodAdmissionController) validateUpdate(
+	reqUID types.UID, _ runtime.Object, _ runtime.Object) *admissionv1.AdmissionResponse {
+	return unsupportedValidatorOperation(reqUID, admissionv1.Update)
+}
+
+func (npc *ndbmtdPodAdmissionController) mutate(obj runtime.Object) *jsonPatchOperations {
+	pod := obj.(*corev1.Pod)
+
+	var patchOps jsonPatchOperations
+
+	ngPodSpec, err := statefulset.GetDataNodeGroupPodSpec(pod)
+	if err != nil {
+		// Should not happen as the operator generates the annotation
+		klog.Errorf("Failed to extract the node group pod spec of the pod %q : %s", pod.Name, err)
+		return &patchOps
+	}
+
+	if ngPodSpec == nil {
+		// No node group specific pod spec for this pod
+		return &patchOps
+	}
+
+	// Apply the node group pod spec and replace the fields
+	podSpec := pod.Spec.DeepCopy()
+	statefulset.ApplyDataNodeGroupPodSpec(podSpec, ngPodSpec)
+	if len(podSpec.NodeSelector) != 0 {
+		patchOps.add("/spec/nodeSelector", podSpec.NodeSelector)
+	}
+	if podSpec.Affinity != nil {
+		patchOps.add("/spec/affinity", podSpec.Affinity)
+	}
+	if len(podSpec.Tolerations) != 0 {
+		patchOps.add("/spec/tolerations", podSpec.Tolerations)
+	}
+
+	return &patchOps
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/mysql/ndb-operator/pkg/resources/statefulset"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ndbmtdPodAdmissionController_mutate(t *testing.T) {
+	nodeGroupPodSpecs := `{"2":{"nodeSelector":{"disk":"ssd"},"tolerations":[{"key":"dedicated","operator":"Exists"}]}}`
+
+	testcases := []struct {
+		desc          string
+		podName       string
+		annotations   map[string]string
+		expectedPatch string
+	}{
+		{
+			desc:    "pod without node group pod specs",
+			podName: "example-ndb-ndbmtd-2",
+			// No patch expected
+		},
+		{
+			desc:        "pod not in any node group with a pod spec",
+			podName:     "example-ndb-ndbmtd-1",
+			annotations: map[string]string{statefulset.DataNodeGroupPodSpecs: nodeGroupPodSpecs},
+			// No patch expected
+		},
+		{
+			desc:        "pod in a node group with a pod spec",
+			podName:     "example-ndb-ndbmtd-2",
+			annotations: map[string]string{statefulset.DataNodeGroupPodSpecs: nodeGroupPodSpecs},
+			expectedPatch: `[{"op":"add","path":"/spec/nodeSelector","value":{"disk":"ssd","zone":"a"}},` +
+				`{"op":"add","path":"/spec/tolerations","value":[{"key":"dedicated","operator":"Exists"}]}]`,
+		},
+	}
+
+	npc := newNdbmtdPodAdmissionController()
+	for _, tc := range testcases {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        tc.podName,
+				Annotations: tc.annotations,
+			},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+			},
+		}
+
+		patch, err := npc.mutate(pod).getPatch()
+		if err != nil {
+			t.Errorf("Testcase %q failed with error %q", tc.desc, err)
+			continue
+		}
+
+		if string(patch) != tc.expectedPatch {
+			t.Errorf("Testcase %q failed : Expected patch `%s` but got `%s`", tc.desc, tc.expectedPatch, string(patch))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ndbMySQLUserAdmissionController implements admissionController for the
+// NdbMySQLUser resource. It rejects the users reserved for the MySQL Server
+// and the NDB Operator, which would otherwise be modified or dropped by it.
+type ndbMySQLUserAdmissionController struct{}
+
+func newNdbMySQLUserAdmissionController() admissionController {
+	return &ndbMySQLUserAdmissionController{}
+}
+
+func (nuc *ndbMySQLUserAdmissionController) getGVR() *metav1.GroupVersionResource {
+	return &metav1.GroupVersionResource{
+		Group:    "mysql.oracle.com",
+		Version:  "v1",
+		Resource: "ndbmysqlusers",
+	}
+}
+
+func (nuc *ndbMySQLUserAdmissionController) getGVK() *schema.GroupVersionKind {
+	return &schema.GroupVersionKind{
+		Group:   "mysql.oracle.com",
+		Version: "v1",
+		Kind:    "NdbMySQLUser",
+	}
+}
+
+func (nuc *ndbMySQLUserAdmissionController) newObject() runtime.Object {
+	return &v1.NdbMySQLUser{}
+}
+
+func (nuc *ndbMySQLUserAdmissionController) validateCreate(
+	reqUID types.UID, obj runtime.Object) *admissionv1.AdmissionResponse {
+	nmu := obj.(*v1.NdbMySQLUser)
+	if isValid, errList := nmu.HasValidSpec(); !isValid {
+		return requestDenied(reqUID, errors.NewInvalid(v1.Kind("NdbMySQLUser"), nmu.Name, errList))
+	}
+
+	return requestAllowed(reqUID)
+}
+
+func (nuc *ndbMySQLUserAdmissionController) validateUpdate(
+	reqUID types.UID, newObj runtime.Object, _ runtime.Object) *admissionv1.AdmissionResponse {
+	// The spec of an existing user is validated just like a new one
+	return nuc.validateCreate(reqUID, newObj)
+}
+
+func (nuc *ndbMySQLUserAdmissionController) mutate(_ runtime.Object) *jsonPatchOperations {
+	// Nothing to mutate
+	return &jsonPatchOperations{}
+}
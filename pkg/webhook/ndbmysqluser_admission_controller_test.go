@@ -0,0 +1,51 @@
+// Copyright (c) 2024, Oracle and/or its affiliates.
+//
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
+
+package webhook
+
+import (
+	"testing"
+
+	v1 "github.com/mysql/ndb-operator/pkg/apis/ndbcontroller/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ndbMySQLUserAdmissionController_validate(t *testing.T) {
+	testcases := []struct {
+		userName string
+		allowed  bool
+	}{
+		{userName: "app", allowed: true},
+		{userName: "rooted", allowed: true},
+		{userName: "root", allowed: false},
+		{userName: "ROOT", allowed: false},
+		{userName: "ndb-operator-user", allowed: false},
+		{userName: "ndb-replication-user", allowed: false},
+		{userName: "mysql.sys", allowed: false},
+	}
+
+	nuc := newNdbMySQLUserAdmissionController()
+	for _, tc := range testcases {
+		nmu := &v1.NdbMySQLUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-user", Namespace: "default"},
+			Spec: v1.NdbMySQLUserSpec{
+				ClusterName:        "example-ndb",
+				UserName:           tc.userName,
+				PasswordSecretName: "test-user-password",
+			},
+		}
+
+		if response := nuc.validateCreate("", nmu); response.Allowed != tc.allowed {
+			t.Errorf("Expected the creation of user %q to be allowed=%v but got %v",
+				tc.userName, tc.allowed, response.Allowed)
+		}
+
+		oldNmu := nmu.DeepCopy()
+		oldNmu.Spec.UserName = "app"
+		if response := nuc.validateUpdate("", nmu, oldNmu); response.Allowed != tc.allowed {
+			t.Errorf("Expected the update to user %q to be allowed=%v but got %v",
+				tc.userName, tc.allowed, response.Allowed)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021, 2023, Oracle and/or its affiliates.
+// Copyright (c) 2021, 2024, Oracle and/or its affiliates.
 //
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl/
 
@@ -128,7 +128,9 @@ func initWebhookServer(ws *http.Server) {
 
 	// pattern to admissionController mapping
 	admissionControllers := map[string]admissionController{
-		"ndb": newNdbAdmissionController(),
+		"ndb":          newNdbAdmissionController(),
+		"ndbmtd-pod":   newNdbmtdPodAdmissionController(),
+		"ndbmysqluser": newNdbMySQLUserAdmissionController(),
 	}
 
 	// allowed admissionController requestTypes